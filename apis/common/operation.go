@@ -0,0 +1,32 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+// PendingOperation is a GCP long-running operation which is in progress.
+// The controller records it in the status of the resource and returns, and a
+// later reconciliation resumes waiting for it rather than issuing the call again.
+// +kubebuilder:object:generate:=true
+type PendingOperation struct {
+	// The name of the operation, e.g. "projects/p/locations/l/operations/o".
+	Name string `json:"name"`
+
+	// The type of the operation, e.g. "create" or "update".
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// The time the operation was started, in RFC3339 format.
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+}
@@ -15,6 +15,7 @@
 package v1alpha1
 
 import (
+	"github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
 	refs "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/k8s/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -97,6 +98,10 @@ type RedisClusterStatus struct {
 	// ObservedState is the state of the resource as most recently observed in GCP.
 	// +optional
 	ObservedState *RedisClusterObservedState `json:"observedState,omitempty"`
+
+	// PendingOperation is the GCP long-running operation which the Config Connector controller is waiting for, if any.
+	// +optional
+	PendingOperation *common.PendingOperation `json:"pendingOperation,omitempty"`
 }
 
 // RedisClusterSpec defines the desired state of RedisCluster
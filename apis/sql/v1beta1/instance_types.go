@@ -17,6 +17,7 @@ package v1beta1
 import (
 	"reflect"
 
+	"github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
 	refsv1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	refsv1beta1secret "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1/secret"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/clients/generated/apis/k8s/v1alpha1"
@@ -543,6 +544,10 @@ type SQLInstanceStatus struct {
 	// +optional
 	ObservedGeneration *int64 `json:"observedGeneration,omitempty"`
 
+	/* PendingOperation is the GCP long-running operation which the Config Connector controller is waiting for, if any. */
+	// +optional
+	PendingOperation *common.PendingOperation `json:"pendingOperation,omitempty"`
+
 	// +optional
 	PrivateIpAddress *string `json:"privateIpAddress,omitempty"`
 
@@ -19,6 +19,7 @@
 package v1beta1
 
 import (
+	"github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
 	refsv1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1/secret"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/clients/generated/apis/k8s/v1alpha1"
@@ -965,6 +966,11 @@ func (in *SQLInstanceStatus) DeepCopyInto(out *SQLInstanceStatus) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.PendingOperation != nil {
+		in, out := &in.PendingOperation, &out.PendingOperation
+		*out = new(common.PendingOperation)
+		**out = **in
+	}
 	if in.PrivateIpAddress != nil {
 		in, out := &in.PrivateIpAddress, &out.PrivateIpAddress
 		*out = new(string)
@@ -15,6 +15,7 @@
 package v1beta1
 
 import (
+	"github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/k8s/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -57,6 +58,10 @@ type WorkstationStatus struct {
 
 	// ObservedState is the state of the resource as most recently observed in GCP.
 	ObservedState *WorkstationObservedState `json:"observedState,omitempty"`
+
+	// PendingOperation is the GCP long-running operation which the Config Connector controller is waiting for, if any.
+	// +optional
+	PendingOperation *common.PendingOperation `json:"pendingOperation,omitempty"`
 }
 
 // WorkstationSpec defines the desired state of Workstation
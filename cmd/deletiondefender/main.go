@@ -21,9 +21,11 @@ import (
 	"net/http"
 	_ "net/http/pprof" // Needed to allow pprof server to accept requests
 
+	operatorv1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/kccmanager/nocache"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/ordereddeletion"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/registration"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/profiler"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/logging"
@@ -90,14 +92,21 @@ func main() {
 	}
 
 	// Setup Scheme for all resources
-	apis.AddToSchemes = append(apis.AddToSchemes, apiextensions.SchemeBuilder.AddToScheme)
+	apis.AddToSchemes = append(apis.AddToSchemes, apiextensions.SchemeBuilder.AddToScheme, operatorv1beta1.AddToScheme)
 	if err := apis.AddToScheme(mgr.GetScheme()); err != nil {
 		log.Fatal(err)
 	}
 
+	// Register the ordered deletion controller, which orders the deletion of the resources of
+	// the namespaces which are deleted or have the ordered deletion annotation.
+	orderedDeletion := ordereddeletion.NewTracker()
+	if err := ordereddeletion.Add(mgr, orderedDeletion); err != nil {
+		log.Fatal(err, "error adding ordered deletion controller")
+	}
+
 	// Register the registration controller, which will dynamically create controllers for
 	// all our resources.
-	if err := registration.Add(mgr, &controller.Deps{}, registration.RegisterDeletionDefenderController); err != nil {
+	if err := registration.Add(mgr, &controller.Deps{OrderedDeletion: orderedDeletion}, registration.RegisterDeletionDefenderController); err != nil {
 		log.Fatal(err, "error adding registration controller")
 	}
 
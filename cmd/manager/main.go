@@ -24,14 +24,16 @@ import (
 	_ "net/http/pprof" // Needed to allow pprof server to accept requests
 
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/kccmanager"
-	controllermetrics "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/metrics"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/ratelimiter"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/profiler"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/ratelimit"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/krmtotf"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/lease/leaser"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/logging"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/metrics"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/ready"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tracing"
 
 	flag "github.com/spf13/pflag"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
@@ -60,9 +62,17 @@ func main() {
 		pprofPort                int
 		rateLimitQps             float32
 		rateLimitBurst           int
+		gcpRateLimitQps          float64
+		gcpRateLimitBurst        int
+		gcpServiceRateLimits     []string
+		leaseBackends            leaser.BackendsConfig
+		tracingConfig            tracing.Config
+		resourceNameLabel        bool
+		metricLabels             []string
 	)
 	flag.StringVar(&prometheusScrapeEndpoint, "prometheus-scrape-endpoint", ":8888", "configure the Prometheus scrape endpoint; :8888 as default")
-	flag.BoolVar(&controllermetrics.ResourceNameLabel, "resource-name-label", false, "option to enable the resource name label on some Prometheus metrics; false by default")
+	flag.BoolVar(&resourceNameLabel, "resource-name-label", false, "option to enable the resource name label on some Prometheus metrics; false by default")
+	flag.StringArrayVar(&metricLabels, "metric-labels", nil, "Selects the labels a Prometheus metric is exported with, in the format <metric>=<label>[,<label>...], e.g. reconcile_requests_total=group_version_kind,status; can be repeated for different metrics. The labels of gauges cannot be dropped.")
 	flag.BoolVar(&userProjectOverride, "user-project-override", false, "option to use the resource project for preconditions, quota, and billing, instead of the project the credentials belong to; false by default")
 	flag.StringVar(&billingProject, "billing-project", "", "project to use for preconditions, quota, and billing if --user-project-override is enabled; empty by default; if this is left empty but --user-project-override is enabled, the resource's project will be used")
 	flag.StringVar(&scopedNamespace, "scoped-namespace", "", "scope controllers to only watch resources in the specified namespace; if unspecified, controllers will run in cluster scope")
@@ -70,6 +80,15 @@ func main() {
 	flag.IntVar(&pprofPort, "pprof-port", 6060, "The port that the pprof server binds to if enabled.")
 	flag.Float32Var(&rateLimitQps, "qps", 20.0, "The client-side token bucket rate limit qps.")
 	flag.IntVar(&rateLimitBurst, "burst", 30, "The client-side token bucket rate limit burst.")
+	flag.Float64Var(&gcpRateLimitQps, "gcp-qps", 0, "The client-side token bucket rate limit qps for the requests to each GCP service in each project; 0 (unlimited) by default.")
+	flag.IntVar(&gcpRateLimitBurst, "gcp-burst", 0, "The client-side token bucket rate limit burst for the requests to each GCP service in each project; defaults to --gcp-qps.")
+	flag.StringArrayVar(&gcpServiceRateLimits, "gcp-service-rate-limit", nil, "Overrides --gcp-qps and --gcp-burst for a GCP service, in the format <service>=<qps>[:<burst>] with a positive qps, e.g. compute.googleapis.com=10:20; can be repeated.")
+	flag.StringVar(&leaseBackends.GCSBucket, "lease-gcs-bucket", "", "The GCS bucket in which the leases of the resources with the 'gcs' management conflict prevention policy are stored; the policy is unavailable if unset.")
+	flag.StringVar(&leaseBackends.GCSPrefix, "lease-gcs-prefix", "", "The prefix of the names of the lease objects in --lease-gcs-bucket.")
+	flag.StringVar(&leaseBackends.HubNamespace, "lease-hub-namespace", "", "The namespace in which the Leases of the resources with the 'kubernetes-lease' management conflict prevention policy are stored; the policy is unavailable if unset.")
+	flag.StringVar(&leaseBackends.HubKubeconfig, "lease-hub-kubeconfig", "", "The kubeconfig of the hub cluster in which the Leases are stored; defaults to the cluster Config Connector runs in.")
+	flag.StringVar(&tracingConfig.OTLPEndpoint, "otlp-endpoint", "", "The OTLP/HTTP endpoint to which the traces of the reconciliations and GCP requests are exported, e.g. http://otel-collector:4318; tracing is disabled if unset.")
+	flag.Float64Var(&tracingConfig.SampleRatio, "trace-sample-ratio", 1.0, "The ratio of the reconciliations which are traced, between 0 and 1; only used if --otlp-endpoint is set.")
 	profiler.AddFlag(flag.CommandLine)
 	flag.CommandLine.AddGoFlagSet(goflag.CommandLine)
 	flag.Parse()
@@ -103,22 +122,54 @@ func main() {
 
 	// Set client site rate limiter to optimize the configconnector re-reconciliation performance.
 	ratelimiter.SetMasterRateLimiter(restCfg, rateLimitQps, rateLimitBurst)
+
+	gcpRateLimit := &ratelimit.Config{
+		Default:  ratelimit.Limit{QPS: gcpRateLimitQps, Burst: gcpRateLimitBurst},
+		Services: make(map[string]ratelimit.Limit),
+	}
+	for _, s := range gcpServiceRateLimits {
+		service, limit, err := ratelimit.ParseServiceLimit(s)
+		if err != nil {
+			logging.Fatal(err, "error parsing --gcp-service-rate-limit")
+		}
+		gcpRateLimit.Services[service] = limit
+	}
+
+	if tracingConfig.IsEnabled() {
+		logger.Info("Setting up the OpenTelemetry trace exporter", "endpoint", tracingConfig.OTLPEndpoint)
+		shutdown, err := tracing.Setup(ctx, tracingConfig, "cnrm-controller-manager")
+		if err != nil {
+			logging.Fatal(err, "error setting up tracing")
+		}
+		defer func() {
+			if err := shutdown(context.Background()); err != nil {
+				logger.Error(err, "error shutting down the trace exporter")
+			}
+		}()
+	}
+
 	logger.Info("Creating the manager")
-	mgr, err := newManager(ctx, restCfg, scopedNamespace, userProjectOverride, billingProject)
+	mgr, err := newManager(ctx, restCfg, scopedNamespace, userProjectOverride, billingProject, gcpRateLimit, leaseBackends, tracingConfig.IsEnabled())
 	if err != nil {
 		logging.Fatal(err, "error creating the manager")
 	}
 
-	// Register controller OpenCensus views
-	logger.Info("Registering controller OpenCensus views.")
-	if controllermetrics.ResourceNameLabel {
-		if err = metrics.RegisterControllerOpenCensusViewsWithResourceNameLabel(); err != nil {
-			logging.Fatal(err, "error registering controller OpenCensus views with resource name label.")
-		}
-	} else {
-		if err = metrics.RegisterControllerOpenCensusViews(); err != nil {
-			logging.Fatal(err, "error registering controller OpenCensus views.")
+	// Register controller metrics
+	logger.Info("Registering controller metrics.")
+	labelConfig := make(metrics.LabelConfig)
+	for _, s := range metricLabels {
+		name, labels, err := metrics.ParseMetricLabels(s)
+		if err != nil {
+			logging.Fatal(err, "error parsing --metric-labels")
 		}
+		labelConfig[name] = labels
+	}
+	if resourceNameLabel {
+		labelConfig.Add("reconcile_requests_total", metrics.ResourceNameLabel)
+		labelConfig.Add("reconcile_request_duration_seconds", metrics.ResourceNameLabel)
+	}
+	if err = metrics.RegisterControllerMetrics(labelConfig); err != nil {
+		logging.Fatal(err, "error registering controller metrics.")
 	}
 
 	// Register the Prometheus exporter
@@ -143,7 +194,7 @@ func main() {
 	logging.Fatal(mgr.Start(stop), "error during manager execution.")
 }
 
-func newManager(ctx context.Context, restCfg *rest.Config, scopedNamespace string, userProjectOverride bool, billingProject string) (manager.Manager, error) {
+func newManager(ctx context.Context, restCfg *rest.Config, scopedNamespace string, userProjectOverride bool, billingProject string, gcpRateLimit *ratelimit.Config, leaseBackends leaser.BackendsConfig, gcpTracing bool) (manager.Manager, error) {
 	krmtotf.SetUserAgentForTerraformProvider()
 	controllersCfg := kccmanager.Config{
 		ManagerOptions: manager.Options{
@@ -153,6 +204,9 @@ func newManager(ctx context.Context, restCfg *rest.Config, scopedNamespace strin
 
 	controllersCfg.UserProjectOverride = userProjectOverride
 	controllersCfg.BillingProject = billingProject
+	controllersCfg.GCPRateLimit = gcpRateLimit
+	controllersCfg.LeaseBackends = leaseBackends
+	controllersCfg.GCPTracing = gcpTracing
 	// TODO(b/320784855): StateIntoSpecDefaultValue and StateIntoSpecUserOverride values should come from the flags.
 	controllersCfg.StateIntoSpecDefaultValue = k8s.StateIntoSpecDefaultValueV1Beta1
 	mgr, err := kccmanager.New(ctx, restCfg, controllersCfg)
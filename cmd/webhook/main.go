@@ -23,6 +23,7 @@ import (
 	_ "net/http/pprof" // Needed to allow pprof server to accept requests
 	"time"
 
+	customizev1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/customize/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/profiler"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
@@ -117,6 +118,8 @@ func main() {
 
 	// Setup Scheme for all resources
 	apis.AddToSchemes = append(apis.AddToSchemes, apiextensions.SchemeBuilder.AddToScheme)
+	// ValidationPolicies are read by the validation-policy webhook.
+	apis.AddToSchemes = append(apis.AddToSchemes, customizev1beta1.AddToScheme)
 	if err := apis.AddToScheme(mgr.GetScheme()); err != nil {
 		log.Fatal(err)
 	}
@@ -339,7 +339,16 @@ func NewHarness(ctx context.Context, t *testing.T, opts ...HarnessOption) *Harne
 	if targetGCP := os.Getenv("E2E_GCP_TARGET"); targetGCP == "mock" {
 		t.Logf("creating mock gcp")
 
-		mockCloud := mockgcp.NewMockRoundTripper(t, h.client, storage.NewInMemoryStorage())
+		var mockStorage storage.Storage = storage.NewInMemoryStorage()
+		if storageDir := os.Getenv("MOCKGCP_STORAGE_DIR"); storageDir != "" {
+			fileStorage, err := storage.NewFileStorage(storageDir)
+			if err != nil {
+				h.Fatalf("error creating mockgcp storage: %v", err)
+			}
+			mockStorage = fileStorage
+		}
+
+		mockCloud := mockgcp.NewMockRoundTripper(t, h.client, mockStorage)
 
 		mockCloudGRPCClientConnection = mockCloud.NewGRPCConnection(ctx)
 		h.MockGCP = mockCloud
@@ -711,6 +720,9 @@ func MaybeSkip(t *testing.T, name string, resources []*unstructured.Unstructured
 			if gvk.Group == "" && gvk.Kind == "MockGCPBackdoor" {
 				continue
 			}
+			if gvk.Group == "" && gvk.Kind == "MockGCPFault" {
+				continue
+			}
 
 			switch gvk.Group {
 			case "core.cnrm.cloud.google.com":
@@ -0,0 +1,194 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/experiments/kompanion/pkg/analyze"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/experiments/kompanion/pkg/utils"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	examples = `
+	# Analyze why Config Connector resources are not ready across all namespaces, excludes \"kube\" namespaces by default
+	kompanion analyze
+
+	# Analyze a report created by kompanion export, without access to the cluster
+	kompanion analyze --report=report-20240101-120000.000.tar.gz
+
+	# target only specific namespace prefixes
+	kompanion analyze --target-namespaces=my-team
+	`
+)
+
+func BuildAnalyzeCmd() *cobra.Command {
+	opts := NewAnalyzeOptions()
+	cmd := &cobra.Command{
+		Use:     "analyze",
+		Short:   "analyze why Config Connector resources are not ready",
+		Example: examples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunAnalyze(cmd.Context(), opts)
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	flags := cmd.Flags()
+	opts.AddFlags(flags)
+
+	return cmd
+}
+
+func RunAnalyze(ctx context.Context, opts *AnalyzeOptions) error {
+	var objects []*unstructured.Unstructured
+	var err error
+	if opts.report != "" {
+		log.Printf("Running kompanion analyze with report: %s", opts.report)
+		objects, err = loadReport(opts.report)
+	} else {
+		log.Printf("Running kompanion analyze with kubeconfig: %s", opts.kubeconfig)
+		objects, err = listObjects(ctx, opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	var analyzed []*analyze.Object
+	for _, u := range objects {
+		if shouldExclude(u.GetNamespace(), opts.ignoreNamespaces, opts.targetNamespaces) {
+			continue
+		}
+		analyzed = append(analyzed, analyze.NewObject(u))
+	}
+
+	analyze.Analyze(analyzed).Print(os.Stdout)
+	return nil
+}
+
+func loadReport(path string) ([]*unstructured.Unstructured, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading report: %w", err)
+	}
+	if fi.IsDir() {
+		return analyze.LoadDirectory(path)
+	}
+	return analyze.LoadReport(path)
+}
+
+func getRESTConfig(ctx context.Context, opts *AnalyzeOptions) (*rest.Config, error) {
+	var loadingRules clientcmd.ClientConfigLoader
+	if opts.kubeconfig != "" {
+		loadingRules = &clientcmd.ClientConfigLoadingRules{ExplicitPath: opts.kubeconfig}
+	} else {
+		loadingRules = clientcmd.NewDefaultClientConfigLoadingRules()
+	}
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		&clientcmd.ConfigOverrides{}).ClientConfig()
+}
+
+// listObjects lists the Config Connector objects in the cluster. If the analysis targets
+// specific namespaces, the objects are listed per namespace, since users targeting a
+// namespace may not have cluster-wide permissions.
+func listObjects(ctx context.Context, opts *AnalyzeOptions) ([]*unstructured.Unstructured, error) {
+	config, err := getRESTConfig(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error building kubeconfig: %w", err)
+	}
+
+	// We rely more on server-side rate limiting now, so give it a high client-side QPS
+	if config.QPS == 0 {
+		config.QPS = 100
+		config.Burst = 20
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Kubernetes clientset: %w", err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating dynamic client: %w", err)
+	}
+
+	resources, err := utils.GetResources(clientset.Discovery(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching resources: %w", err)
+	}
+
+	namespaces := []string{""}
+	if len(opts.targetNamespaces) > 0 {
+		nsList, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error fetching namespaces: %w", err)
+		}
+		namespaces = nil
+		for _, ns := range nsList.Items {
+			if !shouldExclude(ns.Name, opts.ignoreNamespaces, opts.targetNamespaces) {
+				namespaces = append(namespaces, ns.Name)
+			}
+		}
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, gvr := range resources {
+		for _, ns := range namespaces {
+			list, err := dynamicClient.Resource(gvr).Namespace(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("fetching gvr %s resources in namespace %q: %w", gvr, ns, err)
+			}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+		}
+	}
+	return objects, nil
+}
+
+func shouldExclude(name string, excludes []string, includes []string) bool {
+	for _, exclude := range excludes {
+		if strings.Contains(name, exclude) {
+			return true
+		}
+	}
+
+	if len(includes) == 0 {
+		return false // no includes means includes all in this case
+	}
+
+	for _, include := range includes {
+		if strings.Contains(name, include) {
+			return false
+		}
+	}
+
+	// by default exclude if nothing that has been defined included this namespace.
+	return true
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyze
+
+import (
+	"log"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// flag names.
+	kubeconfigFlag = "kubeconfig"
+	reportFlag     = "report"
+
+	targetNamespacesFlag = "target-namespaces"
+	ignoreNamespacesFlag = "exclude-namespaces"
+)
+
+type AnalyzeOptions struct {
+	kubeconfig string
+	report     string
+
+	targetNamespaces []string
+	ignoreNamespaces []string
+}
+
+func (o *AnalyzeOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.kubeconfig, kubeconfigFlag, "", o.kubeconfig, "path to the kubeconfig file.")
+	flags.StringVarP(&o.report, reportFlag, "", o.report, "path to a report created by `kompanion export`, either the .tar.gz file or its extracted directory. If set, the report is analyzed offline instead of the cluster.")
+
+	flags.StringArrayVarP(&o.targetNamespaces, targetNamespacesFlag, "", o.targetNamespaces, "namespace prefix to target. Targets all if empty. Can be specified multiple times.")
+	flags.StringArrayVarP(&o.ignoreNamespaces, ignoreNamespacesFlag, "", o.ignoreNamespaces, "namespace prefix to ignore. Excludes nothing if empty. Can be specified multiple times. Defaults to \"kube\".")
+}
+
+func (o *AnalyzeOptions) Print() {
+	log.Printf("kubeconfig set to %q.\n", o.kubeconfig)
+	log.Printf("report set to %q.\n", o.report)
+	log.Printf("targetNamespaces set to %v.\n", o.targetNamespaces)
+	log.Printf("ignoreNamespaces set to %v.\n", o.ignoreNamespaces)
+}
+
+func NewAnalyzeOptions() *AnalyzeOptions {
+	o := AnalyzeOptions{
+		kubeconfig:       "",
+		report:           "",
+		targetNamespaces: []string{},
+		ignoreNamespaces: []string{"kube"},
+	}
+	return &o
+}
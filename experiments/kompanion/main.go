@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/GoogleCloudPlatform/k8s-config-connector/experiments/kompanion/cmd/analyze"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/experiments/kompanion/cmd/export"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/experiments/kompanion/cmd/summary"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/experiments/kompanion/pkg/version"
@@ -32,6 +33,7 @@ func BuildRootCommand() *cobra.Command {
 
 	rootCmd.AddCommand(export.BuildExportCmd())
 	rootCmd.AddCommand(summary.BuildSummaryCmd())
+	rootCmd.AddCommand(analyze.BuildAnalyzeCmd())
 
 	rootCmd.Version = version.GetVersion()
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
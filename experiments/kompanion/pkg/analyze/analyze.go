@@ -0,0 +1,432 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package analyze finds out why Config Connector objects are not ready: it groups them by the
+// reason of their Ready condition, follows their resource references to the objects blocking
+// them, and flags the objects whose status is stale.
+package analyze
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Reasons of the Ready condition of Config Connector objects which are caused by another object.
+const (
+	reasonDependencyNotReady = "DependencyNotReady"
+	reasonDependencyNotFound = "DependencyNotFound"
+	reasonDependencyInvalid  = "DependencyInvalid"
+
+	// reasonNoReadyCondition groups the objects without a Ready condition, which usually means
+	// that the object has not been reconciled yet.
+	reasonNoReadyCondition = "<no Ready condition>"
+)
+
+// ObjectID identifies an object in the analysis.
+type ObjectID struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (id ObjectID) String() string {
+	return fmt.Sprintf("%s %s/%s", id.Kind, id.Namespace, id.Name)
+}
+
+// Condition is the Ready condition of an object.
+type Condition struct {
+	Status  string
+	Reason  string
+	Message string
+}
+
+// Reference is a resource reference in the spec of an object, e.g. spec.projectRef.
+type Reference struct {
+	// Field is the path of the reference in the object, e.g. "spec.networkRef".
+	Field string
+	// Kind is the kind of the referenced object, if the reference specifies it.
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// Object is the part of a Config Connector object the analysis needs.
+type Object struct {
+	ID ObjectID
+
+	Generation         int64
+	ObservedGeneration int64
+	// HasObservedGeneration is false for objects without status.observedGeneration, e.g. for
+	// objects which have not been reconciled yet.
+	HasObservedGeneration bool
+
+	// Ready is the Ready condition of the object, or nil if it has none.
+	Ready *Condition
+
+	References []Reference
+}
+
+// IsReady returns true if the Ready condition of the object is True.
+func (o *Object) IsReady() bool {
+	return o.Ready != nil && o.Ready.Status == "True"
+}
+
+func (o *Object) reason() string {
+	if o.Ready == nil {
+		return reasonNoReadyCondition
+	}
+	if o.Ready.Reason == "" {
+		return "<no reason>"
+	}
+	return o.Ready.Reason
+}
+
+// isBlockedByDependency returns true if the object is not ready because of another object.
+func (o *Object) isBlockedByDependency() bool {
+	switch o.reason() {
+	case reasonDependencyNotReady, reasonDependencyNotFound, reasonDependencyInvalid:
+		return true
+	}
+	return false
+}
+
+// NewObject extracts the Ready condition, generations and resource references of u.
+func NewObject(u *unstructured.Unstructured) *Object {
+	o := &Object{
+		ID: ObjectID{
+			Kind:      u.GetKind(),
+			Namespace: u.GetNamespace(),
+			Name:      u.GetName(),
+		},
+	}
+	o.Generation, _ = nestedInt64(u.Object, "metadata", "generation")
+	o.ObservedGeneration, o.HasObservedGeneration = nestedInt64(u.Object, "status", "observedGeneration")
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if t, _, _ := unstructured.NestedString(condition, "type"); t != "Ready" {
+			continue
+		}
+		status, _, _ := unstructured.NestedString(condition, "status")
+		reason, _, _ := unstructured.NestedString(condition, "reason")
+		message, _, _ := unstructured.NestedString(condition, "message")
+		o.Ready = &Condition{Status: status, Reason: reason, Message: message}
+	}
+	if spec, found, _ := unstructured.NestedMap(u.Object, "spec"); found {
+		o.References = findReferences("spec", spec, u.GetNamespace())
+	}
+	return o
+}
+
+// nestedInt64 returns the integer at the given path. Unlike unstructured.NestedInt64, it
+// accepts the float64 values of the objects read from YAML or JSON files.
+func nestedInt64(obj map[string]interface{}, fields ...string) (int64, bool) {
+	v, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	if err != nil || !found {
+		return 0, false
+	}
+	switch v := v.(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// findReferences returns the references to other objects in obj, i.e. the fields named
+// "<something>Ref" or "<something>Refs" which have a name. References to external resources
+// are skipped.
+func findReferences(path string, obj map[string]interface{}, defaultNamespace string) []Reference {
+	var refs []Reference
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fieldPath := path + "." + k
+		switch v := obj[k].(type) {
+		case map[string]interface{}:
+			if strings.HasSuffix(k, "Ref") {
+				if ref, ok := toReference(fieldPath, v, defaultNamespace); ok {
+					refs = append(refs, ref)
+				}
+				continue
+			}
+			refs = append(refs, findReferences(fieldPath, v, defaultNamespace)...)
+		case []interface{}:
+			for i, item := range v {
+				m, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				itemPath := fmt.Sprintf("%s[%d]", fieldPath, i)
+				if strings.HasSuffix(k, "Refs") {
+					if ref, ok := toReference(itemPath, m, defaultNamespace); ok {
+						refs = append(refs, ref)
+					}
+					continue
+				}
+				refs = append(refs, findReferences(itemPath, m, defaultNamespace)...)
+			}
+		}
+	}
+	return refs
+}
+
+func toReference(fieldPath string, m map[string]interface{}, defaultNamespace string) (Reference, bool) {
+	name, _, _ := unstructured.NestedString(m, "name")
+	if name == "" {
+		// A reference to an external resource, or not a reference at all.
+		return Reference{}, false
+	}
+	ref := Reference{Field: fieldPath, Name: name, Namespace: defaultNamespace}
+	if namespace, _, _ := unstructured.NestedString(m, "namespace"); namespace != "" {
+		ref.Namespace = namespace
+	}
+	ref.Kind, _, _ = unstructured.NestedString(m, "kind")
+	return ref, true
+}
+
+// GenerationLag is an object whose latest spec has not been reconciled yet.
+type GenerationLag struct {
+	ID                 ObjectID
+	Generation         int64
+	ObservedGeneration int64
+}
+
+// Blocker is an object which is not ready for a reason of its own, or which is referenced but
+// missing, and which blocks other objects from becoming ready.
+type Blocker struct {
+	ID ObjectID
+	// Missing is true if the object is referenced but is not among the analyzed objects.
+	Missing bool
+	// Ready is the Ready condition of the blocker, nil if it is missing or has none.
+	Ready *Condition
+	// Blocked are the objects which are transitively blocked by the blocker.
+	Blocked []ObjectID
+}
+
+// Result is the outcome of the analysis of a set of objects.
+type Result struct {
+	Total int
+	Ready int
+	// ByReason groups the objects which are not ready by the reason of their Ready condition.
+	ByReason map[string][]ObjectID
+	// GenerationLags are the objects whose status.observedGeneration lags their metadata.generation.
+	GenerationLags []GenerationLag
+	// Blockers are the root causes of the objects blocked by their dependencies, the blockers
+	// which block the most objects first.
+	Blockers []Blocker
+}
+
+// Analyze analyzes the given objects.
+func Analyze(objects []*Object) *Result {
+	result := &Result{
+		Total:    len(objects),
+		ByReason: make(map[string][]ObjectID),
+	}
+	for _, o := range objects {
+		if o.IsReady() {
+			result.Ready++
+		} else {
+			result.ByReason[o.reason()] = append(result.ByReason[o.reason()], o.ID)
+		}
+		if o.HasObservedGeneration && o.ObservedGeneration < o.Generation {
+			result.GenerationLags = append(result.GenerationLags, GenerationLag{
+				ID:                 o.ID,
+				Generation:         o.Generation,
+				ObservedGeneration: o.ObservedGeneration,
+			})
+		}
+	}
+	for _, ids := range result.ByReason {
+		sortIDs(ids)
+	}
+	sort.Slice(result.GenerationLags, func(i, j int) bool {
+		return lessID(result.GenerationLags[i].ID, result.GenerationLags[j].ID)
+	})
+	result.Blockers = newGraph(objects).blockers()
+	return result
+}
+
+// graph is the graph of the references between the analyzed objects.
+type graph struct {
+	objects []*Object
+	// byName indexes the objects by namespace and name, since references don't always
+	// specify the kind of the referenced object.
+	byName map[string][]*Object
+	// missing are the referenced objects which are not among the analyzed objects.
+	missing map[ObjectID]*Object
+
+	// roots memoizes the root causes of each object.
+	roots map[ObjectID][]ObjectID
+	// visiting are the objects on the current path of the walk, to break reference cycles.
+	visiting map[ObjectID]bool
+}
+
+func newGraph(objects []*Object) *graph {
+	g := &graph{
+		objects:  objects,
+		byName:   make(map[string][]*Object),
+		missing:  make(map[ObjectID]*Object),
+		roots:    make(map[ObjectID][]ObjectID),
+		visiting: make(map[ObjectID]bool),
+	}
+	for _, o := range objects {
+		key := o.ID.Namespace + "/" + o.ID.Name
+		g.byName[key] = append(g.byName[key], o)
+	}
+	return g
+}
+
+// resolve returns the object a reference points to. References without a kind are resolved
+// to the object with the referenced name whose kind matches the name of the field, e.g. a
+// spec.networkRef to a ComputeNetwork. A missing object is returned if none matches.
+func (g *graph) resolve(ref Reference) *Object {
+	candidates := g.byName[ref.Namespace+"/"+ref.Name]
+	if ref.Kind != "" {
+		for _, c := range candidates {
+			if c.ID.Kind == ref.Kind {
+				return c
+			}
+		}
+		return g.missingObject(ObjectID{Kind: ref.Kind, Namespace: ref.Namespace, Name: ref.Name})
+	}
+	field := ref.Field[strings.LastIndex(ref.Field, ".")+1:]
+	if i := strings.Index(field, "["); i >= 0 {
+		field = field[:i]
+	}
+	field = strings.ToLower(strings.TrimSuffix(strings.TrimSuffix(field, "s"), "Ref"))
+	for _, c := range candidates {
+		if strings.HasSuffix(strings.ToLower(c.ID.Kind), field) {
+			return c
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return g.missingObject(ObjectID{Kind: "<" + field + ">", Namespace: ref.Namespace, Name: ref.Name})
+}
+
+func (g *graph) missingObject(id ObjectID) *Object {
+	if o, found := g.missing[id]; found {
+		return o
+	}
+	o := &Object{ID: id}
+	g.missing[id] = o
+	return o
+}
+
+func (g *graph) isMissing(o *Object) bool {
+	_, found := g.missing[o.ID]
+	return found
+}
+
+// rootCauses returns the objects at the end of the chains of unready dependencies of o.
+func (g *graph) rootCauses(o *Object) []ObjectID {
+	if roots, found := g.roots[o.ID]; found {
+		return roots
+	}
+	if g.isMissing(o) || !o.isBlockedByDependency() {
+		return []ObjectID{o.ID}
+	}
+	if g.visiting[o.ID] {
+		// A reference cycle; the objects of the cycle are reported as their own root causes.
+		return nil
+	}
+	g.visiting[o.ID] = true
+	defer delete(g.visiting, o.ID)
+
+	seen := make(map[ObjectID]bool)
+	var roots []ObjectID
+	for _, ref := range o.References {
+		dep := g.resolve(ref)
+		if dep.IsReady() {
+			continue
+		}
+		for _, root := range g.rootCauses(dep) {
+			if !seen[root] {
+				seen[root] = true
+				roots = append(roots, root)
+			}
+		}
+	}
+	if len(roots) == 0 {
+		// None of the references could be blamed, so the object itself is the root cause.
+		roots = []ObjectID{o.ID}
+	}
+	sortIDs(roots)
+	g.roots[o.ID] = roots
+	return roots
+}
+
+func (g *graph) blockers() []Blocker {
+	blocked := make(map[ObjectID][]ObjectID)
+	for _, o := range g.objects {
+		if o.IsReady() {
+			continue
+		}
+		for _, root := range g.rootCauses(o) {
+			if root != o.ID {
+				blocked[root] = append(blocked[root], o.ID)
+			}
+		}
+	}
+	byID := make(map[ObjectID]*Object)
+	for _, o := range g.objects {
+		byID[o.ID] = o
+	}
+	var blockers []Blocker
+	for root, ids := range blocked {
+		sortIDs(ids)
+		blocker := Blocker{ID: root, Blocked: ids}
+		if o, found := byID[root]; found {
+			blocker.Ready = o.Ready
+		} else {
+			blocker.Missing = true
+		}
+		blockers = append(blockers, blocker)
+	}
+	sort.Slice(blockers, func(i, j int) bool {
+		if len(blockers[i].Blocked) != len(blockers[j].Blocked) {
+			return len(blockers[i].Blocked) > len(blockers[j].Blocked)
+		}
+		return lessID(blockers[i].ID, blockers[j].ID)
+	})
+	return blockers
+}
+
+func lessID(a, b ObjectID) bool {
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	if a.Kind != b.Kind {
+		return a.Kind < b.Kind
+	}
+	return a.Name < b.Name
+}
+
+func sortIDs(ids []ObjectID) {
+	sort.Slice(ids, func(i, j int) bool { return lessID(ids[i], ids[j]) })
+}
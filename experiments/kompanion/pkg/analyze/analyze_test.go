@@ -0,0 +1,409 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyze
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func parseObject(t *testing.T, s string) *unstructured.Unstructured {
+	t.Helper()
+	u := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(s), &u.Object); err != nil {
+		t.Fatalf("error parsing object: %v", err)
+	}
+	return u
+}
+
+// newTestObject returns an object of the given kind and name in namespace "ns", with the
+// given Ready reason. An empty reason means the object is ready.
+func newTestObject(kind, name, reason string, refs ...Reference) *Object {
+	o := &Object{
+		ID:         ObjectID{Kind: kind, Namespace: "ns", Name: name},
+		Ready:      &Condition{Status: "True", Reason: "UpToDate"},
+		References: refs,
+	}
+	if reason != "" {
+		o.Ready = &Condition{Status: "False", Reason: reason, Message: reason + " message"}
+	}
+	return o
+}
+
+func ref(field, kind, name string) Reference {
+	return Reference{Field: field, Kind: kind, Namespace: "ns", Name: name}
+}
+
+func id(kind, name string) ObjectID {
+	return ObjectID{Kind: kind, Namespace: "ns", Name: name}
+}
+
+func TestNewObject(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  string
+		want *Object
+	}{
+		{
+			name: "ready object",
+			obj: `
+apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeNetwork
+metadata:
+  namespace: ns
+  name: network
+  generation: 2
+status:
+  observedGeneration: 2
+  conditions:
+  - type: Ready
+    status: "True"
+    reason: UpToDate
+    message: The resource is up to date
+`,
+			want: &Object{
+				ID:                    id("ComputeNetwork", "network"),
+				Generation:            2,
+				ObservedGeneration:    2,
+				HasObservedGeneration: true,
+				Ready:                 &Condition{Status: "True", Reason: "UpToDate", Message: "The resource is up to date"},
+			},
+		},
+		{
+			name: "object which has not been reconciled",
+			obj: `
+apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeNetwork
+metadata:
+  namespace: ns
+  name: network
+  generation: 1
+`,
+			want: &Object{
+				ID:         id("ComputeNetwork", "network"),
+				Generation: 1,
+			},
+		},
+		{
+			name: "references",
+			obj: `
+apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeSubnetwork
+metadata:
+  namespace: ns
+  name: subnet
+spec:
+  networkRef:
+    name: network
+  projectRef:
+    external: projects/my-project
+  secondaryRanges:
+  - serviceAccountRef:
+      kind: IAMServiceAccount
+      namespace: other-ns
+      name: sa
+  routerRefs:
+  - name: router-1
+  - external: projects/my-project/regions/us-central1/routers/router-2
+status:
+  conditions:
+  - type: Ready
+    status: "False"
+    reason: DependencyNotReady
+`,
+			want: &Object{
+				ID:    id("ComputeSubnetwork", "subnet"),
+				Ready: &Condition{Status: "False", Reason: "DependencyNotReady"},
+				References: []Reference{
+					{Field: "spec.networkRef", Namespace: "ns", Name: "network"},
+					{Field: "spec.routerRefs[0]", Namespace: "ns", Name: "router-1"},
+					{Field: "spec.secondaryRanges[0].serviceAccountRef", Kind: "IAMServiceAccount", Namespace: "other-ns", Name: "sa"},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewObject(parseObject(t, tc.obj))
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("NewObject: got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name    string
+		objects []*Object
+		want    *Result
+	}{
+		{
+			name: "all ready",
+			objects: []*Object{
+				newTestObject("ComputeNetwork", "network", ""),
+				newTestObject("ComputeSubnetwork", "subnet", "", ref("spec.networkRef", "", "network")),
+			},
+			want: &Result{Total: 2, Ready: 2, ByReason: map[string][]ObjectID{}},
+		},
+		{
+			name: "by reason",
+			objects: []*Object{
+				newTestObject("ComputeNetwork", "b", "UpdateFailed"),
+				newTestObject("ComputeNetwork", "a", "UpdateFailed"),
+				{ID: id("ComputeNetwork", "c")},
+				{ID: id("ComputeNetwork", "d"), Ready: &Condition{Status: "False"}},
+			},
+			want: &Result{
+				Total: 4,
+				ByReason: map[string][]ObjectID{
+					"UpdateFailed":         {id("ComputeNetwork", "a"), id("ComputeNetwork", "b")},
+					reasonNoReadyCondition: {id("ComputeNetwork", "c")},
+					"<no reason>":          {id("ComputeNetwork", "d")},
+				},
+			},
+		},
+		{
+			name: "generation lag",
+			objects: []*Object{
+				{ID: id("ComputeNetwork", "lagging"), Generation: 3, ObservedGeneration: 2, HasObservedGeneration: true,
+					Ready: &Condition{Status: "True"}},
+				{ID: id("ComputeNetwork", "new"), Generation: 1, Ready: &Condition{Status: "True"}},
+				{ID: id("ComputeNetwork", "current"), Generation: 1, ObservedGeneration: 1, HasObservedGeneration: true,
+					Ready: &Condition{Status: "True"}},
+			},
+			want: &Result{
+				Total:          3,
+				Ready:          3,
+				ByReason:       map[string][]ObjectID{},
+				GenerationLags: []GenerationLag{{ID: id("ComputeNetwork", "lagging"), Generation: 3, ObservedGeneration: 2}},
+			},
+		},
+		{
+			name: "chain of dependencies",
+			objects: []*Object{
+				newTestObject("ComputeNetwork", "network", "UpdateFailed"),
+				newTestObject("ComputeSubnetwork", "subnet", reasonDependencyNotReady, ref("spec.networkRef", "", "network")),
+				newTestObject("ContainerCluster", "cluster", reasonDependencyNotReady,
+					ref("spec.networkRef", "", "network"), ref("spec.subnetworkRef", "", "subnet")),
+			},
+			want: &Result{
+				Total: 3,
+				ByReason: map[string][]ObjectID{
+					"UpdateFailed":           {id("ComputeNetwork", "network")},
+					reasonDependencyNotReady: {id("ComputeSubnetwork", "subnet"), id("ContainerCluster", "cluster")},
+				},
+				Blockers: []Blocker{{
+					ID:      id("ComputeNetwork", "network"),
+					Ready:   &Condition{Status: "False", Reason: "UpdateFailed", Message: "UpdateFailed message"},
+					Blocked: []ObjectID{id("ComputeSubnetwork", "subnet"), id("ContainerCluster", "cluster")},
+				}},
+			},
+		},
+		{
+			name: "missing dependency",
+			objects: []*Object{
+				newTestObject("ComputeSubnetwork", "subnet", reasonDependencyNotFound, ref("spec.networkRef", "", "network")),
+				newTestObject("ComputeFirewall", "firewall", reasonDependencyNotFound, ref("spec.networkRef", "ComputeNetwork", "network")),
+			},
+			want: &Result{
+				Total: 2,
+				ByReason: map[string][]ObjectID{
+					reasonDependencyNotFound: {id("ComputeFirewall", "firewall"), id("ComputeSubnetwork", "subnet")},
+				},
+				Blockers: []Blocker{
+					{ID: id("<network>", "network"), Missing: true, Blocked: []ObjectID{id("ComputeSubnetwork", "subnet")}},
+					{ID: id("ComputeNetwork", "network"), Missing: true, Blocked: []ObjectID{id("ComputeFirewall", "firewall")}},
+				},
+			},
+		},
+		{
+			name: "reference resolved by field name",
+			objects: []*Object{
+				newTestObject("ComputeNetwork", "shared", "UpdateFailed"),
+				newTestObject("IAMServiceAccount", "shared", ""),
+				newTestObject("ComputeSubnetwork", "subnet", reasonDependencyNotReady,
+					ref("spec.serviceAccountRef", "", "shared"), ref("spec.networkRef", "", "shared")),
+			},
+			want: &Result{
+				Total: 3,
+				Ready: 1,
+				ByReason: map[string][]ObjectID{
+					"UpdateFailed":           {id("ComputeNetwork", "shared")},
+					reasonDependencyNotReady: {id("ComputeSubnetwork", "subnet")},
+				},
+				Blockers: []Blocker{{
+					ID:      id("ComputeNetwork", "shared"),
+					Ready:   &Condition{Status: "False", Reason: "UpdateFailed", Message: "UpdateFailed message"},
+					Blocked: []ObjectID{id("ComputeSubnetwork", "subnet")},
+				}},
+			},
+		},
+		{
+			name: "reference cycle",
+			objects: []*Object{
+				newTestObject("ComputeNetwork", "a", reasonDependencyNotReady, ref("spec.peerRef", "ComputeNetwork", "b")),
+				newTestObject("ComputeNetwork", "b", reasonDependencyNotReady, ref("spec.peerRef", "ComputeNetwork", "a")),
+			},
+			want: &Result{
+				Total: 2,
+				ByReason: map[string][]ObjectID{
+					reasonDependencyNotReady: {id("ComputeNetwork", "a"), id("ComputeNetwork", "b")},
+				},
+				// The walk enters the cycle at a, so b is reported as its own root cause.
+				Blockers: []Blocker{
+					{
+						ID:      id("ComputeNetwork", "b"),
+						Ready:   &Condition{Status: "False", Reason: reasonDependencyNotReady, Message: reasonDependencyNotReady + " message"},
+						Blocked: []ObjectID{id("ComputeNetwork", "a")},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Analyze(tc.objects)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Analyze:\ngot  %+v\nwant %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	result := Analyze([]*Object{
+		newTestObject("ComputeNetwork", "network", "UpdateFailed"),
+		newTestObject("ComputeSubnetwork", "subnet", reasonDependencyNotReady, ref("spec.networkRef", "", "network")),
+		newTestObject("ComputeFirewall", "firewall", reasonDependencyNotFound, ref("spec.networkRef", "ComputeNetwork", "missing")),
+		{ID: id("ComputeRouter", "router"), Generation: 2, ObservedGeneration: 1, HasObservedGeneration: true,
+			Ready: &Condition{Status: "True"}},
+	})
+	var buf bytes.Buffer
+	result.Print(&buf)
+	want := `Analyzed 4 objects: 1 ready, 3 not ready.
+
+Objects which are not ready, by reason:
+- DependencyNotFound (1)
+    ComputeFirewall ns/firewall
+- DependencyNotReady (1)
+    ComputeSubnetwork ns/subnet
+- UpdateFailed (1)
+    ComputeNetwork ns/network
+
+Root-cause blockers:
+- ComputeNetwork ns/missing: not found, blocks 1 objects
+    blocks ComputeFirewall ns/firewall
+- ComputeNetwork ns/network: UpdateFailed, blocks 1 objects
+    message: UpdateFailed message
+    blocks ComputeSubnetwork ns/subnet
+
+Objects whose latest spec has not been reconciled (observedGeneration < generation):
+- ComputeRouter ns/router: generation 2, observedGeneration 1
+`
+	if got := buf.String(); got != want {
+		t.Errorf("Print:\ngot\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPrintTruncatesLongLists(t *testing.T) {
+	var ids []ObjectID
+	for i := 0; i < maxListedObjects+5; i++ {
+		ids = append(ids, id("ComputeNetwork", strings.Repeat("n", i+1)))
+	}
+	var buf bytes.Buffer
+	printIDs(&buf, "", ids)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != maxListedObjects+1 {
+		t.Fatalf("printIDs: got %d lines, want %d", len(lines), maxListedObjects+1)
+	}
+	if got, want := lines[maxListedObjects], "... and 5 more"; got != want {
+		t.Errorf("printIDs: got last line %q, want %q", got, want)
+	}
+}
+
+const testNetworkYAML = `apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeNetwork
+metadata:
+  namespace: ns
+  name: network
+`
+
+func TestLoadReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.tar.gz")
+	var buf bytes.Buffer
+	gzWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzWriter)
+	files := map[string]string{
+		"ns/computenetwork-network.yaml": testNetworkYAML,
+		"ns/notes.txt":                   "not an object",
+		"ns/config.yaml":                 "foo: bar\n",
+	}
+	for name, content := range files {
+		if err := tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := LoadReport(path)
+	if err != nil {
+		t.Fatalf("LoadReport: unexpected error: %v", err)
+	}
+	if len(objects) != 1 || objects[0].GetName() != "network" {
+		t.Errorf("LoadReport: got %v, want the ComputeNetwork only", objects)
+	}
+}
+
+func TestLoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "ns"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ns", "computenetwork-network.yaml"), []byte(testNetworkYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ns", "notes.txt"), []byte("not an object"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := LoadDirectory(dir)
+	if err != nil {
+		t.Fatalf("LoadDirectory: unexpected error: %v", err)
+	}
+	if len(objects) != 1 || objects[0].GetName() != "network" {
+		t.Errorf("LoadDirectory: got %v, want the ComputeNetwork only", objects)
+	}
+}
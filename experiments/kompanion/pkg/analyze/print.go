@@ -0,0 +1,83 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// maxListedObjects is the maximum number of objects listed for each reason or blocker, to keep
+// the output readable for large clusters.
+const maxListedObjects = 20
+
+// Print writes a human readable summary of the result to w.
+func (r *Result) Print(w io.Writer) {
+	fmt.Fprintf(w, "Analyzed %d objects: %d ready, %d not ready.\n", r.Total, r.Ready, r.Total-r.Ready)
+
+	if len(r.ByReason) != 0 {
+		fmt.Fprintf(w, "\nObjects which are not ready, by reason:\n")
+		reasons := make([]string, 0, len(r.ByReason))
+		for reason := range r.ByReason {
+			reasons = append(reasons, reason)
+		}
+		sort.Slice(reasons, func(i, j int) bool {
+			if len(r.ByReason[reasons[i]]) != len(r.ByReason[reasons[j]]) {
+				return len(r.ByReason[reasons[i]]) > len(r.ByReason[reasons[j]])
+			}
+			return reasons[i] < reasons[j]
+		})
+		for _, reason := range reasons {
+			fmt.Fprintf(w, "- %s (%d)\n", reason, len(r.ByReason[reason]))
+			printIDs(w, "    ", r.ByReason[reason])
+		}
+	}
+
+	if len(r.Blockers) != 0 {
+		fmt.Fprintf(w, "\nRoot-cause blockers:\n")
+		for _, b := range r.Blockers {
+			switch {
+			case b.Missing:
+				fmt.Fprintf(w, "- %s: not found, blocks %d objects\n", b.ID, len(b.Blocked))
+			case b.Ready == nil:
+				fmt.Fprintf(w, "- %s: %s, blocks %d objects\n", b.ID, reasonNoReadyCondition, len(b.Blocked))
+			default:
+				fmt.Fprintf(w, "- %s: %s, blocks %d objects\n", b.ID, b.Ready.Reason, len(b.Blocked))
+				if b.Ready.Message != "" {
+					fmt.Fprintf(w, "    message: %s\n", b.Ready.Message)
+				}
+			}
+			printIDs(w, "    blocks ", b.Blocked)
+		}
+	}
+
+	if len(r.GenerationLags) != 0 {
+		fmt.Fprintf(w, "\nObjects whose latest spec has not been reconciled (observedGeneration < generation):\n")
+		for _, lag := range r.GenerationLags {
+			fmt.Fprintf(w, "- %s: generation %d, observedGeneration %d\n", lag.ID, lag.Generation, lag.ObservedGeneration)
+		}
+	}
+}
+
+func printIDs(w io.Writer, prefix string, ids []ObjectID) {
+	for i, id := range ids {
+		if i == maxListedObjects {
+			fmt.Fprintf(w, "%s... and %d more\n", prefix, len(ids)-maxListedObjects)
+			return
+		}
+		fmt.Fprintf(w, "%s%s\n", prefix, id)
+	}
+}
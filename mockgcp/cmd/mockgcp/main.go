@@ -0,0 +1,206 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// mockgcp serves the mock GCP services over HTTP(S), so that tools other than go tests
+// (gcloud, terraform, the KCC manager) can be pointed at them.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/mockgcp"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/mockgcp/pkg/storage"
+)
+
+func main() {
+	if err := run(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+type options struct {
+	// Listen is the address we listen on
+	Listen string
+
+	// TLS enables serving HTTPS, using a certificate signed by a CA generated at startup.
+	TLS bool
+	// CACertOut is the path where the generated CA certificate is written (in PEM format), so that clients can trust it.
+	CACertOut string
+	// TLSHosts are the additional hostnames (or IPs) that the serving certificate is valid for.
+	TLSHosts string
+
+	// StorageDir is the directory for file-backed storage; if empty we store objects in memory.
+	StorageDir string
+
+	// Kubeconfig is the kubeconfig for the cluster used by services that store data in kubernetes (e.g. secret payloads).
+	// If empty, we use an in-memory fake.
+	Kubeconfig string
+}
+
+func run(ctx context.Context) error {
+	var opt options
+	opt.Listen = "localhost:8080"
+
+	flag.StringVar(&opt.Listen, "listen", opt.Listen, "address to listen on")
+	flag.BoolVar(&opt.TLS, "tls", opt.TLS, "serve HTTPS using a certificate signed by a generated CA")
+	flag.StringVar(&opt.CACertOut, "ca-cert-out", opt.CACertOut, "path to write the generated CA certificate to (PEM format)")
+	flag.StringVar(&opt.TLSHosts, "tls-hosts", opt.TLSHosts, "comma-separated list of additional hostnames or IPs for the serving certificate")
+	flag.StringVar(&opt.StorageDir, "storage-dir", opt.StorageDir, "store mock objects as files in this directory, instead of in memory")
+	flag.StringVar(&opt.Kubeconfig, "kubeconfig", opt.Kubeconfig, "kubeconfig for services that store data in kubernetes; uses an in-memory fake if not set")
+	klog.InitFlags(nil)
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var mockStorage storage.Storage = storage.NewInMemoryStorage()
+	if opt.StorageDir != "" {
+		fileStorage, err := storage.NewFileStorage(opt.StorageDir)
+		if err != nil {
+			return err
+		}
+		mockStorage = fileStorage
+	}
+
+	kubeClient, err := buildKubeClient(opt.Kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	mockGCP, stop, err := mockgcp.New(ctx, kubeClient, mockStorage)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	server := &http.Server{
+		Addr:    opt.Listen,
+		Handler: &roundTripperHandler{roundTripper: mockGCP},
+	}
+
+	if opt.TLS {
+		ca, err := newCertificateAuthority()
+		if err != nil {
+			return err
+		}
+		if opt.CACertOut != "" {
+			if err := os.WriteFile(opt.CACertOut, ca.certPEM, 0644); err != nil {
+				return fmt.Errorf("writing CA certificate to %q: %w", opt.CACertOut, err)
+			}
+			klog.Infof("wrote CA certificate to %s", opt.CACertOut)
+		}
+
+		hosts := []string{"googleapis.com", "*.googleapis.com", "localhost", "127.0.0.1"}
+		for _, host := range strings.Split(opt.TLSHosts, ",") {
+			if host := strings.TrimSpace(host); host != "" {
+				hosts = append(hosts, host)
+			}
+		}
+		serverCert, err := ca.issueServingCertificate(hosts)
+		if err != nil {
+			return err
+		}
+		server.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{*serverCert},
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		if err := server.Shutdown(context.Background()); err != nil {
+			klog.Warningf("error shutting down server: %v", err)
+		}
+	}()
+
+	klog.Infof("serving mockgcp on %s (tls=%v)", opt.Listen, opt.TLS)
+	if opt.TLS {
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("error serving: %w", err)
+	}
+	return nil
+}
+
+func buildKubeClient(kubeconfig string) (client.Client, error) {
+	if kubeconfig == "" {
+		return fake.NewClientBuilder().Build(), nil
+	}
+	restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig %q: %w", kubeconfig, err)
+	}
+	kubeClient, err := client.New(restConfig, client.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("building kubernetes client: %w", err)
+	}
+	return kubeClient, nil
+}
+
+// roundTripperHandler adapts the mock http.RoundTripper to an http.Handler.
+// Requests are dispatched to the mock services based on the Host header.
+type roundTripperHandler struct {
+	roundTripper http.RoundTripper
+}
+
+func (h *roundTripperHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := r.Clone(r.Context())
+	req.RequestURI = ""
+
+	// Strip the port, so that the Host header matches the hostnames the services expect
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	req.Host = host
+	req.URL.Host = host
+	req.URL.Scheme = "https"
+
+	response, err := h.roundTripper.RoundTrip(req)
+	if err != nil {
+		klog.Warningf("error serving %s %s: %v", r.Method, r.URL, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer response.Body.Close()
+
+	for k, values := range response.Header {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(response.StatusCode)
+	if _, err := io.Copy(w, response.Body); err != nil {
+		klog.Warningf("error writing response for %s %s: %v", r.Method, r.URL, err)
+	}
+}
@@ -65,6 +65,10 @@ func (m *ServeMux) customErrorHandler(ctx context.Context, mux *runtime.ServeMux
 		wrapped.Error.Status = "PERMISSION_DENIED"
 	case codes.AlreadyExists:
 		wrapped.Error.Status = "ALREADY_EXISTS"
+	case codes.ResourceExhausted:
+		wrapped.Error.Status = "RESOURCE_EXHAUSTED"
+	case codes.Unavailable:
+		wrapped.Error.Status = "UNAVAILABLE"
 	case codes.NotFound:
 		wrapped.Error.Status = "NOT_FOUND"
 		wrapped.Error.Errors = append(wrapped.Error.Errors, ErrorResponseDetails{
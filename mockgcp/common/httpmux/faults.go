@@ -0,0 +1,347 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpmux
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"k8s.io/klog/v2"
+)
+
+// Fault describes a failure (or slowdown) to inject into matching requests.
+// Faults are normally declared in test scripts, to simulate GCP misbehavior.
+type Fault struct {
+	// Service matches the hostname the request was sent to, e.g. "redis.googleapis.com".
+	// If empty, requests to any service match.
+	Service string `json:"service,omitempty"`
+
+	// Method matches the GRPC method name, e.g. "CreateCluster".
+	// If Method is an HTTP verb (e.g. "POST"), the fault is instead injected at the HTTP layer,
+	// before the request is translated to GRPC; this is useful for handlers that are not backed by GRPC.
+	// If empty, requests to any GRPC method match.
+	Method string `json:"method,omitempty"`
+
+	// Resource is a regular expression matched against the resource name of the request
+	// (the name or parent field for GRPC requests, the URL path for HTTP requests).
+	// If empty, requests for any resource match.
+	Resource string `json:"resource,omitempty"`
+
+	// Code is the GRPC status code to fail the request with, e.g. "RESOURCE_EXHAUSTED" or "UNAVAILABLE".
+	// If empty, the request is not failed (but Latency etc still apply).
+	Code string `json:"code,omitempty"`
+
+	// Message is the error message returned with Code.
+	Message string `json:"message,omitempty"`
+
+	// Latency delays the request, e.g. "5s".
+	Latency string `json:"latency,omitempty"`
+
+	// OperationLatency delays the completion of any long-running operation started by the request.
+	OperationLatency string `json:"operationLatency,omitempty"`
+
+	// OperationErrorCode causes any long-running operation started by the request to end in this error.
+	// The operation itself still runs, so this simulates a partial failure.
+	OperationErrorCode string `json:"operationErrorCode,omitempty"`
+
+	// Times is the number of matching requests the fault applies to; 0 means all matching requests.
+	Times int `json:"times,omitempty"`
+}
+
+// activeFault is a parsed Fault, along with its remaining count.
+type activeFault struct {
+	fault Fault
+
+	resourceRegex      *regexp.Regexp
+	code               codes.Code
+	latency            time.Duration
+	operationLatency   time.Duration
+	operationErrorCode codes.Code
+
+	// remaining is the number of times the fault still applies; -1 means unlimited.
+	remaining int
+}
+
+// FaultInjector holds the active faults, and injects them into HTTP and GRPC requests.
+type FaultInjector struct {
+	mutex  sync.Mutex
+	faults []*activeFault
+}
+
+// NewFaultInjector constructs a FaultInjector with no active faults.
+func NewFaultInjector() *FaultInjector {
+	return &FaultInjector{}
+}
+
+// AddFault activates a fault for subsequent requests.
+func (f *FaultInjector) AddFault(fault Fault) error {
+	active := &activeFault{fault: fault, remaining: -1}
+	if fault.Times > 0 {
+		active.remaining = fault.Times
+	}
+
+	if fault.Resource != "" {
+		r, err := regexp.Compile(fault.Resource)
+		if err != nil {
+			return fmt.Errorf("parsing resource %q: %w", fault.Resource, err)
+		}
+		active.resourceRegex = r
+	}
+
+	var err error
+	if active.code, err = parseCode(fault.Code); err != nil {
+		return err
+	}
+	if active.operationErrorCode, err = parseCode(fault.OperationErrorCode); err != nil {
+		return err
+	}
+	if active.latency, err = parseDuration(fault.Latency); err != nil {
+		return err
+	}
+	if active.operationLatency, err = parseDuration(fault.OperationLatency); err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.faults = append(f.faults, active)
+	return nil
+}
+
+// ClearFaults removes all active faults.
+func (f *FaultInjector) ClearFaults() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.faults = nil
+}
+
+// match returns the first active fault matching the request, consuming one of its remaining uses.
+func (f *FaultInjector) match(httpLayer bool, service string, method string, resources []string) *activeFault {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, fault := range f.faults {
+		if fault.remaining == 0 {
+			continue
+		}
+		if isHTTPMethod(fault.fault.Method) != httpLayer {
+			continue
+		}
+		if fault.fault.Service != "" && fault.fault.Service != service {
+			continue
+		}
+		if fault.fault.Method != "" && fault.fault.Method != method {
+			continue
+		}
+		if fault.resourceRegex != nil {
+			matched := false
+			for _, resource := range resources {
+				if resource != "" && fault.resourceRegex.MatchString(resource) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+
+		if fault.remaining > 0 {
+			fault.remaining--
+		}
+		return fault
+	}
+	return nil
+}
+
+// HTTPMiddleware injects faults whose Method is an HTTP verb, before next handles the request.
+func (f *FaultInjector) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fault := f.match(true, r.Host, r.Method, []string{r.URL.Path})
+		if fault == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		klog.Infof("injecting fault %+v into %s %s", fault.fault, r.Method, r.URL)
+		if fault.latency != 0 {
+			time.Sleep(fault.latency)
+		}
+		if fault.code == codes.OK {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		httpStatusCode := runtime.HTTPStatusFromCode(fault.code)
+		wrapped := &wrappedStatus{
+			Error: &ErrorResponse{
+				Code:    httpStatusCode,
+				Message: fault.message(),
+				Status:  codeName(fault.code),
+			},
+		}
+		buf, err := json.Marshal(wrapped)
+		if err != nil {
+			klog.Warningf("Failed to marshal error message: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(httpStatusCode)
+		if _, err := w.Write(buf); err != nil {
+			klog.Warningf("Failed to write response: %v", err)
+		}
+	})
+}
+
+// UnaryServerInterceptor injects faults whose Method is not an HTTP verb into GRPC requests.
+func (f *FaultInjector) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var service, requestPath string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get("x-forwarded-host"); len(v) != 0 {
+				service = v[0]
+			}
+			if v := md.Get("path"); len(v) != 0 {
+				requestPath = v[0]
+			}
+		}
+		method := path.Base(info.FullMethod)
+
+		resources := []string{requestPath}
+		if msg, ok := req.(proto.Message); ok {
+			resources = append(resources, stringField(msg, "name"), stringField(msg, "parent"))
+		}
+
+		fault := f.match(false, service, method, resources)
+		if fault == nil {
+			return handler(ctx, req)
+		}
+
+		klog.Infof("injecting fault %+v into %s", fault.fault, info.FullMethod)
+		if fault.latency != 0 {
+			time.Sleep(fault.latency)
+		}
+		if fault.code != codes.OK {
+			return nil, status.Error(fault.code, fault.message())
+		}
+		if fault.operationLatency != 0 || fault.operationErrorCode != codes.OK {
+			ctx = context.WithValue(ctx, operationFaultKey, &OperationFault{
+				Latency: fault.operationLatency,
+				Code:    fault.operationErrorCode,
+				Message: fault.message(),
+			})
+		}
+		return handler(ctx, req)
+	}
+}
+
+// OperationFault is the fault to apply to a long-running operation.
+type OperationFault struct {
+	// Latency delays the completion of the operation.
+	Latency time.Duration
+	// Code, if not OK, is the error the operation ends with.
+	Code codes.Code
+	// Message is the error message for Code.
+	Message string
+}
+
+// operationFaultKeyType is the (unique) type for storing the OperationFault in the context
+type operationFaultKeyType string
+
+// operationFaultKey is the (unique) value for storing the OperationFault in the context
+var operationFaultKey operationFaultKeyType = "operationFault"
+
+// OperationFaultFromContext returns the fault to apply to long-running operations started by the current request, if any.
+func OperationFaultFromContext(ctx context.Context) *OperationFault {
+	v := ctx.Value(operationFaultKey)
+	if v == nil {
+		return nil
+	}
+	return v.(*OperationFault)
+}
+
+func (f *activeFault) message() string {
+	if f.fault.Message != "" {
+		return f.fault.Message
+	}
+	return "mockgcp injected fault"
+}
+
+func stringField(msg proto.Message, name protoreflect.Name) string {
+	m := msg.ProtoReflect()
+	field := m.Descriptor().Fields().ByName(name)
+	if field == nil || field.Kind() != protoreflect.StringKind || field.IsList() {
+		return ""
+	}
+	return m.Get(field).String()
+}
+
+func isHTTPMethod(s string) bool {
+	switch s {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func parseCode(s string) (codes.Code, error) {
+	if s == "" {
+		return codes.OK, nil
+	}
+	var code codes.Code
+	if err := code.UnmarshalJSON([]byte(strconv.Quote(s))); err != nil {
+		return codes.OK, fmt.Errorf("parsing code %q: %w", s, err)
+	}
+	return code, nil
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("parsing duration %q: %w", s, err)
+	}
+	return d, nil
+}
+
+// codeName returns the canonical (upper-case) name for the code, e.g. RESOURCE_EXHAUSTED
+func codeName(code codes.Code) string {
+	var sb strings.Builder
+	s := code.String()
+	for i, r := range s {
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(rune(s[i-1])) {
+			sb.WriteRune('_')
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+	return sb.String()
+}
@@ -0,0 +1,297 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpmux
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestFaultMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		fault     Fault
+		httpLayer bool
+		service   string
+		method    string
+		resources []string
+		// wantMatches is whether each of the requests, sent in order, matches.
+		wantMatches []bool
+	}{
+		{
+			name:        "empty fault matches any grpc request",
+			fault:       Fault{},
+			service:     "redis.googleapis.com",
+			method:      "CreateCluster",
+			wantMatches: []bool{true, true},
+		},
+		{
+			name:        "grpc fault does not match http requests",
+			fault:       Fault{Method: "CreateCluster"},
+			httpLayer:   true,
+			service:     "redis.googleapis.com",
+			method:      http.MethodPost,
+			wantMatches: []bool{false},
+		},
+		{
+			name:        "http fault matches http requests",
+			fault:       Fault{Method: http.MethodPost},
+			httpLayer:   true,
+			service:     "redis.googleapis.com",
+			method:      http.MethodPost,
+			wantMatches: []bool{true},
+		},
+		{
+			name:        "other service",
+			fault:       Fault{Service: "sqladmin.googleapis.com"},
+			service:     "redis.googleapis.com",
+			method:      "CreateCluster",
+			wantMatches: []bool{false},
+		},
+		{
+			name:        "other method",
+			fault:       Fault{Method: "UpdateCluster"},
+			service:     "redis.googleapis.com",
+			method:      "CreateCluster",
+			wantMatches: []bool{false},
+		},
+		{
+			name:        "resource matches",
+			fault:       Fault{Resource: "clusters/c1$"},
+			method:      "GetCluster",
+			resources:   []string{"", "projects/p/locations/l/clusters/c1"},
+			wantMatches: []bool{true},
+		},
+		{
+			name:        "resource does not match",
+			fault:       Fault{Resource: "clusters/c1$"},
+			method:      "GetCluster",
+			resources:   []string{"projects/p/locations/l/clusters/c2"},
+			wantMatches: []bool{false},
+		},
+		{
+			name:        "limited number of times",
+			fault:       Fault{Times: 2},
+			method:      "GetCluster",
+			wantMatches: []bool{true, true, false},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFaultInjector()
+			if err := f.AddFault(tc.fault); err != nil {
+				t.Fatalf("AddFault: %v", err)
+			}
+			for i, want := range tc.wantMatches {
+				got := f.match(tc.httpLayer, tc.service, tc.method, tc.resources) != nil
+				if got != want {
+					t.Errorf("request %d: got match %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAddFaultErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault Fault
+	}{
+		{name: "invalid resource", fault: Fault{Resource: "("}},
+		{name: "invalid code", fault: Fault{Code: "NOT_A_CODE"}},
+		{name: "invalid operation code", fault: Fault{OperationErrorCode: "NOT_A_CODE"}},
+		{name: "invalid latency", fault: Fault{Latency: "soon"}},
+		{name: "invalid operation latency", fault: Fault{OperationLatency: "soon"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFaultInjector()
+			if err := f.AddFault(tc.fault); err == nil {
+				t.Errorf("AddFault(%+v) succeeded, want error", tc.fault)
+			}
+			if len(f.faults) != 0 {
+				t.Errorf("invalid fault was added")
+			}
+		})
+	}
+}
+
+func TestClearFaults(t *testing.T) {
+	f := NewFaultInjector()
+	if err := f.AddFault(Fault{Code: "UNAVAILABLE"}); err != nil {
+		t.Fatalf("AddFault: %v", err)
+	}
+	f.ClearFaults()
+	if f.match(false, "", "GetCluster", nil) != nil {
+		t.Errorf("cleared fault still matches")
+	}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		fault      Fault
+		wantStatus int
+		wantBody   *ErrorResponse
+	}{
+		{
+			name:       "no fault",
+			fault:      Fault{Method: http.MethodGet},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "error",
+			fault:      Fault{Method: http.MethodPost, Code: "RESOURCE_EXHAUSTED", Message: "quota exceeded"},
+			wantStatus: http.StatusTooManyRequests,
+			wantBody: &ErrorResponse{
+				Code:    http.StatusTooManyRequests,
+				Message: "quota exceeded",
+				Status:  "RESOURCE_EXHAUSTED",
+			},
+		},
+		{
+			name:       "default message",
+			fault:      Fault{Method: http.MethodPost, Code: "UNAVAILABLE"},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody: &ErrorResponse{
+				Code:    http.StatusServiceUnavailable,
+				Message: "mockgcp injected fault",
+				Status:  "UNAVAILABLE",
+			},
+		},
+		{
+			name:       "latency only",
+			fault:      Fault{Method: http.MethodPost, Latency: "1ms"},
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFaultInjector()
+			if err := f.AddFault(tc.fault); err != nil {
+				t.Fatalf("AddFault: %v", err)
+			}
+			handler := f.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "https://redis.googleapis.com/v1/projects/p/locations/l/clusters", nil))
+
+			if w.Code != tc.wantStatus {
+				t.Errorf("got status %d, want %d", w.Code, tc.wantStatus)
+			}
+			if tc.wantBody == nil {
+				return
+			}
+			got := &wrappedStatus{}
+			if err := json.Unmarshal(w.Body.Bytes(), got); err != nil {
+				t.Fatalf("parsing response %q: %v", w.Body.String(), err)
+			}
+			if diff := cmp.Diff(tc.wantBody, got.Error); diff != "" {
+				t.Errorf("unexpected error response (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	tests := []struct {
+		name               string
+		fault              Fault
+		wantCode           codes.Code
+		wantCalled         bool
+		wantOperationFault *OperationFault
+	}{
+		{
+			name:       "fault for another resource",
+			fault:      Fault{Code: "UNAVAILABLE", Resource: "other"},
+			wantCalled: true,
+		},
+		{
+			name:     "error",
+			fault:    Fault{Service: "redis.googleapis.com", Method: "GetCluster", Resource: "clusters/c1$", Code: "UNAVAILABLE"},
+			wantCode: codes.Unavailable,
+		},
+		{
+			name:       "operation error",
+			fault:      Fault{OperationErrorCode: "INTERNAL", OperationLatency: "2s", Message: "boom"},
+			wantCalled: true,
+			wantOperationFault: &OperationFault{
+				Latency: 2e9,
+				Code:    codes.Internal,
+				Message: "boom",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFaultInjector()
+			if err := f.AddFault(tc.fault); err != nil {
+				t.Fatalf("AddFault: %v", err)
+			}
+
+			called := false
+			var gotOperationFault *OperationFault
+			handler := func(ctx context.Context, req any) (any, error) {
+				called = true
+				gotOperationFault = OperationFaultFromContext(ctx)
+				return req, nil
+			}
+
+			ctx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs("x-forwarded-host", "redis.googleapis.com"))
+			req := &descriptorpb.FileDescriptorProto{Name: proto.String("projects/p/locations/l/clusters/c1")}
+			info := &grpc.UnaryServerInfo{FullMethod: "/google.cloud.redis.cluster.v1.CloudRedisCluster/GetCluster"}
+			_, err := f.UnaryServerInterceptor()(ctx, req, info, handler)
+
+			if got := status.Code(err); got != tc.wantCode {
+				t.Errorf("got code %v, want %v", got, tc.wantCode)
+			}
+			if called != tc.wantCalled {
+				t.Errorf("got handler called %v, want %v", called, tc.wantCalled)
+			}
+			switch {
+			case tc.wantOperationFault == nil && gotOperationFault != nil:
+				t.Errorf("got operation fault %+v, want none", *gotOperationFault)
+			case tc.wantOperationFault != nil && (gotOperationFault == nil || *gotOperationFault != *tc.wantOperationFault):
+				t.Errorf("got operation fault %+v, want %+v", gotOperationFault, *tc.wantOperationFault)
+			}
+		})
+	}
+}
+
+func TestCodeName(t *testing.T) {
+	for code, want := range map[codes.Code]string{
+		codes.OK:                "OK",
+		codes.NotFound:          "NOT_FOUND",
+		codes.ResourceExhausted: "RESOURCE_EXHAUSTED",
+		codes.Unavailable:       "UNAVAILABLE",
+	} {
+		if got := codeName(code); got != want {
+			t.Errorf("codeName(%v) = %q, want %q", code, got, want)
+		}
+	}
+}
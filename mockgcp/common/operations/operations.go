@@ -30,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/klog/v2"
 
+	"github.com/GoogleCloudPlatform/k8s-config-connector/mockgcp/common/httpmux"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/mockgcp/pkg/storage"
 )
 
@@ -95,7 +96,12 @@ func (s *Operations) StartLRO(ctx context.Context, prefix string, metadata proto
 		return nil, status.Errorf(codes.Internal, "error creating LRO: %v", err)
 	}
 
+	opFault := httpmux.OperationFaultFromContext(ctx)
+
 	go func() {
+		if opFault != nil && opFault.Latency != 0 {
+			time.Sleep(opFault.Latency)
+		}
 		result, err := callback()
 		finished := &pb.Operation{}
 		if err2 := s.storage.Get(ctx, fqn, finished); err2 != nil {
@@ -117,6 +123,7 @@ func (s *Operations) StartLRO(ctx context.Context, prefix string, metadata proto
 		if err2 := markDone(finished, result, err); err2 != nil {
 			klog.Warningf("error marking LRO as done: %v", err2)
 		}
+		applyOperationFault(finished, opFault)
 
 		if err := s.storage.Update(ctx, fqn, finished); err != nil {
 			klog.Warningf("error updating LRO: %v", err)
@@ -151,6 +158,19 @@ func markDone(op *pb.Operation, result proto.Message, err error) error {
 	return nil
 }
 
+// applyOperationFault makes the operation end in the injected error, if there is one.
+func applyOperationFault(op *pb.Operation, opFault *httpmux.OperationFault) {
+	if opFault == nil || opFault.Code == codes.OK {
+		return
+	}
+	op.Result = &pb.Operation_Error{
+		Error: &rpcstatus.Status{
+			Code:    int32(opFault.Code),
+			Message: opFault.Message,
+		},
+	}
+}
+
 func (s *Operations) DoneLRO(ctx context.Context, prefix string, metadata proto.Message, result proto.Message) (*pb.Operation, error) {
 	now := time.Now()
 	millis := now.UnixMilli()
@@ -167,6 +187,7 @@ func (s *Operations) DoneLRO(ctx context.Context, prefix string, metadata proto.
 	if err := markDone(op, result, nil); err != nil {
 		return nil, err
 	}
+	applyOperationFault(op, httpmux.OperationFaultFromContext(ctx))
 
 	if metadata != nil {
 		metadataAny, err := anypb.New(metadata)
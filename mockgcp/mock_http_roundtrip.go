@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/GoogleCloudPlatform/k8s-config-connector/mockgcp/common"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/mockgcp/common/httpmux"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/mockgcp/common/workflows"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/mockgcp/mockaiplatform"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/mockgcp/mockalloydb"
@@ -92,6 +93,8 @@ type mockRoundTripper struct {
 
 	iamPolicies *mockIAMPolicies
 
+	faults *httpmux.FaultInjector
+
 	services []registeredService
 }
 
@@ -132,6 +135,9 @@ type Interface interface {
 
 	// We can dispatch test commands
 	SupportsTestCommands
+
+	// We can inject faults
+	SupportsFaultInjection
 }
 
 type SupportsTestCommands interface {
@@ -140,10 +146,33 @@ type SupportsTestCommands interface {
 	RunTestCommand(ctx context.Context, service string, command string) error
 }
 
+type SupportsFaultInjection interface {
+	// InjectFault activates a fault (error, latency or failed operation) for subsequent matching requests.
+	// In our script-driven tests, we trigger this with a special `MockGCPFault` object.
+	InjectFault(fault httpmux.Fault) error
+
+	// ClearFaults removes all injected faults.
+	ClearFaults()
+}
+
 func NewMockRoundTripper(t *testing.T, k8sClient client.Client, storage storage.Storage) Interface {
 	ctx := context.Background()
 
-	mockRoundTripper := &mockRoundTripper{}
+	mockRoundTripper, stop, err := New(ctx, k8sClient, storage)
+	if err != nil {
+		t.Fatalf("building mockgcp: %v", err)
+	}
+	t.Cleanup(stop)
+
+	return mockRoundTripper
+}
+
+// New builds the mock services, for use outside of go tests (for example in a standalone server).
+// The returned function stops the services and should be called when they are no longer needed.
+func New(ctx context.Context, k8sClient client.Client, storage storage.Storage) (Interface, func(), error) {
+	mockRoundTripper := &mockRoundTripper{
+		faults: httpmux.NewFaultInjector(),
+	}
 	mockHTTPClient := &http.Client{
 		Transport: mockRoundTripper,
 	}
@@ -153,7 +182,7 @@ func NewMockRoundTripper(t *testing.T, k8sClient client.Client, storage storage.
 
 	workflowEngine, err := workflows.NewEngine(mockHTTPClient)
 	if err != nil {
-		t.Fatalf("building workflow engine: %v", err)
+		return nil, nil, fmt.Errorf("building workflow engine: %w", err)
 	}
 	env.Workflows = workflowEngine
 
@@ -161,6 +190,7 @@ func NewMockRoundTripper(t *testing.T, k8sClient client.Client, storage storage.
 	env.Projects = resourcemanagerService.GetProjectStore()
 
 	var serverOpts []grpc.ServerOption
+	serverOpts = append(serverOpts, grpc.UnaryInterceptor(mockRoundTripper.faults.UnaryServerInterceptor()))
 	server := grpc.NewServer(serverOpts...)
 
 	var services []MockService
@@ -221,19 +251,19 @@ func NewMockRoundTripper(t *testing.T, k8sClient client.Client, storage storage.
 
 	listener, err := net.Listen("tcp", "localhost:0")
 	if err != nil {
-		t.Fatalf("net.Listen failed: %v", err)
+		return nil, nil, fmt.Errorf("net.Listen failed: %w", err)
 	}
 	mockRoundTripper.grpcListener = listener
 
 	go func() {
 		if err := server.Serve(listener); err != nil {
-			t.Errorf("error from grpc server: %v", err)
+			klog.Errorf("error from grpc server: %v", err)
 		}
 	}()
 
-	t.Cleanup(func() {
+	stop := func() {
 		server.Stop()
-	})
+	}
 
 	endpoint := listener.Addr().String()
 
@@ -241,14 +271,22 @@ func NewMockRoundTripper(t *testing.T, k8sClient client.Client, storage storage.
 	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	conn, err := grpc.DialContext(ctx, endpoint, opts...)
 	if err != nil {
-		t.Fatalf("error dialing grpc endpoint %q: %v", endpoint, err)
+		stop()
+		return nil, nil, fmt.Errorf("error dialing grpc endpoint %q: %w", endpoint, err)
 	}
 	mockRoundTripper.grpcConnection = conn
+	stop = func() {
+		if err := conn.Close(); err != nil {
+			klog.Warningf("error closing grpc connection: %v", err)
+		}
+		server.Stop()
+	}
 
 	for _, service := range services {
 		mux, err := service.NewHTTPMux(ctx, conn)
 		if err != nil {
-			t.Fatalf("error building mux: %v", err)
+			stop()
+			return nil, nil, fmt.Errorf("error building mux: %w", err)
 		}
 		var hostRegexes []*regexp.Regexp
 		for _, host := range service.ExpectedHosts() {
@@ -257,13 +295,13 @@ func NewMockRoundTripper(t *testing.T, k8sClient client.Client, storage storage.
 		mockRoundTripper.services = append(mockRoundTripper.services, registeredService{
 			impl:        service,
 			hostRegexes: hostRegexes,
-			handler:     mux,
+			handler:     mockRoundTripper.faults.HTTPMiddleware(mux),
 		})
 	}
 
 	mockRoundTripper.iamPolicies = newMockIAMPolicies()
 
-	return mockRoundTripper
+	return mockRoundTripper, stop, nil
 }
 
 func (m *mockRoundTripper) RunTestCommand(ctx context.Context, serviceName string, command string) error {
@@ -281,6 +319,14 @@ func (m *mockRoundTripper) RunTestCommand(ctx context.Context, serviceName strin
 	return fmt.Errorf("service %q not known", serviceName)
 }
 
+func (m *mockRoundTripper) InjectFault(fault httpmux.Fault) error {
+	return m.faults.AddFault(fault)
+}
+
+func (m *mockRoundTripper) ClearFaults() {
+	m.faults.ClearFaults()
+}
+
 func (m *mockRoundTripper) NewGRPCConnection(ctx context.Context) *grpc.ClientConn {
 	endpoint := m.grpcListener.Addr().String()
 
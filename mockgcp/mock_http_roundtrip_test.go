@@ -0,0 +1,55 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockgcp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/connectivity"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/mockgcp/pkg/storage"
+)
+
+func TestNewAndStop(t *testing.T) {
+	ctx := context.TODO()
+
+	mockGCP, stop, err := New(ctx, fake.NewClientBuilder().Build(), storage.NewInMemoryStorage())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://pubsub.googleapis.com/v1/projects/p/topics/t", nil)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+	response, err := mockGCP.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusNotFound {
+		t.Errorf("got status %d for a missing topic, want %d", response.StatusCode, http.StatusNotFound)
+	}
+
+	stop()
+
+	conn := mockGCP.(*mockRoundTripper).grpcConnection
+	if state := conn.GetState(); state != connectivity.Shutdown {
+		t.Errorf("got grpc connection state %v after stop, want %v", state, connectivity.Shutdown)
+	}
+}
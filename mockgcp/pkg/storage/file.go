@@ -0,0 +1,338 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// FileStorage is a disk-backed implementation of Storage.
+// Each object is stored as a protobuf-JSON file at <dir>/<proto type>/<escaped fqn>.json,
+// so the state survives the process and can be inspected (or snapshotted) with normal tools.
+// If the escaped fqn is too long for a file name, the file name is a prefix of it followed by
+// a hash, and the fqn is stored next to the object in a .key file.
+//
+// Access is serialized per type within a process; writes are atomic (write-then-rename),
+// so concurrent readers in other processes never see partially-written objects.
+type FileStorage struct {
+	dir string
+
+	mutex  sync.Mutex
+	byType map[protoreflect.FullName]*fileTypeStorage
+}
+
+// fileTypeStorage stores objects of a given type
+type fileTypeStorage struct {
+	mutex          sync.Mutex
+	dir            string
+	messageType    protoreflect.FullName
+	objectTypeName string
+}
+
+var _ Storage = &FileStorage{}
+
+// NewFileStorage constructs a FileStorage rooted at dir, creating the directory if needed.
+// Any objects already present in dir are visible immediately.
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating storage directory %q: %w", dir, err)
+	}
+	return &FileStorage{
+		dir:    dir,
+		byType: make(map[protoreflect.FullName]*fileTypeStorage),
+	}, nil
+}
+
+func (s *FileStorage) getTypeStorage(name protoreflect.FullName) *fileTypeStorage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ts := s.byType[name]
+	if ts == nil {
+		objectTypeName := string(name.Name())
+		objectTypeName = strings.ToLower(string(objectTypeName[0])) + objectTypeName[1:]
+		ts = &fileTypeStorage{
+			dir:            filepath.Join(s.dir, string(name)),
+			messageType:    name,
+			objectTypeName: objectTypeName,
+		}
+		s.byType[name] = ts
+	}
+	return ts
+}
+
+// Create stores the object, erroring if it already exists
+func (s *FileStorage) Create(ctx context.Context, fqn string, create proto.Message) error {
+	return s.getTypeStorage(create.ProtoReflect().Descriptor().FullName()).Create(ctx, fqn, create)
+}
+
+func (s *fileTypeStorage) Create(ctx context.Context, fqn string, create proto.Message) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	found, err := s.exists(fqn)
+	if err != nil {
+		return err
+	}
+	if found {
+		return status.Errorf(codes.AlreadyExists, "%v %q already exists", s.objectTypeName, fqn)
+	}
+	return s.write(fqn, create)
+}
+
+// Delete deletes the object, returning a not found error if it does not exist.
+func (s *FileStorage) Delete(ctx context.Context, fqn string, dest proto.Message) error {
+	kind := dest.ProtoReflect().Descriptor()
+	return s.getTypeStorage(kind.FullName()).Delete(ctx, fqn, dest)
+}
+
+func (s *fileTypeStorage) Delete(ctx context.Context, fqn string, dest proto.Message) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.read(fqn, dest); err != nil {
+		return err
+	}
+	if err := os.Remove(s.pathForKey(fqn)); err != nil {
+		return status.Errorf(codes.Internal, "deleting %v %q: %v", s.objectTypeName, fqn, err)
+	}
+	if isHashedFileName(s.fileNameForKey(fqn)) {
+		if err := os.Remove(s.keyPathForKey(fqn)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return status.Errorf(codes.Internal, "deleting %v %q: %v", s.objectTypeName, fqn, err)
+		}
+	}
+	return nil
+}
+
+// Update stores a new version of an object, erroring if it does not already exist
+func (s *FileStorage) Update(ctx context.Context, fqn string, update proto.Message) error {
+	return s.getTypeStorage(update.ProtoReflect().Descriptor().FullName()).Update(ctx, fqn, update)
+}
+
+// Update stores a new version of an object, erroring if it does not already exist
+func (s *fileTypeStorage) Update(ctx context.Context, fqn string, update proto.Message) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	found, err := s.exists(fqn)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return status.Errorf(codes.NotFound, "%v %q not found", s.objectTypeName, fqn)
+	}
+	return s.write(fqn, update)
+}
+
+// Get returns an existing object
+func (s *FileStorage) Get(ctx context.Context, fqn string, dest proto.Message) error {
+	return s.getTypeStorage(dest.ProtoReflect().Descriptor().FullName()).Get(ctx, fqn, dest)
+}
+
+func (s *fileTypeStorage) Get(ctx context.Context, fqn string, dest proto.Message) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.read(fqn, dest)
+}
+
+// List returns all matching objects
+func (s *FileStorage) List(ctx context.Context, kind protoreflect.Descriptor, options ListOptions, callback func(obj proto.Message) error) error {
+	return s.getTypeStorage(kind.FullName()).List(ctx, options, callback)
+}
+
+func (s *fileTypeStorage) List(ctx context.Context, options ListOptions, callback func(obj proto.Message) error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return status.Errorf(codes.Internal, "listing %v objects: %v", s.objectTypeName, err)
+	}
+
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(s.messageType)
+	if err != nil {
+		return status.Errorf(codes.Internal, "finding message type %q: %v", s.messageType, err)
+	}
+
+	var keys []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		fqn, err := s.keyForFileName(name)
+		if err != nil {
+			continue
+		}
+		if options.Prefix != "" && !strings.HasPrefix(fqn, options.Prefix) {
+			continue
+		}
+		keys = append(keys, fqn)
+	}
+	sort.Strings(keys)
+
+	for _, fqn := range keys {
+		obj := messageType.New().Interface()
+		if err := s.read(fqn, obj); err != nil {
+			if status.Code(err) == codes.NotFound {
+				// Removed by another process since we listed the directory
+				continue
+			}
+			return err
+		}
+		if err := callback(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxFileNameLength is the maximum length of a file name on most filesystems.
+const maxFileNameLength = 255
+
+// hashedFileNamePrefixLength is the length of the prefix of the escaped fqn kept in a hashed file name.
+const hashedFileNamePrefixLength = 128
+
+// hashSeparator separates the prefix from the hash in a hashed file name.
+// It cannot occur in an escaped fqn, because url.PathEscape escapes every "%".
+const hashSeparator = "%%"
+
+// fileNameForKey returns the name of the file storing the object, without the .json extension.
+func (s *fileTypeStorage) fileNameForKey(fqn string) string {
+	name := url.PathEscape(fqn)
+	if len(name)+len(".json") <= maxFileNameLength {
+		return name
+	}
+	prefix := name[:hashedFileNamePrefixLength]
+	// Don't cut an escape sequence in half, so the prefix stays readable.
+	if i := strings.LastIndex(prefix, "%"); i >= len(prefix)-2 {
+		prefix = prefix[:i]
+	}
+	hash := sha256.Sum256([]byte(fqn))
+	return prefix + hashSeparator + hex.EncodeToString(hash[:])
+}
+
+func isHashedFileName(name string) bool {
+	return strings.Contains(name, hashSeparator)
+}
+
+func (s *fileTypeStorage) pathForKey(fqn string) string {
+	return filepath.Join(s.dir, s.fileNameForKey(fqn)+".json")
+}
+
+// keyPathForKey returns the path of the file storing the fqn of an object with a hashed file name.
+func (s *fileTypeStorage) keyPathForKey(fqn string) string {
+	return filepath.Join(s.dir, s.fileNameForKey(fqn)+".key")
+}
+
+// keyForFileName returns the fqn of the object stored in the .json file with the given name.
+func (s *fileTypeStorage) keyForFileName(fileName string) (string, error) {
+	name := strings.TrimSuffix(fileName, ".json")
+	if !isHashedFileName(name) {
+		return url.PathUnescape(name)
+	}
+	b, err := os.ReadFile(filepath.Join(s.dir, name+".key"))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (s *fileTypeStorage) exists(fqn string) (bool, error) {
+	_, err := os.Stat(s.pathForKey(fqn))
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return false, status.Errorf(codes.Internal, "checking for %v %q: %v", s.objectTypeName, fqn, err)
+}
+
+func (s *fileTypeStorage) read(fqn string, dest proto.Message) error {
+	b, err := os.ReadFile(s.pathForKey(fqn))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return status.Errorf(codes.NotFound, "%v %q not found", s.objectTypeName, fqn)
+		}
+		return status.Errorf(codes.Internal, "reading %v %q: %v", s.objectTypeName, fqn, err)
+	}
+
+	obj := dest.ProtoReflect().New().Interface()
+	if err := protojson.Unmarshal(b, obj); err != nil {
+		return status.Errorf(codes.Internal, "parsing %v %q: %v", s.objectTypeName, fqn, err)
+	}
+	proto.Merge(dest, obj)
+	return nil
+}
+
+// write stores the object atomically, by writing to a temporary file and renaming it into place.
+func (s *fileTypeStorage) write(fqn string, obj proto.Message) error {
+	b, err := protojson.MarshalOptions{Multiline: true}.Marshal(obj)
+	if err != nil {
+		return status.Errorf(codes.Internal, "serializing %v %q: %v", s.objectTypeName, fqn, err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return status.Errorf(codes.Internal, "creating directory for %v: %v", s.objectTypeName, err)
+	}
+
+	if isHashedFileName(s.fileNameForKey(fqn)) {
+		// The key is written first, so that List can always find the key of an object.
+		if err := os.WriteFile(s.keyPathForKey(fqn), []byte(fqn), 0644); err != nil {
+			return status.Errorf(codes.Internal, "writing %v %q: %v", s.objectTypeName, fqn, err)
+		}
+	}
+
+	f, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return status.Errorf(codes.Internal, "writing %v %q: %v", s.objectTypeName, fqn, err)
+	}
+	tmpPath := f.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return status.Errorf(codes.Internal, "writing %v %q: %v", s.objectTypeName, fqn, err)
+	}
+	if err := f.Close(); err != nil {
+		return status.Errorf(codes.Internal, "writing %v %q: %v", s.objectTypeName, fqn, err)
+	}
+	if err := os.Rename(tmpPath, s.pathForKey(fqn)); err != nil {
+		return status.Errorf(codes.Internal, "writing %v %q: %v", s.objectTypeName, fqn, err)
+	}
+	return nil
+}
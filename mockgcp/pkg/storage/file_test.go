@@ -0,0 +1,146 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestFileStorage(t *testing.T) {
+	ctx := context.TODO()
+	dir := t.TempDir()
+	s, err := NewFileStorage(dir)
+	if err != nil {
+		t.Fatalf("NewFileStorage: %v", err)
+	}
+
+	longName := "projects/p/locations/l/buckets/" + strings.Repeat("b", 300)
+	names := []string{
+		"projects/p/locations/l/buckets/a",
+		"projects/p/locations/l/buckets/b?c",
+		longName,
+		"projects/q/locations/l/buckets/a",
+	}
+	for _, name := range names {
+		if err := s.Create(ctx, name, wrapperspb.String(name)); err != nil {
+			t.Fatalf("Create(%q): %v", name, err)
+		}
+	}
+
+	if err := s.Create(ctx, names[0], wrapperspb.String("again")); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Create of an existing object returned %v, want AlreadyExists", err)
+	}
+
+	got := &wrapperspb.StringValue{}
+	if err := s.Get(ctx, longName, got); err != nil {
+		t.Fatalf("Get(%q): %v", longName, err)
+	}
+	if got.GetValue() != longName {
+		t.Errorf("Get(%q) returned %q", longName, got.GetValue())
+	}
+
+	if err := s.Update(ctx, longName, wrapperspb.String("updated")); err != nil {
+		t.Fatalf("Update(%q): %v", longName, err)
+	}
+	if err := s.Update(ctx, "projects/p/locations/l/buckets/missing", wrapperspb.String("x")); status.Code(err) != codes.NotFound {
+		t.Errorf("Update of a missing object returned %v, want NotFound", err)
+	}
+
+	// A second storage on the same directory sees the same objects, e.g. after a restart.
+	s2, err := NewFileStorage(dir)
+	if err != nil {
+		t.Fatalf("NewFileStorage: %v", err)
+	}
+	var listed []string
+	kind := (&wrapperspb.StringValue{}).ProtoReflect().Descriptor()
+	if err := s2.List(ctx, kind, ListOptions{Prefix: "projects/p/"}, func(obj proto.Message) error {
+		listed = append(listed, obj.(*wrapperspb.StringValue).GetValue())
+		return nil
+	}); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	want := []string{names[0], names[1], "updated"}
+	if strings.Join(listed, ",") != strings.Join(want, ",") {
+		t.Errorf("List returned %q, want %q", listed, want)
+	}
+
+	deleted := &wrapperspb.StringValue{}
+	if err := s2.Delete(ctx, longName, deleted); err != nil {
+		t.Fatalf("Delete(%q): %v", longName, err)
+	}
+	if err := s.Get(ctx, longName, &wrapperspb.StringValue{}); status.Code(err) != codes.NotFound {
+		t.Errorf("Get of a deleted object returned %v, want NotFound", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*"))
+	if err != nil {
+		t.Fatalf("listing files: %v", err)
+	}
+	if len(files) != 3 {
+		t.Errorf("got files %v, want the 3 remaining objects only", files)
+	}
+	for _, f := range files {
+		if len(filepath.Base(f)) > maxFileNameLength {
+			t.Errorf("file name %q is longer than %d bytes", filepath.Base(f), maxFileNameLength)
+		}
+	}
+}
+
+func TestFileNameForKey(t *testing.T) {
+	s := &fileTypeStorage{dir: os.TempDir()}
+	tests := []struct {
+		name       string
+		fqn        string
+		wantHashed bool
+	}{
+		{
+			name: "short name",
+			fqn:  "projects/p/topics/t",
+		},
+		{
+			name:       "long name",
+			fqn:        "projects/p/topics/" + strings.Repeat("t", 250),
+			wantHashed: true,
+		},
+		{
+			name:       "long name with escapes",
+			fqn:        "projects/p/objects/" + strings.Repeat("a/b", 100),
+			wantHashed: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			name := s.fileNameForKey(tc.fqn)
+			if got := isHashedFileName(name); got != tc.wantHashed {
+				t.Errorf("fileNameForKey(%q) = %q, hashed %v, want %v", tc.fqn, name, got, tc.wantHashed)
+			}
+			if len(name+".json") > maxFileNameLength {
+				t.Errorf("fileNameForKey(%q) = %q is longer than %d bytes", tc.fqn, name, maxFileNameLength)
+			}
+			if other := s.fileNameForKey(tc.fqn + "x"); other == name {
+				t.Errorf("fileNameForKey(%q) is the same for a different name", tc.fqn)
+			}
+		})
+	}
+}
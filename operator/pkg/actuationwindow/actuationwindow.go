@@ -0,0 +1,109 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package actuationwindow evaluates the actuation windows of the ConfigConnector
+// and ConfigConnectorContext objects.
+package actuationwindow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// Embed the time zone database, so that time zones can be loaded in
+	// images without one.
+	_ "time/tzdata"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/v1beta1"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Window is a parsed v1beta1.ActuationWindow.
+type Window struct {
+	schedule *cron.SpecSchedule
+	duration time.Duration
+}
+
+// Parse parses and validates an actuation window.
+func Parse(w v1beta1.ActuationWindow) (*Window, error) {
+	if w.Duration.Duration <= 0 {
+		return nil, fmt.Errorf("invalid actuation window duration %v: must be positive", w.Duration.Duration)
+	}
+	if strings.HasPrefix(w.Schedule, "TZ=") || strings.HasPrefix(w.Schedule, "CRON_TZ=") {
+		return nil, fmt.Errorf("invalid actuation window schedule %q: use timeZone to set the time zone", w.Schedule)
+	}
+	schedule, err := cron.ParseStandard(w.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid actuation window schedule %q: %w", w.Schedule, err)
+	}
+	specSchedule, ok := schedule.(*cron.SpecSchedule)
+	if !ok {
+		return nil, fmt.Errorf("invalid actuation window schedule %q: must be a cron expression", w.Schedule)
+	}
+	location := time.UTC
+	if w.TimeZone != "" {
+		location, err = time.LoadLocation(w.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid actuation window time zone %q: %w", w.TimeZone, err)
+		}
+	}
+	specSchedule.Location = location
+	return &Window{schedule: specSchedule, duration: w.Duration.Duration}, nil
+}
+
+// Validate returns an error if any of windows is invalid.
+func Validate(windows []v1beta1.ActuationWindow) error {
+	for i, w := range windows {
+		if _, err := Parse(w); err != nil {
+			return fmt.Errorf("invalid actuation window at index %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Evaluate returns whether now is within the window, and otherwise the start of the next
+// window. The next start is the zero time if the schedule never fires again.
+func (w *Window) Evaluate(now time.Time) (open bool, nextStart time.Time) {
+	// The first start after (now - duration) is the start of the window containing now,
+	// if there is one, and otherwise the start of the next window.
+	start := w.schedule.Next(now.Add(-w.duration))
+	if !start.IsZero() && !start.After(now) {
+		return true, time.Time{}
+	}
+	return false, start
+}
+
+// Evaluate returns whether now is within one of windows, and otherwise the earliest start of
+// the next windows. An empty list of windows is always open. The next start is the zero time if
+// none of the windows ever starts again.
+func Evaluate(windows []v1beta1.ActuationWindow, now time.Time) (open bool, nextStart time.Time, err error) {
+	if len(windows) == 0 {
+		return true, time.Time{}, nil
+	}
+	for i, w := range windows {
+		window, err := Parse(w)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid actuation window at index %d: %w", i, err)
+		}
+		windowOpen, windowNextStart := window.Evaluate(now)
+		if windowOpen {
+			return true, time.Time{}, nil
+		}
+		if !windowNextStart.IsZero() && (nextStart.IsZero() || windowNextStart.Before(nextStart)) {
+			nextStart = windowNextStart
+		}
+	}
+	return false, nextStart, nil
+}
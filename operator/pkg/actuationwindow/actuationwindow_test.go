@@ -0,0 +1,139 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actuationwindow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/v1beta1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func window(schedule string, duration time.Duration, timeZone string) v1beta1.ActuationWindow {
+	return v1beta1.ActuationWindow{
+		Schedule: schedule,
+		Duration: metav1.Duration{Duration: duration},
+		TimeZone: timeZone,
+	}
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("error parsing time %q: %v", value, err)
+	}
+	return parsed
+}
+
+func TestEvaluate(t *testing.T) {
+	// 10pm to 2am on weekdays.
+	weeknights := window("0 22 * * 1-5", 4*time.Hour, "")
+	// 2am to 4am on Sundays, in Berlin.
+	sundays := window("0 2 * * 0", 2*time.Hour, "Europe/Berlin")
+
+	tests := []struct {
+		name          string
+		windows       []v1beta1.ActuationWindow
+		now           string
+		wantOpen      bool
+		wantNextStart string
+	}{
+		{
+			name:     "no windows",
+			now:      "2024-06-05T12:00:00Z",
+			wantOpen: true,
+		},
+		{
+			name:     "within window",
+			windows:  []v1beta1.ActuationWindow{weeknights},
+			now:      "2024-06-05T23:00:00Z", // Wednesday
+			wantOpen: true,
+		},
+		{
+			name:     "within window started the previous day",
+			windows:  []v1beta1.ActuationWindow{weeknights},
+			now:      "2024-06-06T01:59:00Z", // Thursday
+			wantOpen: true,
+		},
+		{
+			name:          "after window",
+			windows:       []v1beta1.ActuationWindow{weeknights},
+			now:           "2024-06-06T02:00:00Z",
+			wantNextStart: "2024-06-06T22:00:00Z",
+		},
+		{
+			name:          "weekend",
+			windows:       []v1beta1.ActuationWindow{weeknights},
+			now:           "2024-06-08T12:00:00Z", // Saturday
+			wantNextStart: "2024-06-10T22:00:00Z",
+		},
+		{
+			name:          "earliest of several windows",
+			windows:       []v1beta1.ActuationWindow{weeknights, sundays},
+			now:           "2024-06-08T12:00:00Z",
+			wantNextStart: "2024-06-09T00:00:00Z", // 2am in Berlin
+		},
+		{
+			name:     "within time zone window",
+			windows:  []v1beta1.ActuationWindow{weeknights, sundays},
+			now:      "2024-06-09T01:30:00Z",
+			wantOpen: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			open, nextStart, err := Evaluate(tc.windows, mustParseTime(t, tc.now))
+			if err != nil {
+				t.Fatalf("Evaluate: unexpected error: %v", err)
+			}
+			if open != tc.wantOpen {
+				t.Errorf("Evaluate: got open %v, want %v", open, tc.wantOpen)
+			}
+			var wantNextStart time.Time
+			if tc.wantNextStart != "" {
+				wantNextStart = mustParseTime(t, tc.wantNextStart)
+			}
+			if !nextStart.Equal(wantNextStart) {
+				t.Errorf("Evaluate: got next start %v, want %v", nextStart, wantNextStart)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  v1beta1.ActuationWindow
+		wantErr bool
+	}{
+		{name: "valid", window: window("0 22 * * 1-5", time.Hour, "")},
+		{name: "valid with time zone", window: window("@daily", time.Hour, "America/New_York")},
+		{name: "invalid schedule", window: window("0 22 * *", time.Hour, ""), wantErr: true},
+		{name: "relative schedule", window: window("@every 1h", time.Hour, ""), wantErr: true},
+		{name: "time zone in schedule", window: window("CRON_TZ=UTC 0 22 * * *", time.Hour, ""), wantErr: true},
+		{name: "zero duration", window: window("0 22 * * *", 0, ""), wantErr: true},
+		{name: "invalid time zone", window: window("0 22 * * *", time.Hour, "Mars/Olympus_Mons"), wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate([]v1beta1.ActuationWindow{tc.window})
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Validate: got error %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -45,6 +45,17 @@ type NamespacedControllerReconcilerSpec struct {
 	// Configures the debug endpoint on the service.
 	// +optional
 	Pprof *PprofConfig `json:"pprof,omitempty"`
+	// GCPRateLimit configures the client-side token bucket rate limit to the requests
+	// the manager container makes to GCP APIs. Each GCP service in each project has its
+	// own token bucket, shared among all the Config Connector resources' requests.
+	// If not specified, the requests to GCP APIs are not rate limited.
+	// +optional
+	GCPRateLimit *GCPRateLimit `json:"gcpRateLimit,omitempty"`
+	// Tracing configures the export of OpenTelemetry traces of the reconciliations and of
+	// the requests to GCP APIs made by the manager container.
+	// If not specified, tracing is disabled.
+	// +optional
+	Tracing *TracingConfig `json:"tracing,omitempty"`
 }
 
 type RateLimit struct {
@@ -56,6 +67,46 @@ type RateLimit struct {
 	Burst int `json:"burst,omitempty"`
 }
 
+type GCPRateLimit struct {
+	// The QPS of the token bucket rate limit for the requests to each GCP service in each project.
+	// +optional
+	QPS int `json:"qps,omitempty"`
+	// The burst of the token bucket rate limit for the requests to each GCP service in each project.
+	// If not specified, the QPS is used.
+	// +optional
+	Burst int `json:"burst,omitempty"`
+	// Services overrides the rate limit for specific GCP services.
+	// +optional
+	Services []GCPServiceRateLimit `json:"services,omitempty"`
+}
+
+type GCPServiceRateLimit struct {
+	// The hostname of the GCP service, e.g. "compute.googleapis.com".
+	// +required
+	Service string `json:"service"`
+	// The QPS of the token bucket rate limit for the requests to the service in each project.
+	// It must be positive, as a service cannot be exempted from the rate limit.
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	QPS int `json:"qps"`
+	// The burst of the token bucket rate limit for the requests to the service in each project.
+	// If not specified, the QPS is used.
+	// +optional
+	Burst int `json:"burst,omitempty"`
+}
+
+type TracingConfig struct {
+	// The URL of the OTLP/HTTP endpoint the traces are exported to, e.g. "http://otel-collector.monitoring:4318".
+	// +required
+	OTLPEndpoint string `json:"otlpEndpoint"`
+	// The percentage of the reconciliations which are traced. If not specified, all the
+	// reconciliations are traced.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	SamplingPercentage *int `json:"samplingPercentage,omitempty"`
+}
+
 type PprofConfig struct {
 	// Control if pprof should be turned on and which types should be enabled.
 	// +kubebuilder:validation:Enum=none;all
@@ -110,6 +161,17 @@ type ControllerReconcilerSpec struct {
 	// Configures the debug endpoint on the service.
 	// +optional
 	Pprof *PprofConfig `json:"pprof,omitempty"`
+	// GCPRateLimit configures the client-side token bucket rate limit to the requests
+	// the manager container makes to GCP APIs. Each GCP service in each project has its
+	// own token bucket, shared among all the Config Connector resources' requests.
+	// If not specified, the requests to GCP APIs are not rate limited.
+	// +optional
+	GCPRateLimit *GCPRateLimit `json:"gcpRateLimit,omitempty"`
+	// Tracing configures the export of OpenTelemetry traces of the reconciliations and of
+	// the requests to GCP APIs made by the manager container.
+	// If not specified, tracing is disabled.
+	// +optional
+	Tracing *TracingConfig `json:"tracing,omitempty"`
 }
 
 // ControllerReconcilerStatus defines the observed state of ControllerReconciler.
@@ -138,6 +200,10 @@ var SupportedPprofControllers = []string{
 	"cnrm-controller-manager",
 }
 
+var SupportedTracingControllers = []string{
+	"cnrm-controller-manager",
+}
+
 func init() {
 	SchemeBuilder.Register(
 		&NamespacedControllerReconciler{},
@@ -97,6 +97,16 @@ func (in *ControllerReconcilerSpec) DeepCopyInto(out *ControllerReconcilerSpec)
 		*out = new(PprofConfig)
 		**out = **in
 	}
+	if in.GCPRateLimit != nil {
+		in, out := &in.GCPRateLimit, &out.GCPRateLimit
+		*out = new(GCPRateLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(TracingConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerReconcilerSpec.
@@ -227,6 +237,41 @@ func (in *ControllerResourceStatus) DeepCopy() *ControllerResourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPRateLimit) DeepCopyInto(out *GCPRateLimit) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]GCPServiceRateLimit, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPRateLimit.
+func (in *GCPRateLimit) DeepCopy() *GCPRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPServiceRateLimit) DeepCopyInto(out *GCPServiceRateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPServiceRateLimit.
+func (in *GCPServiceRateLimit) DeepCopy() *GCPServiceRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPServiceRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MutatingWebhookConfigurationCustomization) DeepCopyInto(out *MutatingWebhookConfigurationCustomization) {
 	*out = *in
@@ -358,6 +403,16 @@ func (in *NamespacedControllerReconcilerSpec) DeepCopyInto(out *NamespacedContro
 		*out = new(PprofConfig)
 		**out = **in
 	}
+	if in.GCPRateLimit != nil {
+		in, out := &in.GCPRateLimit, &out.GCPRateLimit
+		*out = new(GCPRateLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(TracingConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedControllerReconcilerSpec.
@@ -542,6 +597,26 @@ func (in *ResourceRequirements) DeepCopy() *ResourceRequirements {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TracingConfig) DeepCopyInto(out *TracingConfig) {
+	*out = *in
+	if in.SamplingPercentage != nil {
+		in, out := &in.SamplingPercentage, &out.SamplingPercentage
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingConfig.
+func (in *TracingConfig) DeepCopy() *TracingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TracingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ValidatingWebhookConfigurationCustomization) DeepCopyInto(out *ValidatingWebhookConfigurationCustomization) {
 	*out = *in
@@ -0,0 +1,91 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=validationpolicies,scope=Cluster
+
+// ValidationPolicy is the Schema for the policies config connector resources must satisfy to be
+// admitted. The policies are enforced by the config connector webhook.
+type ValidationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ValidationPolicySpec `json:"spec"`
+}
+
+// ValidationPolicySpec is the specification of ValidationPolicy.
+type ValidationPolicySpec struct {
+	// The list of rules config connector resources must satisfy.
+	// Required
+	Rules []ValidationRule `json:"rules"`
+}
+
+// ValidationRule is a CEL expression config connector resources must satisfy.
+type ValidationRule struct {
+	// The name of the rule, which is included in the admission denial when the rule is violated.
+	// Required
+	Name string `json:"name"`
+	// The resources the rule applies to. If not specified, the rule applies to all config
+	// connector resources.
+	// +optional
+	MatchResources []ResourceSelector `json:"matchResources,omitempty"`
+	// The CEL expression, which must evaluate to true for a resource to be admitted.
+	// The resource is available as `object`, and on updates, the existing resource is
+	// available as `oldObject` (`oldObject` is null on creates).
+	// For example: `object.spec.location in ['US', 'EU']`.
+	// Required
+	Expression string `json:"expression"`
+	// The message returned in the admission denial when the rule is violated.
+	// If not specified, the expression is included instead.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ResourceSelector selects config connector resources by their API group and kind.
+type ResourceSelector struct {
+	// The API group of the resources, e.g. `storage.cnrm.cloud.google.com`.
+	// Required
+	Group string `json:"group"`
+	// The kind of the resources, e.g. `StorageBucket`. If not specified, all kinds
+	// in the API group are selected.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// The API version of the resources, e.g. `v1beta1`. If not specified, all versions
+	// are selected.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ValidationPolicyList contains a list of ValidationPolicy.
+type ValidationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ValidationPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(
+		&ValidationPolicy{},
+		&ValidationPolicyList{},
+	)
+}
@@ -51,6 +51,15 @@ type ConfigConnectorSpec struct {
 	//+kubebuilder:validation:Optional
 	Actuation ActuationMode `json:"actuationMode,omitempty"`
 
+	// The actuation windows of Config Connector. If set, and the actuation mode is 'Reconciling',
+	// resources are only actuated onto the cloud provider during one of the windows. Outside of the
+	// windows, k8s resources are still reconciled with the api server but not actuated onto the cloud
+	// provider, and report the 'PendingActuationWindow' reason in their Ready condition.
+	// If Config Connector is running in 'namespaced' mode, then the value in ConfigConnectorContext (CCC)
+	// takes precedence. If CCC doesn't define a value but ConfigConnector (CC) does, we defer to that value.
+	//+kubebuilder:validation:Optional
+	ActuationWindows []ActuationWindow `json:"actuationWindows,omitempty"`
+
 	// StateIntoSpec is the user override of the default value for the
 	// 'cnrm.cloud.google.com/state-into-spec' annotation if the annotation is
 	// unset for a resource.
@@ -67,6 +67,44 @@ type ConfigConnectorContextSpec struct {
 	//+kubebuilder:validation:Enum=Reconciling;Paused
 	//+kubebuilder:validation:Optional
 	Actuation ActuationMode `json:"actuationMode,omitempty"`
+
+	// The actuation windows of Config Connector in the associated namespace. If set, and the actuation
+	// mode is 'Reconciling', resources are only actuated onto the cloud provider during one of the windows.
+	// Outside of the windows, k8s resources are still reconciled with the api server but not actuated onto
+	// the cloud provider, and report the 'PendingActuationWindow' reason in their Ready condition.
+	// Takes precedence over the actuation windows of ConfigConnector.
+	//+kubebuilder:validation:Optional
+	ActuationWindows []ActuationWindow `json:"actuationWindows,omitempty"`
+
+	// CredentialRules selects the Google Service Account used for the
+	// requests made for some of the resources in the associated namespace,
+	// instead of googleServiceAccount. The first matching rule is used, and
+	// resources matching no rule use googleServiceAccount. The rules are
+	// picked up without restarting Config Connector, so a catch-all rule can
+	// also be used to rotate the Google Service Account of the namespace.
+	// googleServiceAccount must have the 'roles/iam.serviceAccountTokenCreator'
+	// role on the Google Service Accounts used in the rules.
+	//+kubebuilder:validation:Optional
+	CredentialRules []CredentialRule `json:"credentialRules,omitempty"`
+}
+
+// CredentialRule maps the resources of some kinds or projects to a Google
+// Service Account.
+type CredentialRule struct {
+	// The Google Service Account to be used by Config Connector to
+	// authenticate with Google Cloud APIs for the matching resources.
+	GoogleServiceAccount string `json:"googleServiceAccount"`
+
+	// The kinds of the matching resources, e.g. 'IAMPolicyMember'. If not
+	// specified, resources of all kinds match. Kinds are matched for the
+	// resources reconciled by every controller, Terraform-based ones included.
+	//+kubebuilder:validation:Optional
+	Kinds []string `json:"kinds,omitempty"`
+
+	// The IDs of the projects of the matching resources. If not specified,
+	// resources in all projects match.
+	//+kubebuilder:validation:Optional
+	Projects []string `json:"projects,omitempty"`
 }
 
 type StateIntoSpecValue string
@@ -79,6 +117,34 @@ const (
 // ConfigConnectorContextStatus defines the observed state of ConfigConnectorContext
 type ConfigConnectorContextStatus struct {
 	addonv1alpha1.CommonStatus `json:",inline"`
+
+	// OrderedDeletion reports the progress of the deletion of the Config
+	// Connector resources in the associated namespace, while the namespace is
+	// being deleted or has the 'cnrm.cloud.google.com/ordered-deletion'
+	// annotation set to 'true'.
+	//+kubebuilder:validation:Optional
+	OrderedDeletion *OrderedDeletionStatus `json:"orderedDeletion,omitempty"`
+}
+
+// OrderedDeletionStatus reports the progress of the deletion of the Config
+// Connector resources in a namespace. The resources are deleted in reverse
+// reference order, i.e. a resource is only deleted once the resources which
+// reference it are gone.
+type OrderedDeletionStatus struct {
+	// Phase is 'InProgress' while Config Connector resources remain in the
+	// namespace, and 'Completed' once all of them are gone.
+	Phase string `json:"phase"`
+	// Remaining is the number of Config Connector resources left in the
+	// namespace.
+	Remaining int `json:"remaining"`
+	// Deleting is the number of remaining resources which are being deleted,
+	// i.e. which are no longer referenced by other remaining resources.
+	Deleting int `json:"deleting"`
+	// Blocked is the number of remaining resources whose deletion waits for
+	// the deletion of the resources referencing them.
+	Blocked int `json:"blocked"`
+	// LastUpdateTime is the last time the progress was updated.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
 }
 
 // +kubebuilder:object:root=true
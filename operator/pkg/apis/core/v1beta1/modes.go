@@ -14,6 +14,10 @@
 
 package v1beta1
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // ActuationMode enum defines the possible actuation values that ConfigConnect (CC)
 // and ConfigConnectorContext (CCC) can specify.
 type ActuationMode string
@@ -26,3 +30,19 @@ const (
 func DefaultActuationMode() ActuationMode {
 	return Reconciling
 }
+
+// ActuationWindow is a recurring window of time during which Config Connector
+// actuates resources onto the cloud provider.
+type ActuationWindow struct {
+	// The cron schedule of the start of the window, in the standard 5-field
+	// format, e.g. '0 22 * * 1-5' for 10pm on weekdays.
+	Schedule string `json:"schedule"`
+
+	// How long the window lasts after each start, e.g. '4h'.
+	Duration metav1.Duration `json:"duration"`
+
+	// The IANA name of the time zone of the schedule, e.g. 'Europe/Berlin'.
+	// Defaults to UTC.
+	//+kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
@@ -8,6 +8,22 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActuationWindow) DeepCopyInto(out *ActuationWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActuationWindow.
+func (in *ActuationWindow) DeepCopy() *ActuationWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ActuationWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigConnector) DeepCopyInto(out *ConfigConnector) {
 	*out = *in
@@ -102,6 +118,18 @@ func (in *ConfigConnectorContextSpec) DeepCopyInto(out *ConfigConnectorContextSp
 		*out = new(StateIntoSpecValue)
 		**out = **in
 	}
+	if in.ActuationWindows != nil {
+		in, out := &in.ActuationWindows, &out.ActuationWindows
+		*out = make([]ActuationWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.CredentialRules != nil {
+		in, out := &in.CredentialRules, &out.CredentialRules
+		*out = make([]CredentialRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigConnectorContextSpec.
@@ -118,6 +146,11 @@ func (in *ConfigConnectorContextSpec) DeepCopy() *ConfigConnectorContextSpec {
 func (in *ConfigConnectorContextStatus) DeepCopyInto(out *ConfigConnectorContextStatus) {
 	*out = *in
 	in.CommonStatus.DeepCopyInto(&out.CommonStatus)
+	if in.OrderedDeletion != nil {
+		in, out := &in.OrderedDeletion, &out.OrderedDeletion
+		*out = new(OrderedDeletionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigConnectorContextStatus.
@@ -166,6 +199,11 @@ func (in *ConfigConnectorList) DeepCopyObject() runtime.Object {
 func (in *ConfigConnectorSpec) DeepCopyInto(out *ConfigConnectorSpec) {
 	*out = *in
 	out.CommonSpec = in.CommonSpec
+	if in.ActuationWindows != nil {
+		in, out := &in.ActuationWindows, &out.ActuationWindows
+		*out = make([]ActuationWindow, len(*in))
+		copy(*out, *in)
+	}
 	if in.StateIntoSpec != nil {
 		in, out := &in.StateIntoSpec, &out.StateIntoSpec
 		*out = new(StateIntoSpecValue)
@@ -198,3 +236,44 @@ func (in *ConfigConnectorStatus) DeepCopy() *ConfigConnectorStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialRule) DeepCopyInto(out *CredentialRule) {
+	*out = *in
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Projects != nil {
+		in, out := &in.Projects, &out.Projects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialRule.
+func (in *CredentialRule) DeepCopy() *CredentialRule {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrderedDeletionStatus) DeepCopyInto(out *OrderedDeletionStatus) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrderedDeletionStatus.
+func (in *OrderedDeletionStatus) DeepCopy() *OrderedDeletionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OrderedDeletionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
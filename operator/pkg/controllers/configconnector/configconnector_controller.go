@@ -107,6 +107,7 @@ func newReconciler(mgr ctrl.Manager, opt *ReconcilerOptions) (*Reconciler, error
 	preflight := preflight.NewCompositePreflight([]declarative.Preflight{
 		preflight.NewNameChecker(mgr.GetClient(), k8s.ConfigConnectorAllowedName),
 		preflight.NewUpgradeChecker(mgr.GetClient(), repo),
+		preflight.NewActuationWindowChecker(),
 	})
 
 	r := &Reconciler{
@@ -864,6 +865,14 @@ func (r *Reconciler) applyControllerReconcilerCR(ctx context.Context, cr *custom
 		msg := fmt.Sprintf("failed to apply pprof customization %s: %v", cr.Name, err)
 		return r.handleApplyControllerReconcilerFailed(ctx, cr, msg)
 	}
+	if err := controllers.ApplyContainerGCPRateLimit(m, cr.Name, cr.Spec.GCPRateLimit); err != nil {
+		msg := fmt.Sprintf("failed to apply gcp rate limit customization %s: %v", cr.Name, err)
+		return r.handleApplyControllerReconcilerFailed(ctx, cr, msg)
+	}
+	if err := controllers.ApplyContainerTracing(m, cr.Name, cr.Spec.Tracing); err != nil {
+		msg := fmt.Sprintf("failed to apply tracing customization %s: %v", cr.Name, err)
+		return r.handleApplyControllerReconcilerFailed(ctx, cr, msg)
+	}
 	return r.handleApplyControllerReconcilerSucceeded(ctx, cr)
 }
 
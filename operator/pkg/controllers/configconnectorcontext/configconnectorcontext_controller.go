@@ -105,6 +105,7 @@ func newReconciler(mgr ctrl.Manager, opt *ReconcilerOptions) (*Reconciler, error
 		preflight.NewNameChecker(mgr.GetClient(), k8s.ConfigConnectorContextAllowedName),
 		preflight.NewUpgradeChecker(mgr.GetClient(), repo),
 		preflight.NewConfigConnectorContextChecker(),
+		preflight.NewActuationWindowChecker(),
 	})
 
 	r := &Reconciler{
@@ -554,6 +555,14 @@ func (r *Reconciler) applyNamespacedControllerReconciler(ctx context.Context, cr
 		msg := fmt.Sprintf("failed to apply pprof customization %s: %v", cr.Name, err)
 		return r.handleApplyNamespacedControllerReconcilerFailed(ctx, cr.Namespace, cr.Name, msg)
 	}
+	if err := controllers.ApplyContainerGCPRateLimit(m, cr.Name, cr.Spec.GCPRateLimit); err != nil {
+		msg := fmt.Sprintf("failed to apply gcp rate limit customization %s: %v", cr.Name, err)
+		return r.handleApplyNamespacedControllerReconcilerFailed(ctx, cr.Namespace, cr.Name, msg)
+	}
+	if err := controllers.ApplyContainerTracing(m, cr.Name, cr.Spec.Tracing); err != nil {
+		msg := fmt.Sprintf("failed to apply tracing customization %s: %v", cr.Name, err)
+		return r.handleApplyNamespacedControllerReconcilerFailed(ctx, cr.Namespace, cr.Name, msg)
+	}
 	return r.handleApplyNamespacedControllerReconcilerSucceeded(ctx, cr.Namespace, cr.Name)
 }
 
@@ -15,13 +15,17 @@
 package controllers
 
 import (
+	"context"
 	"reflect"
+	"strings"
 	"testing"
 
 	customizev1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/customize/v1beta1"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kubebuilder-declarative-pattern/pkg/patterns/declarative/pkg/manifest"
 )
 
 func TestValidateContainerResourceCustomizationValues(t *testing.T) {
@@ -351,3 +355,237 @@ func TestApplyRateLimitToContainerArg(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyGCPRateLimitToContainerArg(t *testing.T) {
+	tests := []struct {
+		desc         string
+		container    map[string]interface{}
+		gcpRateLimit *customizev1beta1.GCPRateLimit
+		want         map[string]interface{}
+		wantErr      bool
+	}{
+		{
+			desc: "default and per-service limits",
+			container: map[string]interface{}{
+				"args": []any{
+					"--prometheus-scrape-endpoint=:8888",
+					"--qps=20",
+				},
+			},
+			gcpRateLimit: &customizev1beta1.GCPRateLimit{
+				QPS:   10,
+				Burst: 20,
+				Services: []customizev1beta1.GCPServiceRateLimit{
+					{Service: "compute.googleapis.com", QPS: 5, Burst: 5},
+					{Service: "pubsub.googleapis.com", QPS: 50},
+				},
+			},
+			want: map[string]interface{}{
+				"args": []any{
+					"--gcp-qps=10",
+					"--gcp-burst=20",
+					"--gcp-service-rate-limit=compute.googleapis.com=5:5",
+					"--gcp-service-rate-limit=pubsub.googleapis.com=50",
+					"--prometheus-scrape-endpoint=:8888",
+					"--qps=20",
+				},
+			},
+		},
+		{
+			desc: "replaces existing gcp rate limit args",
+			container: map[string]interface{}{
+				"args": []any{
+					"--gcp-qps=1",
+					"--gcp-service-rate-limit=compute.googleapis.com=1",
+					"--burst=30",
+				},
+			},
+			gcpRateLimit: &customizev1beta1.GCPRateLimit{
+				QPS: 10,
+			},
+			want: map[string]interface{}{
+				"args": []any{
+					"--gcp-qps=10",
+					"--burst=30",
+				},
+			},
+		},
+		{
+			desc: "service without name",
+			container: map[string]interface{}{
+				"args": []any{},
+			},
+			gcpRateLimit: &customizev1beta1.GCPRateLimit{
+				Services: []customizev1beta1.GCPServiceRateLimit{{QPS: 5}},
+			},
+			wantErr: true,
+		},
+		{
+			desc: "service without qps",
+			container: map[string]interface{}{
+				"args": []any{},
+			},
+			gcpRateLimit: &customizev1beta1.GCPRateLimit{
+				QPS:      10,
+				Services: []customizev1beta1.GCPServiceRateLimit{{Service: "compute.googleapis.com", Burst: 5}},
+			},
+			wantErr: true,
+		},
+		{
+			desc: "nil gcp rate limit",
+			container: map[string]interface{}{
+				"args": []any{
+					"--prometheus-scrape-endpoint=:8888",
+				},
+			},
+			want: map[string]interface{}{
+				"args": []any{
+					"--prometheus-scrape-endpoint=:8888",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := applyGCPRateLimitToContainerArg(tc.container, tc.gcpRateLimit)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("applyGCPRateLimitToContainerArg: got error %v, want error %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(tc.container, tc.want) {
+				t.Errorf("applyGCPRateLimitToContainerArg: got container %v, want container %v", tc.container, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyTracingToContainerArg(t *testing.T) {
+	tests := []struct {
+		desc      string
+		container map[string]interface{}
+		tracing   *customizev1beta1.TracingConfig
+		want      map[string]interface{}
+		wantErr   bool
+	}{
+		{
+			desc: "endpoint and sampling percentage",
+			container: map[string]interface{}{
+				"args": []any{
+					"--prometheus-scrape-endpoint=:8888",
+				},
+			},
+			tracing: &customizev1beta1.TracingConfig{
+				OTLPEndpoint:       "http://otel-collector:4318",
+				SamplingPercentage: func(i int) *int { return &i }(25),
+			},
+			want: map[string]interface{}{
+				"args": []any{
+					"--otlp-endpoint=http://otel-collector:4318",
+					"--trace-sample-ratio=0.25",
+					"--prometheus-scrape-endpoint=:8888",
+				},
+			},
+		},
+		{
+			desc: "replaces existing tracing args",
+			container: map[string]interface{}{
+				"args": []any{
+					"--otlp-endpoint=http://old:4318",
+					"--trace-sample-ratio=0.5",
+					"--qps=20",
+				},
+			},
+			tracing: &customizev1beta1.TracingConfig{
+				OTLPEndpoint: "http://new:4318",
+			},
+			want: map[string]interface{}{
+				"args": []any{
+					"--otlp-endpoint=http://new:4318",
+					"--qps=20",
+				},
+			},
+		},
+		{
+			desc: "missing endpoint",
+			container: map[string]interface{}{
+				"args": []any{},
+			},
+			tracing: &customizev1beta1.TracingConfig{},
+			wantErr: true,
+		},
+		{
+			desc: "nil tracing",
+			container: map[string]interface{}{
+				"args": []any{
+					"--prometheus-scrape-endpoint=:8888",
+				},
+			},
+			want: map[string]interface{}{
+				"args": []any{
+					"--prometheus-scrape-endpoint=:8888",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := applyTracingToContainerArg(tc.container, tc.tracing)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("applyTracingToContainerArg: got error %v, want error %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(tc.container, tc.want) {
+				t.Errorf("applyTracingToContainerArg: got container %v, want container %v", tc.container, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyContainerTracing(t *testing.T) {
+	objects, err := manifest.ParseObjects(context.Background(), `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: cnrm-controller-manager
+  namespace: cnrm-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        args:
+        - --prometheus-scrape-endpoint=:8888
+      - name: prom-to-sd
+`)
+	if err != nil {
+		t.Fatalf("error parsing manifest: %v", err)
+	}
+	tracing := &customizev1beta1.TracingConfig{OTLPEndpoint: "otel-collector:4317"}
+
+	err = ApplyContainerTracing(objects, "cnrm-webhook-manager", tracing)
+	wantErr := "tracing customization for cnrm-webhook-manager is not supported. Supported controllers: cnrm-controller-manager"
+	if err == nil || !strings.Contains(err.Error(), wantErr) {
+		t.Fatalf("ApplyContainerTracing: got error %v, want error %q", err, wantErr)
+	}
+
+	if err := ApplyContainerTracing(objects, "cnrm-controller-manager", tracing); err != nil {
+		t.Fatalf("ApplyContainerTracing: got error %v", err)
+	}
+	containers, _, err := unstructured.NestedSlice(objects.Items[0].UnstructuredObject().Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		t.Fatalf("error getting containers: %v", err)
+	}
+	managerArgs, _, _ := unstructured.NestedStringSlice(containers[0].(map[string]interface{}), "args")
+	if len(managerArgs) <= 1 {
+		t.Errorf("got manager args %v, want the tracing args to be added", managerArgs)
+	}
+	if _, found, _ := unstructured.NestedStringSlice(containers[1].(map[string]interface{}), "args"); found {
+		t.Errorf("got args in container 'prom-to-sd', want only the manager container to be customized")
+	}
+}
@@ -17,6 +17,8 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	customizev1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/customize/v1beta1"
@@ -676,3 +678,152 @@ func applyPprofConfigToContainerArg(container map[string]interface{}, pprofConfi
 	}
 	return nil
 }
+
+func ApplyContainerGCPRateLimit(m *manifest.Objects, targetControllerName string, gcpRateLimit *customizev1beta1.GCPRateLimit) error {
+	if gcpRateLimit == nil {
+		return nil
+	}
+	return applyToControllerContainer(m, targetControllerName, "gcp rate limit", customizev1beta1.ValidRateLimitControllers,
+		func(container map[string]interface{}) error {
+			return applyGCPRateLimitToContainerArg(container, gcpRateLimit)
+		})
+}
+
+func applyGCPRateLimitToContainerArg(container map[string]interface{}, gcpRateLimit *customizev1beta1.GCPRateLimit) error {
+	if gcpRateLimit == nil {
+		return nil
+	}
+	origArgs, found, err := unstructured.NestedStringSlice(container, "args")
+	if err != nil {
+		return fmt.Errorf("error getting args in container: %w", err)
+	}
+	wantArgs := []string{}
+	if gcpRateLimit.QPS > 0 {
+		wantArgs = append(wantArgs, fmt.Sprintf("--gcp-qps=%d", gcpRateLimit.QPS))
+	}
+	if gcpRateLimit.Burst > 0 {
+		wantArgs = append(wantArgs, fmt.Sprintf("--gcp-burst=%d", gcpRateLimit.Burst))
+	}
+	for _, s := range gcpRateLimit.Services {
+		if s.Service == "" {
+			return fmt.Errorf("gcp rate limit for a service must specify the service name")
+		}
+		// A qps of 0 would mean that the requests to the service are not rate limited at all.
+		if s.QPS <= 0 {
+			return fmt.Errorf("gcp rate limit for service %s must specify a positive qps", s.Service)
+		}
+		arg := fmt.Sprintf("--gcp-service-rate-limit=%s=%d", s.Service, s.QPS)
+		if s.Burst > 0 {
+			arg = fmt.Sprintf("%s:%d", arg, s.Burst)
+		}
+		wantArgs = append(wantArgs, arg)
+	}
+	if found {
+		for _, arg := range origArgs {
+			if strings.Contains(arg, "--gcp-qps") || strings.Contains(arg, "--gcp-burst") || strings.Contains(arg, "--gcp-service-rate-limit") {
+				// drop the old value on the floor
+				continue
+			}
+			wantArgs = append(wantArgs, arg)
+		}
+	}
+	if err := unstructured.SetNestedStringSlice(container, wantArgs, "args"); err != nil {
+		return fmt.Errorf("error setting args in container: %w", err)
+	}
+	return nil
+}
+
+func ApplyContainerTracing(m *manifest.Objects, targetControllerName string, tracing *customizev1beta1.TracingConfig) error {
+	if tracing == nil {
+		return nil
+	}
+	return applyToControllerContainer(m, targetControllerName, "tracing", customizev1beta1.SupportedTracingControllers,
+		func(container map[string]interface{}) error {
+			return applyTracingToContainerArg(container, tracing)
+		})
+}
+
+// applyToControllerContainer applies the given customization to the container of the target
+// controller in the manifest. The customization is only supported for the given controllers.
+func applyToControllerContainer(m *manifest.Objects, targetControllerName, customization string, supportedControllers []string,
+	applyFn func(container map[string]interface{}) error) error {
+	var (
+		targetContainerName string
+		targetControllerGVK schema.GroupVersionKind
+	)
+	switch targetControllerName {
+	case "cnrm-controller-manager":
+		targetContainerName = "manager"
+		targetControllerGVK = schema.GroupVersionKind{
+			Group:   appsv1.SchemeGroupVersion.Group,
+			Version: appsv1.SchemeGroupVersion.Version,
+			Kind:    "StatefulSet",
+		}
+	}
+	if targetContainerName == "" || !slices.Contains(supportedControllers, targetControllerName) {
+		return fmt.Errorf("%s customization for %s is not supported. "+
+			"Supported controllers: %s",
+			customization, targetControllerName, strings.Join(supportedControllers, ", "))
+	}
+
+	count := 0
+	for _, item := range m.Items {
+		if item.GroupVersionKind() != targetControllerGVK {
+			continue
+		}
+		if !strings.HasPrefix(item.GetName(), targetControllerName) {
+			continue
+		}
+		err := item.MutateContainers(func(container map[string]interface{}) error {
+			name, _, err := unstructured.NestedString(container, "name")
+			if err != nil {
+				return fmt.Errorf("error reading container name: %w", err)
+			}
+			if name != targetContainerName {
+				return nil
+			}
+			return applyFn(container)
+		})
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	if count != 1 {
+		return fmt.Errorf("%s customization for %s modified %d instances.", customization, targetControllerName, count)
+	}
+	return nil
+}
+
+func applyTracingToContainerArg(container map[string]interface{}, tracing *customizev1beta1.TracingConfig) error {
+	if tracing == nil {
+		return nil
+	}
+	if tracing.OTLPEndpoint == "" {
+		return fmt.Errorf("tracing must specify the OTLP endpoint")
+	}
+	origArgs, found, err := unstructured.NestedStringSlice(container, "args")
+	if err != nil {
+		return fmt.Errorf("error getting args in container: %w", err)
+	}
+	wantArgs := []string{fmt.Sprintf("--otlp-endpoint=%s", tracing.OTLPEndpoint)}
+	if p := tracing.SamplingPercentage; p != nil {
+		if *p < 0 || *p > 100 {
+			return fmt.Errorf("tracing sampling percentage must be between 0 and 100, got %d", *p)
+		}
+		wantArgs = append(wantArgs, fmt.Sprintf("--trace-sample-ratio=%s", strconv.FormatFloat(float64(*p)/100, 'f', -1, 64)))
+	}
+	if found {
+		for _, arg := range origArgs {
+			if strings.Contains(arg, "--otlp-endpoint") || strings.Contains(arg, "--trace-sample-ratio") {
+				// drop the old value on the floor
+				continue
+			}
+			wantArgs = append(wantArgs, arg)
+		}
+	}
+	if err := unstructured.SetNestedStringSlice(container, wantArgs, "args"); err != nil {
+		return fmt.Errorf("error setting args in container: %w", err)
+	}
+	return nil
+}
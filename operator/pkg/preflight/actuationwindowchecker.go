@@ -0,0 +1,50 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preflight
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/actuationwindow"
+	corev1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/v1beta1"
+
+	"sigs.k8s.io/kubebuilder-declarative-pattern/pkg/patterns/declarative"
+)
+
+// ActuationWindowChecker validates the actuation windows of the ConfigConnector and
+// ConfigConnectorContext objects.
+type ActuationWindowChecker struct {
+}
+
+func NewActuationWindowChecker() *ActuationWindowChecker {
+	return &ActuationWindowChecker{}
+}
+
+func (c *ActuationWindowChecker) Preflight(_ context.Context, o declarative.DeclarativeObject) error {
+	var windows []corev1beta1.ActuationWindow
+	switch obj := o.(type) {
+	case *corev1beta1.ConfigConnector:
+		windows = obj.Spec.ActuationWindows
+	case *corev1beta1.ConfigConnectorContext:
+		windows = obj.Spec.ActuationWindows
+	default:
+		return fmt.Errorf("expected the resource to be a ConfigConnector or ConfigConnectorContext, but it was not. Object: %v", o)
+	}
+	if err := actuationwindow.Validate(windows); err != nil {
+		return fmt.Errorf("invalid spec.actuationWindows: %w", err)
+	}
+	return nil
+}
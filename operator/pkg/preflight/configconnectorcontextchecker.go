@@ -57,6 +57,15 @@ func (c *ConfigConnectorContextChecker) Preflight(_ context.Context, o declarati
 		return err
 	}
 
+	for i, rule := range ccc.Spec.CredentialRules {
+		if rule.GoogleServiceAccount == "" {
+			return fmt.Errorf("spec.credentialRules[%d].googleServiceAccount must be set", i)
+		}
+		if err := validateGSAFormat(rule.GoogleServiceAccount); err != nil {
+			return fmt.Errorf("invalid spec.credentialRules[%d]: %w", i, err)
+		}
+	}
+
 	return nil
 }
 
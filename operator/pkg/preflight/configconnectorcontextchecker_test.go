@@ -157,6 +157,64 @@ func TestConfigConnectorContextChecker(t *testing.T) {
 			},
 			err: fmt.Errorf("spec.billingProject cannot be set if spec.requestProjectPolicy is not set to %v", k8s.BillingProjectPolicy),
 		},
+
+		{
+			name: "CCC has valid spec.credentialRules",
+			ccc: &corev1beta1.ConfigConnectorContext{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      k8s.ConfigConnectorContextAllowedName,
+					Namespace: "foo-ns",
+				},
+				Spec: corev1beta1.ConfigConnectorContextSpec{
+					GoogleServiceAccount: "foo@bar.iam.gserviceaccount.com",
+					CredentialRules: []corev1beta1.CredentialRule{
+						{
+							GoogleServiceAccount: "iam-admin@bar.iam.gserviceaccount.com",
+							Kinds:                []string{"IAMPolicyMember"},
+						},
+					},
+				},
+			},
+			err: nil,
+		},
+
+		{
+			name: "CCC has spec.credentialRules with an invalid googleServiceAccount",
+			ccc: &corev1beta1.ConfigConnectorContext{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      k8s.ConfigConnectorContextAllowedName,
+					Namespace: "foo-ns",
+				},
+				Spec: corev1beta1.ConfigConnectorContextSpec{
+					GoogleServiceAccount: "foo@bar.iam.gserviceaccount.com",
+					CredentialRules: []corev1beta1.CredentialRule{
+						{
+							GoogleServiceAccount: "iam-admin",
+						},
+					},
+				},
+			},
+			err: fmt.Errorf("invalid spec.credentialRules[0]: invalid GoogleServiceAccount format for %q", "iam-admin"),
+		},
+
+		{
+			name: "CCC has spec.credentialRules with an empty googleServiceAccount",
+			ccc: &corev1beta1.ConfigConnectorContext{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      k8s.ConfigConnectorContextAllowedName,
+					Namespace: "foo-ns",
+				},
+				Spec: corev1beta1.ConfigConnectorContextSpec{
+					GoogleServiceAccount: "foo@bar.iam.gserviceaccount.com",
+					CredentialRules: []corev1beta1.CredentialRule{
+						{
+							Projects: []string{"my-project"},
+						},
+					},
+				},
+			},
+			err: fmt.Errorf("spec.credentialRules[0].googleServiceAccount must be set"),
+		},
 	}
 
 	checker := NewConfigConnectorContextChecker()
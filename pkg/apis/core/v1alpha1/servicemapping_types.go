@@ -201,6 +201,11 @@ type ResourceConfig struct {
 	// The fields should be snake case paths in TF. For example,
 	// `master_auth.client_certificate`.
 	ObservedFields *[]string `json:"observedFields,omitempty"`
+
+	// TracksOperations tells if the direct controller of the resource records
+	// its GCP long-running operations under `status.pendingOperation` in the
+	// CRD, rather than waiting for them to be done.
+	TracksOperations bool `json:"tracksOperations,omitempty"`
 }
 
 type IAMConfig struct {
@@ -49,6 +49,9 @@ type IAMAuditConfigStatus struct {
 	// If this is equal to metadata.generation, then that means that the current reported status reflects the most recent desired state of the resource.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Etag is the etag of the IAM policy most recently applied by Config Connector.
+	// +optional
+	Etag string `json:"etag,omitempty"`
 }
 
 // +genclient
@@ -0,0 +1,117 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/k8s/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IAMDenyRule denies principals the use of permissions, unless an exception applies.
+type IAMDenyRule struct {
+	// Required. The identities that are prevented from using one or more
+	// permissions on Google Cloud resources, e.g.
+	// 'principal://goog/subject/user@example.com' or
+	// 'principalSet://goog/public:all'.
+	DeniedPrincipals []string `json:"deniedPrincipals"`
+	// Optional. The identities that are excluded from the deny rule, even if
+	// they are listed in deniedPrincipals.
+	ExceptionPrincipals []string `json:"exceptionPrincipals,omitempty"`
+	// Required. The permissions that are explicitly denied by this rule, in
+	// the format '{service_fqdn}/{resource}.{verb}', e.g.
+	// 'iam.googleapis.com/roles.list'.
+	DeniedPermissions []string `json:"deniedPermissions"`
+	// Optional. The permissions that are excluded from the set of denied
+	// permissions.
+	ExceptionPermissions []string `json:"exceptionPermissions,omitempty"`
+	// Optional. The condition that determines whether this deny rule applies
+	// to a request.
+	DenialCondition *IAMCondition `json:"denialCondition,omitempty"`
+}
+
+// IAMDenyPolicyRule is a rule of an IAM deny policy.
+type IAMDenyPolicyRule struct {
+	// Optional. A user-specified description of the rule.
+	Description string `json:"description,omitempty"`
+	// Required. The deny rule.
+	DenyRule IAMDenyRule `json:"denyRule"`
+}
+
+// IAMDenyPolicySpec defines the desired state of IAMDenyPolicy
+type IAMDenyPolicySpec struct {
+	// Immutable. Required. The GCP resource the deny policy is attached to.
+	// Only Project, Folder and Organization are supported.
+	ResourceReference ResourceReference `json:"resourceRef"`
+	// Immutable. Optional. The ID of the deny policy. If not given, the
+	// metadata.name of the resource is used.
+	ResourceID *string `json:"resourceID,omitempty"`
+	// Optional. A user-specified description of the deny policy.
+	DisplayName string `json:"displayName,omitempty"`
+	// Required. The rules of the deny policy.
+	Rules []IAMDenyPolicyRule `json:"rules"`
+}
+
+// IAMDenyPolicyStatus defines the observed state of IAMDenyPolicy
+type IAMDenyPolicyStatus struct {
+	// Conditions represent the latest available observations of the IAM
+	// deny policy's current state.
+	Conditions []v1alpha1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the resource that was most recently observed by the Config Connector controller.
+	// If this is equal to metadata.generation, then that means that the current reported status reflects the most recent desired state of the resource.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Etag is the etag of the deny policy most recently applied by Config Connector.
+	// +optional
+	Etag string `json:"etag,omitempty"`
+	// UID is the globally unique ID of the deny policy.
+	// +optional
+	UID string `json:"uid,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IAMDenyPolicy is the Schema for the iamdenypolicies API
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type=='Ready')].status",description="When 'True' the most recent reconcile of the resource succeeded"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.conditions[?(@.type=='Ready')].reason",description="The reason for the value in 'Ready'"
+// +kubebuilder:printcolumn:name="Status Age",type="date",JSONPath=".status.conditions[?(@.type=='Ready')].lastTransitionTime"
+// +kubebuilder:subresource:status
+// +k8s:openapi-gen=true
+type IAMDenyPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IAMDenyPolicySpec   `json:"spec,omitempty"`
+	Status IAMDenyPolicyStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IAMDenyPolicyList contains a list of IAMDenyPolicy
+type IAMDenyPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IAMDenyPolicy `json:"items"`
+}
+
+const IAMDenyPolicyReconcileInterval = 10 * time.Minute
+
+func init() {
+	SchemeBuilder.Register(&IAMDenyPolicy{}, &IAMDenyPolicyList{})
+}
@@ -66,6 +66,13 @@ type IAMPartialPolicyStatus struct {
 	LastAppliedBindings []IAMPolicyBinding `json:"lastAppliedBindings,omitempty"`
 	// AllBindings surfaces all IAM bindings for the referenced resource.
 	AllBindings []IAMPolicyBinding `json:"allBindings,omitempty"`
+	// Etag is the etag of the IAM policy most recently applied by Config Connector.
+	// +optional
+	Etag string `json:"etag,omitempty"`
+	// Version is the version of the IAM policy most recently applied by Config Connector:
+	// 3 if the policy has conditional bindings, and 1 otherwise.
+	// +optional
+	Version int64 `json:"version,omitempty"`
 }
 
 // +genclient
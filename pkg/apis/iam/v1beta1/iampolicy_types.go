@@ -72,6 +72,13 @@ type IAMPolicyStatus struct {
 	// If this is equal to metadata.generation, then that means that the current reported status reflects the most recent desired state of the resource.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Etag is the etag of the IAM policy most recently applied by Config Connector.
+	// +optional
+	Etag string `json:"etag,omitempty"`
+	// Version is the version of the IAM policy most recently applied by Config Connector:
+	// 3 if the policy has conditional bindings, and 1 otherwise.
+	// +optional
+	Version int64 `json:"version,omitempty"`
 }
 
 // +genclient
@@ -167,6 +167,175 @@ func (in *IAMCondition) DeepCopy() *IAMCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IAMDenyPolicy) DeepCopyInto(out *IAMDenyPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMDenyPolicy.
+func (in *IAMDenyPolicy) DeepCopy() *IAMDenyPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(IAMDenyPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IAMDenyPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IAMDenyPolicyList) DeepCopyInto(out *IAMDenyPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IAMDenyPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMDenyPolicyList.
+func (in *IAMDenyPolicyList) DeepCopy() *IAMDenyPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(IAMDenyPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IAMDenyPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IAMDenyPolicyRule) DeepCopyInto(out *IAMDenyPolicyRule) {
+	*out = *in
+	in.DenyRule.DeepCopyInto(&out.DenyRule)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMDenyPolicyRule.
+func (in *IAMDenyPolicyRule) DeepCopy() *IAMDenyPolicyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(IAMDenyPolicyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IAMDenyPolicySpec) DeepCopyInto(out *IAMDenyPolicySpec) {
+	*out = *in
+	out.ResourceReference = in.ResourceReference
+	if in.ResourceID != nil {
+		in, out := &in.ResourceID, &out.ResourceID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]IAMDenyPolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMDenyPolicySpec.
+func (in *IAMDenyPolicySpec) DeepCopy() *IAMDenyPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IAMDenyPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IAMDenyPolicyStatus) DeepCopyInto(out *IAMDenyPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1alpha1.Condition, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMDenyPolicyStatus.
+func (in *IAMDenyPolicyStatus) DeepCopy() *IAMDenyPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IAMDenyPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IAMDenyRule) DeepCopyInto(out *IAMDenyRule) {
+	*out = *in
+	if in.DeniedPrincipals != nil {
+		in, out := &in.DeniedPrincipals, &out.DeniedPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExceptionPrincipals != nil {
+		in, out := &in.ExceptionPrincipals, &out.ExceptionPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedPermissions != nil {
+		in, out := &in.DeniedPermissions, &out.DeniedPermissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExceptionPermissions != nil {
+		in, out := &in.ExceptionPermissions, &out.ExceptionPermissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DenialCondition != nil {
+		in, out := &in.DenialCondition, &out.DenialCondition
+		*out = new(IAMCondition)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMDenyRule.
+func (in *IAMDenyRule) DeepCopy() *IAMDenyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(IAMDenyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IAMPartialPolicy) DeepCopyInto(out *IAMPartialPolicy) {
 	*out = *in
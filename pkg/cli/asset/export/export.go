@@ -59,7 +59,7 @@ func ForParentToStorageObject(ctx context.Context, httpClient *http.Client, pare
 	if err != nil {
 		return fmt.Errorf("error response from exportassets request: %w", err)
 	}
-	if _, err := gcp.WaitForAssetInventoryOperationDefaultTimeout(assetClient, op, projectNumString, nil); err != nil {
+	if _, err := gcp.WaitForAssetInventoryOperationDefaultTimeout(ctx, assetClient, op, projectNumString, nil); err != nil {
 		return fmt.Errorf("error waiting for operation: %w", err)
 	}
 	return nil
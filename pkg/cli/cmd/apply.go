@@ -33,8 +33,8 @@ var (
 	applyCmd    = &cobra.Command{
 		Use:    applyCommandName,
 		Hidden: true,
-		Short:  "Apply a KRM resource configuration file to Google Cloud Platform backend",
-		Long:   `Apply a KRM resource configuration file to Google Cloud Platform backend`,
+		Short:  "Apply KRM resource configuration files to Google Cloud Platform backend",
+		Long:   `Apply KRM resource configuration files to Google Cloud Platform backend, in the order of the references between the resources`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			if err := parameters.Validate(&applyParams, os.Stdin); err != nil {
@@ -49,6 +49,6 @@ var (
 
 func init() {
 	commonparams.AddOAuth2TokenParam(applyCmd, &applyParams.OAuth2Token)
-	inputUsage := "the input file path containing the KRM file to be applied."
+	inputUsage := "the input file or directory path containing the KRM resources to be applied; multiple resources are applied in the order of their references."
 	applyCmd.Flags().StringVarP(&applyParams.Input, parameters.InputParam, "i", "", inputUsage)
 }
@@ -0,0 +1,92 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"context"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type appliedKey struct {
+	gk  schema.GroupKind
+	key client.ObjectKey
+}
+
+// appliedResourcesClient is a kubernetes client which serves the resources
+// applied so far, so that the references of the resources applied next
+// resolve to them. All the other calls fail.
+type appliedResourcesClient struct {
+	client.Client
+	resources map[appliedKey]*unstructured.Unstructured
+}
+
+func newAppliedResourcesClient() *appliedResourcesClient {
+	return &appliedResourcesClient{
+		Client:    k8s.NewErroringClient(),
+		resources: make(map[appliedKey]*unstructured.Unstructured),
+	}
+}
+
+// add records the given applied resource as ready, which is what the
+// resolution of the references to it requires.
+func (c *appliedResourcesClient) add(u *unstructured.Unstructured) error {
+	resource, err := k8s.NewResource(u.DeepCopy())
+	if err != nil {
+		return err
+	}
+	condition, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ptr(k8s.NewCustomReadyCondition(corev1.ConditionTrue, k8s.UpToDate, k8s.UpToDateMessage)))
+	if err != nil {
+		return err
+	}
+	if resource.Status == nil {
+		resource.Status = make(map[string]interface{})
+	}
+	resource.Status["conditions"] = []interface{}{condition}
+	ready, err := resource.MarshalAsUnstructured()
+	if err != nil {
+		return err
+	}
+	key := appliedKey{gk: u.GroupVersionKind().GroupKind(), key: client.ObjectKeyFromObject(u)}
+	c.resources[key] = ready
+	return nil
+}
+
+func (c *appliedResourcesClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return c.Client.Get(ctx, key, obj, opts...)
+	}
+	gk := u.GroupVersionKind().GroupKind()
+	applied, ok := c.resources[appliedKey{gk: gk, key: key}]
+	if !ok {
+		return apierrors.NewNotFound(schema.GroupResource{Group: gk.Group, Resource: gk.Kind}, key.Name)
+	}
+	gvk := u.GroupVersionKind()
+	u.Object = applied.DeepCopy().Object
+	u.SetGroupVersionKind(gvk)
+	return nil
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -18,12 +18,16 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/cmd/apply/parameters"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/cmd/apply/yamlresource"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/gcpclient"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/tf"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/resourcegraph"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func Execute(ctx context.Context, params *parameters.Parameters, output io.Writer) error {
@@ -36,15 +40,55 @@ func Execute(ctx context.Context, params *parameters.Parameters, output io.Write
 		return fmt.Errorf("error loading service mappings: %w", err)
 	}
 
-	usFromFile, err := yamlresource.UnstructuredFromYamlFile(params.Input)
+	objects, err := resourcegraph.LoadFiles([]string{params.Input})
 	if err != nil {
-		return fmt.Errorf("error loading resource from file: %w", err)
+		return fmt.Errorf("error loading resources from file: %w", err)
 	}
-	client := gcpclient.New(tfProvider, smLoader)
-	appliedResource, err := client.Apply(usFromFile)
-	if err != nil {
-		return fmt.Errorf("error applying resource from file: %w", err)
+	if len(objects) == 1 {
+		client := gcpclient.New(tfProvider, smLoader)
+		appliedResource, err := client.Apply(ctx, objects[0])
+		if err != nil {
+			return fmt.Errorf("error applying resource from file: %w", err)
+		}
+		return yamlresource.RenderJSON(appliedResource, output)
 	}
+	kubeClient := newAppliedResourcesClient()
+	return applyInDependencyOrder(ctx, objects, gcpclient.NewWithKubeClient(tfProvider, smLoader, kubeClient), kubeClient, output)
+}
 
-	return yamlresource.RenderJSON(appliedResource, output)
+// applyInDependencyOrder applies the given resources such that each resource
+// is applied after the resources it references, which are resolved from the
+// resources applied before it.
+func applyInDependencyOrder(ctx context.Context, objects []*unstructured.Unstructured, client gcpclient.Client, applied *appliedResourcesClient, output io.Writer) error {
+	g, err := resourcegraph.Build(objects)
+	if err != nil {
+		return fmt.Errorf("error building resource graph: %w", err)
+	}
+	if dangling := g.Dangling(); len(dangling) > 0 {
+		var refs []string
+		for _, ref := range dangling {
+			refs = append(refs, fmt.Sprintf("%v %v: %v", ref.From, ref.Field, ref.Reason))
+		}
+		return fmt.Errorf("references to resources which are not in the input cannot be resolved, use 'external' instead: %v", strings.Join(refs, "; "))
+	}
+	order, err := g.CreateOrder()
+	if err != nil {
+		return err
+	}
+	for _, id := range order {
+		appliedResource, err := client.Apply(ctx, g.Object(id))
+		if err != nil {
+			return fmt.Errorf("error applying resource %v: %w", id, err)
+		}
+		if err := applied.add(appliedResource); err != nil {
+			return fmt.Errorf("error recording applied resource %v: %w", id, err)
+		}
+		if err := yamlresource.RenderJSON(appliedResource, output); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(output); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,203 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/resourcegraph"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	network = `
+apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeNetwork
+metadata:
+  name: net
+  namespace: ns
+`
+	subnetwork = `
+apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeSubnetwork
+metadata:
+  name: subnet
+  namespace: ns
+spec:
+  networkRef:
+    name: net
+`
+	router = `
+apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeRouter
+metadata:
+  name: router
+  namespace: ns
+spec:
+  networkRef:
+    name: net
+  subnetworkRef:
+    name: subnet
+`
+	cycleA = `
+apiVersion: test.cnrm.cloud.google.com/v1beta1
+kind: TestA
+metadata:
+  name: a
+  namespace: ns
+spec:
+  testBRef:
+    name: b
+`
+	cycleB = `
+apiVersion: test.cnrm.cloud.google.com/v1beta1
+kind: TestB
+metadata:
+  name: b
+  namespace: ns
+spec:
+  testARef:
+    name: a
+`
+)
+
+// fakeGCPClient records the order of the applies, and checks that the
+// resources each resource references were applied before it, as the
+// references are resolved through the kube client.
+type fakeGCPClient struct {
+	t       *testing.T
+	applied *appliedResourcesClient
+	order   []string
+}
+
+func (c *fakeGCPClient) Get(_ context.Context, _ *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	c.t.Fatalf("unimplemented")
+	return nil, nil
+}
+
+func (c *fakeGCPClient) Apply(ctx context.Context, u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	spec, _, _ := unstructured.NestedMap(u.Object, "spec")
+	for field, v := range spec {
+		ref, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		dep := &unstructured.Unstructured{}
+		dep.SetAPIVersion(u.GetAPIVersion())
+		dep.SetKind(referencedKind(field))
+		key := client.ObjectKey{Namespace: u.GetNamespace(), Name: ref["name"].(string)}
+		if err := c.applied.Get(ctx, key, dep); err != nil {
+			c.t.Errorf("%v %v applied before its reference %v: %v", u.GetKind(), u.GetName(), field, err)
+			continue
+		}
+		resource, err := k8s.NewResource(dep)
+		if err != nil {
+			c.t.Fatalf("error parsing applied resource: %v", err)
+		}
+		if !k8s.IsResourceReady(resource) {
+			c.t.Errorf("referenced resource %v %v is not ready", dep.GetKind(), dep.GetName())
+		}
+	}
+	c.order = append(c.order, u.GetKind()+" "+u.GetName())
+	return u.DeepCopy(), nil
+}
+
+func (c *fakeGCPClient) Delete(_ *unstructured.Unstructured) error {
+	c.t.Fatalf("unimplemented")
+	return nil
+}
+
+func (c *fakeGCPClient) IsSupported(_ string) bool {
+	return true
+}
+
+func referencedKind(field string) string {
+	switch field {
+	case "networkRef":
+		return "ComputeNetwork"
+	case "subnetworkRef":
+		return "ComputeSubnetwork"
+	}
+	return ""
+}
+
+func parseObjects(t *testing.T, manifests ...string) []*unstructured.Unstructured {
+	t.Helper()
+	var objects []*unstructured.Unstructured
+	for _, m := range manifests {
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(m), &u.Object); err != nil {
+			t.Fatalf("error parsing test object: %v", err)
+		}
+		objects = append(objects, u)
+	}
+	return objects
+}
+
+func TestApplyInDependencyOrder(t *testing.T) {
+	ctx := context.Background()
+	applied := newAppliedResourcesClient()
+	gcpClient := &fakeGCPClient{t: t, applied: applied}
+	var output bytes.Buffer
+	objects := parseObjects(t, router, subnetwork, network)
+	if err := applyInDependencyOrder(ctx, objects, gcpClient, applied, &output); err != nil {
+		t.Fatalf("error applying resources: %v", err)
+	}
+	expectedOrder := []string{"ComputeNetwork net", "ComputeSubnetwork subnet", "ComputeRouter router"}
+	if diff := cmp.Diff(expectedOrder, gcpClient.order); diff != "" {
+		t.Errorf("unexpected apply order (-want +got):\n%v", diff)
+	}
+	if output.Len() == 0 {
+		t.Errorf("got no output, want the applied resources")
+	}
+}
+
+func TestApplyInDependencyOrderCycle(t *testing.T) {
+	ctx := context.Background()
+	applied := newAppliedResourcesClient()
+	gcpClient := &fakeGCPClient{t: t, applied: applied}
+	var output bytes.Buffer
+	objects := parseObjects(t, cycleA, cycleB)
+	err := applyInDependencyOrder(ctx, objects, gcpClient, applied, &output)
+	var cycleErr *resourcegraph.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("got error %v, want a cycle error", err)
+	}
+	if len(gcpClient.order) != 0 {
+		t.Errorf("got applies %v, want none", gcpClient.order)
+	}
+}
+
+func TestApplyInDependencyOrderDanglingReference(t *testing.T) {
+	ctx := context.Background()
+	applied := newAppliedResourcesClient()
+	gcpClient := &fakeGCPClient{t: t, applied: applied}
+	var output bytes.Buffer
+	objects := parseObjects(t, router, subnetwork)
+	if err := applyInDependencyOrder(ctx, objects, gcpClient, applied, &output); err == nil {
+		t.Fatalf("got no error, want an error for the reference to the missing network")
+	}
+	if len(gcpClient.order) != 0 {
+		t.Errorf("got applies %v, want none", gcpClient.order)
+	}
+}
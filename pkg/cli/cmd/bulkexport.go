@@ -27,6 +27,7 @@ import (
 
 const (
 	onErrorDefault        = parameters.HaltOnErrorOption
+	discoveryDefault      = parameters.AssetInventoryDiscoveryOption
 	bulkExportCommandName = "bulk-export"
 )
 
@@ -56,6 +57,7 @@ func init() {
 	commonparams.AddFilterDeletedIAMMembersParam(bulkExportCmd, &bulkExportParams.FilterDeletedIAMMembers)
 	commonparams.AddOutputParam(bulkExportCmd, &bulkExportParams.Output)
 	commonparams.AddResourceFormatParam(bulkExportCmd, &bulkExportParams.ResourceFormat)
+	commonparams.AddTFStateOutputParam(bulkExportCmd, &bulkExportParams.TFStateOutput)
 	inputUsage := fmt.Sprintf("an optional input file path containing an asset inventory export, cannot be used with piped input or '%v'", parameters.StorageKeyParam)
 	bulkExportCmd.Flags().StringVarP(&bulkExportParams.Input, parameters.InputParam, "i", "", inputUsage)
 	onErrorUsage := fmt.Sprintf("control the behavior when a recoverable error occurs, options are '%v', '%v', or '%v'", parameters.ContinueOnErrorOption, parameters.HaltOnErrorOption, parameters.IgnoreOnErrorOption)
@@ -68,6 +70,9 @@ func init() {
 	bulkExportCmd.Flags().IntVar(&bulkExportParams.FolderID, parameters.FolderIDParam, 0, folderUsage)
 	organizationUsage := fmt.Sprintf("an optional organization id for which a cloud asset inventory will be exported to a temporary bucket; use the '%v' parameter to avoid the creation of a temporary bucket", parameters.StorageKeyParam)
 	bulkExportCmd.Flags().IntVar(&bulkExportParams.OrganizationID, parameters.OrganizationIDParam, 0, organizationUsage)
+	discoveryUsage := fmt.Sprintf("control how resources are discovered, options are '%v' or '%v'; with '%v', resources are discovered with the List APIs of the services instead of cloud asset inventory, which requires the '%v' parameter and only finds resources supported by direct controllers",
+		parameters.AssetInventoryDiscoveryOption, parameters.ListDiscoveryOption, parameters.ListDiscoveryOption, parameters.ProjectIDParam)
+	bulkExportCmd.Flags().StringVar(&bulkExportParams.Discovery, parameters.DiscoveryParam, discoveryDefault, discoveryUsage)
 }
 
 func fillRootFlagsOnBulkExportParams(params *parameters.Parameters) {
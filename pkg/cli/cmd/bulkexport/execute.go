@@ -30,6 +30,7 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/stream"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/tf"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -50,17 +51,25 @@ func Execute(ctx context.Context, params *parameters.Parameters) error {
 		return err
 	}
 
-	assetStream, err := newFilteredAssetStream(ctx, params, tfProvider)
-	if err != nil {
-		return err
-	}
-	defer assetStream.Close()
-	yamlStream, err := outputstream.NewResourceByteStream(tfProvider, params, assetStream)
-	if err != nil {
-		return err
+	var yamlStream stream.ByteStream
+	if params.Discovery == parameters.ListDiscoveryOption {
+		yamlStream, err = outputstream.NewListResourceByteStream(tfProvider, params)
+		if err != nil {
+			return err
+		}
+	} else {
+		assetStream, err := newFilteredAssetStream(ctx, params, tfProvider)
+		if err != nil {
+			return err
+		}
+		defer assetStream.Close()
+		yamlStream, err = outputstream.NewResourceByteStream(tfProvider, params, assetStream)
+		if err != nil {
+			return err
+		}
 	}
 	recoverableStream := stream.NewRecoverableByteStream(yamlStream)
-	outputSink, err := outputsink.New(tfProvider, params.Output, outputsink.ResourceFormat(params.ResourceFormat))
+	outputSink, err := newOutputSink(tfProvider, params)
 	if err != nil {
 		return err
 	}
@@ -78,9 +87,28 @@ func Execute(ctx context.Context, params *parameters.Parameters) error {
 			}
 		}
 	}
+	// The terraform state is only written once all resources have been exported successfully.
+	if tfStateSink, ok := outputSink.(*outputsink.TFStateSink); ok {
+		return tfStateSink.WriteState()
+	}
 	return nil
 }
 
+func newOutputSink(tfProvider *schema.Provider, params *parameters.Parameters) (outputsink.OutputSink, error) {
+	outputSink, err := outputsink.New(tfProvider, params.Output, outputsink.ResourceFormat(params.ResourceFormat))
+	if err != nil {
+		return nil, err
+	}
+	if params.TFStateOutput == "" {
+		return outputSink, nil
+	}
+	smLoader, err := servicemappingloader.New()
+	if err != nil {
+		return nil, fmt.Errorf("error creating service mapping loader: %w", err)
+	}
+	return outputsink.NewTFStateSink(outputSink, params.TFStateOutput, smLoader, tfProvider), nil
+}
+
 func newFilteredAssetStream(ctx context.Context, params *parameters.Parameters, tfProvider *schema.Provider) (stream.AssetStream, error) {
 	config := params.ControllerConfig()
 
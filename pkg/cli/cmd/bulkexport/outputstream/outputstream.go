@@ -42,6 +42,21 @@ func NewResourceByteStream(tfProvider *schema.Provider, params *parameters.Param
 	return stream.NewByteStream(outputsink.ResourceFormat(params.ResourceFormat), unstructuredStream, smLoader, tfProvider)
 }
 
+// NewListResourceByteStream returns a stream of the resources in the project, discovered with the List APIs
+// of the direct controllers rather than from an asset inventory export.
+func NewListResourceByteStream(tfProvider *schema.Provider, params *parameters.Parameters) (stream.ByteStream, error) {
+	smLoader, err := servicemappingloader.New()
+	if err != nil {
+		return nil, fmt.Errorf("error creating service mapping loader: %w", err)
+	}
+	listStream := stream.NewUnstructuredResourceStreamFromList("projects/"+params.ProjectID, params.ControllerConfig())
+	unstructuredStream, err := withFixupAndIAM(params, listStream, tfProvider, smLoader)
+	if err != nil {
+		return nil, err
+	}
+	return stream.NewByteStream(outputsink.ResourceFormat(params.ResourceFormat), unstructuredStream, smLoader, tfProvider)
+}
+
 func NewUnstructuredStream(params *parameters.Parameters, assetStream stream.AssetStream, provider *schema.Provider, smLoader *servicemappingloader.ServiceMappingLoader) (stream.UnstructuredStream, error) {
 	httpClient, err := serviceclient.NewHTTPClient(context.TODO(), params.OAuth2Token)
 	if err != nil {
@@ -54,6 +69,10 @@ func NewUnstructuredStream(params *parameters.Parameters, assetStream stream.Ass
 	if err != nil {
 		return nil, fmt.Errorf("error creating unstructured resource stream: %w", err)
 	}
+	return withFixupAndIAM(params, unstructuredResourceStream, provider, smLoader)
+}
+
+func withFixupAndIAM(params *parameters.Parameters, unstructuredResourceStream stream.UnstructuredStream, provider *schema.Provider, smLoader *servicemappingloader.ServiceMappingLoader) (stream.UnstructuredStream, error) {
 	fixupStream := stream.NewUnstructuredResourceFixupStream(unstructuredResourceStream)
 	if params.IAMFormat == commonparams.NoneIAMFormatOption {
 		return fixupStream, nil
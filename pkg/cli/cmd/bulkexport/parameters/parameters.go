@@ -37,10 +37,14 @@ const (
 	ProjectIDParam      = "project"
 	FolderIDParam       = "folder"
 	OrganizationIDParam = "organization"
+	DiscoveryParam      = "discovery"
 
 	ContinueOnErrorOption = "continue"
 	HaltOnErrorOption     = "halt"
 	IgnoreOnErrorOption   = "ignore"
+
+	AssetInventoryDiscoveryOption = "asset-inventory"
+	ListDiscoveryOption           = "list"
 )
 
 var (
@@ -49,6 +53,10 @@ var (
 		HaltOnErrorOption,
 		IgnoreOnErrorOption,
 	}
+	AllDiscoveryOptions = []string{
+		AssetInventoryDiscoveryOption,
+		ListDiscoveryOption,
+	}
 )
 
 type Parameters struct {
@@ -63,6 +71,8 @@ type Parameters struct {
 	OrganizationID          int
 	OAuth2Token             string
 	ResourceFormat          string
+	TFStateOutput           string
+	Discovery               string
 	Verbose                 bool
 }
 
@@ -85,6 +95,13 @@ type param struct {
 }
 
 func Validate(p *Parameters, stdin *os.File) error {
+	if err := validateDiscovery(p); err != nil {
+		return err
+	}
+	if p.Discovery == ListDiscoveryOption {
+		return validateListDiscovery(p, stdin)
+	}
+
 	inputParam := param{Value: &p.Input, Name: InputParam}
 	storageKeyParam := param{Value: &p.StorageKey, Name: StorageKeyParam}
 	projectIDParam := param{Value: &p.ProjectID, Name: ProjectIDParam}
@@ -114,10 +131,60 @@ func Validate(p *Parameters, stdin *os.File) error {
 	if err := commonparams.ValidateResourceFormat(p.ResourceFormat, p.IAMFormat); err != nil {
 		return err
 	}
+	if err := commonparams.ValidateTFStateOutput(p.TFStateOutput, p.ResourceFormat); err != nil {
+		return err
+	}
 
 	return validateOneInput(p, stdin)
 }
 
+// validateListDiscovery validates the parameters when resources are discovered with the List APIs of the
+// direct controllers, rather than from an asset inventory export.
+func validateListDiscovery(p *Parameters, stdin *os.File) error {
+	for _, other := range []param{
+		{Value: &p.Input, Name: InputParam},
+		{Value: &p.StorageKey, Name: StorageKeyParam},
+		{Value: &p.FolderID, Name: FolderIDParam},
+		{Value: &p.OrganizationID, Name: OrganizationIDParam},
+	} {
+		if !valutil.IsDefaultValue(other.Value) {
+			return fmt.Errorf("cannot supply the '%v' parameter when '%v' is '%v'", other.Name, DiscoveryParam, ListDiscoveryOption)
+		}
+	}
+	piped, err := IsInputPiped(stdin)
+	if err != nil {
+		return err
+	}
+	if piped {
+		return fmt.Errorf("cannot supply input on stdin when '%v' is '%v'", DiscoveryParam, ListDiscoveryOption)
+	}
+	if valutil.IsDefaultValue(p.ProjectID) {
+		return fmt.Errorf("the '%v' parameter must be defined when '%v' is '%v'", ProjectIDParam, DiscoveryParam, ListDiscoveryOption)
+	}
+	if err := validateOnError(p); err != nil {
+		return err
+	}
+	if err := commonparams.ValidateIAMFormat(p.IAMFormat); err != nil {
+		return err
+	}
+	if err := commonparams.ValidateResourceFormat(p.ResourceFormat, p.IAMFormat); err != nil {
+		return err
+	}
+	return commonparams.ValidateTFStateOutput(p.TFStateOutput, p.ResourceFormat)
+}
+
+func validateDiscovery(p *Parameters) error {
+	if valutil.IsDefaultValue(p.Discovery) {
+		return fmt.Errorf("invalid empty value for %v: must be one of {%v}", DiscoveryParam, strings.Join(AllDiscoveryOptions, ", "))
+	}
+	for _, o := range AllDiscoveryOptions {
+		if p.Discovery == o {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %v value of '%v': must be one of {%v}", DiscoveryParam, p.Discovery, strings.Join(AllDiscoveryOptions, ", "))
+}
+
 func IsInputPiped(stdin *os.File) (bool, error) {
 	fi, err := stdin.Stat()
 	if err != nil {
@@ -143,6 +143,7 @@ func TestBulkExportValidateFlags(t *testing.T) {
 		OrganizationID string
 		OAuth2Token    string
 		ResourceFormat string
+		Discovery      string
 		Verbose        string
 		Stdin          string
 		Error          string
@@ -659,13 +660,115 @@ func TestBulkExportValidateFlags(t *testing.T) {
 			Stdin:          "value",
 			Error:          "invalid resource-format value of 'garbage': must be one of {krm, hcl}",
 		},
+		{
+			Name:           "list discovery with project should succeed",
+			IAMFormat:      "",
+			Input:          "",
+			Output:         "",
+			StorageKey:     "",
+			OnError:        "",
+			ProjectID:      "my-project",
+			FolderID:       "",
+			OrganizationID: "",
+			OAuth2Token:    "",
+			ResourceFormat: "",
+			Discovery:      "list",
+			Verbose:        "",
+			Stdin:          "",
+			Error:          "",
+		},
+		{
+			Name:           "list discovery without project should fail",
+			IAMFormat:      "",
+			Input:          "",
+			Output:         "",
+			StorageKey:     "",
+			OnError:        "",
+			ProjectID:      "",
+			FolderID:       "",
+			OrganizationID: "",
+			OAuth2Token:    "",
+			ResourceFormat: "",
+			Discovery:      "list",
+			Verbose:        "",
+			Stdin:          "",
+			Error:          "the 'project' parameter must be defined when 'discovery' is 'list'",
+		},
+		{
+			Name:           "list discovery with folder should fail",
+			IAMFormat:      "",
+			Input:          "",
+			Output:         "",
+			StorageKey:     "",
+			OnError:        "",
+			ProjectID:      "",
+			FolderID:       "1234",
+			OrganizationID: "",
+			OAuth2Token:    "",
+			ResourceFormat: "",
+			Discovery:      "list",
+			Verbose:        "",
+			Stdin:          "",
+			Error:          "cannot supply the 'folder' parameter when 'discovery' is 'list'",
+		},
+		{
+			Name:           "list discovery with input should fail",
+			IAMFormat:      "",
+			Input:          "/tmp/my-file",
+			Output:         "",
+			StorageKey:     "",
+			OnError:        "",
+			ProjectID:      "my-project",
+			FolderID:       "",
+			OrganizationID: "",
+			OAuth2Token:    "",
+			ResourceFormat: "",
+			Discovery:      "list",
+			Verbose:        "",
+			Stdin:          "",
+			Error:          "cannot supply the 'input' parameter when 'discovery' is 'list'",
+		},
+		{
+			Name:           "list discovery with piped input should fail",
+			IAMFormat:      "",
+			Input:          "",
+			Output:         "",
+			StorageKey:     "",
+			OnError:        "",
+			ProjectID:      "my-project",
+			FolderID:       "",
+			OrganizationID: "",
+			OAuth2Token:    "",
+			ResourceFormat: "",
+			Discovery:      "list",
+			Verbose:        "",
+			Stdin:          "value",
+			Error:          "cannot supply input on stdin when 'discovery' is 'list'",
+		},
+		{
+			Name:           "discovery with garbage value should error",
+			IAMFormat:      "",
+			Input:          "",
+			Output:         "",
+			StorageKey:     "",
+			OnError:        "",
+			ProjectID:      "my-project",
+			FolderID:       "",
+			OrganizationID: "",
+			OAuth2Token:    "",
+			ResourceFormat: "",
+			Discovery:      "garbage",
+			Verbose:        "",
+			Stdin:          "",
+			Error:          "invalid discovery value of 'garbage': must be one of {asset-inventory, list}",
+		},
 	}
 	defaultParams := bulkExportParams
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
 			args := buildArgs(t, tc.Verbose, "input", tc.Input, "output", tc.Output, "storage-key", tc.StorageKey,
 				"on-error", tc.OnError, "project", tc.ProjectID, "folder", tc.FolderID, "organization", tc.OrganizationID,
-				"oauth2-token", tc.OAuth2Token, "iam-format", tc.IAMFormat, "resource-format", tc.ResourceFormat)
+				"oauth2-token", tc.OAuth2Token, "iam-format", tc.IAMFormat, "resource-format", tc.ResourceFormat, "discovery", tc.Discovery)
 			// reset the package local global variable back to the default values
 			bulkExportParams = defaultParams
 			err := bulkExportCmd.Flags().Parse(args)
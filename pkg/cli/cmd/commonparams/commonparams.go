@@ -16,6 +16,7 @@ package commonparams
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/outputsink"
@@ -34,6 +35,7 @@ const (
 	OAuth2TokenParamName             = "oauth2-token"
 	OutputParamName                  = "output"
 	ResourceFormatParamName          = "resource-format"
+	TFStateOutputParamName           = "tfstate-output"
 
 	PartialPolicyFormatOption   = "partialpolicy"
 	PolicyIAMFormatOption       = "policy"
@@ -48,6 +50,7 @@ const (
 	OAuth2TokenDefault             = ""
 	OutputDefault                  = ""
 	ResourceFormatDefault          = KRMResourceFormatOption
+	TFStateOutputDefault           = ""
 
 	OAuth2TokenUsage = "an optional OAuth 2.0 access token to be used as the identity for communication with GCP services, can be obtained with 'gcloud auth print-access-token'"
 	OutputUsage      = "an optional output file path, disables standard output, when a file the result will contain all of the command output, when a directory, the directory will contain a new file for each resource in the output"
@@ -57,6 +60,7 @@ var (
 	IAMFormatUsage               = fmt.Sprintf("specify the IAM resource format or disable IAM output, options are '%v', '%v', '%v', or '%v'", PartialPolicyFormatOption, PolicyIAMFormatOption, PolicyMemberIAMFormatOption, NoneIAMFormatOption)
 	FilterDeletedIAMMembersUsage = fmt.Sprintf("specify whether to filter out deleted IAM members, options are '%v' or '%v', (default: '%v')", true, false, FilterDeletedIAMMembersDefault)
 	ResourceFormatUsage          = fmt.Sprintf("specify the format of the outputted resources, options are '%v' or '%v' (default: '%v')", KRMResourceFormatOption, HCLResourceFormatOption, ResourceFormatDefault)
+	TFStateOutputUsage           = fmt.Sprintf("an optional file path to write a terraform state file (version 4) for the outputted resources, requires '%v' to be '%v'", ResourceFormatParamName, HCLResourceFormatOption)
 )
 
 func AddOAuth2TokenParam(cmd *cobra.Command, value *string) {
@@ -109,6 +113,26 @@ func AddResourceFormatParam(cmd *cobra.Command, value *string) {
 	}
 }
 
+func AddTFStateOutputParam(cmd *cobra.Command, value *string) {
+	cmd.Flags().StringVar(value, TFStateOutputParamName, TFStateOutputDefault, TFStateOutputUsage)
+	if err := cmd.Flags().MarkHidden(TFStateOutputParamName); err != nil {
+		panic(err)
+	}
+}
+
+func ValidateTFStateOutput(tfStateOutput, resourceFormat string) error {
+	if valutil.IsDefaultValue(tfStateOutput) {
+		return nil
+	}
+	if resourceFormat != HCLResourceFormatOption {
+		return fmt.Errorf("the '%v' flag can only be used when '%v' is '%v'", TFStateOutputParamName, ResourceFormatParamName, HCLResourceFormatOption)
+	}
+	if fi, err := os.Stat(tfStateOutput); err == nil && fi.IsDir() {
+		return fmt.Errorf("invalid %v value of '%v': must be a file, not a directory", TFStateOutputParamName, tfStateOutput)
+	}
+	return nil
+}
+
 func ValidateResourceFormat(resourceFormat, iamFormat string) error {
 	if err := validateResourceFormatValue(resourceFormat); err != nil {
 		return err
@@ -0,0 +1,57 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/cmd/commonparams"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/cmd/diff"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/cmd/diff/parameters"
+)
+
+const (
+	diffCommandName = "diff"
+)
+
+var (
+	diffParams = parameters.Parameters{}
+	diffCmd    = &cobra.Command{
+		Use:   diffCommandName,
+		Short: "Compare Config Connector YAML files with the live state of the resources in GCP",
+		Long: `Compare Config Connector YAML files with the live state of the resources in GCP.
+
+Each resource is exported from GCP and compared field-by-field with the spec in the file,
+printing the changes that applying the file would make. References to other resources
+must be external references, as the resources are not read from a cluster.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			diffParams.Verbose = verbose
+			if err := parameters.Validate(&diffParams); err != nil {
+				return err
+			}
+			rootCmd.SilenceUsage = true
+			return diff.Execute(ctx, &diffParams, cmd.OutOrStdout())
+		},
+		Args: cobra.NoArgs,
+	}
+)
+
+func init() {
+	commonparams.AddOAuth2TokenParam(diffCmd, &diffParams.OAuth2Token)
+	diffCmd.Flags().StringVarP(&diffParams.Input, parameters.InputParam, "i", "", "a KRM YAML file, or a directory containing KRM YAML files, to compare with GCP")
+	diffCmd.Flags().BoolVar(&diffParams.ShowDefaultedFields, parameters.ShowDefaultedFieldsParam, false, "also show fields that are set in GCP but not in the files (usually defaulted by GCP)")
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/powertools/diffs"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Compare builds the field-level diff between the spec of the live (exported) object
+// and the spec of the local object. Unless showDefaultedFields is set, fields that are
+// only set in the live object are ignored, as they are typically defaulted by GCP.
+func Compare(local, live *unstructured.Unstructured, showDefaultedFields bool) (*diffs.ObjectDiff, error) {
+	localSpec, err := normalize(local.Object["spec"])
+	if err != nil {
+		return nil, fmt.Errorf("error normalizing local spec: %w", err)
+	}
+	liveSpec, err := normalize(live.Object["spec"])
+	if err != nil {
+		return nil, fmt.Errorf("error normalizing live spec: %w", err)
+	}
+	if !showDefaultedFields {
+		liveSpec = pruneToLocal(liveSpec, localSpec)
+	}
+
+	oldObj := objectWithSpec(local, liveSpec)
+	newObj := objectWithSpec(local, localSpec)
+	return diffs.BuildObjectDiff(oldObj, newObj)
+}
+
+// objectWithSpec builds an object with the identity of u, and the given spec.
+func objectWithSpec(u *unstructured.Unstructured, spec any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetGroupVersionKind(u.GroupVersionKind())
+	obj.SetNamespace(u.GetNamespace())
+	obj.SetName(u.GetName())
+	if spec != nil {
+		obj.Object["spec"] = spec
+	}
+	return obj
+}
+
+// normalize round-trips v through JSON, so that values parsed from YAML and values
+// built by the exporters have the same types (e.g. all numbers are float64).
+func normalize(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// pruneToLocal removes the map keys from live that are not set in local, recursively.
+func pruneToLocal(live, local any) any {
+	switch local := local.(type) {
+	case map[string]any:
+		liveMap, ok := live.(map[string]any)
+		if !ok {
+			return live
+		}
+		pruned := make(map[string]any)
+		for k, localValue := range local {
+			if liveValue, found := liveMap[k]; found {
+				pruned[k] = pruneToLocal(liveValue, localValue)
+			}
+		}
+		return pruned
+
+	case []any:
+		liveSlice, ok := live.([]any)
+		if !ok {
+			return live
+		}
+		pruned := make([]any, len(liveSlice))
+		for i := range liveSlice {
+			if i < len(local) {
+				pruned[i] = pruneToLocal(liveSlice[i], local[i])
+			} else {
+				pruned[i] = liveSlice[i]
+			}
+		}
+		return pruned
+
+	default:
+		return live
+	}
+}
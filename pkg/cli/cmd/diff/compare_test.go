@@ -0,0 +1,197 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/powertools/diffs"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func parseObject(t *testing.T, s string) *unstructured.Unstructured {
+	t.Helper()
+	u := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(s), &u.Object); err != nil {
+		t.Fatalf("error parsing yaml: %v", err)
+	}
+	return u
+}
+
+func TestCompare(t *testing.T) {
+	local := parseObject(t, `
+apiVersion: pubsub.cnrm.cloud.google.com/v1beta1
+kind: PubSubTopic
+metadata:
+  name: test-topic
+  namespace: ns
+spec:
+  messageRetentionDuration: 86400s
+  labels:
+    env: prod
+`)
+	live := parseObject(t, `
+apiVersion: pubsub.cnrm.cloud.google.com/v1beta1
+kind: PubSubTopic
+metadata:
+  name: test-topic
+spec:
+  messageRetentionDuration: 3600s
+  resourceID: test-topic
+  labels:
+    env: prod
+    managed-by-cnrm: "true"
+`)
+
+	tests := []struct {
+		name                string
+		showDefaultedFields bool
+		want                []string
+		notWant             []string
+	}{
+		{
+			name:    "ignores defaulted fields",
+			want:    []string{"messageRetentionDuration: 3600s -> 86400s"},
+			notWant: []string{"resourceID", "managed-by-cnrm", "env:"},
+		},
+		{
+			name:                "shows defaulted fields",
+			showDefaultedFields: true,
+			want:                []string{"messageRetentionDuration: 3600s -> 86400s", "resourceID: test-topic -> <nil>", "managed-by-cnrm: true -> <nil>"},
+			notWant:             []string{"env:"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := Compare(local, live, tc.showDefaultedFields)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			if !d.HasChanges() {
+				t.Fatalf("expected changes, got none")
+			}
+			var out bytes.Buffer
+			d.PrettyPrintTo(diffs.PrettyPrintOptions{}, &out)
+			for _, s := range tc.want {
+				if !strings.Contains(out.String(), s) {
+					t.Errorf("expected diff to contain %q, got:\n%s", s, out.String())
+				}
+			}
+			for _, s := range tc.notWant {
+				if strings.Contains(out.String(), s) {
+					t.Errorf("expected diff not to contain %q, got:\n%s", s, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCompareNoChanges(t *testing.T) {
+	local := parseObject(t, `
+apiVersion: storage.cnrm.cloud.google.com/v1beta1
+kind: StorageBucket
+metadata:
+  name: test-bucket
+spec:
+  location: US
+  lifecycleRule:
+  - action:
+      type: Delete
+    condition:
+      age: 7
+`)
+	live := parseObject(t, `
+apiVersion: storage.cnrm.cloud.google.com/v1beta1
+kind: StorageBucket
+metadata:
+  name: test-bucket
+spec:
+  location: US
+  storageClass: STANDARD
+  lifecycleRule:
+  - action:
+      type: Delete
+    condition:
+      age: 7
+      withState: ANY
+`)
+	d, err := Compare(local, live, false)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if d.HasChanges() {
+		var out bytes.Buffer
+		d.PrettyPrintTo(diffs.PrettyPrintOptions{}, &out)
+		t.Errorf("expected no changes, got:\n%s", out.String())
+	}
+}
+
+func TestLoadManifests(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"topics.yaml": `
+apiVersion: pubsub.cnrm.cloud.google.com/v1beta1
+kind: PubSubTopic
+metadata:
+  name: topic-a
+---
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: not-kcc
+---
+apiVersion: pubsub.cnrm.cloud.google.com/v1beta1
+kind: PubSubTopic
+metadata:
+  name: topic-b
+`,
+		"sub/bucket.yml": `
+apiVersion: storage.cnrm.cloud.google.com/v1beta1
+kind: StorageBucket
+metadata:
+  name: bucket
+`,
+		"README.md": "not yaml",
+	}
+	for name, contents := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("error creating directory: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(contents), 0o644); err != nil {
+			t.Fatalf("error writing file: %v", err)
+		}
+	}
+
+	objects, err := LoadManifests(dir)
+	if err != nil {
+		t.Fatalf("LoadManifests failed: %v", err)
+	}
+	var got []string
+	for _, u := range objects {
+		got = append(got, u.GetKind()+"/"+u.GetName())
+	}
+	want := "StorageBucket/bucket,PubSubTopic/topic-a,PubSubTopic/topic-b"
+	if strings.Join(got, ",") != want {
+		t.Errorf("unexpected objects; got %v, want %v", strings.Join(got, ","), want)
+	}
+}
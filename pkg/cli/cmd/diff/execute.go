@@ -0,0 +1,161 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/cmd/diff/parameters"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/gcpclient"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/log"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/powertools/diffs"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/tf"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// errNotFound is returned by the live fetchers when the resource does not exist in GCP.
+var errNotFound = errors.New("not found in GCP")
+
+// errUnsupported is returned by the live fetchers for kinds that cannot be exported.
+var errUnsupported = errors.New("unsupported kind")
+
+func Execute(ctx context.Context, params *parameters.Parameters, output io.Writer) error {
+	objects, err := LoadManifests(params.Input)
+	if err != nil {
+		return fmt.Errorf("error loading resources from '%v': %w", params.Input, err)
+	}
+
+	tfProvider, err := tf.NewProvider(ctx, params.OAuth2Token)
+	if err != nil {
+		return err
+	}
+	smLoader, err := servicemappingloader.New()
+	if err != nil {
+		return fmt.Errorf("error loading service mappings: %w", err)
+	}
+	// Initialize direct controllers/exporters
+	if err := registry.Init(ctx, params.ControllerConfig()); err != nil {
+		return err
+	}
+
+	f := &liveFetcher{
+		gcpClient: gcpclient.New(tfProvider, smLoader),
+		// References must be external, as we do not have access to a cluster.
+		reader: k8s.NewErroringClient(),
+	}
+
+	printOpts := diffs.PrettyPrintOptions{PrintObjectInfo: true}
+	var changed, failed int
+	for _, local := range objects {
+		id := fmt.Sprintf("%v %v/%v", local.GetKind(), local.GetNamespace(), local.GetName())
+
+		live, err := f.Get(ctx, local)
+		if err != nil {
+			if errors.Is(err, errNotFound) {
+				fmt.Fprintf(output, "%v:\n  (%v)\n", id, err)
+				changed++
+				continue
+			}
+			if errors.Is(err, errUnsupported) {
+				log.Info("skipping %v: %v", id, err)
+				continue
+			}
+			log.Error("error fetching live state for %v: %v", id, err)
+			failed++
+			continue
+		}
+
+		d, err := Compare(local, live, params.ShowDefaultedFields)
+		if err != nil {
+			log.Error("error comparing %v: %v", id, err)
+			failed++
+			continue
+		}
+		if d.HasChanges() {
+			changed++
+		}
+		d.PrettyPrintTo(printOpts, output)
+	}
+
+	fmt.Fprintf(output, "\n%d resource(s) compared, %d with differences\n", len(objects), changed)
+	if failed != 0 {
+		return fmt.Errorf("error fetching or comparing %d resource(s)", failed)
+	}
+	return nil
+}
+
+// liveFetcher exports the live state of a resource from GCP, using the same
+// controller (direct or terraform) that would reconcile the resource.
+type liveFetcher struct {
+	gcpClient gcpclient.Client
+	reader    client.Reader
+}
+
+func (f *liveFetcher) Get(ctx context.Context, local *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	gk := local.GroupVersionKind().GroupKind()
+
+	useDirect := registry.IsDirectByGK(gk)
+	if useDirect && f.gcpClient.IsSupported(gk.Kind) {
+		// If we have a choice of controllers, choose the same way as the controller manager.
+		reconcileGate := registry.GetReconcileGate(gk)
+		useDirect = reconcileGate != nil && reconcileGate.ShouldReconcile(local)
+	}
+
+	if useDirect {
+		return f.getDirect(ctx, local)
+	}
+	if f.gcpClient.IsSupported(gk.Kind) {
+		return f.getTerraform(ctx, local)
+	}
+	return nil, errUnsupported
+}
+
+func (f *liveFetcher) getDirect(ctx context.Context, local *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	model, err := registry.GetModel(local.GroupVersionKind().GroupKind())
+	if err != nil {
+		return nil, err
+	}
+	adapter, err := model.AdapterForObject(ctx, f.reader, local.DeepCopy())
+	if err != nil {
+		return nil, err
+	}
+	found, err := adapter.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errNotFound
+	}
+	return adapter.Export(ctx)
+}
+
+func (f *liveFetcher) getTerraform(ctx context.Context, local *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	live, err := f.gcpClient.Get(ctx, local.DeepCopy())
+	if err != nil {
+		if errors.Is(err, gcpclient.ErrNotFound) {
+			return nil, errNotFound
+		}
+		return nil, err
+	}
+	return live, nil
+}
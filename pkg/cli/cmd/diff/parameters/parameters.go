@@ -0,0 +1,71 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parameters
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/util/valutil"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	InputParam               = "input"
+	ShowDefaultedFieldsParam = "show-defaulted-fields"
+)
+
+type Parameters struct {
+	// Input is the path to a KRM YAML file, or to a directory that is searched (recursively) for YAML files.
+	Input string
+
+	// ShowDefaultedFields includes fields that are set in GCP but not in the local files,
+	// which are usually defaulted by the service.
+	ShowDefaultedFields bool
+
+	// OAuth2Token is the (optional) static authentication token to use for GCP authentication.
+	OAuth2Token string
+	Verbose     bool
+
+	// HTTPClient allows for overriding the default HTTP Client
+	HTTPClient *http.Client
+}
+
+func (p *Parameters) ControllerConfig() *config.ControllerConfig {
+	c := &config.ControllerConfig{
+		HTTPClient: p.HTTPClient,
+		UserAgent:  gcp.KCCUserAgent,
+	}
+	if p.OAuth2Token != "" {
+		c.GCPTokenSource = oauth2.StaticTokenSource(
+			&oauth2.Token{AccessToken: p.OAuth2Token},
+		)
+	}
+	return c
+}
+
+func Validate(p *Parameters) error {
+	if valutil.IsDefaultValue(p.Input) {
+		return fmt.Errorf("'%v' parameter cannot be empty", InputParam)
+	}
+	if _, err := os.Stat(p.Input); err != nil {
+		return fmt.Errorf("error reading '%v' parameter: %w", InputParam, err)
+	}
+	return nil
+}
@@ -71,6 +71,7 @@ func init() {
 	commonparams.AddFilterDeletedIAMMembersParam(exportCmd, &exportParams.FilterDeletedIAMMembers)
 	commonparams.AddOutputParam(exportCmd, &exportParams.Output)
 	commonparams.AddResourceFormatParam(exportCmd, &exportParams.ResourceFormat)
+	commonparams.AddTFStateOutputParam(exportCmd, &exportParams.TFStateOutput)
 }
 
 func fillRootFlagsOnExportParams(params *parameters.Parameters) {
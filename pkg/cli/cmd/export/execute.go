@@ -17,6 +17,7 @@ package export
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/cmd/export/outputstream"
@@ -25,6 +26,9 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/stream"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/tf"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func Execute(ctx context.Context, params *parameters.Parameters) error {
@@ -44,7 +48,7 @@ func Execute(ctx context.Context, params *parameters.Parameters) error {
 	}
 	recoverableStream := stream.NewRecoverableByteStream(byteStream)
 
-	outputSink, err := outputsink.New(tfProvider, params.Output, outputsink.ResourceFormat(params.ResourceFormat))
+	outputSink, err := newOutputSink(tfProvider, params)
 	if err != nil {
 		return err
 	}
@@ -57,5 +61,24 @@ func Execute(ctx context.Context, params *parameters.Parameters) error {
 			return err
 		}
 	}
+	// The terraform state is only written once all resources have been exported successfully.
+	if tfStateSink, ok := outputSink.(*outputsink.TFStateSink); ok {
+		return tfStateSink.WriteState()
+	}
 	return nil
 }
+
+func newOutputSink(tfProvider *schema.Provider, params *parameters.Parameters) (outputsink.OutputSink, error) {
+	outputSink, err := outputsink.New(tfProvider, params.Output, outputsink.ResourceFormat(params.ResourceFormat))
+	if err != nil {
+		return nil, err
+	}
+	if params.TFStateOutput == "" {
+		return outputSink, nil
+	}
+	smLoader, err := servicemappingloader.New()
+	if err != nil {
+		return nil, fmt.Errorf("error creating service mapping loader: %w", err)
+	}
+	return outputsink.NewTFStateSink(outputSink, params.TFStateOutput, smLoader, tfProvider), nil
+}
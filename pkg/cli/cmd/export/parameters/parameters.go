@@ -32,6 +32,7 @@ type Parameters struct {
 
 	Output         string
 	ResourceFormat string
+	TFStateOutput  string
 	URI            string
 	Verbose        bool
 
@@ -57,5 +58,9 @@ func Validate(p *Parameters) error {
 		return err
 	}
 
-	return commonparams.ValidateResourceFormat(p.ResourceFormat, p.IAMFormat)
+	if err := commonparams.ValidateResourceFormat(p.ResourceFormat, p.IAMFormat); err != nil {
+		return err
+	}
+
+	return commonparams.ValidateTFStateOutput(p.TFStateOutput, p.ResourceFormat)
 }
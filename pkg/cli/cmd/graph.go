@@ -0,0 +1,58 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/cmd/graph"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/cmd/graph/parameters"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	graphCommandName = "graph"
+)
+
+var (
+	graphParams         = parameters.Parameters{}
+	graphCmdDescription = "Print the graph of the references between the Config Connector resources in a set of files or in a namespace, along with the order in which they can be created and deleted"
+	graphCmd            = &cobra.Command{
+		Use:   graphCommandName,
+		Short: graphCmdDescription,
+		Long:  graphCmdDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := parameters.Validate(&graphParams); err != nil {
+				return err
+			}
+			rootCmd.SilenceUsage = true
+			return graph.Execute(cmd.Context(), &graphParams, os.Stdout)
+		},
+		Args: cobra.NoArgs,
+	}
+)
+
+func init() {
+	inputUsage := "the files or directories containing the KRM resources, can be repeated."
+	graphCmd.Flags().StringArrayVarP(&graphParams.Inputs, parameters.InputParam, "i", nil, inputUsage)
+	namespaceUsage := fmt.Sprintf("the namespace whose resources are read from the cluster, instead of '%v'.", parameters.InputParam)
+	graphCmd.Flags().StringVarP(&graphParams.Namespace, parameters.NamespaceParam, "n", "", namespaceUsage)
+	outputFormatUsage := fmt.Sprintf("specify the format of the output, options are '%v' or '%v' (default: '%v')",
+		parameters.DOTOutputFormat, parameters.JSONOutputFormat, parameters.DefaultOutputFormat)
+	graphCmd.Flags().StringVarP(&graphParams.OutputFormat, parameters.OutputFormatParam, "o", parameters.DefaultOutputFormat, outputFormatUsage)
+	graphParams.ClusterOptions.AddFlags(graphCmd)
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/cmd/graph/parameters"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/powertools/kubecli"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/resourcegraph"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Execute(ctx context.Context, params *parameters.Parameters, output io.Writer) error {
+	var objects []*unstructured.Unstructured
+	if len(params.Inputs) > 0 {
+		objs, err := resourcegraph.LoadFiles(params.Inputs)
+		if err != nil {
+			return fmt.Errorf("error loading resources from files: %w", err)
+		}
+		objects = objs
+	} else {
+		clusterOptions := params.ClusterOptions
+		if clusterOptions.ImpersonateUser != "" {
+			clusterOptions.Impersonate = &rest.ImpersonationConfig{
+				UserName: clusterOptions.ImpersonateUser,
+				Groups:   clusterOptions.ImpersonateGroups,
+			}
+		}
+		kubeClient, err := kubecli.NewClient(ctx, clusterOptions)
+		if err != nil {
+			return fmt.Errorf("error creating kubernetes client: %w", err)
+		}
+		objs, err := listNamespace(ctx, kubeClient, params.Namespace)
+		if err != nil {
+			return err
+		}
+		objects = objs
+	}
+
+	g, err := resourcegraph.Build(objects)
+	if err != nil {
+		return fmt.Errorf("error building resource graph: %w", err)
+	}
+	switch params.OutputFormat {
+	case parameters.JSONOutputFormat:
+		return g.WriteJSON(output)
+	case parameters.DOTOutputFormat:
+		return g.WriteDOT(output)
+	default:
+		return fmt.Errorf("unhandled output format '%v'", params.OutputFormat)
+	}
+}
+
+// listNamespace lists the Config Connector resources of all kinds in the
+// given namespace.
+func listNamespace(ctx context.Context, kubeClient *kubecli.Client, namespace string) ([]*unstructured.Unstructured, error) {
+	resources, err := kubeClient.DiscoveryClient.ServerPreferredNamespacedResources()
+	if err != nil {
+		return nil, fmt.Errorf("discovering server resources: %w", err)
+	}
+	var objects []*unstructured.Unstructured
+	for _, group := range resources {
+		gv, err := schema.ParseGroupVersion(group.GroupVersion)
+		if err != nil {
+			return nil, fmt.Errorf("parsing group version %q: %w", group.GroupVersion, err)
+		}
+		if !strings.HasSuffix(gv.Group, k8s.APIDomainSuffix) {
+			continue
+		}
+		for _, resource := range group.APIResources {
+			if strings.Contains(resource.Name, "/") || !hasVerb(resource.Verbs, "list") {
+				continue
+			}
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gv.WithKind(resource.Kind + "List"))
+			if err := kubeClient.List(ctx, list, client.InNamespace(namespace)); err != nil {
+				return nil, fmt.Errorf("error listing %v in namespace %v: %w", resource.Kind, namespace, err)
+			}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+		}
+	}
+	return objects, nil
+}
+
+func hasVerb(verbs []string, verb string) bool {
+	for _, v := range verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parameters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/powertools/kubecli"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/util/valutil"
+)
+
+const (
+	InputParam        = "input-file"
+	NamespaceParam    = "namespace"
+	OutputFormatParam = "output-format"
+	DOTOutputFormat   = "dot"
+	JSONOutputFormat  = "json"
+
+	DefaultOutputFormat = DOTOutputFormat
+)
+
+type Parameters struct {
+	Inputs         []string
+	Namespace      string
+	OutputFormat   string
+	ClusterOptions kubecli.ClusterOptions
+}
+
+func Validate(p *Parameters) error {
+	if len(p.Inputs) == 0 && valutil.IsDefaultValue(p.Namespace) {
+		return fmt.Errorf("one of the '%v' or '%v' parameters must be specified", InputParam, NamespaceParam)
+	}
+	if len(p.Inputs) > 0 && !valutil.IsDefaultValue(p.Namespace) {
+		return fmt.Errorf("the '%v' and '%v' parameters cannot be specified together", InputParam, NamespaceParam)
+	}
+	return validateOutputFormat(p.OutputFormat)
+}
+
+func validateOutputFormat(value string) error {
+	outputFormatOptions := []string{DOTOutputFormat, JSONOutputFormat}
+	for _, o := range outputFormatOptions {
+		if value == o {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %v value of '%v': must be one of {%v}", OutputFormatParam, value, strings.Join(outputFormatOptions, ", "))
+}
@@ -59,9 +59,11 @@ func init() {
 	rootCmd.AddCommand(exportCmd)
 	rootCmd.AddCommand(bulkExportCmd)
 	rootCmd.AddCommand(printResourcesCmd)
+	rootCmd.AddCommand(diffCmd)
 	AddVersionCommand(rootCmd)
 	AddLicensesCommand(rootCmd)
 	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(graphCmd)
 
 	powertools.AddCommands(rootCmd)
 
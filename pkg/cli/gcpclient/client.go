@@ -36,24 +36,30 @@ var (
 
 type Client interface {
 	Get(ctx context.Context, u *unstructured.Unstructured) (*unstructured.Unstructured, error)
-	Apply(u *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	Apply(ctx context.Context, u *unstructured.Unstructured) (*unstructured.Unstructured, error)
 	Delete(u *unstructured.Unstructured) error
 	IsSupported(kind string) bool
 }
 
 type gcpClient struct {
-	erroringK8sClient client.Client
-	smLoader          *servicemappingloader.ServiceMappingLoader
-	tfProvider        *schema.Provider
-	supportedKinds    map[string]bool
+	kubeClient     client.Client
+	smLoader       *servicemappingloader.ServiceMappingLoader
+	tfProvider     *schema.Provider
+	supportedKinds map[string]bool
 }
 
 func New(provider *schema.Provider, smLoader *servicemappingloader.ServiceMappingLoader) Client {
+	return NewWithKubeClient(provider, smLoader, k8s.NewErroringClient())
+}
+
+// NewWithKubeClient returns a Client which reads the resources referenced by
+// the resources it applies from the given kubernetes client.
+func NewWithKubeClient(provider *schema.Provider, smLoader *servicemappingloader.ServiceMappingLoader, kubeClient client.Client) Client {
 	client := gcpClient{
-		erroringK8sClient: k8s.NewErroringClient(),
-		smLoader:          smLoader,
-		tfProvider:        provider,
-		supportedKinds:    buildSupportedKindSet(smLoader),
+		kubeClient:     kubeClient,
+		smLoader:       smLoader,
+		tfProvider:     provider,
+		supportedKinds: buildSupportedKindSet(smLoader),
 	}
 	return &client
 }
@@ -72,7 +78,7 @@ func (c *gcpClient) Get(ctx context.Context, u *unstructured.Unstructured) (*uns
 	if err != nil {
 		return nil, fmt.Errorf("could not parse resource %s: %w", u.GetName(), err)
 	}
-	state, err := krmtotf.FetchLiveState(ctx, resource, c.tfProvider, c.erroringK8sClient, c.smLoader)
+	state, err := krmtotf.FetchLiveState(ctx, resource, c.tfProvider, c.kubeClient, c.smLoader)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching live state: %w", err)
 	}
@@ -82,8 +88,7 @@ func (c *gcpClient) Get(ctx context.Context, u *unstructured.Unstructured) (*uns
 	return updateResourceAndNewUnstructuredFromState(resource, state)
 }
 
-func (c *gcpClient) Apply(u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
-	ctx := context.Background()
+func (c *gcpClient) Apply(ctx context.Context, u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 	sm, err := c.smLoader.GetServiceMapping(u.GroupVersionKind().Group)
 	if err != nil {
 		return nil, err
@@ -92,11 +97,11 @@ func (c *gcpClient) Apply(u *unstructured.Unstructured) (*unstructured.Unstructu
 	if err != nil {
 		return nil, fmt.Errorf("could not parse resource %s: %w", u.GetName(), err)
 	}
-	liveState, err := krmtotf.FetchLiveState(ctx, krmResource, c.tfProvider, c.erroringK8sClient, c.smLoader)
+	liveState, err := krmtotf.FetchLiveState(ctx, krmResource, c.tfProvider, c.kubeClient, c.smLoader)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching live state: %w", err)
 	}
-	config, _, err := krmtotf.KRMResourceToTFResourceConfig(krmResource, c.erroringK8sClient, c.smLoader)
+	config, _, err := krmtotf.KRMResourceToTFResourceConfig(krmResource, c.kubeClient, c.smLoader)
 	if err != nil {
 		return nil, fmt.Errorf("error expanding resource configuration: %w", err)
 	}
@@ -131,7 +136,7 @@ func (c *gcpClient) Delete(u *unstructured.Unstructured) error {
 	if err != nil {
 		return fmt.Errorf("could not parse resource %s: %w", u.GetName(), err)
 	}
-	liveState, err := krmtotf.FetchLiveState(ctx, krmResource, c.tfProvider, c.erroringK8sClient, c.smLoader)
+	liveState, err := krmtotf.FetchLiveState(ctx, krmResource, c.tfProvider, c.kubeClient, c.smLoader)
 	if err != nil {
 		return fmt.Errorf("error fetching live state: %w", err)
 	}
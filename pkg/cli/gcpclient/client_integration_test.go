@@ -112,7 +112,7 @@ func TestCRUD(t *testing.T) {
 			if k8s.IsManagedByKCC(d.GroupVersionKind()) {
 				applyProjectRefOrAnnotation(t, smLoader, d, projectID)
 				defer buildDeleteFunc(t, client, d)()
-				d = clientApply(t, client, d)
+				d = clientApply(t, ctx, client, d)
 			}
 			resources = append(resources, d)
 		}
@@ -120,12 +120,12 @@ func TestCRUD(t *testing.T) {
 		applyProjectRefOrAnnotation(t, smLoader, createUnstruct, projectID)
 		resolveAPIServerDependenciesIfKCCManaged(t, smLoader, tfProvider, resources, createUnstruct)
 		defer buildDeleteFunc(t, client, createUnstruct)()
-		clientApply(t, client, createUnstruct)
+		clientApply(t, ctx, client, createUnstruct)
 		clientGet(t, ctx, client, createUnstruct)
 		if testContext.UpdateUnstruct != nil {
 			resolveAPIServerDependenciesIfKCCManaged(t, smLoader, tfProvider, resources, testContext.UpdateUnstruct)
 			applyProjectRefOrAnnotation(t, smLoader, testContext.UpdateUnstruct, projectID)
-			clientApply(t, client, testContext.UpdateUnstruct)
+			clientApply(t, ctx, client, testContext.UpdateUnstruct)
 		}
 		clientDelete(t, client, createUnstruct)
 		for i := len(testContext.DependencyUnstructs) - 1; i >= 0; i-- {
@@ -149,9 +149,9 @@ func clientGet(t *testing.T, ctx context.Context, client gcpclient.Client, u *un
 	return u
 }
 
-func clientApply(t *testing.T, client gcpclient.Client, u *unstructured.Unstructured) *unstructured.Unstructured {
+func clientApply(t *testing.T, ctx context.Context, client gcpclient.Client, u *unstructured.Unstructured) *unstructured.Unstructured {
 	t.Helper()
-	newUnstruct, err := client.Apply(u)
+	newUnstruct, err := client.Apply(ctx, u)
 	if err != nil {
 		t.Fatalf("error applying %s object %s: %v", u.GroupVersionKind().Kind, k8s.GetNamespacedName(u), err)
 	}
@@ -52,7 +52,7 @@ func TestQuickStart(t *testing.T) {
 		t.Fatalf("error creating disk skeleton: %v", err)
 	}
 	// apply the resource, this will create the resource if it does not exist or update the resource if it does exist
-	disk, err := client.Apply(diskSkeleton)
+	disk, err := client.Apply(ctx, diskSkeleton)
 	if err != nil {
 		t.Fatalf("error creating disk: %v", err)
 	}
@@ -31,18 +31,79 @@ import (
 )
 
 func UnstructuredToHCL(ctx context.Context, u *unstructured.Unstructured, smLoader *servicemappingloader.ServiceMappingLoader, tfProvider *schema.Provider) (string, error) {
+	krmResource, exportOp, err := toTerraformExport(ctx, u, smLoader, tfProvider)
+	if err != nil {
+		return "", err
+	}
+
+	hcl, err := serialization.InstanceStateToHCL(exportOp.TerraformState, exportOp.TerraformInfo, tfProvider)
+	if err != nil {
+		return "", fmt.Errorf("error generating hcl: %w", err)
+	}
+
+	importID, err := krmResource.GetImportID(k8s.NewErroringClient(), smLoader)
+	if err != nil {
+		return "", fmt.Errorf("error getting import id for '%v': %w", krmResource.GetName(), err)
+	}
+	// append a comment with terraform import command for two reasons:
+	// 1. A human reading the output could use the value to perform an import
+	// 2. gcloud is looking for this output and printing it out for their users
+	//
+	// any changes to the format of this output should be communicated to the gcloud team
+	hcl = fmt.Sprintf("%v# terraform import %v.%v %v\n", hcl, exportOp.TerraformInfo.Type, krmResource.TFInfo.Id, importID)
+	return hcl, nil
+}
+
+// UnstructuredToTFStateResource converts the resource into an entry of a Terraform state file.
+// The address of the entry matches the address of the resource in the HCL returned by
+// UnstructuredToHCL, so that the two can be used together without a 'terraform import'.
+func UnstructuredToTFStateResource(ctx context.Context, u *unstructured.Unstructured, smLoader *servicemappingloader.ServiceMappingLoader, tfProvider *schema.Provider) (*serialization.StateResource, error) {
+	krmResource, exportOp, err := toTerraformExport(ctx, u, smLoader, tfProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	importID, err := krmResource.GetImportID(k8s.NewErroringClient(), smLoader)
+	if err != nil {
+		return nil, fmt.Errorf("error getting import id for '%v': %w", krmResource.GetName(), err)
+	}
+	// The ID stored in the state is the one Terraform would record after an import, which is not
+	// always the same as the import ID. Parsing the import ID does not make any network calls.
+	id := importID
+	if !krmResource.ResourceConfig.SkipImport {
+		imported, err := krmtotf.ImportState(ctx, importID, &terraform.InstanceInfo{Type: exportOp.TerraformInfo.Type}, tfProvider)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing import id '%v' for '%v': %w", importID, krmResource.GetName(), err)
+		}
+		if imported.ID != "" {
+			id = imported.ID
+		}
+	}
+	state := exportOp.TerraformState.DeepCopy()
+	state.ID = id
+
+	r, err := serialization.InstanceStateToStateResource(state, exportOp.TerraformInfo, tfProvider)
+	if err != nil {
+		return nil, fmt.Errorf("error generating terraform state: %w", err)
+	}
+	return r, nil
+}
+
+// toTerraformExport converts the resource into the terraform state and info used to
+// generate its terraform configuration.
+func toTerraformExport(ctx context.Context, u *unstructured.Unstructured, smLoader *servicemappingloader.ServiceMappingLoader, tfProvider *schema.Provider) (*krmtotf.Resource, *operations.TerraformExport, error) {
 	gvk := u.GroupVersionKind()
 	sm, err := smLoader.GetServiceMapping(u.GroupVersionKind().Group)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 	krmResource, err := krmtotf.NewResource(u, sm, tfProvider)
 	if err != nil {
-		return "", fmt.Errorf("could not parse resource %s: %w", u.GetName(), err)
+		return nil, nil, fmt.Errorf("could not parse resource %s: %w", u.GetName(), err)
 	}
 	config, _, err := krmtotf.KRMResourceToTFResourceConfigFull(krmResource, k8s.NewErroringClient(), smLoader, nil, nil, true)
 	if err != nil {
-		return "", fmt.Errorf("error expanding resource configuration: %w", err)
+		return nil, nil, fmt.Errorf("error expanding resource configuration: %w", err)
 	}
 	configAsMap := krmtotf.ResourceConfigToMap(config)
 	tfResource := krmResource.TFResource
@@ -62,25 +123,9 @@ func UnstructuredToHCL(ctx context.Context, u *unstructured.Unstructured, smLoad
 	}
 
 	if err := resourceoverrides.Handler.PreTerraformExport(ctx, gvk, exportOp); err != nil {
-		return "", err
-	}
-
-	hcl, err := serialization.InstanceStateToHCL(exportOp.TerraformState, exportOp.TerraformInfo, tfProvider)
-	if err != nil {
-		return "", fmt.Errorf("error generating hcl: %w", err)
+		return nil, nil, err
 	}
-
-	importID, err := krmResource.GetImportID(k8s.NewErroringClient(), smLoader)
-	if err != nil {
-		return "", fmt.Errorf("error getting import id for '%v': %w", krmResource.GetName(), err)
-	}
-	// append a comment with terraform import command for two reasons:
-	// 1. A human reading the output could use the value to perform an import
-	// 2. gcloud is looking for this output and printing it out for their users
-	//
-	// any changes to the format of this output should be communicated to the gcloud team
-	hcl = fmt.Sprintf("%v# terraform import %v.%v %v\n", hcl, exportOp.TerraformInfo.Type, krmResource.TFInfo.Id, importID)
-	return hcl, nil
+	return krmResource, exportOp, nil
 }
 
 // removingConflictingFields removes values that conflict with each other
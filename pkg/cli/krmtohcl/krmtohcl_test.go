@@ -30,7 +30,9 @@ import (
 	testservicemappingloader "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/test/servicemappingloader"
 	testyaml "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/test/yaml"
 	tfprovider "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tf/provider"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tf/serialization"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -82,6 +84,59 @@ func testUnstructuredToHCL(t *testing.T, krmFile, goldenHCLFile string, smLoader
 	testcmp.UnorderedLineByLineComparisonIgnoreBlankLines(t, goldenHCL, hcl)
 }
 
+// when adding a new test case or when a resource schema changes run this test with '-update' parameter to update the
+// 'golden files'.
+func TestUnstructuredToTFStateResource(t *testing.T) {
+	smLoader := testservicemappingloader.New(t)
+	tfProvider := tfprovider.NewOrLogFatal(tfprovider.UnitTestConfig())
+	testDir := "testdata"
+
+	testCases := FindTestCases(t, testDir, ".golden.tfstate")
+	for _, testCase := range testCases {
+		t.Run(testCase, func(t *testing.T) {
+			krmFile := testCase + ".yaml"
+			goldenStateFile := testCase + ".golden.tfstate"
+
+			testUnstructuredToTFStateResource(t, krmFile, goldenStateFile, smLoader, tfProvider)
+		})
+	}
+}
+
+func testUnstructuredToTFStateResource(t *testing.T, krmFile, goldenStateFile string, smLoader *servicemappingloader.ServiceMappingLoader, tfProvider *schema.Provider) {
+	ctx := context.TODO()
+
+	var u unstructured.Unstructured
+	testyaml.UnmarshalFile(t, krmFile, &u)
+	labels := u.GetLabels()
+	delete(labels, "managed-by-cnrm")
+	u.SetLabels(labels)
+	resource, err := krmtohcl.UnstructuredToTFStateResource(ctx, &u, smLoader, tfProvider)
+	if err != nil {
+		t.Fatalf("error converting unstructured to terraform state: %v", err)
+	}
+	state := serialization.NewState()
+	state.Lineage = "00000000-0000-0000-0000-000000000000"
+	if err := state.AddResource(resource); err != nil {
+		t.Fatalf("error adding resource to terraform state: %v", err)
+	}
+	var b strings.Builder
+	if _, err := state.WriteTo(&b); err != nil {
+		t.Fatalf("error writing terraform state: %v", err)
+	}
+	if *update {
+		if err := ioutil.WriteFile(goldenStateFile, []byte(b.String()), 0644); err != nil {
+			t.Fatalf("error writing file '%v': %v", goldenStateFile, err)
+		}
+	}
+	bytes, err := ioutil.ReadFile(goldenStateFile)
+	if err != nil {
+		t.Fatalf("error reading file '%v': %v", goldenStateFile, err)
+	}
+	if diff := cmp.Diff(string(bytes), b.String()); diff != "" {
+		t.Errorf("unexpected terraform state (-want +got):\n%v", diff)
+	}
+}
+
 // FindTestCases returns all the test cases under basedir.
 // It only returns ones which match the suffix, and strips the suffix.
 func FindTestCases(t *testing.T, basedir string, suffix string) []string {
@@ -26,6 +26,7 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/outputsink"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/test"
 	tfprovider "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tf/provider"
 
@@ -439,3 +440,59 @@ func unstructuredFromYamlFile(t *testing.T, filePath string) *unstructured.Unstr
 	}
 	return &unstructured.Unstructured{Object: value}
 }
+
+// recordingSink records the names of the resources it receives.
+type recordingSink struct {
+	received []string
+}
+
+func (rs *recordingSink) Receive(_ context.Context, _ []byte, u *unstructured.Unstructured) error {
+	rs.received = append(rs.received, u.GetName())
+	return nil
+}
+
+func (rs *recordingSink) Close() error {
+	return nil
+}
+
+func TestTFStateSink(t *testing.T) {
+	ctx := context.TODO()
+	tmpDir, cleanup := newTmpDir(t)
+	defer cleanup()
+	smLoader, err := servicemappingloader.New()
+	if err != nil {
+		t.Fatalf("error creating service mapping loader: %v", err)
+	}
+	tfProvider := tfprovider.NewOrLogFatal(tfprovider.UnitTestConfig())
+	stateFile := filepath.Join(tmpDir, "terraform.tfstate")
+
+	recorder := &recordingSink{}
+	stateSink := outputsink.NewTFStateSink(recorder, stateFile, smLoader, tfProvider)
+	bucket := unstructuredFromYamlFile(t, "storagebucket.yaml")
+	if err := stateSink.Receive(ctx, []byte("bucket"), bucket); err != nil {
+		t.Fatalf("error receiving resource: %v", err)
+	}
+	unsupported := &unstructured.Unstructured{}
+	unsupported.SetAPIVersion("unsupported.cnrm.cloud.google.com/v1beta1")
+	unsupported.SetKind("UnsupportedKind")
+	unsupported.SetName("unsupported")
+	if err := stateSink.Receive(ctx, []byte("unsupported"), unsupported); err == nil {
+		t.Fatalf("expected an error receiving an unsupported resource")
+	}
+	if want := []string{bucket.GetName()}; !reflect.DeepEqual(recorder.received, want) {
+		t.Errorf("resources passed to the wrapped sink: got %v, want %v", recorder.received, want)
+	}
+
+	if err := stateSink.Close(); err != nil {
+		t.Fatalf("error closing sink: %v", err)
+	}
+	if _, err := os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Errorf("expected no state file to be written on Close, got error %v", err)
+	}
+	if err := stateSink.WriteState(); err != nil {
+		t.Fatalf("error writing state: %v", err)
+	}
+	if state := string(fileToBytes(t, stateFile)); !strings.Contains(state, "google_storage_bucket") {
+		t.Errorf("state file does not contain the bucket:\n%v", state)
+	}
+}
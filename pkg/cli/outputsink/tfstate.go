@@ -0,0 +1,86 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package outputsink
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/krmtohcl"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tf/serialization"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TFStateSink wraps an OutputSink receiving HCL. In addition to passing along the received bytes,
+// it records each received resource in a terraform state file, which is written by WriteState. The
+// resource addresses in the state file match the addresses in the HCL, so that 'terraform plan'
+// on the combined output shows no changes.
+type TFStateSink struct {
+	OutputSink
+
+	filePath   string
+	smLoader   *servicemappingloader.ServiceMappingLoader
+	tfProvider *schema.Provider
+	state      *serialization.State
+}
+
+// NewTFStateSink returns a TFStateSink which writes the terraform state to the file at filePath.
+func NewTFStateSink(sink OutputSink, filePath string, smLoader *servicemappingloader.ServiceMappingLoader, tfProvider *schema.Provider) *TFStateSink {
+	return &TFStateSink{
+		OutputSink: sink,
+		filePath:   filePath,
+		smLoader:   smLoader,
+		tfProvider: tfProvider,
+		state:      serialization.NewState(),
+	}
+}
+
+// Receive records the resource in the terraform state and passes it along to the wrapped sink. A resource
+// which cannot be recorded is not passed along, so that the HCL and the state always match.
+func (ts *TFStateSink) Receive(ctx context.Context, bytes []byte, unstructured *unstructured.Unstructured) error {
+	resource, err := krmtohcl.UnstructuredToTFStateResource(ctx, unstructured, ts.smLoader, ts.tfProvider)
+	if err != nil {
+		return fmt.Errorf("error converting krm to terraform state: %w", err)
+	}
+	if err := ts.state.AddResource(resource); err != nil {
+		return err
+	}
+	if err := ts.OutputSink.Receive(ctx, bytes, unstructured); err != nil {
+		ts.state.Resources = ts.state.Resources[:len(ts.state.Resources)-1]
+		return err
+	}
+	return nil
+}
+
+// WriteState writes the terraform state file. It should only be called once all resources have been
+// received successfully, a TFStateSink which is closed without calling WriteState leaves no state file behind.
+func (ts *TFStateSink) WriteState() error {
+	file, err := os.Create(ts.filePath)
+	if err != nil {
+		return fmt.Errorf("error opening file '%v': %w", ts.filePath, err)
+	}
+	if _, err := ts.state.WriteTo(file); err != nil {
+		file.Close()
+		return fmt.Errorf("error writing terraform state to '%v': %w", ts.filePath, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error closing '%v': %w", ts.filePath, err)
+	}
+	return nil
+}
@@ -56,7 +56,7 @@ func (d *ObjectDiff) walkMap(oldObj, newObj map[string]any, fieldPath *FieldPath
 func (d *ObjectDiff) walkSlice(oldSlice, newSlice []any, fieldPath *FieldPath) {
 	minLen := min(len(oldSlice), len(newSlice))
 	for i := 0; i < minLen; i++ {
-		oldValue := newSlice[i]
+		oldValue := oldSlice[i]
 		newValue := newSlice[i]
 		d.walkAny(oldValue, newValue, fieldPath.With(fmt.Sprintf("[%d]", i)))
 	}
@@ -65,7 +65,7 @@ func (d *ObjectDiff) walkSlice(oldSlice, newSlice []any, fieldPath *FieldPath) {
 		d.walkAny(nil, newValue, fieldPath.With(fmt.Sprintf("[%d]", i)))
 	}
 	for i := minLen; i < len(oldSlice); i++ {
-		oldValue := newSlice[i]
+		oldValue := oldSlice[i]
 		d.walkAny(oldValue, nil, fieldPath.With(fmt.Sprintf("[%d]", i)))
 	}
 }
@@ -93,6 +93,12 @@ func (d *ObjectDiff) walkAny(oldVal, newVal any, fieldPath *FieldPath) {
 			addDiff = false
 		}
 
+	case nil:
+		// The field was added
+		if newVal == nil {
+			addDiff = false
+		}
+
 	default:
 		klog.Warningf("type %T not handled", oldVal)
 	}
@@ -111,6 +117,11 @@ func BuildObjectDiff(oldObj, newObj *unstructured.Unstructured) (*ObjectDiff, er
 	return d, nil
 }
 
+// HasChanges returns true if any field differs between the old and new objects.
+func (d *ObjectDiff) HasChanges() bool {
+	return len(d.fieldDiffs) != 0
+}
+
 type prettyPrintFieldPath struct {
 	fieldDiff
 	keyPath []string
@@ -89,7 +89,7 @@ func (m *mockGCPClient) Get(_ context.Context, u *unstructured.Unstructured) (*u
 	return &newUnstruct, nil
 }
 
-func (m *mockGCPClient) Apply(_ *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+func (m *mockGCPClient) Apply(_ context.Context, _ *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 	m.t.Fatalf("unimplemented")
 	return nil, nil
 }
@@ -0,0 +1,93 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/log"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ListToUnstructuredResourceStream discovers resources by calling the List APIs of the direct controllers
+// that implement directbase.Lister, instead of relying on Cloud Asset Inventory. Each discovered resource
+// is exported and returned as an unstructured.
+//
+// An error listing one kind, or exporting one resource, is returned from Next, but does not end the stream;
+// subsequent calls to Next continue with the remaining resources.
+type ListToUnstructuredResourceStream struct {
+	parent string
+	config config.ControllerConfig
+
+	groupKinds []schema.GroupKind
+	listed     bool
+	urls       []string
+}
+
+// NewUnstructuredResourceStreamFromList returns a stream of the resources under parent (for example
+// "projects/my-project"). registry.Init must have been called before calling Next.
+func NewUnstructuredResourceStreamFromList(parent string, config *config.ControllerConfig) *ListToUnstructuredResourceStream {
+	stream := ListToUnstructuredResourceStream{
+		parent: parent,
+		config: *config,
+	}
+	return &stream
+}
+
+func (s *ListToUnstructuredResourceStream) Next(ctx context.Context) (*unstructured.Unstructured, error) {
+	if !s.listed {
+		groupKinds, err := registry.ListableGroupKinds()
+		if err != nil {
+			return nil, err
+		}
+		s.groupKinds = groupKinds
+		s.listed = true
+	}
+
+	for {
+		if len(s.urls) != 0 {
+			url := s.urls[0]
+			s.urls = s.urls[1:]
+			u, err := direct.Export(ctx, url, &s.config)
+			if err != nil {
+				return nil, fmt.Errorf("error exporting '%v': %w", url, err)
+			}
+			if u == nil {
+				log.Verbose("skipping '%v', as it is not supported by a direct controller", url)
+				continue
+			}
+			return u, nil
+		}
+
+		if len(s.groupKinds) == 0 {
+			return nil, io.EOF
+		}
+		gk := s.groupKinds[0]
+		s.groupKinds = s.groupKinds[1:]
+		log.Verbose("listing %v resources in %v", gk, s.parent)
+		urls, err := registry.ListURLs(ctx, gk, s.parent)
+		if err != nil {
+			return nil, fmt.Errorf("error listing %v resources in '%v': %w", gk, s.parent, err)
+		}
+		s.urls = urls
+	}
+}
@@ -347,6 +347,19 @@ type ClusterObservedStateStatus struct {
 	ClusterType *string `json:"clusterType,omitempty"`
 }
 
+type ClusterPendingOperationStatus struct {
+	/* The name of the operation, e.g. "projects/p/locations/l/operations/o". */
+	Name string `json:"name"`
+
+	/* The time the operation was started, in RFC3339 format. */
+	// +optional
+	StartTime *string `json:"startTime,omitempty"`
+
+	/* The type of the operation, e.g. "create" or "update". */
+	// +optional
+	Type *string `json:"type,omitempty"`
+}
+
 type AlloyDBClusterStatus struct {
 	/* Conditions represent the latest available observations of the
 	   AlloyDBCluster's current state. */
@@ -383,6 +396,10 @@ type AlloyDBClusterStatus struct {
 	// +optional
 	ObservedState *ClusterObservedStateStatus `json:"observedState,omitempty"`
 
+	/* PendingOperation is the GCP long-running operation which the Config Connector controller is waiting for, if any. */
+	// +optional
+	PendingOperation *ClusterPendingOperationStatus `json:"pendingOperation,omitempty"`
+
 	/* The system-generated UID of the resource. */
 	// +optional
 	Uid *string `json:"uid,omitempty"`
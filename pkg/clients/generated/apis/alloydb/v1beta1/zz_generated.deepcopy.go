@@ -395,6 +395,11 @@ func (in *AlloyDBClusterStatus) DeepCopyInto(out *AlloyDBClusterStatus) {
 		*out = new(ClusterObservedStateStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PendingOperation != nil {
+		in, out := &in.PendingOperation, &out.PendingOperation
+		*out = new(ClusterPendingOperationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Uid != nil {
 		in, out := &in.Uid, &out.Uid
 		*out = new(string)
@@ -1153,6 +1158,32 @@ func (in *ClusterPassword) DeepCopy() *ClusterPassword {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPendingOperationStatus) DeepCopyInto(out *ClusterPendingOperationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = new(string)
+		**out = **in
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPendingOperationStatus.
+func (in *ClusterPendingOperationStatus) DeepCopy() *ClusterPendingOperationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPendingOperationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterQuantityBasedRetention) DeepCopyInto(out *ClusterQuantityBasedRetention) {
 	*out = *in
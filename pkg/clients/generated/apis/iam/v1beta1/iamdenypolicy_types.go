@@ -0,0 +1,140 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// ----------------------------------------------------------------------------
+//
+//     ***     AUTO GENERATED CODE    ***    AUTO GENERATED CODE     ***
+//
+// ----------------------------------------------------------------------------
+//
+//     This file is automatically generated by Config Connector and manual
+//     changes will be clobbered when the file is regenerated.
+//
+// ----------------------------------------------------------------------------
+
+// *** DISCLAIMER ***
+// Config Connector's go-client for CRDs is currently in ALPHA, which means
+// that future versions of the go-client may include breaking changes.
+// Please try it out and give us feedback!
+
+package v1beta1
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/clients/generated/apis/k8s/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type DenypolicyDenialCondition struct {
+	// +optional
+	Description *string `json:"description,omitempty"`
+
+	Expression string `json:"expression"`
+
+	Title string `json:"title"`
+}
+
+type DenypolicyDenyRule struct {
+	/* Optional. The condition that determines whether this deny rule applies to a request. */
+	// +optional
+	DenialCondition *DenypolicyDenialCondition `json:"denialCondition,omitempty"`
+
+	/* Required. The permissions that are explicitly denied by this rule, in the format '{service_fqdn}/{resource}.{verb}', e.g. 'iam.googleapis.com/roles.list'. */
+	DeniedPermissions []string `json:"deniedPermissions"`
+
+	/* Required. The identities that are prevented from using one or more permissions on Google Cloud resources, e.g. 'principal://goog/subject/user@example.com' or 'principalSet://goog/public:all'. */
+	DeniedPrincipals []string `json:"deniedPrincipals"`
+
+	/* Optional. The permissions that are excluded from the set of denied permissions. */
+	// +optional
+	ExceptionPermissions []string `json:"exceptionPermissions,omitempty"`
+
+	/* Optional. The identities that are excluded from the deny rule, even if they are listed in deniedPrincipals. */
+	// +optional
+	ExceptionPrincipals []string `json:"exceptionPrincipals,omitempty"`
+}
+
+type DenypolicyRules struct {
+	/* Required. The deny rule. */
+	DenyRule DenypolicyDenyRule `json:"denyRule"`
+
+	/* Optional. A user-specified description of the rule. */
+	// +optional
+	Description *string `json:"description,omitempty"`
+}
+
+type IAMDenyPolicySpec struct {
+	/* Optional. A user-specified description of the deny policy. */
+	// +optional
+	DisplayName *string `json:"displayName,omitempty"`
+
+	/* Immutable. Optional. The ID of the deny policy. If not given, the metadata.name of the resource is used. */
+	// +optional
+	ResourceID *string `json:"resourceID,omitempty"`
+
+	/* Immutable. Required. The GCP resource the deny policy is attached to. Only Project, Folder and Organization are supported. */
+	ResourceRef v1alpha1.ResourceRef `json:"resourceRef"`
+
+	/* Required. The rules of the deny policy. */
+	Rules []DenypolicyRules `json:"rules"`
+}
+
+type IAMDenyPolicyStatus struct {
+	/* Conditions represent the latest available observations of the
+	   IAMDenyPolicy's current state. */
+	Conditions []v1alpha1.Condition `json:"conditions,omitempty"`
+	/* Etag is the etag of the deny policy most recently applied by Config Connector. */
+	// +optional
+	Etag *string `json:"etag,omitempty"`
+
+	/* ObservedGeneration is the generation of the resource that was most recently observed by the Config Connector controller. If this is equal to metadata.generation, then that means that the current reported status reflects the most recent desired state of the resource. */
+	// +optional
+	ObservedGeneration *int64 `json:"observedGeneration,omitempty"`
+
+	/* UID is the globally unique ID of the deny policy. */
+	// +optional
+	Uid *string `json:"uid,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:categories=gcp,shortName=gcpiamdenypolicy;gcpiamdenypolicies
+// +kubebuilder:subresource:status
+// +kubebuilder:metadata:labels="cnrm.cloud.google.com/managed-by-kcc=true";"cnrm.cloud.google.com/system=true"
+// +kubebuilder:printcolumn:name="Age",JSONPath=".metadata.creationTimestamp",type="date"
+// +kubebuilder:printcolumn:name="Ready",JSONPath=".status.conditions[?(@.type=='Ready')].status",type="string",description="When 'True' the most recent reconcile of the resource succeeded"
+// +kubebuilder:printcolumn:name="Status",JSONPath=".status.conditions[?(@.type=='Ready')].reason",type="string",description="The reason for the value in 'Ready'"
+// +kubebuilder:printcolumn:name="Status Age",JSONPath=".status.conditions[?(@.type=='Ready')].lastTransitionTime",type="date"
+
+// IAMDenyPolicy is the Schema for the iam API
+// +k8s:openapi-gen=true
+type IAMDenyPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IAMDenyPolicySpec   `json:"spec,omitempty"`
+	Status IAMDenyPolicyStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IAMDenyPolicyList contains a list of IAMDenyPolicy
+type IAMDenyPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IAMDenyPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IAMDenyPolicy{}, &IAMDenyPolicyList{})
+}
@@ -90,9 +90,17 @@ type IAMPolicyMemberStatus struct {
 	/* Conditions represent the latest available observations of the
 	   IAMPolicyMember's current state. */
 	Conditions []v1alpha1.Condition `json:"conditions,omitempty"`
+	/* Etag is the etag of the IAM policy most recently applied by Config Connector. */
+	// +optional
+	Etag *string `json:"etag,omitempty"`
+
 	/* ObservedGeneration is the generation of the resource that was most recently observed by the Config Connector controller. If this is equal to metadata.generation, then that means that the current reported status reflects the most recent desired state of the resource. */
 	// +optional
 	ObservedGeneration *int64 `json:"observedGeneration,omitempty"`
+
+	/* Version is the version of the IAM policy required by the member's binding: 3 if the binding has a condition, and 1 otherwise. */
+	// +optional
+	Version *int64 `json:"version,omitempty"`
 }
 
 // +genclient
@@ -71,6 +71,12 @@ var (
 		Kind:    reflect.TypeOf(IAMCustomRole{}).Name(),
 	}
 
+	IAMDenyPolicyGVK = schema.GroupVersionKind{
+		Group:   SchemeGroupVersion.Group,
+		Version: SchemeGroupVersion.Version,
+		Kind:    reflect.TypeOf(IAMDenyPolicy{}).Name(),
+	}
+
 	IAMPartialPolicyGVK = schema.GroupVersionKind{
 		Group:   SchemeGroupVersion.Group,
 		Version: SchemeGroupVersion.Version,
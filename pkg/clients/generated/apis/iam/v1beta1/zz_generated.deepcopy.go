@@ -138,6 +138,90 @@ func (in *AuditconfigAuditLogConfigs) DeepCopy() *AuditconfigAuditLogConfigs {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DenypolicyDenialCondition) DeepCopyInto(out *DenypolicyDenialCondition) {
+	*out = *in
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DenypolicyDenialCondition.
+func (in *DenypolicyDenialCondition) DeepCopy() *DenypolicyDenialCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(DenypolicyDenialCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DenypolicyDenyRule) DeepCopyInto(out *DenypolicyDenyRule) {
+	*out = *in
+	if in.DenialCondition != nil {
+		in, out := &in.DenialCondition, &out.DenialCondition
+		*out = new(DenypolicyDenialCondition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeniedPermissions != nil {
+		in, out := &in.DeniedPermissions, &out.DeniedPermissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedPrincipals != nil {
+		in, out := &in.DeniedPrincipals, &out.DeniedPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExceptionPermissions != nil {
+		in, out := &in.ExceptionPermissions, &out.ExceptionPermissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExceptionPrincipals != nil {
+		in, out := &in.ExceptionPrincipals, &out.ExceptionPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DenypolicyDenyRule.
+func (in *DenypolicyDenyRule) DeepCopy() *DenypolicyDenyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DenypolicyDenyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DenypolicyRules) DeepCopyInto(out *DenypolicyRules) {
+	*out = *in
+	in.DenyRule.DeepCopyInto(&out.DenyRule)
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DenypolicyRules.
+func (in *DenypolicyRules) DeepCopy() *DenypolicyRules {
+	if in == nil {
+		return nil
+	}
+	out := new(DenypolicyRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IAMAccessBoundaryPolicy) DeepCopyInto(out *IAMAccessBoundaryPolicy) {
 	*out = *in
@@ -357,6 +441,11 @@ func (in *IAMAuditConfigStatus) DeepCopyInto(out *IAMAuditConfigStatus) {
 		*out = make([]v1alpha1.Condition, len(*in))
 		copy(*out, *in)
 	}
+	if in.Etag != nil {
+		in, out := &in.Etag, &out.Etag
+		*out = new(string)
+		**out = **in
+	}
 	if in.ObservedGeneration != nil {
 		in, out := &in.ObservedGeneration, &out.ObservedGeneration
 		*out = new(int64)
@@ -508,6 +597,137 @@ func (in *IAMCustomRoleStatus) DeepCopy() *IAMCustomRoleStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IAMDenyPolicy) DeepCopyInto(out *IAMDenyPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMDenyPolicy.
+func (in *IAMDenyPolicy) DeepCopy() *IAMDenyPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(IAMDenyPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IAMDenyPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IAMDenyPolicyList) DeepCopyInto(out *IAMDenyPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IAMDenyPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMDenyPolicyList.
+func (in *IAMDenyPolicyList) DeepCopy() *IAMDenyPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(IAMDenyPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IAMDenyPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IAMDenyPolicySpec) DeepCopyInto(out *IAMDenyPolicySpec) {
+	*out = *in
+	if in.DisplayName != nil {
+		in, out := &in.DisplayName, &out.DisplayName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResourceID != nil {
+		in, out := &in.ResourceID, &out.ResourceID
+		*out = new(string)
+		**out = **in
+	}
+	out.ResourceRef = in.ResourceRef
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]DenypolicyRules, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMDenyPolicySpec.
+func (in *IAMDenyPolicySpec) DeepCopy() *IAMDenyPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IAMDenyPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IAMDenyPolicyStatus) DeepCopyInto(out *IAMDenyPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1alpha1.Condition, len(*in))
+		copy(*out, *in)
+	}
+	if in.Etag != nil {
+		in, out := &in.Etag, &out.Etag
+		*out = new(string)
+		**out = **in
+	}
+	if in.ObservedGeneration != nil {
+		in, out := &in.ObservedGeneration, &out.ObservedGeneration
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Uid != nil {
+		in, out := &in.Uid, &out.Uid
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMDenyPolicyStatus.
+func (in *IAMDenyPolicyStatus) DeepCopy() *IAMDenyPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IAMDenyPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IAMPartialPolicy) DeepCopyInto(out *IAMPartialPolicy) {
 	*out = *in
@@ -608,6 +828,11 @@ func (in *IAMPartialPolicyStatus) DeepCopyInto(out *IAMPartialPolicyStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Etag != nil {
+		in, out := &in.Etag, &out.Etag
+		*out = new(string)
+		**out = **in
+	}
 	if in.LastAppliedBindings != nil {
 		in, out := &in.LastAppliedBindings, &out.LastAppliedBindings
 		*out = make([]PartialpolicyLastAppliedBindingsStatus, len(*in))
@@ -620,6 +845,11 @@ func (in *IAMPartialPolicyStatus) DeepCopyInto(out *IAMPartialPolicyStatus) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -795,11 +1025,21 @@ func (in *IAMPolicyMemberStatus) DeepCopyInto(out *IAMPolicyMemberStatus) {
 		*out = make([]v1alpha1.Condition, len(*in))
 		copy(*out, *in)
 	}
+	if in.Etag != nil {
+		in, out := &in.Etag, &out.Etag
+		*out = new(string)
+		**out = **in
+	}
 	if in.ObservedGeneration != nil {
 		in, out := &in.ObservedGeneration, &out.ObservedGeneration
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -852,11 +1092,21 @@ func (in *IAMPolicyStatus) DeepCopyInto(out *IAMPolicyStatus) {
 		*out = make([]v1alpha1.Condition, len(*in))
 		copy(*out, *in)
 	}
+	if in.Etag != nil {
+		in, out := &in.Etag, &out.Etag
+		*out = new(string)
+		**out = **in
+	}
 	if in.ObservedGeneration != nil {
 		in, out := &in.ObservedGeneration, &out.ObservedGeneration
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
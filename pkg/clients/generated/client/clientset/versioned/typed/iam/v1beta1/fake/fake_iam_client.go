@@ -43,6 +43,10 @@ func (c *FakeIamV1beta1) IAMCustomRoles(namespace string) v1beta1.IAMCustomRoleI
 	return &FakeIAMCustomRoles{c, namespace}
 }
 
+func (c *FakeIamV1beta1) IAMDenyPolicies(namespace string) v1beta1.IAMDenyPolicyInterface {
+	return &FakeIAMDenyPolicies{c, namespace}
+}
+
 func (c *FakeIamV1beta1) IAMPartialPolicies(namespace string) v1beta1.IAMPartialPolicyInterface {
 	return &FakeIAMPartialPolicies{c, namespace}
 }
@@ -27,6 +27,8 @@ type IAMAuditConfigExpansion interface{}
 
 type IAMCustomRoleExpansion interface{}
 
+type IAMDenyPolicyExpansion interface{}
+
 type IAMPartialPolicyExpansion interface{}
 
 type IAMPolicyExpansion interface{}
@@ -34,6 +34,7 @@ type IamV1beta1Interface interface {
 	IAMAccessBoundaryPoliciesGetter
 	IAMAuditConfigsGetter
 	IAMCustomRolesGetter
+	IAMDenyPoliciesGetter
 	IAMPartialPoliciesGetter
 	IAMPoliciesGetter
 	IAMPolicyMembersGetter
@@ -62,6 +63,10 @@ func (c *IamV1beta1Client) IAMCustomRoles(namespace string) IAMCustomRoleInterfa
 	return newIAMCustomRoles(c, namespace)
 }
 
+func (c *IamV1beta1Client) IAMDenyPolicies(namespace string) IAMDenyPolicyInterface {
+	return newIAMDenyPolicies(c, namespace)
+}
+
 func (c *IamV1beta1Client) IAMPartialPolicies(namespace string) IAMPartialPolicyInterface {
 	return newIAMPartialPolicies(c, namespace)
 }
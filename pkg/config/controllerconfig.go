@@ -17,6 +17,10 @@ package config
 import (
 	"net/http"
 
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/credentials"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/ratelimit"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tracing"
+
 	"golang.org/x/oauth2"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
@@ -44,6 +48,18 @@ type ControllerConfig struct {
 	// GCPTokenSource mints OAuth2 tokens to be passed with GCP API calls,
 	// allowing use of a non-default OAuth2 identity
 	GCPTokenSource oauth2.TokenSource
+
+	// GCPRateLimiter, if set, throttles the requests made to GCP APIs. For REST clients
+	// it only applies when HTTPClient is also set.
+	GCPRateLimiter *ratelimit.Limiter
+
+	// GCPCredentialSelector, if set, selects the service account used for the requests made
+	// to GCP APIs. For REST clients it only applies when HTTPClient is also set.
+	GCPCredentialSelector *credentials.Selector
+
+	// GCPTracing, if set, records the requests made to GCP APIs as OpenTelemetry spans.
+	// For REST clients it only applies when HTTPClient is also set.
+	GCPTracing bool
 }
 
 func (c *ControllerConfig) RESTClientOptions() ([]option.ClientOption, error) {
@@ -59,10 +75,20 @@ func (c *ControllerConfig) RESTClientOptions() ([]option.ClientOption, error) {
 	if c.HTTPClient != nil {
 		httpClient := &http.Client{}
 		*httpClient = *c.HTTPClient
+		inner := c.HTTPClient.Transport
+		if c.GCPCredentialSelector != nil {
+			inner = c.GCPCredentialSelector.RoundTripper(inner)
+		}
 		httpClient.Transport = &optionsRoundTripper{
 			config:       *c,
 			quotaProject: quotaProject,
-			inner:        c.HTTPClient.Transport,
+			inner:        inner,
+		}
+		if c.GCPRateLimiter != nil {
+			httpClient.Transport = c.GCPRateLimiter.RoundTripper(httpClient.Transport)
+		}
+		if c.GCPTracing {
+			httpClient.Transport = tracing.RoundTripper(httpClient.Transport)
 		}
 		opts = append(opts, option.WithHTTPClient(httpClient))
 
@@ -89,14 +115,34 @@ func (c *ControllerConfig) GRPCClientOptions() ([]option.ClientOption, error) {
 	if c.UserAgent != "" {
 		opts = append(opts, option.WithUserAgent(c.UserAgent))
 	}
+	quotaProject := ""
 	if c.UserProjectOverride && c.BillingProject != "" {
-		opts = append(opts, option.WithQuotaProject(c.BillingProject))
+		quotaProject = c.BillingProject
 	}
-	if c.GCPTokenSource != nil {
-		opts = append(opts, option.WithTokenSource(c.GCPTokenSource))
+	if c.GCPCredentialSelector != nil {
+		// The credentials are selected per call, so they replace the credentials of the connection.
+		opts = append(opts, option.WithoutAuthentication())
+		opts = append(opts, option.WithGRPCDialOption(grpc.WithPerRPCCredentials(c.GCPCredentialSelector.PerRPCCredentials(c.GCPTokenSource, quotaProject))))
+	} else {
+		if quotaProject != "" {
+			opts = append(opts, option.WithQuotaProject(quotaProject))
+		}
+		if c.GCPTokenSource != nil {
+			opts = append(opts, option.WithTokenSource(c.GCPTokenSource))
+		}
+	}
+	var interceptors []grpc.UnaryClientInterceptor
+	if c.GCPRateLimiter != nil {
+		interceptors = append(interceptors, c.GCPRateLimiter.UnaryClientInterceptor())
 	}
 	if c.GRPCUnaryClientInterceptor != nil {
-		opts = append(opts, option.WithGRPCDialOption(grpc.WithUnaryInterceptor(c.GRPCUnaryClientInterceptor)))
+		interceptors = append(interceptors, c.GRPCUnaryClientInterceptor)
+	}
+	if len(interceptors) != 0 {
+		opts = append(opts, option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(interceptors...)))
+	}
+	if c.GCPTracing {
+		opts = append(opts, option.WithGRPCDialOption(grpc.WithStatsHandler(tracing.GRPCStatsHandler())))
 	}
 
 	// TODO: support endpoints?
@@ -17,8 +17,10 @@ package controller
 import (
 	"github.com/GoogleCloudPlatform/declarative-resource-client-library/dcl"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/jitter"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/ordereddeletion"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/conversion"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/lease/leaser"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
 	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -31,4 +33,9 @@ type Deps struct {
 	DclConverter *conversion.Converter
 	Defaulters   []k8s.Defaulter
 	JitterGen    jitter.Generator
+	// LeaseBackends are the external backends of the management conflict prevention leases.
+	LeaseBackends leaser.Backends
+	// OrderedDeletion holds the resources which block the deletion of other resources in the
+	// namespaces under ordered deletion; the deletion defender doesn't wait for them if nil.
+	OrderedDeletion *ordereddeletion.Tracker
 }
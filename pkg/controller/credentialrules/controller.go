@@ -0,0 +1,121 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package credentialrules keeps the credential selector of a namespaced controller
+// manager in sync with the credentialRules of the namespace's ConfigConnectorContext,
+// so that service accounts can be added or rotated without restarting the manager.
+package credentialrules
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/v1beta1"
+	operatork8s "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/k8s"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/credentials"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	klog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const controllerName = "credential-rules-controller"
+
+var logger = klog.Log.WithName(controllerName)
+
+type Reconciler struct {
+	client.Client
+	selector *credentials.Selector
+}
+
+// Add registers a controller which sets the rules of selector from the ConfigConnectorContext
+// of the namespace the manager is scoped to.
+func Add(mgr manager.Manager, selector *credentials.Selector) error {
+	r := &Reconciler{
+		Client:   mgr.GetClient(),
+		selector: selector,
+	}
+	_, err := builder.
+		ControllerManagedBy(mgr).
+		Named(controllerName).
+		For(&operatorv1beta1.ConfigConnectorContext{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc:  func(e event.CreateEvent) bool { return isAllowedName(e.Object) },
+			UpdateFunc:  func(e event.UpdateEvent) bool { return isAllowedName(e.ObjectNew) },
+			DeleteFunc:  func(e event.DeleteEvent) bool { return isAllowedName(e.Object) },
+			GenericFunc: func(e event.GenericEvent) bool { return isAllowedName(e.Object) },
+		})).
+		Build(r)
+	if err != nil {
+		return fmt.Errorf("error creating new controller: %w", err)
+	}
+	return nil
+}
+
+func isAllowedName(obj client.Object) bool {
+	return obj.GetName() == operatork8s.ConfigConnectorContextAllowedName
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	if err := syncRules(ctx, r.Client, req.NamespacedName, r.selector); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// Load sets the rules of selector from the ConfigConnectorContext of namespace once. It should
+// be called with an uncached reader before adding the controllers whose requests are subject
+// to the rules, so that they do not start with the default credentials while the controller
+// registered by Add has yet to reconcile.
+func Load(ctx context.Context, reader client.Reader, namespace string, selector *credentials.Selector) error {
+	key := types.NamespacedName{Namespace: namespace, Name: operatork8s.ConfigConnectorContextAllowedName}
+	if err := syncRules(ctx, reader, key, selector); err != nil {
+		return fmt.Errorf("error loading credential rules: %w", err)
+	}
+	return nil
+}
+
+func syncRules(ctx context.Context, reader client.Reader, key types.NamespacedName, selector *credentials.Selector) error {
+	ccc := &operatorv1beta1.ConfigConnectorContext{}
+	if err := reader.Get(ctx, key, ccc); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("ConfigConnectorContext not found; clearing credential rules", "resource", key)
+			selector.SetRules(nil)
+			return nil
+		}
+		return err
+	}
+	rules := RulesFromConfigConnectorContext(ccc)
+	selector.SetRules(rules)
+	logger.Info("updated credential rules", "resource", key, "rules", len(rules))
+	return nil
+}
+
+// RulesFromConfigConnectorContext returns the credential rules of ccc.
+func RulesFromConfigConnectorContext(ccc *operatorv1beta1.ConfigConnectorContext) []credentials.Rule {
+	var rules []credentials.Rule
+	for _, rule := range ccc.Spec.CredentialRules {
+		rules = append(rules, credentials.Rule{
+			GoogleServiceAccount: rule.GoogleServiceAccount,
+			Kinds:                rule.Kinds,
+			Projects:             rule.Projects,
+		})
+	}
+	return rules
+}
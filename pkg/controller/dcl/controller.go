@@ -28,6 +28,7 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/lifecyclehandler"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/metrics"
 	kccpredicate "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/predicate"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/priorityqueue"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/ratelimiter"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourceactuation"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourcewatcher"
@@ -40,11 +41,13 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/livestate"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/schema/dclschemaloader"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/execution"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/credentials"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/lease/leasable"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/lease/leaser"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/resourceoverrides"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tracing"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/util"
 
 	mmdcl "github.com/GoogleCloudPlatform/declarative-resource-client-library/dcl"
@@ -100,7 +103,7 @@ type Reconciler struct {
 
 func Add(mgr manager.Manager, crd *apiextensions.CustomResourceDefinition, converter *conversion.Converter,
 	dclConfig *mmdcl.Config, serviceMappingLoader *servicemappingloader.ServiceMappingLoader, defaulters []k8s.Defaulter, jitterGenerator jitter.Generator,
-	additionalPredicate predicate.Predicate) (k8s.SchemaReferenceUpdater, error) {
+	leaseBackends leaser.Backends, additionalPredicate predicate.Predicate) (k8s.SchemaReferenceUpdater, error) {
 	if jitterGenerator == nil {
 		return nil, fmt.Errorf("jitter generator not initialized")
 	}
@@ -113,7 +116,7 @@ func Add(mgr manager.Manager, crd *apiextensions.CustomResourceDefinition, conve
 	controllerName := fmt.Sprintf("%v-controller", strings.ToLower(kind))
 	immediateReconcileRequests := make(chan event.GenericEvent, k8s.ImmediateReconcileRequestsBufferSize)
 	resourceWatcherRoutines := semaphore.NewWeighted(k8s.MaxNumResourceWatcherRoutines)
-	r, err := NewReconciler(mgr, crd, converter, dclConfig, serviceMappingLoader, immediateReconcileRequests, resourceWatcherRoutines, defaulters, jitterGenerator)
+	r, err := NewReconciler(mgr, crd, converter, dclConfig, serviceMappingLoader, immediateReconcileRequests, resourceWatcherRoutines, defaulters, jitterGenerator, leaseBackends)
 	if err != nil {
 		return nil, err
 	}
@@ -123,13 +126,14 @@ func Add(mgr manager.Manager, crd *apiextensions.CustomResourceDefinition, conve
 			"apiVersion": apiVersion,
 		},
 	}
+	gate := priorityqueue.NewGate(obj.GroupVersionKind(), k8s.ControllerMaxConcurrentReconciles)
 	_, err = builder.
 		ControllerManagedBy(mgr).
 		Named(controllerName).
 		WithOptions(controller.Options{MaxConcurrentReconciles: k8s.ControllerMaxConcurrentReconciles, RateLimiter: ratelimiter.NewRateLimiter()}).
-		WatchesRawSource(&source.Channel{Source: immediateReconcileRequests}, &handler.EnqueueRequestForObject{}).
-		For(obj, builder.OnlyMetadata, builder.WithPredicates(predicates...)).
-		Build(r)
+		WatchesRawSource(gate.Source(&source.Channel{Source: immediateReconcileRequests}), &handler.EnqueueRequestForObject{}).
+		WatchesRawSource(gate.Kind(mgr.GetCache(), obj.GroupVersionKind()), &handler.EnqueueRequestForObject{}, builder.WithPredicates(predicates...)).
+		Build(gate.Reconciler(r))
 	if err != nil {
 		return nil, fmt.Errorf("error creating new controller: %w", err)
 	}
@@ -137,7 +141,7 @@ func Add(mgr manager.Manager, crd *apiextensions.CustomResourceDefinition, conve
 	return r, nil
 }
 
-func NewReconciler(mgr manager.Manager, crd *apiextensions.CustomResourceDefinition, converter *conversion.Converter, dclConfig *mmdcl.Config, serviceMappingLoader *servicemappingloader.ServiceMappingLoader, immediateReconcileRequests chan event.GenericEvent, resourceWatcherRoutines *semaphore.Weighted, defaulters []k8s.Defaulter, jitterGenerator jitter.Generator) (*Reconciler, error) {
+func NewReconciler(mgr manager.Manager, crd *apiextensions.CustomResourceDefinition, converter *conversion.Converter, dclConfig *mmdcl.Config, serviceMappingLoader *servicemappingloader.ServiceMappingLoader, immediateReconcileRequests chan event.GenericEvent, resourceWatcherRoutines *semaphore.Weighted, defaulters []k8s.Defaulter, jitterGenerator jitter.Generator, leaseBackends leaser.Backends) (*Reconciler, error) {
 	controllerName := fmt.Sprintf("%v-controller", strings.ToLower(crd.Spec.Names.Kind))
 	gvk := schema.GroupVersionKind{
 		Group:   crd.Spec.Group,
@@ -154,16 +158,13 @@ func NewReconciler(mgr manager.Manager, crd *apiextensions.CustomResourceDefinit
 			mgr.GetClient(),
 			mgr.GetEventRecorderFor(controllerName),
 		),
-		ReconcilerMetrics: metrics.ReconcilerMetrics{
-			ResourceNameLabel: metrics.ResourceNameLabel,
-		},
 		mgr: mgr,
 		schemaRef: &k8s.SchemaReference{
 			CRD:        crd,
 			JSONSchema: k8s.GetOpenAPIV3SchemaFromCRD(crd),
 			GVK:        gvk,
 		},
-		resourceLeaser:             leaser.NewResourceLeaser(nil, nil, mgr.GetClient()),
+		resourceLeaser:             leaser.NewResourceLeaser(nil, nil, mgr.GetClient(), leaseBackends),
 		defaulters:                 defaulters,
 		schema:                     dclSchema,
 		logger:                     logger.WithName(controllerName),
@@ -181,9 +182,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (res
 	defer r.schemaRefMu.RUnlock()
 	r.logger.Info("starting reconcile", "resource", req.NamespacedName)
 	startTime := time.Now()
+	ctx, span := tracing.StartReconcile(ctx, r.schemaRef.GVK, req.NamespacedName)
+	defer func() { tracing.End(span, err) }()
 	r.RecordReconcileWorkers(ctx, r.schemaRef.GVK)
 	defer r.AfterReconcile()
 	defer r.RecordReconcileMetrics(ctx, r.schemaRef.GVK, req.Namespace, req.Name, startTime, &err)
+	ctx = credentials.WithKind(ctx, r.schemaRef.GVK.Kind)
 
 	u := &unstructured.Unstructured{}
 	u.SetGroupVersionKind(r.schemaRef.GVK)
@@ -195,6 +199,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (res
 		}
 		return reconcile.Result{}, err
 	}
+	if k8s.HasReconcilePausedAnnotation(u) {
+		r.logger.Info("Skipping reconcile as the resource has the reconcile-paused annotation", "resource", req.NamespacedName)
+		resource, err := k8s.NewResource(u)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("could not parse resource %s: %w", req.NamespacedName.String(), err)
+		}
+		return reconcile.Result{}, r.HandlePaused(ctx, resource)
+	}
 	skip, err := resourceactuation.ShouldSkip(u)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -224,9 +236,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (res
 		return reconcile.Result{}, err
 	}
 
-	am := resourceactuation.DecideActuationMode(cc, ccc)
-	switch am {
+	now := time.Now()
+	decision, err := resourceactuation.DecideActuationMode(cc, ccc, now)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	switch am := decision.Mode; am {
 	case v1beta1.Reconciling:
+		if decision.PendingActuationWindow {
+			jitteredPeriod, err := r.jitterGenerator.JitteredReenqueue(r.schemaRef.GVK, u)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+
+			ensureFinalizers := func() error {
+				return r.EnsureFinalizers(ctx, resource.Original, &resource.Resource, k8s.ControllerFinalizerName, k8s.DeletionDefenderFinalizerName)
+			}
+			toResource := func() (*k8s.Resource, error) { return &resource.Resource, nil }
+			requeueAfter := decision.RequeueAfter(jitteredPeriod, now)
+			return reconcile.Result{RequeueAfter: requeueAfter}, decision.HandlePendingActuationWindow(ctx, r, &resource.Resource, ensureFinalizers, toResource)
+		}
 		r.logger.V(2).Info("Actuating a resource as actuation mode is \"Reconciling\"", "resource", req.NamespacedName)
 	case v1beta1.Paused:
 		jitteredPeriod, err := r.jitterGenerator.JitteredReenqueue(r.schemaRef.GVK, u)
@@ -275,7 +304,9 @@ func (r *Reconciler) sync(ctx context.Context, resource *dcl.Resource) (requeue
 		return r.finalizeResourceDeletion(ctx, resource, dclConfig)
 	}
 
-	liveLite, err := livestate.FetchLiveState(ctx, resource, dclConfig, r.converter, r.serviceMappingLoader, r.Client)
+	fetchCtx, span := tracing.Start(ctx, "FetchLiveState")
+	liveLite, err := livestate.FetchLiveState(fetchCtx, resource, dclConfig, r.converter, r.serviceMappingLoader, r.Client)
+	tracing.End(span, err)
 	if err != nil {
 		if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
 			r.logger.Info(unwrappedErr.Error(), "resource", resource.GetNamespacedName())
@@ -312,8 +343,10 @@ func (r *Reconciler) sync(ctx context.Context, resource *dcl.Resource) (requeue
 	if err != nil {
 		return false, r.HandleUpdateFailed(ctx, &resource.Resource, err)
 	}
-	// KCC Full to KCC Lite
+	// KCC Full to KCC Lite, which resolves the references of the resource
+	_, span = tracing.Start(ctx, "ResolveReferences")
 	lite, secretVersions, err := kcclite.ToKCCLiteAndSecretVersions(desired, r.converter.MetadataLoader, r.converter.SchemaLoader, r.serviceMappingLoader, r.Client)
+	tracing.End(span, err)
 	if err != nil {
 		if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
 			r.logger.Info(unwrappedErr.Error(), "resource", resource.GetNamespacedName())
@@ -354,7 +387,9 @@ func (r *Reconciler) sync(ctx context.Context, resource *dcl.Resource) (requeue
 		return false, err
 	}
 	lifecycleParams := append(LifecycleParams, stateHintApplyOption)
-	newState, err := dclunstruct.Apply(ctx, dclConfig, dclResource, lifecycleParams...)
+	applyCtx, span := tracing.Start(ctx, "DCLApply")
+	newState, err := dclunstruct.Apply(applyCtx, dclConfig, dclResource, lifecycleParams...)
+	tracing.End(span, err)
 	if err != nil {
 		r.logger.Error(err, "error applying desired state", "resource", resource.GetNamespacedName())
 		return false, r.HandleUpdateFailed(ctx, &resource.Resource, fmt.Errorf("error applying desired state: %w", err))
@@ -440,6 +475,17 @@ func (r *Reconciler) obtainResourceLeaseIfNecessary(ctx context.Context, resourc
 	if err != nil {
 		return err
 	}
+	if k8s.IsExternalLeasePolicy(conflictPolicy) {
+		resourceID, err := leaseResourceID(resource)
+		if err != nil {
+			return err
+		}
+		if err := r.resourceLeaser.ObtainExternal(ctx, conflictPolicy, &resource.Resource, resourceID); err != nil {
+			return r.HandleObtainLeaseFailed(ctx, &resource.Resource, fmt.Errorf("error obtaining lease on '%v': %w",
+				resource.GetNamespacedName(), err))
+		}
+		return nil
+	}
 	if conflictPolicy != k8s.ManagementConflictPreventionPolicyResource {
 		return nil
 	}
@@ -493,9 +539,33 @@ func (r *Reconciler) handleDeleted(ctx context.Context, resource *dcl.Resource)
 	if err := resourceoverrides.Handler.PostActuationTransform(resource.Original, &resource.Resource, nil, nil); err != nil {
 		return r.HandlePostActuationTransformFailed(ctx, &resource.Resource, fmt.Errorf("error applying post-actuation transformation to resource '%v': %w", resource.GetNamespacedName(), err))
 	}
+	// A failure to release the lease is not fatal: the lease expires on its own.
+	resourceID, err := leaseResourceID(resource)
+	if err == nil {
+		err = r.resourceLeaser.ReleaseExternalIfNecessary(ctx, &resource.Resource, resourceID)
+	}
+	if err != nil {
+		r.logger.Error(err, "error releasing lease", "resource", resource.GetNamespacedName())
+	}
 	return r.HandleDeleted(ctx, &resource.Resource)
 }
 
+// leaseResourceID returns the ID of the GCP resource which identifies its external lease, or ""
+// if the ID is generated by the server and the GCP resource has not been created yet.
+func leaseResourceID(resource *dcl.Resource) (string, error) {
+	notCreated, err := resource.HasServerGeneratedIDButNotConfigured()
+	if err != nil {
+		return "", err
+	}
+	if notCreated {
+		return "", nil
+	}
+	if resourceID, ok := resource.Spec[k8s.ResourceIDFieldName].(string); ok && resourceID != "" {
+		return resourceID, nil
+	}
+	return resource.GetName(), nil
+}
+
 func (r *Reconciler) updateSpecAndStatusWithLiveState(ctx context.Context, liveLite *unstructured.Unstructured, resource *dcl.Resource, secretVersions map[string]string) (requeue bool, err error) {
 	newSpec, newStatus, err := kcclite.ResolveSpecAndStatus(liveLite, resource, r.converter.MetadataLoader)
 	if err != nil {
@@ -595,7 +665,9 @@ func (r *Reconciler) finalizeResourceDeletion(ctx context.Context, resource *dcl
 	}
 
 	// check if the underlying resource exists
-	liveLite, err := livestate.FetchLiveState(ctx, resource, r.dclConfig, r.converter, r.serviceMappingLoader, r.Client)
+	fetchCtx, span := tracing.Start(ctx, "FetchLiveState")
+	liveLite, err := livestate.FetchLiveState(fetchCtx, resource, r.dclConfig, r.converter, r.serviceMappingLoader, r.Client)
+	tracing.End(span, err)
 	if err != nil {
 		return false, r.HandleDeleteFailed(ctx, &resource.Resource, fmt.Errorf("error fetching live state: %w", err))
 	}
@@ -616,7 +688,10 @@ func (r *Reconciler) finalizeResourceDeletion(ctx context.Context, resource *dcl
 		return false, fmt.Errorf("error converting KCC lite to dcl resource: %w", err)
 	}
 	r.logger.Info("deleting underlying resource", "resource", resource.GetNamespacedName())
-	if err := dclunstruct.Delete(ctx, dclConfig, dclResource); err != nil {
+	deleteCtx, span := tracing.Start(ctx, "DCLDelete")
+	err = dclunstruct.Delete(deleteCtx, dclConfig, dclResource)
+	tracing.End(span, err)
+	if err != nil {
 		if dcl.IsNoSuchMethodError(err) {
 			r.logger.Info("underlying resource cannot be deleted since there is no delete API; only clean up the kubernetes resource object", "resource", k8s.GetNamespacedName(resource))
 			return false, r.handleDeleted(ctx, resource)
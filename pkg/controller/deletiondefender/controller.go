@@ -19,15 +19,19 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/ordereddeletion"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/resourcegraph"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -47,9 +51,12 @@ type Reconciler struct {
 	crd       *apiextensions.CustomResourceDefinition
 	gvk       schema.GroupVersionKind
 	logger    logr.Logger
+	// orderedDeletion holds the resources which block the deletion of other resources in the
+	// namespaces under ordered deletion, if enabled.
+	orderedDeletion *ordereddeletion.Tracker
 }
 
-func Add(mgr manager.Manager, crd *apiextensions.CustomResourceDefinition) error {
+func Add(mgr manager.Manager, crd *apiextensions.CustomResourceDefinition, orderedDeletion *ordereddeletion.Tracker) error {
 	kind := crd.Spec.Names.Kind
 	apiVersion := k8s.GetAPIVersionFromCRD(crd)
 	controllerName := fmt.Sprintf("%v-deletion-defender-controller", strings.ToLower(kind))
@@ -57,6 +64,7 @@ func Add(mgr manager.Manager, crd *apiextensions.CustomResourceDefinition) error
 	if err != nil {
 		return err
 	}
+	r.orderedDeletion = orderedDeletion
 	obj := &unstructured.Unstructured{
 		Object: map[string]interface{}{
 			"kind":       kind,
@@ -121,6 +129,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (res
 		return reconcile.Result{}, fmt.Errorf("error determining if CRD is uninstalling: %w", err)
 	}
 
+	if !uninstalling && r.orderedDeletion != nil {
+		blocked, err := r.isBlockedByOrderedDeletion(ctx, u)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if blocked {
+			return reconcile.Result{RequeueAfter: ordereddeletion.RequeuePeriod}, nil
+		}
+	}
+
 	// If we are uninstalling, remove both KCC finalizers and set the resource to abandon. Otherwise,
 	// remove just the deletion defender finalizer and allow the controller to delete the underlying
 	// resource on GCP.
@@ -141,6 +159,33 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (res
 	return reconcile.Result{}, nil
 }
 
+// isBlockedByOrderedDeletion returns whether the resource's namespace is under ordered deletion
+// and resources which reference the resource still exist.
+func (r *Reconciler) isBlockedByOrderedDeletion(ctx context.Context, u *unstructured.Unstructured) (bool, error) {
+	// The namespace is read from the informer the ordered deletion controller watches the
+	// namespaces with, rather than from the API server on every deletion.
+	ns := &corev1.Namespace{}
+	if err := r.mgr.GetCache().Get(ctx, types.NamespacedName{Name: u.GetNamespace()}, ns); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error getting namespace '%v': %w", u.GetNamespace(), err)
+	}
+	if !ordereddeletion.IsActive(ns) {
+		return false, nil
+	}
+	blockers, ok := r.orderedDeletion.Blockers(resourcegraph.NodeIDFromUnstructured(u))
+	if !ok {
+		r.logger.Info("namespace is under ordered deletion but its resources have not been ordered yet; requeuing", "resource", k8s.GetNamespacedName(u))
+		return true, nil
+	}
+	if len(blockers) > 0 {
+		r.logger.Info("resource is still referenced by other resources under deletion; requeuing", "resource", k8s.GetNamespacedName(u), "blockers", len(blockers), "firstBlocker", blockers[0].String())
+		return true, nil
+	}
+	return false, nil
+}
+
 func (r *Reconciler) isUninstalling(ctx context.Context) (bool, error) {
 	// Check if the associated CRD has its deletion timestamp set.
 	// it is important to use the clientset.Clientset here rather than the controller-runtime client.Client, because
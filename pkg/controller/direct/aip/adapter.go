@@ -0,0 +1,313 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aip
+
+import (
+	"context"
+	"fmt"
+
+	commonv1 "github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/k8s/v1alpha1"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewModel returns the model of r.
+func NewModel[O any, T proto.Message](r *Resource[O, T], config *config.ControllerConfig) directbase.Model {
+	return &model[O, T]{resource: r, config: *config}
+}
+
+type model[O any, T proto.Message] struct {
+	resource *Resource[O, T]
+	config   config.ControllerConfig
+}
+
+var _ directbase.Model = &model[struct{}, proto.Message]{}
+
+func (m *model[O, T]) AdapterForObject(ctx context.Context, reader client.Reader, u *unstructured.Unstructured) (directbase.Adapter, error) {
+	obj := new(O)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		return nil, fmt.Errorf("error converting to %T: %w", obj, err)
+	}
+
+	id, err := m.resource.Identity(ctx, reader, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.resource.ResolveRefs != nil {
+		if err := m.resource.ResolveRefs(ctx, reader, obj); err != nil {
+			return nil, err
+		}
+	}
+
+	mapCtx := &direct.MapContext{}
+	desired := m.resource.ToProto(mapCtx, obj)
+	if mapCtx.Err() != nil {
+		return nil, mapCtx.Err()
+	}
+	setStringField(desired, "name", id.String())
+
+	gcpClient, err := m.resource.NewClient(ctx, &m.config)
+	if err != nil {
+		return nil, err
+	}
+	return &Adapter[O, T]{
+		resource:  m.resource,
+		id:        id,
+		gcpClient: gcpClient,
+		desired:   desired,
+	}, nil
+}
+
+func (m *model[O, T]) AdapterForURL(ctx context.Context, url string) (directbase.Adapter, error) {
+	if m.resource.ParseURL == nil {
+		return nil, nil
+	}
+	id, err := m.resource.ParseURL(url)
+	if err != nil {
+		// Not recognized
+		return nil, nil
+	}
+
+	gcpClient, err := m.resource.NewClient(ctx, &m.config)
+	if err != nil {
+		return nil, err
+	}
+	return &Adapter[O, T]{
+		resource:  m.resource,
+		id:        id,
+		gcpClient: gcpClient,
+	}, nil
+}
+
+// Adapter reconciles a GCP resource which follows the standard methods.
+type Adapter[O any, T proto.Message] struct {
+	resource  *Resource[O, T]
+	id        Identity
+	gcpClient Client[T]
+
+	// desired is unset when exporting by URL.
+	desired T
+	actual  T
+	found   bool
+}
+
+var _ directbase.Adapter = &Adapter[struct{}, proto.Message]{}
+var _ directbase.Planner = &Adapter[struct{}, proto.Message]{}
+var _ directbase.OperationPoller = &Adapter[struct{}, proto.Message]{}
+
+func (a *Adapter[O, T]) kind() string {
+	return a.resource.GVK.Kind
+}
+
+// Find implements the Adapter interface.
+func (a *Adapter[O, T]) Find(ctx context.Context) (bool, error) {
+	log := klog.FromContext(ctx)
+	log.V(2).Info("getting "+a.kind(), "name", a.id.String())
+
+	actual, err := a.gcpClient.Get(ctx, a.id.String())
+	if err != nil {
+		if direct.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("getting %s %q: %w", a.kind(), a.id.String(), err)
+	}
+
+	a.actual = actual
+	a.found = true
+	return true, nil
+}
+
+// Create implements the Adapter interface.
+func (a *Adapter[O, T]) Create(ctx context.Context, createOp *directbase.CreateOperation) error {
+	log := klog.FromContext(ctx)
+	log.V(2).Info("creating "+a.kind(), "name", a.id.String())
+
+	parent, resourceID, err := splitName(a.id.String())
+	if err != nil {
+		return err
+	}
+	resource := proto.Clone(a.desired).(T)
+	result, err := a.gcpClient.Create(ctx, parent, resourceID, resource)
+	if err != nil {
+		return fmt.Errorf("creating %s %q: %w", a.kind(), a.id.String(), err)
+	}
+	if result.Operation != "" {
+		// The next reconciliations poll the operation, and write the status once it is done.
+		log.V(2).Info("started creating "+a.kind(), "name", a.id.String(), "operation", result.Operation)
+		createOp.TrackOperation(OperationTypeCreate, result.Operation)
+		return nil
+	}
+	log.V(2).Info("successfully created "+a.kind(), "name", a.id.String())
+
+	return a.updateStatus(ctx, createOp, result.Resource)
+}
+
+// Update implements the Adapter interface.
+func (a *Adapter[O, T]) Update(ctx context.Context, updateOp *directbase.UpdateOperation) error {
+	log := klog.FromContext(ctx)
+	log.V(2).Info("updating "+a.kind(), "name", a.id.String())
+
+	resource := a.desiredForUpdate()
+	diff, err := common.DiffProtoMessages(resource, a.actual, a.resource.DiffOptions...)
+	if err != nil {
+		return err
+	}
+	if diff.IsEmpty() {
+		log.V(2).Info("no field needs update", "name", a.id.String())
+		return a.updateStatus(ctx, updateOp, a.actual)
+	}
+	log.V(2).Info("updating fields", "name", a.id.String(), "diff", diff.String())
+
+	result, err := a.gcpClient.Update(ctx, resource, diff.UpdateMask())
+	if err != nil {
+		return fmt.Errorf("updating %s %q: %w", a.kind(), a.id.String(), err)
+	}
+	if result.Operation != "" {
+		log.V(2).Info("started updating "+a.kind(), "name", a.id.String(), "operation", result.Operation)
+		updateOp.TrackOperation(OperationTypeUpdate, result.Operation)
+		return nil
+	}
+	log.V(2).Info("successfully updated "+a.kind(), "name", a.id.String())
+
+	return a.updateStatus(ctx, updateOp, result.Resource)
+}
+
+// PollOperation implements the OperationPoller interface.
+func (a *Adapter[O, T]) PollOperation(ctx context.Context, op *commonv1.PendingOperation) (bool, error) {
+	poller, ok := a.gcpClient.(OperationClient)
+	if !ok {
+		// We cannot poll the operation, so fall back to Find: the operation may well have
+		// succeeded, and reporting it as failed would clear it with an UpdateFailed condition.
+		log := klog.FromContext(ctx)
+		log.V(2).Info("cannot poll operation, falling back to Find", "kind", a.kind(), "operation", op.Name)
+		return true, nil
+	}
+	return poller.PollOperation(ctx, op)
+}
+
+// Plan implements the Planner interface.
+func (a *Adapter[O, T]) Plan(ctx context.Context, planOp *directbase.PlanOperation) (*directbase.Plan, error) {
+	if !a.found {
+		return &directbase.Plan{Action: directbase.PlanActionCreate}, nil
+	}
+
+	resource := a.desiredForUpdate()
+	diff, err := common.DiffProtoMessages(resource, a.actual, a.resource.DiffOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return directbase.NewUpdatePlan(resource, a.actual, sets.List(diff.Paths())), nil
+}
+
+// desiredForUpdate returns the desired resource with the etag of the actual
+// one, if it has an etag (AIP-154), so that the update is rejected if the
+// resource changed since Find.
+func (a *Adapter[O, T]) desiredForUpdate() T {
+	resource := proto.Clone(a.desired).(T)
+	if getStringField(resource, "etag") == "" {
+		setStringField(resource, "etag", getStringField(a.actual, "etag"))
+	}
+	return resource
+}
+
+// statusUpdater is implemented by the CreateOperation and the UpdateOperation.
+type statusUpdater interface {
+	GetUnstructured() *unstructured.Unstructured
+	UpdateStatus(ctx context.Context, typedStatus any, readyCondition *v1alpha1.Condition) error
+}
+
+func (a *Adapter[O, T]) updateStatus(ctx context.Context, op statusUpdater, actual T) error {
+	mapCtx := &direct.MapContext{}
+	status := a.resource.StatusFromProto(mapCtx, actual)
+	if mapCtx.Err() != nil {
+		return mapCtx.Err()
+	}
+	// UpdateStatus keeps the externalRef of the object if the status doesn't set it.
+	if err := unstructured.SetNestedField(op.GetUnstructured().Object, a.id.String(), "status", "externalRef"); err != nil {
+		return fmt.Errorf("setting status.externalRef: %w", err)
+	}
+	return op.UpdateStatus(ctx, status, nil)
+}
+
+// Export implements the Adapter interface.
+func (a *Adapter[O, T]) Export(ctx context.Context) (*unstructured.Unstructured, error) {
+	if !a.found {
+		return nil, fmt.Errorf("Find() not called")
+	}
+	_, resourceID, err := splitName(a.id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	mapCtx := &direct.MapContext{}
+	obj := a.resource.FromProto(mapCtx, a.id, a.actual)
+	if mapCtx.Err() != nil {
+		return nil, mapCtx.Err()
+	}
+	uObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &unstructured.Unstructured{Object: uObj}
+	u.SetName(resourceID)
+	u.SetGroupVersionKind(a.resource.GVK)
+	return u, nil
+}
+
+// Delete implements the Adapter interface.
+func (a *Adapter[O, T]) Delete(ctx context.Context, deleteOp *directbase.DeleteOperation) (bool, error) {
+	log := klog.FromContext(ctx)
+	log.V(2).Info("deleting "+a.kind(), "name", a.id.String())
+
+	if err := a.gcpClient.Delete(ctx, a.id.String()); err != nil {
+		if direct.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("deleting %s %q: %w", a.kind(), a.id.String(), err)
+	}
+	log.V(2).Info("successfully deleted "+a.kind(), "name", a.id.String())
+	return true, nil
+}
+
+func getStringField(m proto.Message, name protoreflect.Name) string {
+	r := m.ProtoReflect()
+	field := r.Descriptor().Fields().ByName(name)
+	if field == nil || field.Kind() != protoreflect.StringKind || field.IsList() {
+		return ""
+	}
+	return r.Get(field).String()
+}
+
+func setStringField(m proto.Message, name protoreflect.Name, value string) {
+	r := m.ProtoReflect()
+	field := r.Descriptor().Fields().ByName(name)
+	if field == nil || field.Kind() != protoreflect.StringKind || field.IsList() {
+		return
+	}
+	r.Set(field, protoreflect.ValueOfString(value))
+}
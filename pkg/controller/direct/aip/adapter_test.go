@@ -0,0 +1,388 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aip
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/lifecyclehandler"
+
+	pb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	commonv1 "github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/gax-go/v2/apierror"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var testGVK = schema.GroupVersionKind{Group: "test.cnrm.cloud.google.com", Version: "v1alpha1", Kind: "TestSecret"}
+
+type testSecret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   testSecretSpec   `json:"spec,omitempty"`
+	Status testSecretStatus `json:"status,omitempty"`
+}
+
+type testSecretSpec struct {
+	ProjectID string            `json:"projectID,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+type testSecretStatus struct {
+	ExternalRef *string `json:"externalRef,omitempty"`
+	CreateTime  *string `json:"createTime,omitempty"`
+}
+
+type testIdentity string
+
+func (i testIdentity) String() string {
+	return string(i)
+}
+
+// fakeClient is a Client backed by a map of resources.
+type fakeClient struct {
+	resources map[string]*pb.Secret
+	// updateMasks holds the update masks of the calls to Update.
+	updateMasks [][]string
+}
+
+func notFound(name string) error {
+	err, _ := apierror.FromError(&googleapi.Error{Code: 404, Message: fmt.Sprintf("secret %q not found", name)})
+	return err
+}
+
+func (c *fakeClient) Get(ctx context.Context, name string) (*pb.Secret, error) {
+	secret, ok := c.resources[name]
+	if !ok {
+		return nil, notFound(name)
+	}
+	return proto.Clone(secret).(*pb.Secret), nil
+}
+
+func (c *fakeClient) Create(ctx context.Context, parent, id string, resource *pb.Secret) (*Result[*pb.Secret], error) {
+	created := proto.Clone(resource).(*pb.Secret)
+	created.Name = parent + "/secrets/" + id
+	created.CreateTime = &timestamppb.Timestamp{Seconds: 1700000000}
+	created.Etag = "1"
+	c.resources[created.Name] = created
+	return Done(proto.Clone(created).(*pb.Secret), nil)
+}
+
+func (c *fakeClient) Update(ctx context.Context, resource *pb.Secret, updateMask *fieldmaskpb.FieldMask) (*Result[*pb.Secret], error) {
+	c.updateMasks = append(c.updateMasks, updateMask.GetPaths())
+	actual, ok := c.resources[resource.Name]
+	if !ok {
+		return nil, notFound(resource.Name)
+	}
+	if resource.Etag != actual.Etag {
+		return nil, &googleapi.Error{Code: 409, Message: "etag mismatch"}
+	}
+	for _, path := range updateMask.GetPaths() {
+		if path == "labels" {
+			actual.Labels = resource.Labels
+		}
+	}
+	actual.Etag += "1"
+	return Done(proto.Clone(actual).(*pb.Secret), nil)
+}
+
+func (c *fakeClient) Delete(ctx context.Context, name string) error {
+	if _, ok := c.resources[name]; !ok {
+		return notFound(name)
+	}
+	delete(c.resources, name)
+	return nil
+}
+
+// fakeOperationClient is a fakeClient whose Create and Update return a
+// long-running operation, which is done once it is polled.
+type fakeOperationClient struct {
+	*fakeClient
+	// polled holds the types of the polled operations.
+	polled []string
+}
+
+var _ OperationClient = &fakeOperationClient{}
+
+func (c *fakeOperationClient) Create(ctx context.Context, parent, id string, resource *pb.Secret) (*Result[*pb.Secret], error) {
+	if _, err := c.fakeClient.Create(ctx, parent, id, resource); err != nil {
+		return nil, err
+	}
+	return &Result[*pb.Secret]{Operation: "operations/create"}, nil
+}
+
+func (c *fakeOperationClient) Update(ctx context.Context, resource *pb.Secret, updateMask *fieldmaskpb.FieldMask) (*Result[*pb.Secret], error) {
+	if _, err := c.fakeClient.Update(ctx, resource, updateMask); err != nil {
+		return nil, err
+	}
+	return &Result[*pb.Secret]{Operation: "operations/update"}, nil
+}
+
+func (c *fakeOperationClient) PollOperation(ctx context.Context, op *commonv1.PendingOperation) (bool, error) {
+	c.polled = append(c.polled, op.Type)
+	return true, nil
+}
+
+func testResource(gcpClient Client[*pb.Secret]) *Resource[testSecret, *pb.Secret] {
+	return &Resource[testSecret, *pb.Secret]{
+		GVK: testGVK,
+		NewClient: func(ctx context.Context, config *config.ControllerConfig) (Client[*pb.Secret], error) {
+			return gcpClient, nil
+		},
+		Identity: func(ctx context.Context, reader client.Reader, obj *testSecret) (Identity, error) {
+			return testIdentity("projects/" + obj.Spec.ProjectID + "/secrets/" + obj.Name), nil
+		},
+		ToProto: func(mapCtx *direct.MapContext, obj *testSecret) *pb.Secret {
+			return &pb.Secret{Labels: obj.Spec.Labels}
+		},
+		FromProto: func(mapCtx *direct.MapContext, id Identity, actual *pb.Secret) *testSecret {
+			return &testSecret{Spec: testSecretSpec{ProjectID: "p", Labels: actual.Labels}}
+		},
+		StatusFromProto: func(mapCtx *direct.MapContext, actual *pb.Secret) any {
+			return &testSecretStatus{CreateTime: direct.StringTimestamp_FromProto(mapCtx, actual.CreateTime)}
+		},
+	}
+}
+
+func newTestObject(t *testing.T, labels map[string]string) (*unstructured.Unstructured, client.Client) {
+	obj := &testSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s", Namespace: "ns"},
+		Spec:       testSecretSpec{ProjectID: "p", Labels: labels},
+	}
+	uObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		t.Fatalf("converting to unstructured: %v", err)
+	}
+	u := &unstructured.Unstructured{Object: uObj}
+	u.SetGroupVersionKind(testGVK)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(testGVK, meta.RESTScopeNamespace)
+	kube := fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(u).WithStatusSubresource(u).
+		WithInterceptorFuncs(interceptor.Funcs{SubResourcePatch: applyAsMergePatch}).Build()
+	return u, kube
+}
+
+// applyAsMergePatch sends the server-side applies to the status as merge
+// patches, as the fake client can't apply unstructured objects.
+func applyAsMergePatch(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if patch.Type() == types.ApplyPatchType {
+		data, err := patch.Data(obj)
+		if err != nil {
+			return err
+		}
+		patch = client.RawPatch(types.MergePatchType, data)
+	}
+	return c.SubResource(subResourceName).Patch(ctx, obj, patch)
+}
+
+func updateOp(kube client.Client, u *unstructured.Unstructured) *directbase.UpdateOperation {
+	return directbase.NewUpdateOperation(lifecyclehandler.NewLifecycleHandler(kube, record.NewFakeRecorder(10)), kube, u)
+}
+
+func TestAdapter(t *testing.T) {
+	ctx := context.TODO()
+	gcpClient := &fakeClient{resources: map[string]*pb.Secret{}}
+	m := NewModel(testResource(gcpClient), &config.ControllerConfig{})
+
+	u, kube := newTestObject(t, map[string]string{"env": "prod"})
+	adapter, err := m.AdapterForObject(ctx, kube, u)
+	if err != nil {
+		t.Fatalf("AdapterForObject: %v", err)
+	}
+	if found, err := adapter.Find(ctx); err != nil || found {
+		t.Fatalf("Find() = %v, %v; want false, nil", found, err)
+	}
+	if err := adapter.Create(ctx, directbase.NewCreateOperation(kube, u)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	wantStatus := map[string]interface{}{
+		"externalRef":        "projects/p/secrets/s",
+		"createTime":         "2023-11-14T22:13:20Z",
+		"observedGeneration": int64(0),
+	}
+	if diff := cmp.Diff(wantStatus, u.Object["status"]); diff != "" {
+		t.Errorf("unexpected status after create (-want +got):\n%s", diff)
+	}
+
+	// An update without changes doesn't call the GCP API.
+	adapter, err = m.AdapterForObject(ctx, kube, u)
+	if err != nil {
+		t.Fatalf("AdapterForObject: %v", err)
+	}
+	if found, err := adapter.Find(ctx); err != nil || !found {
+		t.Fatalf("Find() = %v, %v; want true, nil", found, err)
+	}
+	if err := adapter.Update(ctx, updateOp(kube, u)); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if len(gcpClient.updateMasks) != 0 {
+		t.Errorf("unexpected updates %v", gcpClient.updateMasks)
+	}
+
+	// An update sends the changed fields, with the etag of the actual resource.
+	if err := unstructured.SetNestedStringMap(u.Object, map[string]string{"env": "dev"}, "spec", "labels"); err != nil {
+		t.Fatal(err)
+	}
+	adapter, err = m.AdapterForObject(ctx, kube, u)
+	if err != nil {
+		t.Fatalf("AdapterForObject: %v", err)
+	}
+	if _, err := adapter.Find(ctx); err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	plan, err := adapter.(directbase.Planner).Plan(ctx, directbase.NewPlanOperation(u))
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if plan.Action != directbase.PlanActionUpdate || len(plan.Changes) != 1 || plan.Changes[0].Path != "labels" {
+		t.Errorf("unexpected plan %v", plan)
+	}
+	if err := adapter.Update(ctx, updateOp(kube, u)); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if diff := cmp.Diff([][]string{{"labels"}}, gcpClient.updateMasks); diff != "" {
+		t.Errorf("unexpected update masks (-want +got):\n%s", diff)
+	}
+	if got := gcpClient.resources["projects/p/secrets/s"].Labels["env"]; got != "dev" {
+		t.Errorf("got label %q after update, want %q", got, "dev")
+	}
+
+	if _, err := adapter.Find(ctx); err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	exported, err := adapter.Export(ctx)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if exported.GetName() != "s" || exported.GroupVersionKind() != testGVK {
+		t.Errorf("unexpected exported object %v", exported)
+	}
+	if got, _, _ := unstructured.NestedString(exported.Object, "spec", "labels", "env"); got != "dev" {
+		t.Errorf("got exported label %q, want %q", got, "dev")
+	}
+
+	deleteOp := directbase.NewDeleteOperation(kube, u)
+	if deleted, err := adapter.Delete(ctx, deleteOp); err != nil || !deleted {
+		t.Fatalf("Delete() = %v, %v; want true, nil", deleted, err)
+	}
+	if deleted, err := adapter.Delete(ctx, deleteOp); err != nil || deleted {
+		t.Fatalf("Delete() of a deleted resource = %v, %v; want false, nil", deleted, err)
+	}
+}
+
+func TestAdapterTracksOperations(t *testing.T) {
+	ctx := context.TODO()
+	gcpClient := &fakeOperationClient{fakeClient: &fakeClient{resources: map[string]*pb.Secret{}}}
+	m := NewModel(testResource(gcpClient), &config.ControllerConfig{})
+
+	// Create doesn't wait for the operation, nor write the status.
+	u, kube := newTestObject(t, map[string]string{"env": "prod"})
+	adapter, err := m.AdapterForObject(ctx, kube, u)
+	if err != nil {
+		t.Fatalf("AdapterForObject: %v", err)
+	}
+	if _, err := adapter.Find(ctx); err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if err := adapter.Create(ctx, directbase.NewCreateOperation(kube, u)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if status, _, _ := unstructured.NestedMap(u.Object, "status"); len(status) != 0 {
+		t.Errorf("unexpected status %v while the create operation is pending", status)
+	}
+	pending := &commonv1.PendingOperation{Name: "operations/create", Type: OperationTypeCreate}
+	if done, err := adapter.(directbase.OperationPoller).PollOperation(ctx, pending); err != nil || !done {
+		t.Fatalf("PollOperation() = %v, %v; want true, nil", done, err)
+	}
+
+	// Neither does Update.
+	if err := unstructured.SetNestedStringMap(u.Object, map[string]string{"env": "dev"}, "spec", "labels"); err != nil {
+		t.Fatal(err)
+	}
+	adapter, err = m.AdapterForObject(ctx, kube, u)
+	if err != nil {
+		t.Fatalf("AdapterForObject: %v", err)
+	}
+	if found, err := adapter.Find(ctx); err != nil || !found {
+		t.Fatalf("Find() = %v, %v; want true, nil", found, err)
+	}
+	if err := adapter.Update(ctx, updateOp(kube, u)); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if status, _, _ := unstructured.NestedMap(u.Object, "status"); len(status) != 0 {
+		t.Errorf("unexpected status %v while the update operation is pending", status)
+	}
+	pending = &commonv1.PendingOperation{Name: "operations/update", Type: OperationTypeUpdate}
+	if done, err := adapter.(directbase.OperationPoller).PollOperation(ctx, pending); err != nil || !done {
+		t.Fatalf("PollOperation() = %v, %v; want true, nil", done, err)
+	}
+	if diff := cmp.Diff([]string{OperationTypeCreate, OperationTypeUpdate}, gcpClient.polled); diff != "" {
+		t.Errorf("unexpected polled operations (-want +got):\n%s", diff)
+	}
+
+	// A client without operations cannot poll them, so Find decides whether the operation succeeded.
+	m = NewModel(testResource(gcpClient.fakeClient), &config.ControllerConfig{})
+	adapter, err = m.AdapterForObject(ctx, kube, u)
+	if err != nil {
+		t.Fatalf("AdapterForObject: %v", err)
+	}
+	if done, err := adapter.(directbase.OperationPoller).PollOperation(ctx, pending); err != nil || !done {
+		t.Errorf("PollOperation() = %v, %v; want true, nil", done, err)
+	}
+}
+
+func TestSplitName(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantParent string
+		wantID     string
+		wantErr    bool
+	}{
+		{name: "projects/p/locations/l/widgets/w", wantParent: "projects/p/locations/l", wantID: "w"},
+		{name: "projects/p/secrets/s", wantParent: "projects/p", wantID: "s"},
+		{name: "widgets/w", wantErr: true},
+		{name: "w", wantErr: true},
+	}
+	for _, tc := range tests {
+		parent, id, err := splitName(tc.name)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("splitName(%q) returned error %v, want error %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if parent != tc.wantParent || id != tc.wantID {
+			t.Errorf("splitName(%q) = %q, %q; want %q, %q", tc.name, parent, id, tc.wantParent, tc.wantID)
+		}
+	}
+}
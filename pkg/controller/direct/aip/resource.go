@@ -0,0 +1,202 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aip implements a generic direct controller for the GCP resources
+// which follow the standard methods of the API Improvement Proposals: Get
+// (AIP-131), Create (AIP-133), Update (AIP-134) and Delete (AIP-135).
+//
+// A kind registers a Resource, which holds its client, its identity and its
+// mappers, and the package implements the Find/Create/Update/Delete/Export
+// contract of directbase.Adapter, e.g.
+//
+//	func init() {
+//		aip.Register(&aip.Resource[krm.Widget, *pb.Widget]{
+//			GVK:             krm.WidgetGVK,
+//			NewClient:       newWidgetClient,
+//			Identity:        widgetIdentity,
+//			ToProto:         widgetToProto,
+//			FromProto:       widgetFromProto,
+//			StatusFromProto: widgetStatusFromProto,
+//		})
+//	}
+//
+// If Create or Update return a long-running operation, the adapter records it
+// with TrackOperation instead of waiting for it, and the Client implements
+// OperationClient to poll it.
+package aip
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	commonv1 "github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
+
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Client calls the standard methods of a GCP resource of type T.
+type Client[T proto.Message] interface {
+	// Get returns the resource with the given name.
+	Get(ctx context.Context, name string) (T, error)
+	// Create creates the resource with the given ID under parent.
+	Create(ctx context.Context, parent, id string, resource T) (*Result[T], error)
+	// Update updates the fields of the resource in updateMask.
+	Update(ctx context.Context, resource T, updateMask *fieldmaskpb.FieldMask) (*Result[T], error)
+	// Delete deletes the resource with the given name. If the method returns
+	// a long-running operation, it waits for it, see WaitOperation.
+	Delete(ctx context.Context, name string) error
+}
+
+// OperationClient is implemented by the Client of a resource whose Create or
+// Update methods return a long-running operation.
+type OperationClient interface {
+	// PollOperation checks once whether the operation recorded by the
+	// adapter is done, see directbase.OperationPoller. The type of op is
+	// OperationTypeCreate or OperationTypeUpdate, e.g.
+	//
+	//	case aip.OperationTypeCreate:
+	//		return directbase.PollGAPICOperation(ctx, c.CreateWidgetOperation(op.Name))
+	PollOperation(ctx context.Context, op *commonv1.PendingOperation) (done bool, err error)
+}
+
+// The types of the operations recorded by the adapter.
+const (
+	OperationTypeCreate = "create"
+	OperationTypeUpdate = "update"
+)
+
+// Result is the result of Create or Update: either the resource, if the
+// method returns it, or the name of the long-running operation it started.
+// The adapter doesn't wait for the operation, but records it in
+// status.pendingOperation, and later reconciliations poll it.
+type Result[T proto.Message] struct {
+	Resource  T
+	Operation string
+}
+
+// Done returns the result of a method which returns the resource, e.g.
+//
+//	return aip.Done(c.CreateWidget(ctx, req))
+func Done[T proto.Message](resource T, err error) (*Result[T], error) {
+	if err != nil {
+		return nil, err
+	}
+	return &Result[T]{Resource: resource}, nil
+}
+
+// Started returns the result of a method which returns a long-running
+// operation, e.g.
+//
+//	op, err := c.CreateWidget(ctx, req)
+//	return aip.Started[*pb.Widget](op, err)
+func Started[T proto.Message](op interface{ Name() string }, err error) (*Result[T], error) {
+	if err != nil {
+		return nil, err
+	}
+	return &Result[T]{Operation: op.Name()}, nil
+}
+
+// Operation is a long-running operation returned by a GAPIC client.
+type Operation[T any] interface {
+	Wait(ctx context.Context, opts ...gax.CallOption) (T, error)
+}
+
+// WaitOperation waits for the long-running operation returned by a call to
+// a GAPIC client, e.g. in Delete, which isn't tracked across reconciliations:
+//
+//	op, err := c.DeleteWidget(ctx, req)
+//	_, err = aip.WaitOperation(ctx, op, err)
+//	return err
+func WaitOperation[T any, Op Operation[T]](ctx context.Context, op Op, err error) (T, error) {
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return op.Wait(ctx)
+}
+
+// Identity is the identity of a GCP resource.
+type Identity interface {
+	// String returns the resource name, in the format
+	// "{parent}/{collection}/{id}" of AIP-122, e.g.
+	// "projects/p/locations/l/widgets/w".
+	String() string
+}
+
+// splitName returns the parent and the ID of a resource name.
+func splitName(name string) (parent, id string, err error) {
+	i := strings.LastIndex(name, "/")
+	if i <= 0 {
+		return "", "", fmt.Errorf("invalid resource name %q", name)
+	}
+	j := strings.LastIndex(name[:i], "/")
+	if j <= 0 {
+		return "", "", fmt.Errorf("invalid resource name %q", name)
+	}
+	return name[:j], name[i+1:], nil
+}
+
+// Resource describes a KCC kind whose GCP resource follows the standard
+// methods. O is the KRM type, e.g. krm.WorkstationConfig, and T the proto
+// type, e.g. *pb.WorkstationConfig.
+type Resource[O any, T proto.Message] struct {
+	// GVK is the group, version and kind of O.
+	GVK schema.GroupVersionKind
+
+	// NewClient builds the client of the GCP resource.
+	NewClient func(ctx context.Context, config *config.ControllerConfig) (Client[T], error)
+
+	// Identity returns the identity of obj, resolving its parent.
+	Identity func(ctx context.Context, reader client.Reader, obj *O) (Identity, error)
+
+	// ParseURL returns the identity of the resource with the given URL, in
+	// the Cloud Asset Inventory format. It returns an error if the URL isn't
+	// of this kind. It is optional, and is needed to export by URL.
+	ParseURL func(url string) (Identity, error)
+
+	// ResolveRefs resolves the references in the spec of obj. It is optional.
+	ResolveRefs func(ctx context.Context, reader client.Reader, obj *O) error
+
+	// ToProto returns the desired GCP resource for the spec of obj.
+	ToProto func(mapCtx *direct.MapContext, obj *O) T
+
+	// FromProto returns the KRM object for the actual GCP resource, for
+	// export. Its name and GVK are set from id and GVK.
+	FromProto func(mapCtx *direct.MapContext, id Identity, actual T) *O
+
+	// StatusFromProto returns the status of the KRM object for the actual GCP
+	// resource. The externalRef of the status is set to the resource name.
+	StatusFromProto func(mapCtx *direct.MapContext, actual T) any
+
+	// DiffOptions are the options to compare the desired and actual GCP
+	// resources on update, e.g. the comparators of server-normalized fields.
+	DiffOptions []common.DiffOption
+}
+
+// Register registers the model of r with the direct controller registry.
+func Register[O any, T proto.Message](r *Resource[O, T]) {
+	registry.RegisterModel(r.GVK, func(ctx context.Context, config *config.ControllerConfig) (directbase.Model, error) {
+		return NewModel(r, config), nil
+	})
+}
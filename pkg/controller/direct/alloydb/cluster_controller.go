@@ -29,6 +29,7 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	commonv1 "github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/clients/generated/apis/alloydb/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
@@ -65,6 +66,9 @@ type clusterAdapter struct {
 
 var _ directbase.Adapter = &clusterAdapter{}
 
+// adapter implements the OperationPoller interface.
+var _ directbase.OperationPoller = &clusterAdapter{}
+
 // AdapterForObject implements the Model interface.
 func (m *clusterModel) AdapterForObject(ctx context.Context, reader client.Reader, u *unstructured.Unstructured) (directbase.Adapter, error) {
 	klog.FromContext(ctx).V(0).Info("creating adapter", "u", u)
@@ -165,21 +169,40 @@ func (a *clusterAdapter) Delete(ctx context.Context, deleteOp *directbase.Delete
 
 func (a *clusterAdapter) waitForOp(ctx context.Context, op *api.Operation) error {
 	for {
-		current, err := a.client.Projects.Locations.Operations.Get(op.Name).Context(ctx).Do()
-		if err != nil {
-			return fmt.Errorf("getting operation status of %q: %w", op.Name, err)
-		}
-		if current.Done {
-			if current.Error != nil {
-				return fmt.Errorf("operation %q completed with error: %v", op.Name, current.Error)
-			} else {
-				return nil
-			}
+		done, err := a.operationDone(ctx, op.Name)
+		if done || err != nil {
+			return err
 		}
 		time.Sleep(2 * time.Second)
 	}
 }
 
+// operationDone returns whether the operation is done, and its error if it failed.
+func (a *clusterAdapter) operationDone(ctx context.Context, name string) (bool, error) {
+	current, err := a.client.Projects.Locations.Operations.Get(name).Context(ctx).Do()
+	if err != nil {
+		return false, fmt.Errorf("getting operation status of %q: %w", name, err)
+	}
+	if !current.Done {
+		return false, nil
+	}
+	if current.Error != nil {
+		return true, fmt.Errorf("operation %q completed with error: %v", name, current.Error)
+	}
+	return true, nil
+}
+
+const (
+	operationTypeCreate = "create"
+	operationTypeUpdate = "update"
+)
+
+// PollOperation implements the OperationPoller interface.
+// The create and update operations of a cluster are polled the same way.
+func (a *clusterAdapter) PollOperation(ctx context.Context, op *commonv1.PendingOperation) (bool, error) {
+	return a.operationDone(ctx, op.Name)
+}
+
 // Create implements the Adapter interface.
 func (a *clusterAdapter) Create(ctx context.Context, createOp *directbase.CreateOperation) error {
 	u := createOp.GetUnstructured()
@@ -203,30 +226,15 @@ func (a *clusterAdapter) Create(ctx context.Context, createOp *directbase.Create
 		return fmt.Errorf("creating cluster: %w", err)
 	}
 
-	if err := a.waitForOp(ctx, op); err != nil {
-		return fmt.Errorf("waiting for cluster create %s: %w", a.fullyQualifiedName(), err)
-	}
-
-	created, err := a.client.Projects.Locations.Clusters.Get(a.fullyQualifiedName()).Context(ctx).Do()
-	if err != nil {
-		return fmt.Errorf("getting created cluster: %w", err)
-	}
+	// Creating a cluster takes a long time, so we don't wait for it here.
+	// The next reconciliations poll the operation, and write the observed state once it is done.
+	log.V(0).Info("started cluster create", "operation", op.Name)
+	createOp.TrackOperation(operationTypeCreate, op.Name)
 
-	log.V(0).Info("created cluster", "cluster", created)
-
-	resourceID := created.Name
-	if err := unstructured.SetNestedField(u.Object, resourceID, "spec", "resourceID"); err != nil {
+	if err := createOp.ApplySpec(ctx, map[string]interface{}{"resourceID": a.resourceID}); err != nil {
 		return fmt.Errorf("setting spec.resourceID: %w", err)
 	}
-
-	mapCtx := &direct.MapContext{
-		// kube: kube,
-	}
-	observedState := ClusterStatusFromApi(mapCtx, created)
-	if mapCtx.Err() != nil {
-		return mapCtx.Err()
-	}
-	return setStatus(u, observedState)
+	return nil
 }
 
 // Update implements the Adapter interface.
@@ -235,7 +243,6 @@ func (a *clusterAdapter) Update(ctx context.Context, updateOp *directbase.Update
 
 	log := klog.FromContext(ctx)
 	log.V(0).Info("updating object", "u", u)
-	var latest *api.Cluster
 
 	updateMask := &fieldmaskpb.FieldMask{}
 	if a.desired.DisplayName != a.actual.DisplayName {
@@ -276,30 +283,21 @@ func (a *clusterAdapter) Update(ctx context.Context, updateOp *directbase.Update
 	}
 	if len(updateMask.Paths) != 0 {
 		cluster := a.desired
-		var clusterName string
-		op, err := a.client.Projects.Locations.Clusters.Patch(clusterName, cluster).UpdateMask(strings.Join(updateMask.Paths, ",")).Context(ctx).Do()
+		op, err := a.client.Projects.Locations.Clusters.Patch(a.fullyQualifiedName(), cluster).UpdateMask(strings.Join(updateMask.Paths, ",")).Context(ctx).Do()
 		if err != nil {
 			return err
 		}
 
-		if err := a.waitForOp(ctx, op); err != nil {
-			return fmt.Errorf("waiting for cluster update %s: %w", a.fullyQualifiedName(), err)
-		}
-
-		updated, err := a.client.Projects.Locations.Clusters.Get(a.fullyQualifiedName()).Context(ctx).Do()
-		if err != nil {
-			return fmt.Errorf("getting updated cluster: %w", err)
-		}
-		log.V(0).Info("updated cluster", "cluster", updated)
-		latest = updated
-	} else {
-		latest = a.actual
+		// The next reconciliations poll the operation, and write the observed state of the updated cluster once it is done.
+		log.V(0).Info("started cluster update", "operation", op.Name)
+		updateOp.TrackOperation(operationTypeUpdate, op.Name)
+		return nil
 	}
 
 	mapCtx := &direct.MapContext{
 		// kube: kube,
 	}
-	observedState := ClusterStatusFromApi(mapCtx, latest)
+	observedState := ClusterStatusFromApi(mapCtx, a.actual)
 	if mapCtx.Err() != nil {
 		return mapCtx.Err()
 	}
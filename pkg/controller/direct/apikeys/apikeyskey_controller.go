@@ -17,7 +17,6 @@ package apikeys
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	api "cloud.google.com/go/apikeys/apiv2"
 	pb "cloud.google.com/go/apikeys/apiv2/apikeyspb"
@@ -30,6 +29,7 @@ import (
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/clients/generated/apis/apikeys/v1alpha1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 
@@ -233,20 +233,32 @@ func (a *adapter) Create(ctx context.Context, createOp *directbase.CreateOperati
 func (a *adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperation) error {
 	// u := Op.GetUnstructured()
 
+	key := &pb.Key{}
+	if err := keyMapping.Map(a.desired, key); err != nil {
+		return err
+	}
+	actual := &pb.Key{}
+	if err := keyMapping.Map(a.actual, actual); err != nil {
+		return err
+	}
+	diff, err := common.DiffProtoMessages(key, actual)
+	if err != nil {
+		return err
+	}
+
 	// TODO: Skip updates if no changes
 	// TODO: Where/how do we want to enforce immutability?
 	updateMask := &fieldmaskpb.FieldMask{}
 
-	// TODO: I think we can do this with a helper
-	if !reflect.DeepEqual(a.desired.Spec.DisplayName, a.actual.Spec.DisplayName) {
+	if diff.HasField("display_name") {
 		updateMask.Paths = append(updateMask.Paths, "display_name")
 	}
-	if !reflect.DeepEqual(a.desired.Spec.Restrictions, a.actual.Spec.Restrictions) {
+	if diff.HasField("restrictions") {
 		updateMask.Paths = append(updateMask.Paths, "restrictions")
 	}
 
 	// TODO: Annotations
-	// if !reflect.DeepEqual(a.desired.Annotations, a.actual.Annotations) {
+	// if diff.HasField("annotations") {
 	// 	updateMask.Paths = append(updateMask.Paths, "annotations")
 	// }
 
@@ -255,11 +267,6 @@ func (a *adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 		return nil
 	}
 
-	key := &pb.Key{}
-	if err := keyMapping.Map(a.desired, key); err != nil {
-		return err
-	}
-
 	req := &pb.UpdateKeyRequest{
 		Key:        key,
 		UpdateMask: updateMask,
@@ -267,7 +274,7 @@ func (a *adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 
 	req.Key.Name = a.fullyQualifiedName()
 
-	_, err := a.gcp.UpdateKey(ctx, req)
+	_, err = a.gcp.UpdateKey(ctx, req)
 	if err != nil {
 		return err
 	}
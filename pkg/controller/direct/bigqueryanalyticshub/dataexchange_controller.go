@@ -17,12 +17,12 @@ package bigqueryanalyticshub
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/bigqueryanalyticshub/v1beta1"
 	refs "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 
@@ -205,29 +205,6 @@ func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 	log.V(2).Info("updating DataExchange", "name", a.id.FullyQualifiedName())
 	mapCtx := &direct.MapContext{}
 
-	// TODO(kcc): Autogen "func immutable()" for each field
-	// TODO(kcc): autogen updateMastk.path for mutable gcp fields.
-	updateMask := &fieldmaskpb.FieldMask{}
-	if !reflect.DeepEqual(a.desired.Spec.DisplayName, a.actual.DisplayName) {
-		updateMask.Paths = append(updateMask.Paths, "display_name")
-	}
-	if !reflect.DeepEqual(a.desired.Spec.Description, a.actual.Description) {
-		updateMask.Paths = append(updateMask.Paths, "description")
-	}
-	if !reflect.DeepEqual(a.desired.Spec.PrimaryContact, a.actual.PrimaryContact) {
-		updateMask.Paths = append(updateMask.Paths, "primary_contact")
-	}
-	if !reflect.DeepEqual(a.desired.Spec.Documentation, a.actual.Documentation) {
-		updateMask.Paths = append(updateMask.Paths, "documentation")
-	}
-	// not yet
-	// if !reflect.DeepEqual(a.desired.Spec.Icon, string(a.actual.Icon)) {
-	// 	updateMask.Paths = append(updateMask.Paths, "icon")
-	// }
-	if a.desired.Spec.DiscoveryType != nil && !reflect.DeepEqual(a.desired.Spec.DiscoveryType, a.actual.DiscoveryType.String()) {
-		updateMask.Paths = append(updateMask.Paths, "discovery_type")
-	}
-
 	desired := a.desired.DeepCopy()
 	resource := BigQueryAnalyticsHubDataExchangeSpec_ToProto(mapCtx, &desired.Spec)
 	if mapCtx.Err() != nil {
@@ -235,6 +212,29 @@ func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 	}
 	resource.Name = a.actual.Name
 
+	var opts []common.DiffOption
+	if a.desired.Spec.DiscoveryType == nil {
+		opts = append(opts, common.IgnoreFields("discovery_type"))
+	}
+	diff, err := common.DiffProtoMessages(resource, a.actual, opts...)
+	if err != nil {
+		return err
+	}
+
+	// TODO(kcc): Autogen "func immutable()" for each field
+	// TODO(kcc): autogen updateMastk.path for mutable gcp fields.
+	updateMask := &fieldmaskpb.FieldMask{}
+	// not yet: icon
+	for _, field := range []string{"display_name", "description", "primary_contact", "documentation", "discovery_type"} {
+		if diff.HasField(field) {
+			updateMask.Paths = append(updateMask.Paths, field)
+		}
+	}
+	if len(updateMask.Paths) == 0 {
+		log.V(2).Info("no field needs update", "name", a.id.FullyQualifiedName())
+		return nil
+	}
+
 	req := &bigqueryanalyticshubpb.UpdateDataExchangeRequest{
 		UpdateMask:   updateMask,
 		DataExchange: resource,
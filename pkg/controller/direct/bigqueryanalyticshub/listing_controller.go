@@ -17,12 +17,12 @@ package bigqueryanalyticshub
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/bigqueryanalyticshub/v1beta1"
 	refs "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 
@@ -199,62 +199,27 @@ func (a *ListingAdapter) Update(ctx context.Context, updateOp *directbase.Update
 
 	resource.Name = a.id.External
 
-	updateMask := &fieldmaskpb.FieldMask{}
-	if a.desired.Spec.DisplayName != nil && !reflect.DeepEqual(a.desired.Spec.DisplayName, a.actual.DisplayName) {
-		updateMask.Paths = append(updateMask.Paths, "display_name")
-	}
-	if a.desired.Spec.Description != nil && !reflect.DeepEqual(a.desired.Spec.Description, a.actual.Description) {
-		updateMask.Paths = append(updateMask.Paths, "description")
-	}
-	if a.desired.Spec.PrimaryContact != nil && !reflect.DeepEqual(a.desired.Spec.PrimaryContact, a.actual.PrimaryContact) {
-		updateMask.Paths = append(updateMask.Paths, "primary_contact")
-	}
-	if a.desired.Spec.Documentation != nil && !reflect.DeepEqual(a.desired.Spec.Documentation, a.actual.Documentation) {
-		updateMask.Paths = append(updateMask.Paths, "documentation")
-	}
-	if a.desired.Spec.DiscoveryType != nil && !reflect.DeepEqual(a.desired.Spec.DiscoveryType, a.actual.DiscoveryType.String()) {
-		updateMask.Paths = append(updateMask.Paths, "discovery_type")
-	}
-	if a.desired.Spec.RequestAccess != nil && reflect.DeepEqual(a.desired.Spec.RequestAccess, a.actual.RequestAccess) {
-		updateMask.Paths = append(updateMask.Paths, "request_access")
-	}
-
-	// NOT YET
-	// if a.desired.Spec.Icon != nil && !reflect.DeepEqual(a.desired.Spec.Icon, a.actual.Icon) {
-	// 	updateMask.Paths = append(updateMask.Paths, "icon")
-	// }
-	if a.desired.Spec.DataProvider != nil {
-		mapCtx := &direct.MapContext{}
-		toProto := DataProvider_ToProto(mapCtx, a.desired.Spec.DataProvider)
-		if mapCtx.Err() != nil {
-			return fmt.Errorf("converting data provider: %w", mapCtx.Err())
-		}
-
-		if !reflect.DeepEqual(toProto, a.actual.DataProvider) {
-			updateMask.Paths = append(updateMask.Paths, "data_provider")
-		}
-	}
-
-	if a.desired.Spec.Publisher != nil {
-		mapCtx := &direct.MapContext{}
-		toProto := Publisher_ToProto(mapCtx, a.desired.Spec.Publisher)
-		if mapCtx.Err() != nil {
-			return fmt.Errorf("converting publisher: %w", mapCtx.Err())
-		}
-
-		if !reflect.DeepEqual(toProto, a.actual.Publisher) {
-			updateMask.Paths = append(updateMask.Paths, "publisher")
-		}
+	// The fields which are not set are not managed.
+	diff, err := common.DiffProtoMessages(resource, a.actual, common.IgnoreUnsetFields())
+	if err != nil {
+		return err
 	}
 
-	if a.desired.Spec.Categories != nil {
-		mapCtx := &direct.MapContext{}
-		toProto := Categories_ToProto(mapCtx, a.desired.Spec.Categories)
-		if mapCtx.Err() != nil {
-			return fmt.Errorf("converting categories: %w", mapCtx.Err())
-		}
-		if !reflect.DeepEqual(toProto, a.actual.Categories) {
-			updateMask.Paths = append(updateMask.Paths, "categories")
+	updateMask := &fieldmaskpb.FieldMask{}
+	// NOT YET: icon
+	for _, field := range []string{
+		"display_name",
+		"description",
+		"primary_contact",
+		"documentation",
+		"discovery_type",
+		"request_access",
+		"data_provider",
+		"publisher",
+		"categories",
+	} {
+		if diff.HasField(field) {
+			updateMask.Paths = append(updateMask.Paths, field)
 		}
 	}
 
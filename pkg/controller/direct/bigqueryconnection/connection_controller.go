@@ -30,11 +30,9 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 	"google.golang.org/api/option"
-	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -278,19 +276,20 @@ func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 	if mapCtx.Err() != nil {
 		return mapCtx.Err()
 	}
-	paths, err := common.CompareProtoMessage(connection, a.actual, common.BasicDiff)
+	diff, err := common.DiffProtoMessages(connection, a.actual)
 	if err != nil {
 		return err
 	}
-	if len(paths) == 0 {
+	if diff.IsEmpty() {
 		log.V(2).Info("no field needs update", "name", a.id.External)
 		return nil
 	}
+	log.V(2).Info("updating fields", "name", a.id.External, "diff", diff.String())
 	fqn := a.id.External
 	req := &pb.UpdateConnectionRequest{
 		Name:       fqn,
 		Connection: connection,
-		UpdateMask: &fieldmaskpb.FieldMask{Paths: sets.List(paths)},
+		UpdateMask: diff.UpdateMask(),
 	}
 	updated, err := a.gcpClient.UpdateConnection(ctx, req)
 	if err != nil {
@@ -17,12 +17,12 @@ package bigquerydatatransfer
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/bigquerydatatransfer/v1beta1"
 	refv1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 
@@ -279,50 +279,67 @@ func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 	actual := a.actual
 	resource := proto.Clone(a.actual).(*bigquerydatatransferpb.TransferConfig) // this is the proto resource we are passing to GCP API update call.
 
+	// The optional messages which are not set are left to the service.
+	var unset []string
+	for field, isSet := range map[string]bool{
+		"email_preferences":        desired.EmailPreferences != nil,
+		"encryption_configuration": desired.EncryptionConfiguration != nil,
+		"params":                   desired.Params != nil,
+		"schedule_options":         desired.ScheduleOptions != nil,
+	} {
+		if !isSet {
+			unset = append(unset, field)
+		}
+	}
+	diff, err := common.DiffProtoMessages(desired, actual, common.IgnoreFields(unset...))
+	if err != nil {
+		return err
+	}
+
 	// Check for immutable fields
-	if !reflect.DeepEqual(desired.DataSourceId, a.actual.DataSourceId) {
+	if diff.HasField("data_source_id") {
 		return fmt.Errorf("BigQueryDataTransferConfig %s/%s data source ID cannot be changed", u.GetNamespace(), u.GetName())
 	}
-	if !reflect.DeepEqual(desired.Destination, a.actual.Destination) {
+	if diff.HasField("destination_dataset_id") {
 		return fmt.Errorf("BigQueryDataTransferConfig %s/%s destination dataset cannot be changed", u.GetNamespace(), u.GetName())
 	}
 
 	// Find diff
 	updateMask := &fieldmaskpb.FieldMask{}
-	if !reflect.DeepEqual(desired.DataRefreshWindowDays, actual.DataRefreshWindowDays) {
+	if diff.HasField("data_refresh_window_days") {
 		resource.DataRefreshWindowDays = desired.DataRefreshWindowDays
 		updateMask.Paths = append(updateMask.Paths, "data_refresh_window_days")
 	}
-	if !reflect.DeepEqual(desired.Disabled, actual.Disabled) {
+	if diff.HasField("disabled") {
 		resource.Disabled = desired.Disabled
 		updateMask.Paths = append(updateMask.Paths, "disabled")
 	}
-	if !reflect.DeepEqual(desired.DisplayName, actual.DisplayName) {
+	if diff.HasField("display_name") {
 		resource.DisplayName = desired.DisplayName
 		updateMask.Paths = append(updateMask.Paths, "display_name")
 	}
-	if desired.EmailPreferences != nil && !reflect.DeepEqual(desired.EmailPreferences, actual.EmailPreferences) {
+	if diff.HasField("email_preferences") {
 		resource.EmailPreferences = desired.EmailPreferences
 		updateMask.Paths = append(updateMask.Paths, "email_preferences")
 	}
-	if desired.EncryptionConfiguration != nil && !reflect.DeepEqual(desired.EncryptionConfiguration, actual.EncryptionConfiguration) {
+	if diff.HasField("encryption_configuration") {
 		resource.EncryptionConfiguration = desired.EncryptionConfiguration
 		updateMask.Paths = append(updateMask.Paths, "encryption_configuration")
 	}
-	if !reflect.DeepEqual(desired.NotificationPubsubTopic, actual.NotificationPubsubTopic) {
+	if diff.HasField("notification_pubsub_topic") {
 		resource.NotificationPubsubTopic = desired.NotificationPubsubTopic
 		updateMask.Paths = append(updateMask.Paths, "notification_pubsub_topic")
 	}
-	if desired.Params != nil && !reflect.DeepEqual(desired.Params, actual.Params) {
+	if diff.HasField("params") {
 		// TODO: sensitive fields maybe masked by the service, leading to constant diff.
 		resource.Params = desired.Params
 		updateMask.Paths = append(updateMask.Paths, "params")
 	}
-	if !reflect.DeepEqual(desired.Schedule, actual.Schedule) {
+	if diff.HasField("schedule") {
 		resource.Schedule = desired.Schedule
 		updateMask.Paths = append(updateMask.Paths, "schedule")
 	}
-	if desired.ScheduleOptions != nil && !reflect.DeepEqual(desired.ScheduleOptions, actual.ScheduleOptions) {
+	if diff.HasField("schedule_options") {
 		resource.ScheduleOptions = desired.ScheduleOptions
 		updateMask.Paths = append(updateMask.Paths, "schedule_options")
 	}
@@ -17,7 +17,6 @@ package certificatemanager
 import (
 	"context"
 	"fmt"
-	"reflect"
 	"strings"
 
 	gcp "cloud.google.com/go/certificatemanager/apiv1"
@@ -25,6 +24,7 @@ import (
 	refs "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 
@@ -216,25 +216,12 @@ func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 	log := klog.FromContext(ctx).WithName(ctrlName)
 	log.V(2).Info("updating DnsAuthorization", "name", a.id.FullyQualifiedName())
 	mapCtx := &direct.MapContext{}
-	updateMask := &fieldmaskpb.FieldMask{}
-
-	if !reflect.DeepEqual(a.desired.Spec.Description, a.actual.Description) {
-		updateMask.Paths = append(updateMask.Paths, "description")
-	}
-
-	if !reflect.DeepEqual(a.desired.Labels, a.actual.Labels) {
-		updateMask.Paths = append(updateMask.Paths, "labels")
-	}
-
-	if len(updateMask.Paths) == 0 {
-		return nil
-	}
-
 	desired := a.desired.DeepCopy()
 	resource := CertificateManagerDNSAuthorizationSpec_ToProto(mapCtx, &desired.Spec)
 	if mapCtx.Err() != nil {
 		return mapCtx.Err()
 	}
+	resource.Name = a.id.FullyQualifiedName()
 
 	resource.Labels = make(map[string]string)
 	for k, v := range a.desired.GetObjectMeta().GetLabels() {
@@ -242,6 +229,25 @@ func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 	}
 	resource.Labels["managed-by-cnrm"] = "true"
 
+	// The type is not in the spec, so it is always the default of the service.
+	diff, err := common.DiffProtoMessages(resource, a.actual, common.IgnoreFields("type"))
+	if err != nil {
+		return err
+	}
+	updateMask := &fieldmaskpb.FieldMask{}
+
+	if diff.HasField("description") {
+		updateMask.Paths = append(updateMask.Paths, "description")
+	}
+
+	if diff.HasField("labels") {
+		updateMask.Paths = append(updateMask.Paths, "labels")
+	}
+
+	if len(updateMask.Paths) == 0 {
+		return nil
+	}
+
 	req := &certificatemanagerpb.UpdateDnsAuthorizationRequest{
 		UpdateMask:       updateMask,
 		DnsAuthorization: &certificatemanagerpb.DnsAuthorization{Description: resource.Description, Labels: resource.Labels, Name: a.id.FullyQualifiedName()},
@@ -24,7 +24,6 @@ import (
 	gcp "cloud.google.com/go/cloudbuild/apiv1/v2"
 	cloudbuildpb "cloud.google.com/go/cloudbuild/apiv1/v2/cloudbuildpb"
 	cloudresourcemanager "cloud.google.com/go/resourcemanager/apiv3"
-	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/cloudbuild/v1beta1"
 	refs "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
@@ -192,6 +191,7 @@ type Adapter struct {
 }
 
 var _ directbase.Adapter = &Adapter{}
+var _ directbase.Planner = &Adapter{}
 
 func (a *Adapter) Find(ctx context.Context) (bool, error) {
 	req := &cloudbuildpb.GetWorkerPoolRequest{Name: a.id.FullyQualifiedName()}
@@ -252,34 +252,26 @@ func (a *Adapter) Create(ctx context.Context, createOp *directbase.CreateOperati
 func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperation) error {
 	u := updateOp.GetUnstructured()
 
-	err := a.resolveDependencies(ctx, a.reader, a.desired)
-	if err != nil {
-		return err
-	}
-
 	log := klog.FromContext(ctx).WithName(ctrlName)
 
-	desired := a.desired.DeepCopy()
-	mapCtx := &direct.MapContext{}
-	wp := CloudBuildWorkerPoolSpec_ToProto(mapCtx, &desired.Spec)
-	if mapCtx.Err() != nil {
-		return mapCtx.Err()
+	wp, err := a.desiredForUpdate(ctx)
+	if err != nil {
+		return err
 	}
-	wp.Name = a.id.FullyQualifiedName()
-	wp.Etag = a.actual.Etag
 
-	paths, err := common.CompareProtoMessage(wp, a.actual, common.BasicDiff)
+	diff, err := common.DiffProtoMessages(wp, a.actual)
 	if err != nil {
 		return err
 	}
 
-	if len(paths) == 0 {
+	if diff.IsEmpty() {
 		log.V(2).Info("no field needs update", "name", a.id.AsExternalRef())
 		return nil
 	}
+	log.V(2).Info("updating fields", "name", a.id.AsExternalRef(), "diff", diff.String())
 	req := &cloudbuildpb.UpdateWorkerPoolRequest{
 		WorkerPool: wp,
-		UpdateMask: &fieldmaskpb.FieldMask{Paths: sets.List(paths)},
+		UpdateMask: diff.UpdateMask(),
 	}
 	op, err := a.gcpClient.UpdateWorkerPool(ctx, req)
 	if err != nil {
@@ -289,6 +281,7 @@ func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 	if err != nil {
 		return fmt.Errorf("cloudbuildworkerpool %s waiting update failed: %w", wp.Name, err)
 	}
+	mapCtx := &direct.MapContext{}
 	status := &krm.CloudBuildWorkerPoolStatus{}
 	status.ObservedState = CloudBuildWorkerPoolObservedState_FromProto(mapCtx, updated)
 	if mapCtx.Err() != nil {
@@ -297,6 +290,41 @@ func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 	return setStatus(u, status)
 }
 
+// Plan implements the Planner interface.
+func (a *Adapter) Plan(ctx context.Context, planOp *directbase.PlanOperation) (*directbase.Plan, error) {
+	if a.actual == nil {
+		return &directbase.Plan{Action: directbase.PlanActionCreate}, nil
+	}
+
+	wp, err := a.desiredForUpdate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := common.DiffProtoMessages(wp, a.actual)
+	if err != nil {
+		return nil, err
+	}
+	return directbase.NewUpdatePlan(wp, a.actual, sets.List(diff.Paths())), nil
+}
+
+// desiredForUpdate builds the WorkerPool proto that Update would send to GCP.
+func (a *Adapter) desiredForUpdate(ctx context.Context) (*cloudbuildpb.WorkerPool, error) {
+	if err := a.resolveDependencies(ctx, a.reader, a.desired); err != nil {
+		return nil, err
+	}
+
+	desired := a.desired.DeepCopy()
+	mapCtx := &direct.MapContext{}
+	wp := CloudBuildWorkerPoolSpec_ToProto(mapCtx, &desired.Spec)
+	if mapCtx.Err() != nil {
+		return nil, mapCtx.Err()
+	}
+	wp.Name = a.id.FullyQualifiedName()
+	wp.Etag = a.actual.Etag
+	return wp, nil
+}
+
 func (a *Adapter) Export(ctx context.Context) (*unstructured.Unstructured, error) {
 	if a.actual == nil {
 		return nil, fmt.Errorf("Find() not called")
@@ -0,0 +1,353 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ProtoDiff is the difference between a desired and an actual proto message.
+//
+// Only proto messages are compared. The controllers which use a REST client
+// generated from a discovery document, i.e. SQLInstance, AlloyDBCluster,
+// BigQueryDataset, GKEHubFeatureMembership and LoggingLogMetric, keep their
+// own comparison of the REST types.
+type ProtoDiff struct {
+	Fields []FieldDiff
+}
+
+// FieldDiff is a field whose desired and actual values differ.
+type FieldDiff struct {
+	// Path is the path of the field in the format of an update mask, e.g.
+	// "settings.backup_configuration". A nested message is recursed into only
+	// if it is set on both sides, and lists and maps are compared as a whole.
+	Path    string
+	Desired string
+	Actual  string
+}
+
+// IsEmpty returns whether the messages are equal.
+func (d *ProtoDiff) IsEmpty() bool {
+	return len(d.Fields) == 0
+}
+
+// Paths returns the paths of the fields which differ.
+func (d *ProtoDiff) Paths() sets.Set[string] {
+	paths := sets.New[string]()
+	for _, f := range d.Fields {
+		paths.Insert(f.Path)
+	}
+	return paths
+}
+
+// HasField returns whether the field with the given path differs, or a field
+// nested in it, e.g. "rotation" differs if "rotation.rotation_period" does.
+func (d *ProtoDiff) HasField(path string) bool {
+	for _, f := range d.Fields {
+		if f.Path == path || strings.HasPrefix(f.Path, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateMask returns the update mask of the fields which differ.
+func (d *ProtoDiff) UpdateMask() *fieldmaskpb.FieldMask {
+	return &fieldmaskpb.FieldMask{Paths: sets.List(d.Paths())}
+}
+
+// String returns a human-readable diff, with one line per field.
+func (d *ProtoDiff) String() string {
+	var b strings.Builder
+	for _, f := range d.Fields {
+		fmt.Fprintf(&b, "%s: %s -> %s\n", f.Path, f.Actual, f.Desired)
+	}
+	return b.String()
+}
+
+// FieldComparator returns whether the desired and actual values of a field are
+// equal, e.g. ignoring the case of a value normalized by the server. For a
+// list or map field, it compares the elements or values.
+type FieldComparator func(desired, actual protoreflect.Value) bool
+
+type DiffOption func(*protoDiffer)
+
+// WithFieldComparator compares the field with the given path, e.g.
+// "settings.tier", using comparator. The paths of the fields of the messages
+// in a list or map don't contain an index or key.
+func WithFieldComparator(path string, comparator FieldComparator) DiffOption {
+	return func(d *protoDiffer) {
+		d.comparators[path] = comparator
+	}
+}
+
+// IgnoreFields skips the fields with the given paths.
+func IgnoreFields(paths ...string) DiffOption {
+	return func(d *protoDiffer) {
+		d.ignored.Insert(paths...)
+	}
+}
+
+// IgnoreUnsetFields skips the fields which are unset in the desired message,
+// i.e. lets the server default them.
+func IgnoreUnsetFields() DiffOption {
+	return func(d *protoDiffer) {
+		d.ignoreUnset = true
+	}
+}
+
+type protoDiffer struct {
+	comparators map[string]FieldComparator
+	ignored     sets.Set[string]
+	ignoreUnset bool
+}
+
+// DiffProtoMessages compares the desired message to the actual one, recursing
+// into nested messages. Output-only fields are skipped, and a change to an
+// immutable field is an error.
+func DiffProtoMessages(desired, actual proto.Message, opts ...DiffOption) (*ProtoDiff, error) {
+	d := &protoDiffer{
+		comparators: make(map[string]FieldComparator),
+		ignored:     sets.New[string](),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	diff := &ProtoDiff{}
+	if err := d.diffMessage(diff, "", desired.ProtoReflect(), actual.ProtoReflect(), true, false); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// diffMessage appends the fields of desired which differ from actual to diff.
+// If inImmutable is set, the message is a field of an immutable message.
+func (d *protoDiffer) diffMessage(diff *ProtoDiff, prefix string, desired, actual protoreflect.Message, checkImmutable, inImmutable bool) error {
+	fields := desired.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		path := string(field.Name())
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		if d.ignored.Has(path) || IsFieldBehavior(field, annotations.FieldBehavior_OUTPUT_ONLY) {
+			continue
+		}
+		desiredSet := desired.Has(field)
+		actualSet := actual.IsValid() && actual.Has(field)
+		if !desiredSet && (d.ignoreUnset || !actualSet) {
+			continue
+		}
+		desiredVal := desired.Get(field)
+		actualVal := actual.Get(field)
+		immutable := inImmutable || IsFieldBehavior(field, annotations.FieldBehavior_IMMUTABLE)
+		_, hasComparator := d.comparators[path]
+		if desiredSet && actualSet && isRecursedMessage(field) && !hasComparator {
+			if err := d.diffMessage(diff, path, desiredVal.Message(), actualVal.Message(), checkImmutable, immutable); err != nil {
+				return err
+			}
+			continue
+		}
+		if d.equalField(path, field, desiredVal, actualVal) {
+			continue
+		}
+		if checkImmutable && immutable {
+			return fmt.Errorf("change to immutable field %s", path)
+		}
+		diff.Fields = append(diff.Fields, FieldDiff{
+			Path:    path,
+			Desired: formatField(field, desiredVal, desiredSet),
+			Actual:  formatField(field, actualVal, actualSet),
+		})
+	}
+	return nil
+}
+
+// isRecursedMessage returns whether the field is a singular message which is
+// compared field by field. The well-known types, e.g. Timestamp, are compared
+// as a whole, otherwise the update mask would be wrong.
+func isRecursedMessage(field protoreflect.FieldDescriptor) bool {
+	if field.Message() == nil || field.IsList() || field.IsMap() {
+		return false
+	}
+	return !isWellKnownType(field.Message())
+}
+
+func isWellKnownType(m protoreflect.MessageDescriptor) bool {
+	return strings.HasPrefix(string(m.FullName()), "google.protobuf.")
+}
+
+func (d *protoDiffer) equalField(path string, field protoreflect.FieldDescriptor, desired, actual protoreflect.Value) bool {
+	switch {
+	case field.IsList():
+		desiredList, actualList := desired.List(), actual.List()
+		if desiredList.Len() != actualList.Len() {
+			return false
+		}
+		for i := 0; i < desiredList.Len(); i++ {
+			if !d.equalValue(path, field, desiredList.Get(i), actualList.Get(i)) {
+				return false
+			}
+		}
+		return true
+	case field.IsMap():
+		desiredMap, actualMap := desired.Map(), actual.Map()
+		if desiredMap.Len() != actualMap.Len() {
+			return false
+		}
+		equal := true
+		desiredMap.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			if !actualMap.Has(k) || !d.equalValue(path, field.MapValue(), v, actualMap.Get(k)) {
+				equal = false
+			}
+			return equal
+		})
+		return equal
+	default:
+		return d.equalValue(path, field, desired, actual)
+	}
+}
+
+// equalValue compares a singular value, a list element or a map value.
+func (d *protoDiffer) equalValue(path string, field protoreflect.FieldDescriptor, desired, actual protoreflect.Value) bool {
+	if comparator, ok := d.comparators[path]; ok {
+		return comparator(desired, actual)
+	}
+	if field.Message() == nil {
+		return desired.Equal(actual)
+	}
+	if isWellKnownType(field.Message()) {
+		return proto.Equal(desired.Message().Interface(), actual.Message().Interface())
+	}
+	// The messages in a list or map are compared with the same options, so
+	// that the comparators of their fields apply.
+	diff := &ProtoDiff{}
+	if err := d.diffMessage(diff, path, desired.Message(), actual.Message(), false, false); err != nil {
+		return false
+	}
+	return diff.IsEmpty()
+}
+
+func formatField(field protoreflect.FieldDescriptor, v protoreflect.Value, set bool) string {
+	if !set {
+		return "<unset>"
+	}
+	switch {
+	case field.IsList():
+		var elems []string
+		for i := 0; i < v.List().Len(); i++ {
+			elems = append(elems, formatValue(field, v.List().Get(i)))
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case field.IsMap():
+		var entries []string
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			entries = append(entries, fmt.Sprintf("%v: %s", k.Interface(), formatValue(field.MapValue(), v)))
+			return true
+		})
+		sort.Strings(entries)
+		return "{" + strings.Join(entries, ", ") + "}"
+	default:
+		return formatValue(field, v)
+	}
+}
+
+func formatValue(field protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		b, err := protojson.Marshal(v.Message().Interface())
+		if err != nil {
+			return fmt.Sprintf("<%v>", err)
+		}
+		return string(b)
+	case protoreflect.EnumKind:
+		if ev := field.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return fmt.Sprintf("%d", v.Enum())
+	case protoreflect.StringKind:
+		return fmt.Sprintf("%q", v.String())
+	case protoreflect.BytesKind:
+		return fmt.Sprintf("<%d bytes>", len(v.Bytes()))
+	default:
+		return fmt.Sprintf("%v", v.Interface())
+	}
+}
+
+// EqualOrDefault returns a comparator which also treats an unset desired
+// value as equal to the given default value of the server.
+func EqualOrDefault(defaultValue interface{}) FieldComparator {
+	def := protoreflect.ValueOf(defaultValue)
+	return func(desired, actual protoreflect.Value) bool {
+		if desired.Equal(actual) {
+			return true
+		}
+		return isZeroValue(desired) && def.Equal(actual)
+	}
+}
+
+func isZeroValue(v protoreflect.Value) bool {
+	switch x := v.Interface().(type) {
+	case nil:
+		return true
+	case string:
+		return x == ""
+	case bool:
+		return !x
+	case int32:
+		return x == 0
+	case int64:
+		return x == 0
+	case uint32:
+		return x == 0
+	case uint64:
+		return x == 0
+	case float32:
+		return x == 0
+	case float64:
+		return x == 0
+	case protoreflect.EnumNumber:
+		return x == 0
+	case []byte:
+		return len(x) == 0
+	default:
+		return false
+	}
+}
+
+// SameResourceName compares resource names ignoring the form they are in,
+// e.g. "projects/p/global/networks/n" and
+// "https://www.googleapis.com/compute/v1/projects/p/global/networks/n".
+func SameResourceName(desired, actual protoreflect.Value) bool {
+	return trimResourceName(desired.String()) == trimResourceName(actual.String())
+}
+
+func trimResourceName(name string) string {
+	for _, collection := range []string{"projects/", "folders/", "organizations/"} {
+		if i := strings.Index(name, collection); i > 0 {
+			return name[i:]
+		}
+	}
+	return name
+}
@@ -0,0 +1,187 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"strings"
+	"testing"
+
+	pb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDiffProtoMessages(t *testing.T) {
+	base := func() *pb.Secret {
+		return &pb.Secret{
+			Name:   "projects/p/secrets/s",
+			Labels: map[string]string{"env": "prod"},
+			Replication: &pb.Replication{
+				Replication: &pb.Replication_Automatic_{Automatic: &pb.Replication_Automatic{
+					CustomerManagedEncryption: &pb.CustomerManagedEncryption{KmsKeyName: "key-1"},
+				}},
+			},
+			Topics: []*pb.Topic{{Name: "projects/p/topics/t"}},
+			Rotation: &pb.Rotation{
+				NextRotationTime: &timestamppb.Timestamp{Seconds: 1700000000},
+				RotationPeriod:   durationpb.New(3600e9),
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		desired   func(*pb.Secret)
+		actual    func(*pb.Secret)
+		opts      []DiffOption
+		wantPaths []string
+		wantErr   string
+	}{
+		{
+			name: "equal",
+		},
+		{
+			name:      "nested message field",
+			desired:   func(s *pb.Secret) { s.Rotation.RotationPeriod = durationpb.New(7200e9) },
+			wantPaths: []string{"rotation.rotation_period"},
+		},
+		{
+			name:      "map field",
+			desired:   func(s *pb.Secret) { s.Labels["team"] = "a" },
+			wantPaths: []string{"labels"},
+		},
+		{
+			name:    "output-only field",
+			actual:  func(s *pb.Secret) { s.CreateTime = timestamppb.Now() },
+			desired: func(s *pb.Secret) {},
+		},
+		{
+			name: "field within immutable field",
+			desired: func(s *pb.Secret) {
+				s.GetReplication().GetAutomatic().CustomerManagedEncryption.KmsKeyName = "key-2"
+			},
+			wantErr: "change to immutable field replication.automatic.customer_managed_encryption.kms_key_name",
+		},
+		{
+			name:      "unset field",
+			desired:   func(s *pb.Secret) { s.Labels = nil },
+			wantPaths: []string{"labels"},
+		},
+		{
+			name:    "ignore unset field",
+			desired: func(s *pb.Secret) { s.Labels = nil },
+			opts:    []DiffOption{IgnoreUnsetFields()},
+		},
+		{
+			name:    "ignore field",
+			desired: func(s *pb.Secret) { s.Labels["team"] = "a" },
+			opts:    []DiffOption{IgnoreFields("labels")},
+		},
+		{
+			name:    "map values with a comparator",
+			desired: func(s *pb.Secret) { s.Labels["env"] = "projects/p/envs/prod" },
+			actual:  func(s *pb.Secret) { s.Labels["env"] = "https://example.com/v1/projects/p/envs/prod" },
+			opts:    []DiffOption{WithFieldComparator("labels", SameResourceName)},
+		},
+		{
+			name:    "resource name of a list element",
+			actual:  func(s *pb.Secret) { s.Topics[0].Name = "//pubsub.googleapis.com/projects/p/topics/t" },
+			opts:    []DiffOption{WithFieldComparator("topics.name", SameResourceName)},
+			desired: func(s *pb.Secret) {},
+		},
+		{
+			name:      "resource name of a list element without comparator",
+			actual:    func(s *pb.Secret) { s.Topics[0].Name = "//pubsub.googleapis.com/projects/p/topics/t" },
+			wantPaths: []string{"topics"},
+		},
+		{
+			name:   "server default",
+			actual: func(s *pb.Secret) { s.Etag = "default" },
+			opts:   []DiffOption{WithFieldComparator("etag", EqualOrDefault("default"))},
+		},
+		{
+			name:      "not the server default",
+			actual:    func(s *pb.Secret) { s.Etag = "other" },
+			opts:      []DiffOption{WithFieldComparator("etag", EqualOrDefault("default"))},
+			wantPaths: []string{"etag"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			desired, actual := base(), base()
+			if tc.desired != nil {
+				tc.desired(desired)
+			}
+			if tc.actual != nil {
+				tc.actual(actual)
+			}
+			diff, err := DiffProtoMessages(desired, actual, tc.opts...)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantPaths, diff.UpdateMask().GetPaths(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("unexpected update mask (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProtoDiffString(t *testing.T) {
+	desired := &pb.Secret{
+		Etag:     "b",
+		Labels:   map[string]string{"b": "2", "a": "1"},
+		Rotation: &pb.Rotation{RotationPeriod: durationpb.New(60e9)},
+	}
+	actual := &pb.Secret{
+		Etag:     "a",
+		Rotation: &pb.Rotation{},
+	}
+	diff, err := DiffProtoMessages(desired, actual)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `labels: <unset> -> {a: "1", b: "2"}
+etag: "a" -> "b"
+rotation.rotation_period: <unset> -> "60s"
+`
+	if diff := cmp.Diff(want, diff.String()); diff != "" {
+		t.Errorf("unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestProtoDiffHasField(t *testing.T) {
+	diff := &ProtoDiff{Fields: []FieldDiff{{Path: "rotation.rotation_period"}, {Path: "labels"}}}
+	for path, want := range map[string]bool{
+		"labels":                   true,
+		"rotation":                 true,
+		"rotation.rotation_period": true,
+		"rotation.next":            false,
+		"rot":                      false,
+		"etag":                     false,
+	} {
+		if got := diff.HasField(path); got != want {
+			t.Errorf("HasField(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
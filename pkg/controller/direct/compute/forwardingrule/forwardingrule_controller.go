@@ -17,7 +17,6 @@ package forwardingrule
 import (
 	"context"
 	"fmt"
-	"reflect"
 	"strings"
 
 	"k8s.io/klog/v2"
@@ -29,6 +28,7 @@ import (
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/compute/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 	kccpredicate "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/predicate"
@@ -323,9 +323,17 @@ func (a *forwardingRuleAdapter) Update(ctx context.Context, updateOp *directbase
 	forwardingRule.Name = direct.LazyPtr(a.id.forwardingRule)
 	forwardingRule.Labels = desired.Labels
 
+	// The target of a forwarding rule is a URL of the target resource when read back.
+	diff, err := common.DiffProtoMessages(forwardingRule, a.actual,
+		common.WithFieldComparator("target", common.SameResourceName))
+	if err != nil {
+		return err
+	}
+	paths := diff.Paths()
+
 	op := &gcp.Operation{}
 	updated := &computepb.ForwardingRule{}
-	if !reflect.DeepEqual(forwardingRule.AllowGlobalAccess, a.actual.AllowGlobalAccess) {
+	if paths.Has("allow_global_access") {
 		// To match the request body in TF-controller log
 		// https://github.com/hashicorp/terraform-provider-google/blob/main/google/services/compute/resource_compute_forwarding_rule.go#L1151
 		reqBody := &computepb.ForwardingRule{AllowGlobalAccess: forwardingRule.AllowGlobalAccess}
@@ -354,7 +362,7 @@ func (a *forwardingRuleAdapter) Update(ctx context.Context, updateOp *directbase
 	}
 
 	// Use setTarget and setLabels to update target and labels fields.
-	if !reflect.DeepEqual(forwardingRule.Labels, a.actual.Labels) {
+	if paths.Has("labels") {
 		op, err := a.setLabels(ctx, a.actual.LabelFingerprint, forwardingRule.Labels)
 		if err != nil {
 			return fmt.Errorf("updating ComputeForwardingRule labels %s: %w", a.fullyQualifiedName(), err)
@@ -369,7 +377,7 @@ func (a *forwardingRuleAdapter) Update(ctx context.Context, updateOp *directbase
 	}
 
 	// setTarget request is sent when there are updates to target.
-	if !reflect.DeepEqual(forwardingRule.Target, a.actual.Target) {
+	if paths.Has("target") {
 		if a.id.location == "global" {
 			setTargetReq := &computepb.SetTargetGlobalForwardingRuleRequest{
 				ForwardingRule:          a.id.forwardingRule,
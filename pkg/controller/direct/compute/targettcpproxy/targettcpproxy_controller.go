@@ -17,7 +17,6 @@ package targettcpproxy
 import (
 	"context"
 	"fmt"
-	"reflect"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
@@ -29,6 +28,7 @@ import (
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/compute/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 	kccpredicate "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/predicate"
@@ -275,7 +275,16 @@ func (a *targetTCPProxyAdapter) Update(ctx context.Context, updateOp *directbase
 	tokens := strings.Split(a.id.String(), "/")
 	targetTCPProxy.Name = direct.LazyPtr(tokens[len(tokens)-1])
 
-	if !reflect.DeepEqual(targetTCPProxy.ProxyHeader, a.actual.ProxyHeader) {
+	// The server defaults the proxy header to NONE, and returns the backend service as a URL.
+	diff, err := common.DiffProtoMessages(targetTCPProxy, a.actual,
+		common.WithFieldComparator("proxy_header", common.EqualOrDefault("NONE")),
+		common.WithFieldComparator("service", common.SameResourceName))
+	if err != nil {
+		return err
+	}
+	paths := diff.Paths()
+
+	if paths.Has("proxy_header") {
 		setProxyHeaderReq := &computepb.SetProxyHeaderTargetTcpProxyRequest{
 			Project: parent.ProjectID,
 			TargetTcpProxiesSetProxyHeaderRequestResource: &computepb.TargetTcpProxiesSetProxyHeaderRequest{ProxyHeader: targetTCPProxy.ProxyHeader},
@@ -294,7 +303,7 @@ func (a *targetTCPProxyAdapter) Update(ctx context.Context, updateOp *directbase
 		log.V(2).Info("successfully updated ComputeTargetTCPProxy proxy header", "name", a.id)
 	}
 
-	if !reflect.DeepEqual(targetTCPProxy.Service, a.actual.Service) {
+	if paths.Has("service") {
 		setBackendServiceReq := &computepb.SetBackendServiceTargetTcpProxyRequest{
 			Project: parent.ProjectID,
 			TargetTcpProxiesSetBackendServiceRequestResource: &computepb.TargetTcpProxiesSetBackendServiceRequest{Service: targetTCPProxy.Service},
@@ -17,12 +17,12 @@ package dataform
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/dataform/v1beta1"
 	apirefs "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 
@@ -231,52 +231,44 @@ func (a *Adapter) Create(ctx context.Context, createOp *directbase.CreateOperati
 func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperation) error {
 	u := updateOp.GetUnstructured()
 
-	updateMask := &fieldmaskpb.FieldMask{}
-
-	if a.desired.Spec.GitRemoteSettings != nil {
-		mapCtx := &direct.MapContext{}
-		protoDesired := RepositoryGitRemoteSettings_ToProto(mapCtx, a.desired.Spec.GitRemoteSettings)
-		if mapCtx.Err() != nil {
-			return fmt.Errorf("converting GitRemoteSettings to api: %w", mapCtx.Err())
-		}
-
-		if !reflect.DeepEqual(protoDesired, a.actual.GitRemoteSettings) {
-			updateMask.Paths = append(updateMask.Paths, "git_remote_settings")
-		}
+	desired := a.desired.DeepCopy()
+	mapCtx := &direct.MapContext{}
+	resource := DataformRepositorySpec_ToProto(mapCtx, &desired.Spec)
+	if mapCtx.Err() != nil {
+		return fmt.Errorf("converting DataformRepository spec to api: %w", mapCtx.Err())
 	}
 
-	if a.desired.Spec.WorkspaceCompilationOverrides != nil {
-		mapCtx := &direct.MapContext{}
-		protoDesired := RepositoryWorkspaceCompilationOverrides_ToProto(mapCtx, a.desired.Spec.WorkspaceCompilationOverrides)
-		if mapCtx.Err() != nil {
-			return fmt.Errorf("converting WorkspaceCompilationOverrides to api: %w", mapCtx.Err())
-		}
-
-		if !reflect.DeepEqual(protoDesired, a.actual.WorkspaceCompilationOverrides) {
-			updateMask.Paths = append(updateMask.Paths, "workspace_compilation_overrides")
-		}
+	// The settings which are not set are not managed.
+	var unmanaged []string
+	if a.desired.Spec.GitRemoteSettings == nil {
+		unmanaged = append(unmanaged, "git_remote_settings")
 	}
-
-	if a.desired.Spec.NpmrcEnvironmentVariablesSecretVersionRef != nil {
-		if !reflect.DeepEqual(a.desired.Spec.NpmrcEnvironmentVariablesSecretVersionRef.External, a.actual.NpmrcEnvironmentVariablesSecretVersion) {
-			updateMask.Paths = append(updateMask.Paths, "npmrc_environment_variables_secret_version")
-		}
+	if a.desired.Spec.WorkspaceCompilationOverrides == nil {
+		unmanaged = append(unmanaged, "workspace_compilation_overrides")
 	}
-
-	if a.desired.Spec.SetAuthenticatedUserAdmin != a.actual.SetAuthenticatedUserAdmin {
-		updateMask.Paths = append(updateMask.Paths, "set_authenticated_user_admin")
+	if a.desired.Spec.NpmrcEnvironmentVariablesSecretVersionRef == nil {
+		unmanaged = append(unmanaged, "npmrc_environment_variables_secret_version")
+	}
+	diff, err := common.DiffProtoMessages(resource, a.actual, common.IgnoreFields(unmanaged...))
+	if err != nil {
+		return err
 	}
 
-	desired := a.desired.DeepCopy()
-	mapCtx := &direct.MapContext{}
-	resource := DataformRepositorySpec_ToProto(mapCtx, &desired.Spec)
-	if mapCtx.Err() != nil {
-		return fmt.Errorf("converting DataformRepository spec to api: %w", mapCtx.Err())
+	updateMask := &fieldmaskpb.FieldMask{}
+	for _, field := range []string{
+		"git_remote_settings",
+		"workspace_compilation_overrides",
+		"npmrc_environment_variables_secret_version",
+		"set_authenticated_user_admin",
+	} {
+		if diff.HasField(field) {
+			updateMask.Paths = append(updateMask.Paths, field)
+		}
 	}
 
 	resource.Name = a.id.FullyQualifiedName()
 	req := &dataformpb.UpdateRepositoryRequest{UpdateMask: updateMask, Repository: resource}
-	_, err := a.gcpClient.UpdateRepository(ctx, req)
+	_, err = a.gcpClient.UpdateRepository(ctx, req)
 	if err != nil {
 		return fmt.Errorf("DataformRepository %s update failed: %w", resource.Name, err)
 	}
@@ -0,0 +1,272 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/k8s/v1alpha1"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FieldManager is the field manager of the server-side applies of the direct
+// controllers. It is the field manager of the lifecycle handler, so the spec
+// fields it writes are not considered to be managed by users, see
+// k8s.GetK8sManagedFields.
+const FieldManager = k8s.ControllerManagedFieldManager
+
+// FieldManagerConflict is a field of an apply which is managed by another
+// field manager.
+type FieldManagerConflict struct {
+	// Field is the path of the field, e.g. ".spec.resourceID".
+	Field string
+	// Manager is the name of the other field manager.
+	Manager string
+}
+
+// FieldManagerConflictError is returned when a server-side apply conflicts
+// with the fields managed by other field managers.
+type FieldManagerConflictError struct {
+	Conflicts []FieldManagerConflict
+
+	err error
+}
+
+func (e *FieldManagerConflictError) Error() string {
+	var fields []string
+	for _, c := range e.Conflicts {
+		fields = append(fields, fmt.Sprintf("%s (managed by %q)", c.Field, c.Manager))
+	}
+	return fmt.Sprintf("fields are managed by another field manager: %s", strings.Join(fields, ", "))
+}
+
+func (e *FieldManagerConflictError) Unwrap() error {
+	return e.err
+}
+
+// IsFieldManagerConflict returns whether err is caused by a FieldManagerConflictError.
+func IsFieldManagerConflict(err error) bool {
+	var conflictErr *FieldManagerConflictError
+	return errors.As(err, &conflictErr)
+}
+
+// conflictManagerRegexp matches the field manager in the message of a
+// conflict, e.g. `conflict with "kubectl" using v1`.
+var conflictManagerRegexp = regexp.MustCompile(`conflict with "([^"]*)"`)
+
+// conflictsFromError returns the field manager conflicts of err.
+func conflictsFromError(err error) []FieldManagerConflict {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return nil
+	}
+	var conflicts []FieldManagerConflict
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflict := FieldManagerConflict{Field: cause.Field}
+		if m := conflictManagerRegexp.FindStringSubmatch(cause.Message); m != nil {
+			conflict.Manager = m[1]
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
+
+// serverSideApply applies the fields of patch with FieldManager, to the status
+// subresource if status is set. The fields which were written by FieldManager
+// with an update rather than an apply are taken over, but a conflict with
+// another field manager is returned as a FieldManagerConflictError.
+//
+// On success, patch holds the object returned by the API server.
+func serverSideApply(ctx context.Context, c client.Client, patch *unstructured.Unstructured, status bool) error {
+	apply := func(force bool) error {
+		if status {
+			opts := []client.SubResourcePatchOption{client.FieldOwner(FieldManager)}
+			if force {
+				opts = append(opts, client.ForceOwnership)
+			}
+			return c.Status().Patch(ctx, patch, client.Apply, opts...)
+		}
+		opts := []client.PatchOption{client.FieldOwner(FieldManager)}
+		if force {
+			opts = append(opts, client.ForceOwnership)
+		}
+		return c.Patch(ctx, patch, client.Apply, opts...)
+	}
+
+	err := apply(false)
+	if err == nil || !apierrors.IsConflict(err) {
+		return err
+	}
+	conflicts := conflictsFromError(err)
+	if len(conflicts) == 0 {
+		return err
+	}
+	var others []FieldManagerConflict
+	for _, conflict := range conflicts {
+		if conflict.Manager != FieldManager {
+			others = append(others, conflict)
+		}
+	}
+	if len(others) != 0 {
+		return &FieldManagerConflictError{Conflicts: others, err: err}
+	}
+	return apply(true)
+}
+
+// newApplyPatch returns the skeleton of an apply patch for u.
+func newApplyPatch(u *unstructured.Unstructured) *unstructured.Unstructured {
+	patch := &unstructured.Unstructured{}
+	patch.SetGroupVersionKind(u.GroupVersionKind())
+	patch.SetNamespace(u.GetNamespace())
+	patch.SetName(u.GetName())
+	return patch
+}
+
+// syncFromApplied copies the given field and the metadata written by the API
+// server from the applied object to u.
+func syncFromApplied(u, applied *unstructured.Unstructured, field string) {
+	u.Object[field] = applied.Object[field]
+	u.SetResourceVersion(applied.GetResourceVersion())
+	u.SetGeneration(applied.GetGeneration())
+	u.SetManagedFields(applied.GetManagedFields())
+}
+
+// directStatusFields are the status fields which the direct controller writes for every resource.
+var directStatusFields = sets.New("conditions", "observedGeneration", "externalRef", "observedState")
+
+// applyStatus writes the status fields of u which the direct controller owns
+// to the status subresource with a server-side apply. Unlike the status update
+// of the lifecycle handler, the status fields of other field managers are kept,
+// and are not co-owned by FieldManager.
+func applyStatus(ctx context.Context, c client.Client, u *unstructured.Unstructured) error {
+	status, err := ownedStatus(u)
+	if err != nil {
+		return err
+	}
+
+	patch := newApplyPatch(u)
+	patch.Object["status"] = status
+	if err := serverSideApply(ctx, c, patch, true); err != nil {
+		return fmt.Errorf("applying object status: %w", err)
+	}
+	syncFromApplied(u, patch, "status")
+	return nil
+}
+
+// ownedStatus returns the status fields of u which the direct controller owns:
+// the fields it writes for every resource, and the fields an adapter wrote, i.e.
+// the fields which FieldManager already manages or which no other field manager
+// manages.
+func ownedStatus(u *unstructured.Unstructured) (map[string]interface{}, error) {
+	status, _, err := unstructured.NestedMap(u.Object, "status")
+	if err != nil {
+		return nil, fmt.Errorf("reading status: %w", err)
+	}
+	mine, others, err := statusFieldManagers(u)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[string]interface{})
+	for k, v := range status {
+		// An unset field is left out of the apply, rather than applied as null.
+		if v == nil {
+			continue
+		}
+		if directStatusFields.Has(k) || mine.Has(k) || !others.Has(k) {
+			owned[k] = v
+		}
+	}
+	return owned, nil
+}
+
+// statusFieldManagers returns the top-level status fields of u which are
+// managed by FieldManager, and the ones which are managed by other field
+// managers.
+func statusFieldManagers(u *unstructured.Unstructured) (mine, others sets.Set[string], err error) {
+	mine, others = sets.New[string](), sets.New[string]()
+	for _, entry := range u.GetManagedFields() {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			return nil, nil, fmt.Errorf("reading managed fields of %v: %w", entry.Manager, err)
+		}
+		status, _ := fields["f:status"].(map[string]interface{})
+		for k := range status {
+			field, ok := strings.CutPrefix(k, "f:")
+			if !ok {
+				continue
+			}
+			if entry.Manager == FieldManager {
+				mine.Insert(field)
+			} else {
+				others.Insert(field)
+			}
+		}
+	}
+	return mine, others, nil
+}
+
+// applyReadyCondition sets the ready condition and the observed generation of
+// u, and writes the status of u with applyStatus. The status fields which the
+// adapter set on u, e.g. the observed state, are written along with the
+// condition.
+func applyReadyCondition(ctx context.Context, c client.Client, u *unstructured.Unstructured, readyCondition v1alpha1.Condition) error {
+	conditions, err := getStatusConditions(u)
+	if err != nil {
+		return err
+	}
+	// The last transition time is kept if the status of the condition does not change.
+	SetStatusCondition(&conditions, readyCondition)
+	unstructuredConditions, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&statusWithConditions{Conditions: conditions})
+	if err != nil {
+		return fmt.Errorf("error converting status.conditions to unstructured: %w", err)
+	}
+	if err := unstructured.SetNestedField(u.Object, unstructuredConditions["conditions"], "status", "conditions"); err != nil {
+		return fmt.Errorf("setting status.conditions: %w", err)
+	}
+	if err := unstructured.SetNestedField(u.Object, u.GetGeneration(), "status", "observedGeneration"); err != nil {
+		return fmt.Errorf("setting status.observedGeneration: %w", err)
+	}
+	return applyStatus(ctx, c, u)
+}
+
+func getStatusConditions(u *unstructured.Unstructured) ([]v1alpha1.Condition, error) {
+	status, _, _ := unstructured.NestedMap(u.Object, "status")
+	var s statusWithConditions
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(status, &s); err != nil {
+		return nil, fmt.Errorf("error converting status.conditions from unstructured: %w", err)
+	}
+	if s.Conditions == nil {
+		s.Conditions = []v1alpha1.Condition{}
+	}
+	return s.Conditions, nil
+}
@@ -0,0 +1,228 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directbase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func applyConflict(manager, field string) error {
+	return apierrors.NewApplyConflict([]metav1.StatusCause{{
+		Type:    metav1.CauseTypeFieldManagerConflict,
+		Message: `conflict with "` + manager + `" using v1beta1`,
+		Field:   field,
+	}}, "Apply failed with 1 conflict")
+}
+
+func TestServerSideApply(t *testing.T) {
+	tests := []struct {
+		name          string
+		conflict      error
+		wantApplies   []bool
+		wantConflicts []FieldManagerConflict
+	}{
+		{
+			name:        "no conflict",
+			wantApplies: []bool{false},
+		},
+		{
+			name:        "conflict with an update of the controller",
+			conflict:    applyConflict(FieldManager, ".status.observedGeneration"),
+			wantApplies: []bool{false, true},
+		},
+		{
+			name:          "conflict with another field manager",
+			conflict:      applyConflict("kubectl", ".status.externalRef"),
+			wantApplies:   []bool{false},
+			wantConflicts: []FieldManagerConflict{{Field: ".status.externalRef", Manager: "kubectl"}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// applies records whether each apply was forced.
+			var applies []bool
+			c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+					options := &client.SubResourcePatchOptions{}
+					options.ApplyOptions(opts)
+					if options.FieldManager != FieldManager {
+						t.Errorf("got field manager %q, want %q", options.FieldManager, FieldManager)
+					}
+					force := options.Force != nil && *options.Force
+					applies = append(applies, force)
+					if tc.conflict != nil && !force {
+						return tc.conflict
+					}
+					return nil
+				},
+			}).Build()
+
+			patch := &unstructured.Unstructured{}
+			patch.SetGroupVersionKind(schema.GroupVersionKind{Group: "test.cnrm.cloud.google.com", Version: "v1beta1", Kind: "TestKind"})
+			patch.SetName("test")
+			err := serverSideApply(context.TODO(), c, patch, true)
+
+			if diff := cmp.Diff(tc.wantApplies, applies); diff != "" {
+				t.Errorf("unexpected applies (-want +got):\n%s", diff)
+			}
+			if tc.wantConflicts == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !IsFieldManagerConflict(err) {
+				t.Fatalf("got error %v, want a FieldManagerConflictError", err)
+			}
+			if diff := cmp.Diff(tc.wantConflicts, err.(*FieldManagerConflictError).Conflicts); diff != "" {
+				t.Errorf("unexpected conflicts (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// applyAsMergePatch sends the server-side applies to the status as merge
+// patches, as the fake client can't apply unstructured objects.
+func applyAsMergePatch(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if patch.Type() == types.ApplyPatchType {
+		data, err := patch.Data(obj)
+		if err != nil {
+			return err
+		}
+		patch = client.RawPatch(types.MergePatchType, data)
+	}
+	return c.SubResource(subResourceName).Patch(ctx, obj, patch)
+}
+
+func TestApplyReadyCondition(t *testing.T) {
+	ctx := context.TODO()
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(schema.GroupVersionKind{Group: "test.cnrm.cloud.google.com", Version: "v1beta1", Kind: "TestKind"})
+	u.SetNamespace("ns")
+	u.SetName("test")
+	u.SetGeneration(2)
+	u.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{
+				"type":               "Ready",
+				"status":             "True",
+				"reason":             k8s.UpToDate,
+				"message":            k8s.UpToDateMessage,
+				"lastTransitionTime": "2024-01-01T00:00:00Z",
+			},
+		},
+		"observedGeneration": int64(1),
+	}
+	c := fake.NewClientBuilder().WithObjects(u).WithStatusSubresource(u).
+		WithInterceptorFuncs(interceptor.Funcs{SubResourcePatch: applyAsMergePatch}).Build()
+
+	// The adapter sets the observed state on the object, which is written along with the condition.
+	if err := unstructured.SetNestedField(u.Object, "projects/p/things/t", "status", "observedState", "name"); err != nil {
+		t.Fatalf("setting observed state: %v", err)
+	}
+	ready := k8s.NewCustomReadyCondition(corev1.ConditionTrue, k8s.Updating, "updating")
+	if err := applyReadyCondition(ctx, c, u, ready); err != nil {
+		t.Fatalf("applyReadyCondition: %v", err)
+	}
+
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(u.GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(u), got); err != nil {
+		t.Fatalf("getting object: %v", err)
+	}
+	want := map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{
+				"type":    "Ready",
+				"status":  "True",
+				"reason":  k8s.Updating,
+				"message": "updating",
+				// The status of the condition did not change.
+				"lastTransitionTime": "2024-01-01T00:00:00Z",
+			},
+		},
+		"observedGeneration": int64(2),
+		"observedState": map[string]interface{}{
+			"name": "projects/p/things/t",
+		},
+	}
+	if diff := cmp.Diff(want, got.Object["status"]); diff != "" {
+		t.Errorf("unexpected status (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want, u.Object["status"]); diff != "" {
+		t.Errorf("status of the object was not synced from the applied object (-want +got):\n%s", diff)
+	}
+}
+
+func TestOwnedStatus(t *testing.T) {
+	u := &unstructured.Unstructured{}
+	u.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:  FieldManager,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:conditions":{},"f:legacy":{}}}`)},
+		},
+		{
+			Manager:  "other-controller",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:custom":{},"f:legacy":{}}}`)},
+		},
+		{
+			Manager:  "kubectl",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:foo":{}}}`)},
+		},
+	})
+	u.Object["status"] = map[string]interface{}{
+		"conditions":         []interface{}{},
+		"observedGeneration": int64(1),
+		// Written by the adapter, but not yet managed by any field manager.
+		"externalRef": "projects/p/things/t",
+		"new":         "value",
+		// Managed by both FieldManager and another field manager.
+		"legacy": "value",
+		// Managed by another field manager only.
+		"custom": "value",
+		// Unset fields are left out.
+		"observedState": nil,
+	}
+
+	got, err := ownedStatus(u)
+	if err != nil {
+		t.Fatalf("ownedStatus: %v", err)
+	}
+	want := map[string]interface{}{
+		"conditions":         []interface{}{},
+		"observedGeneration": int64(1),
+		"externalRef":        "projects/p/things/t",
+		"new":                "value",
+		"legacy":             "value",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected owned status (-want +got):\n%s", diff)
+	}
+}
@@ -19,28 +19,36 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/kccstate"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/jitter"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/lifecyclehandler"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/metrics"
 	kccpredicate "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/predicate"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/priorityqueue"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/ratelimiter"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourceactuation"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourcewatcher"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/execution"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/credentials"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/lease/leaser"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tracing"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/util"
 
 	"golang.org/x/sync/semaphore"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
@@ -61,7 +69,7 @@ func AddController(mgr manager.Manager, gvk schema.GroupVersionKind, model Model
 		return fmt.Errorf("model is nil for gvk %s", gvk)
 	}
 
-	reconciler, err := NewReconciler(mgr, immediateReconcileRequests, resourceWatcherRoutines, gvk, model, deps.JitterGenerator)
+	reconciler, err := NewReconciler(mgr, immediateReconcileRequests, resourceWatcherRoutines, gvk, model, deps.JitterGenerator, deps.LeaseBackends)
 	if err != nil {
 		return err
 	}
@@ -70,13 +78,13 @@ func AddController(mgr manager.Manager, gvk schema.GroupVersionKind, model Model
 
 // NewReconciler returns a new reconcile.Reconciler.
 func NewReconciler(mgr manager.Manager, immediateReconcileRequests chan event.GenericEvent, resourceWatcherRoutines *semaphore.Weighted,
-	gvk schema.GroupVersionKind, model Model, jg jitter.Generator) (*DirectReconciler, error) {
+	gvk schema.GroupVersionKind, model Model, jg jitter.Generator, leaseBackends leaser.Backends) (*DirectReconciler, error) {
 	controllerName := strings.ToLower(gvk.Kind) + "-controller"
 	if jg == nil {
 		return nil, fmt.Errorf("jitter generator is not initialized")
 	}
 	r := DirectReconciler{
-		LifecycleHandler: lifecyclehandler.NewLifecycleHandler(
+		LifecycleHandler: newLifecycleHandler(
 			mgr.GetClient(),
 			mgr.GetEventRecorderFor(controllerName),
 		),
@@ -89,31 +97,34 @@ func NewReconciler(mgr manager.Manager, immediateReconcileRequests chan event.Ge
 		gvk:                        gvk,
 		model:                      model,
 		controllerName:             controllerName,
-		ReconcilerMetrics: metrics.ReconcilerMetrics{
-			ResourceNameLabel: metrics.ResourceNameLabel,
-		},
-		jitterGenerator: jg,
+		jitterGenerator:            jg,
+		resourceLeaser:             leaser.NewResourceLeaser(nil, nil, mgr.GetClient(), leaseBackends),
 	}
 	return &r, nil
 }
 
+// newLifecycleHandler returns a lifecycle handler which writes the status with applyStatus.
+func newLifecycleHandler(c client.Client, recorder record.EventRecorder) lifecyclehandler.LifecycleHandler {
+	return lifecyclehandler.NewLifecycleHandlerWithStatusWriter(c, recorder, func(ctx context.Context, u *unstructured.Unstructured) error {
+		return applyStatus(ctx, c, u)
+	})
+}
+
 // add adds a new Controller to mgr with r as the reconcile.Reconciler.
 func add(mgr manager.Manager, r *DirectReconciler, reconcilePredicate predicate.Predicate) error {
-	obj := &unstructured.Unstructured{}
-	obj.SetGroupVersionKind(r.gvk)
-
 	predicateList := []predicate.Predicate{kccpredicate.UnderlyingResourceOutOfSyncPredicate{}}
 	if reconcilePredicate != nil {
 		predicateList = append(predicateList, reconcilePredicate)
 	}
 
+	gate := priorityqueue.NewGate(r.gvk, k8s.ControllerMaxConcurrentReconciles)
 	_, err := builder.
 		ControllerManagedBy(mgr).
 		Named(r.controllerName).
 		WithOptions(crcontroller.Options{MaxConcurrentReconciles: k8s.ControllerMaxConcurrentReconciles, RateLimiter: ratelimiter.NewRateLimiter()}).
-		WatchesRawSource(&source.Channel{Source: r.immediateReconcileRequests}, &handler.EnqueueRequestForObject{}).
-		For(obj, builder.OnlyMetadata, builder.WithPredicates(predicateList...)).
-		Build(r)
+		WatchesRawSource(gate.Source(&source.Channel{Source: r.immediateReconcileRequests}), &handler.EnqueueRequestForObject{}).
+		WatchesRawSource(gate.Kind(mgr.GetCache(), r.gvk), &handler.EnqueueRequestForObject{}, builder.WithPredicates(predicateList...)).
+		Build(gate.Reconciler(r))
 	if err != nil {
 		return fmt.Errorf("error creating new controller: %w", err)
 	}
@@ -125,6 +136,8 @@ var _ reconcile.Reconciler = &DirectReconciler{}
 type Deps struct {
 	JitterGenerator    jitter.Generator
 	ReconcilePredicate predicate.Predicate
+	// LeaseBackends are the external backends of the management conflict prevention leases.
+	LeaseBackends leaser.Backends
 }
 
 // DirectReconciler is a reconciler for reconciling resources that support the Model/Adapter pattern.
@@ -140,6 +153,10 @@ type DirectReconciler struct {
 	immediateReconcileRequests chan event.GenericEvent
 	resourceWatcherRoutines    *semaphore.Weighted // Used to cap number of goroutines watching unready dependencies
 	jitterGenerator            jitter.Generator
+	resourceLeaser             *leaser.ResourceLeaser
+	// planNotSupportedWarnings holds, by UID, the generation of the objects in plan mode
+	// which were last warned that their kind has no field-level plan.
+	planNotSupportedWarnings sync.Map
 
 	controllerName string
 }
@@ -148,6 +165,10 @@ type reconcileContext struct {
 	gvk            schema.GroupVersionKind
 	Reconciler     *DirectReconciler
 	NamespacedName types.NamespacedName
+	// actuation is the actuation decision made by doReconcile.
+	actuation resourceactuation.ActuationDecision
+	// requeueAfter, if set by doReconcile, replaces the reconcile interval, e.g. to poll a pending operation.
+	requeueAfter time.Duration
 }
 
 // Reconcile checks k8s for the current state of the resource.
@@ -158,6 +179,9 @@ func (r *DirectReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 	startTime := time.Now()
 	ctx, cancel := context.WithTimeout(ctx, k8s.ReconcileDeadline)
 	defer cancel()
+	ctx = credentials.WithKind(ctx, r.gvk.Kind)
+	ctx, span := tracing.StartReconcile(ctx, r.gvk, request.NamespacedName)
+	defer func() { tracing.End(span, err) }()
 	r.RecordReconcileWorkers(ctx, r.gvk)
 	defer r.AfterReconcile()
 	defer r.RecordReconcileMetrics(ctx, r.gvk, request.Namespace, request.Name, startTime, &err)
@@ -179,6 +203,11 @@ func (r *DirectReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 		NamespacedName: request.NamespacedName,
 	}
 
+	if k8s.HasReconcilePausedAnnotation(obj) {
+		logger.Info("Skipping reconcile as the resource has the reconcile-paused annotation", "resource", request.NamespacedName)
+		return reconcile.Result{}, runCtx.handlePaused(ctx, obj)
+	}
+
 	skip, err := resourceactuation.ShouldSkip(obj)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -195,12 +224,17 @@ func (r *DirectReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 	if requeue {
 		return reconcile.Result{Requeue: true}, nil
 	}
+	if runCtx.requeueAfter != 0 {
+		logger.Info("successfully finished reconcile", "resource", request.NamespacedName, "time to next reconciliation", runCtx.requeueAfter)
+		return reconcile.Result{RequeueAfter: runCtx.requeueAfter}, nil
+	}
 	jitteredPeriod, err := r.jitterGenerator.JitteredReenqueue(r.gvk, obj)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	logger.Info("successfully finished reconcile", "resource", request.NamespacedName, "time to next reconciliation", jitteredPeriod)
-	return reconcile.Result{RequeueAfter: jitteredPeriod}, nil
+	requeueAfter := runCtx.actuation.RequeueAfter(jitteredPeriod, time.Now())
+	logger.Info("successfully finished reconcile", "resource", request.NamespacedName, "time to next reconciliation", requeueAfter)
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *reconcileContext) doReconcile(ctx context.Context, u *unstructured.Unstructured) (requeue bool, err error) {
@@ -211,9 +245,18 @@ func (r *reconcileContext) doReconcile(ctx context.Context, u *unstructured.Unst
 		return true, err
 	}
 
-	am := resourceactuation.DecideActuationMode(cc, ccc)
-	switch am {
+	decision, err := resourceactuation.DecideActuationMode(cc, ccc, time.Now())
+	if err != nil {
+		return false, err
+	}
+	r.actuation = decision
+	switch am := decision.Mode; am {
 	case v1beta1.Reconciling:
+		if decision.PendingActuationWindow {
+			ensureFinalizers := func() error { return r.ensureFinalizers(ctx, u) }
+			toResource := func() (*k8s.Resource, error) { return toK8sResource(u) }
+			return false, decision.HandlePendingActuationWindow(ctx, r.Reconciler, u, ensureFinalizers, toResource)
+		}
 		logger.V(2).Info("Actuating a resource as actuation mode is \"Reconciling\"", "resource", r.NamespacedName)
 	case v1beta1.Paused:
 		logger.Info("Skipping actuation of resource as actuation mode is \"Paused\"", "resource", r.NamespacedName)
@@ -230,7 +273,9 @@ func (r *reconcileContext) doReconcile(ctx context.Context, u *unstructured.Unst
 		return false, fmt.Errorf("unknown actuation mode %v", am)
 	}
 
-	adapter, err := r.Reconciler.model.AdapterForObject(ctx, r.Reconciler.Client, u)
+	adapterCtx, span := tracing.Start(ctx, "AdapterForObject")
+	adapter, err := r.Reconciler.model.AdapterForObject(adapterCtx, r.Reconciler.Client, u)
+	tracing.End(span, err)
 	if err != nil {
 		if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
 			logger.Info(unwrappedErr.Error(), "resource", k8s.GetNamespacedName(u))
@@ -239,10 +284,22 @@ func (r *reconcileContext) doReconcile(ctx context.Context, u *unstructured.Unst
 		return false, r.handleUpdateFailed(ctx, u, err)
 	}
 
+	// Wait for a long-running operation started by a previous reconciliation before looking at the GCP object.
+	// A deletion waits too, e.g. for the creation to be done, unless it leaves the GCP object alone.
+	deleting := !u.GetDeletionTimestamp().IsZero()
+	if !k8s.HasPlanModeAnnotation(u) && (!deleting || (k8s.HasFinalizer(u, k8s.ControllerFinalizerName) && !k8s.HasAbandonAnnotation(u))) {
+		done, err := r.pollPendingOperation(ctx, u, adapter)
+		if !done {
+			return false, err
+		}
+	}
+
 	// To create, update or delete the GCP object, we need to get the GCP object first.
 	// Because the object contains the cloud service information like `selfLink` `ID` required to validate
 	// the resource uniqueness before updating/deleting.
-	existsAlready, err := adapter.Find(ctx)
+	findCtx, span := tracing.Start(ctx, "Find")
+	existsAlready, err := adapter.Find(findCtx)
+	tracing.End(span, err)
 	if err != nil {
 		if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
 			logger.Info(unwrappedErr.Error(), "resource", k8s.GetNamespacedName(u))
@@ -252,6 +309,10 @@ func (r *reconcileContext) doReconcile(ctx context.Context, u *unstructured.Unst
 	}
 
 	defer execution.RecoverWithInternalError(&err)
+	// In plan mode, a deletion which needs no GCP call still proceeds, so that the object is not stuck.
+	if k8s.HasPlanModeAnnotation(u) && (u.GetDeletionTimestamp().IsZero() || deletesGCPObject(u, existsAlready)) {
+		return false, r.handlePlan(ctx, u, adapter, existsAlready)
+	}
 	if !u.GetDeletionTimestamp().IsZero() {
 		if !k8s.HasFinalizer(u, k8s.ControllerFinalizerName) {
 			// Resource has no controller finalizer; no finalization necessary
@@ -262,18 +323,22 @@ func (r *reconcileContext) doReconcile(ctx context.Context, u *unstructured.Unst
 			logger.Info("deletion defender has not yet finalized; requeuing", "resource", k8s.GetNamespacedName(u))
 			return true, nil
 		}
-		if !k8s.HasAbandonAnnotation(u) {
+		if !k8s.HasAbandonAnnotation(u) && !k8s.HasPlanModeAnnotation(u) {
+			if existsAlready {
+				if err := r.obtainResourceLeaseIfNecessary(ctx, u, existsAlready); err != nil {
+					return false, err
+				}
+			}
 			deleteOp := NewDeleteOperation(r.Reconciler.Client, u)
-			if _, err := adapter.Delete(ctx, deleteOp); err != nil {
+			deleteCtx, span := tracing.Start(ctx, "Delete")
+			_, err := adapter.Delete(deleteCtx, deleteOp)
+			tracing.End(span, err)
+			if err != nil {
 				if !errors.Is(err, k8s.ErrIAMNotFound) && !k8s.IsReferenceNotFoundError(err) {
 					if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
 						logger.Info(unwrappedErr.Error(), "resource", k8s.GetNamespacedName(u))
-						resource, err := toK8sResource(u)
-						if err != nil {
-							return false, fmt.Errorf("error converting k8s resource while handling unresolvable dependencies event: %w", err)
-						}
 						// Requeue resource for reconciliation with exponential backoff applied
-						return true, r.Reconciler.HandleUnresolvableDeps(ctx, resource, unwrappedErr)
+						return true, r.handleUnresolvableDepsCondition(ctx, u, unwrappedErr)
 					}
 					return false, r.handleDeleteFailed(ctx, u, err)
 				}
@@ -286,34 +351,57 @@ func (r *reconcileContext) doReconcile(ctx context.Context, u *unstructured.Unst
 		return false, err
 	}
 
+	if err := r.obtainResourceLeaseIfNecessary(ctx, u, existsAlready); err != nil {
+		return false, err
+	}
+
 	// set the etag to an empty string, since IAMPolicy is the authoritative intent, KCC wants to overwrite the underlying policy regardless
 	//policy.Spec.Etag = ""
 
 	hasSetReadyCondition := false
 	requeueRequested := false
+	var pendingOperation *common.PendingOperation
 
 	if !existsAlready {
 		createOp := NewCreateOperation(r.Reconciler.Client, u)
-		if err := adapter.Create(ctx, createOp); err != nil {
+		createCtx, span := tracing.Start(ctx, "Create")
+		err := adapter.Create(createCtx, createOp)
+		tracing.End(span, err)
+		if err != nil {
 			if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
 				logger.Info(unwrappedErr.Error(), "resource", k8s.GetNamespacedName(u))
 				return r.handleUnresolvableDeps(ctx, u, unwrappedErr)
 			}
+			if IsFieldManagerConflict(err) {
+				return false, r.handleFieldManagerConflict(ctx, u, err)
+			}
 			return false, r.handleUpdateFailed(ctx, u, fmt.Errorf("error creating: %w", err))
 		}
 		hasSetReadyCondition = createOp.HasSetReadyCondition
 		requeueRequested = createOp.RequeueRequested
+		pendingOperation = createOp.pendingOperation
 	} else {
 		updateOp := NewUpdateOperation(r.Reconciler.LifecycleHandler, r.Reconciler.Client, u)
-		if err := adapter.Update(ctx, updateOp); err != nil {
+		updateCtx, span := tracing.Start(ctx, "Update")
+		err := adapter.Update(updateCtx, updateOp)
+		tracing.End(span, err)
+		if err != nil {
 			if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
 				logger.Info(unwrappedErr.Error(), "resource", k8s.GetNamespacedName(u))
 				return r.handleUnresolvableDeps(ctx, u, unwrappedErr)
 			}
+			if IsFieldManagerConflict(err) {
+				return false, r.handleFieldManagerConflict(ctx, u, err)
+			}
 			return false, r.handleUpdateFailed(ctx, u, fmt.Errorf("error updating: %w", err))
 		}
 		hasSetReadyCondition = updateOp.HasSetReadyCondition
 		requeueRequested = updateOp.RequeueRequested
+		pendingOperation = updateOp.pendingOperation
+	}
+
+	if pendingOperation != nil {
+		return false, r.handleOperationPending(ctx, u, pendingOperation)
 	}
 
 	if !hasSetReadyCondition && isAPIServerUpdateRequired(u) {
@@ -322,6 +410,148 @@ func (r *reconcileContext) doReconcile(ctx context.Context, u *unstructured.Unst
 	return requeueRequested, nil
 }
 
+// pollPendingOperation polls the long-running operation recorded in status.pendingOperation, if any.
+// It returns true if the reconciliation can continue, i.e. there is no pending operation or it is done.
+func (r *reconcileContext) pollPendingOperation(ctx context.Context, u *unstructured.Unstructured, adapter Adapter) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	pending, err := getPendingOperation(u)
+	if err != nil {
+		return false, err
+	}
+	if pending == nil {
+		return true, nil
+	}
+	poller, ok := adapter.(OperationPoller)
+	if !ok {
+		// The adapter waits for its operations in Create and Update, so we can only find out the outcome with Find.
+		logger.Info("adapter does not support pending operations; ignoring", "resource", k8s.GetNamespacedName(u), "operation", pending.Name)
+		return true, clearPendingOperation(ctx, r.Reconciler.Client, u)
+	}
+
+	pollCtx, span := tracing.Start(ctx, "PollOperation")
+	done, pollErr := poller.PollOperation(pollCtx, pending)
+	tracing.End(span, pollErr)
+	if !done {
+		if pollErr != nil {
+			return false, r.handleUpdateFailed(ctx, u, fmt.Errorf("error polling %s operation %s: %w", pending.Type, pending.Name, pollErr))
+		}
+		logger.Info("operation is still in progress", "resource", k8s.GetNamespacedName(u), "operation", pending.Name)
+		return false, r.handleOperationPending(ctx, u, pending)
+	}
+
+	if err := clearPendingOperation(ctx, r.Reconciler.Client, u); err != nil {
+		return false, err
+	}
+	if pollErr != nil {
+		if !u.GetDeletionTimestamp().IsZero() {
+			// Whether there is a GCP object left to delete is up to Find.
+			logger.Info("operation failed; continuing with the deletion", "resource", k8s.GetNamespacedName(u), "operation", pending.Name, "error", pollErr.Error())
+			return true, nil
+		}
+		return false, r.handleUpdateFailed(ctx, u, fmt.Errorf("%s operation %s failed: %w", pending.Type, pending.Name, pollErr))
+	}
+	logger.Info("operation is done", "resource", k8s.GetNamespacedName(u), "operation", pending.Name)
+	return true, nil
+}
+
+// handlePlan computes the GCP calls that reconciliation would make and records them as an event,
+// without calling Create, Update or Delete and without writing to the object.
+func (r *reconcileContext) handlePlan(ctx context.Context, u *unstructured.Unstructured, adapter Adapter, existsAlready bool) error {
+	logger := log.FromContext(ctx)
+
+	var plan *Plan
+	planner, supportsPlan := adapter.(Planner)
+	switch {
+	case !u.GetDeletionTimestamp().IsZero():
+		r.Reconciler.planNotSupportedWarnings.Delete(u.GetUID())
+		plan = &Plan{Action: PlanActionDelete}
+	case supportsPlan:
+		p, err := planner.Plan(ctx, NewPlanOperation(u))
+		if err != nil {
+			r.Reconciler.Recorder.Event(u, corev1.EventTypeWarning, k8s.PlanFailed, err.Error())
+			return fmt.Errorf("error computing plan: %w", err)
+		}
+		plan = p
+	case !existsAlready:
+		plan = &Plan{Action: PlanActionCreate}
+	default:
+		// The warning only changes with the spec, so it is recorded once per generation
+		// rather than on every reconciliation.
+		if warned, ok := r.Reconciler.planNotSupportedWarnings.Load(u.GetUID()); ok && warned.(int64) == u.GetGeneration() {
+			return nil
+		}
+		r.Reconciler.planNotSupportedWarnings.Store(u.GetUID(), u.GetGeneration())
+		msg := fmt.Sprintf("field-level plan is not supported for %v; reconciliation would call Update", r.gvk.Kind)
+		r.Reconciler.Recorder.Event(u, corev1.EventTypeWarning, k8s.PlanFailed, msg)
+		return nil
+	}
+
+	logger.Info("computed plan for resource", "resource", r.NamespacedName, "action", plan.Action, "changes", plan.Changes)
+	r.Reconciler.Recorder.Event(u, corev1.EventTypeNormal, k8s.Planned, plan.String())
+	if plan.Action == PlanActionDelete {
+		// The object is kept until the plan-mode annotation is removed, so we tell the user why.
+		return r.handleDeletionBlockedByPlanMode(ctx, u)
+	}
+	return nil
+}
+
+// deletesGCPObject returns whether the deletion of u would delete the GCP object.
+func deletesGCPObject(u *unstructured.Unstructured, existsAlready bool) bool {
+	return existsAlready && k8s.HasFinalizer(u, k8s.ControllerFinalizerName) && !k8s.HasAbandonAnnotation(u)
+}
+
+// obtainResourceLeaseIfNecessary obtains the lease of the resource if its management conflict
+// prevention policy stores the leases in an external backend. Direct resources do not support
+// the label-based "resource" policy.
+func (r *reconcileContext) obtainResourceLeaseIfNecessary(ctx context.Context, u *unstructured.Unstructured, existsAlready bool) error {
+	policy, err := k8s.GetManagementConflictPreventionAnnotationValue(u)
+	if err != nil {
+		return err
+	}
+	if !k8s.IsExternalLeasePolicy(policy) {
+		return nil
+	}
+	resource, err := toK8sResource(u)
+	if err != nil {
+		return fmt.Errorf("error converting to k8s resource while obtaining lease: %w", err)
+	}
+	resourceID, err := leaseResourceID(u, existsAlready)
+	if err != nil {
+		return err
+	}
+	if err := r.Reconciler.resourceLeaser.ObtainExternal(ctx, policy, resource, resourceID); err != nil {
+		return r.handleObtainLeaseFailed(ctx, u, fmt.Errorf("error obtaining lease on '%v': %w",
+			k8s.GetNamespacedName(u), err))
+	}
+	return nil
+}
+
+// leaseResourceID returns the ID of the GCP object which identifies its external lease: the last
+// segment of status.externalRef once the GCP object is known, otherwise spec.resourceID or
+// metadata.name. It returns "" if the GCP object does not exist yet and spec.resourceID is
+// unset, since the ID of the GCP object may then be generated by the server on creation.
+func leaseResourceID(u *unstructured.Unstructured, existsAlready bool) (string, error) {
+	externalRef, _, err := unstructured.NestedString(u.Object, "status", "externalRef")
+	if err != nil {
+		return "", fmt.Errorf("reading status.externalRef: %w", err)
+	}
+	if externalRef != "" {
+		return externalRef[strings.LastIndex(externalRef, "/")+1:], nil
+	}
+	resourceID, _, err := unstructured.NestedString(u.Object, "spec", "resourceID")
+	if err != nil {
+		return "", fmt.Errorf("reading spec.resourceID: %w", err)
+	}
+	if resourceID != "" {
+		return resourceID, nil
+	}
+	if !existsAlready {
+		return "", nil
+	}
+	return u.GetName(), nil
+}
+
 // ensureFinalizers will apply our finalizers to the object if they are not present.
 // We update the kube-apiserver immediately if any changes are needed.
 func (r *reconcileContext) ensureFinalizers(ctx context.Context, u *unstructured.Unstructured) error {
@@ -337,44 +567,106 @@ func (r *reconcileContext) ensureFinalizers(ctx context.Context, u *unstructured
 	return nil
 }
 
+// handleUpToDate writes the ready condition with applyReadyCondition rather than with
+// the lifecycle handler, whose HandleUpToDate updates the whole object.
 func (r *reconcileContext) handleUpToDate(ctx context.Context, u *unstructured.Unstructured) error {
+	ready := k8s.NewCustomReadyCondition(corev1.ConditionTrue, k8s.UpToDate, k8s.UpToDateMessage)
+	if err := applyReadyCondition(ctx, r.Reconciler.Client, u, ready); err != nil {
+		return fmt.Errorf("error writing %v condition: %w", k8s.UpToDate, err)
+	}
+	r.Reconciler.Recorder.Event(u, corev1.EventTypeNormal, k8s.UpToDate, k8s.UpToDateMessage)
+	return nil
+}
+
+// handleWithLifecycleHandler calls handle with u converted to a k8s resource, and syncs u with
+// the resource written by the lifecycle handler. The lifecycle handler writes the status with
+// applyStatus, so that the status fields of other field managers are kept.
+func handleWithLifecycleHandler(u *unstructured.Unstructured, event string, handle func(resource *k8s.Resource) error) error {
 	resource, err := toK8sResource(u)
 	if err != nil {
-		return fmt.Errorf("error converting to k8s resource while handling %v event: %w", k8s.UpToDate, err)
+		return fmt.Errorf("error converting to k8s resource while handling %v event: %w", event, err)
+	}
+	handleErr := handle(resource)
+	synced, err := resource.MarshalAsUnstructured()
+	if err != nil {
+		return fmt.Errorf("error converting k8s resource while handling %v event: %w", event, err)
 	}
-	return r.Reconciler.HandleUpToDate(ctx, resource)
+	u.Object = synced.Object
+	return handleErr
 }
 
-func (r *reconcileContext) handleUpdateFailed(ctx context.Context, policy *unstructured.Unstructured, origErr error) error {
-	logger := log.FromContext(ctx)
+func (r *reconcileContext) handlePaused(ctx context.Context, u *unstructured.Unstructured) error {
+	return handleWithLifecycleHandler(u, k8s.Paused, func(resource *k8s.Resource) error {
+		return r.Reconciler.HandlePaused(ctx, resource)
+	})
+}
 
-	resource, err := toK8sResource(policy)
+// handleOperationPending records the pending operation in the status, and schedules the reconciliation which polls it.
+func (r *reconcileContext) handleOperationPending(ctx context.Context, u *unstructured.Unstructured, op *common.PendingOperation) error {
+	r.requeueAfter = PendingOperationPollInterval
+	recorded, err := getPendingOperation(u)
 	if err != nil {
-		logger.Error(err, "error converting to k8s resource while handling event",
-			"resource", k8s.GetNamespacedName(policy), "event", k8s.UpdateFailed)
-		return fmt.Errorf("Update call failed: %w", origErr)
+		return err
+	}
+	if recorded == nil || *recorded != *op {
+		if err := writePendingOperation(ctx, r.Reconciler.Client, u, op); err != nil {
+			return err
+		}
 	}
-	return r.Reconciler.HandleUpdateFailed(ctx, resource, origErr)
+	return handleWithLifecycleHandler(u, k8s.Updating, func(resource *k8s.Resource) error {
+		return r.Reconciler.HandleOperationPending(ctx, resource, op.Type, op.Name)
+	})
 }
 
-func (r *reconcileContext) handleDeleted(ctx context.Context, policy *unstructured.Unstructured) error {
-	resource, err := toK8sResource(policy)
-	if err != nil {
-		return fmt.Errorf("error converting to k8s resource while handling %v event: %w", k8s.Deleted, err)
-	}
-	return r.Reconciler.HandleDeleted(ctx, resource)
+func (r *reconcileContext) handleUpdateFailed(ctx context.Context, u *unstructured.Unstructured, origErr error) error {
+	return handleWithLifecycleHandler(u, k8s.UpdateFailed, func(resource *k8s.Resource) error {
+		return r.Reconciler.HandleUpdateFailed(ctx, resource, origErr)
+	})
 }
 
-func (r *reconcileContext) handleDeleteFailed(ctx context.Context, policy *unstructured.Unstructured, origErr error) error {
-	logger := log.FromContext(ctx)
+func (r *reconcileContext) handleFieldManagerConflict(ctx context.Context, u *unstructured.Unstructured, origErr error) error {
+	return handleWithLifecycleHandler(u, k8s.ManagementConflict, func(resource *k8s.Resource) error {
+		return r.Reconciler.HandleFieldManagerConflict(ctx, resource, origErr)
+	})
+}
 
-	resource, err := toK8sResource(policy)
-	if err != nil {
-		logger.Error(err, "error converting to k8s resource while handling event",
-			"resource", k8s.GetNamespacedName(policy), "event", k8s.DeleteFailed)
-		return fmt.Errorf(k8s.DeleteFailedMessageTmpl, origErr)
-	}
-	return r.Reconciler.HandleDeleteFailed(ctx, resource, origErr)
+func (r *reconcileContext) handleObtainLeaseFailed(ctx context.Context, u *unstructured.Unstructured, origErr error) error {
+	return handleWithLifecycleHandler(u, k8s.ManagementConflict, func(resource *k8s.Resource) error {
+		return r.Reconciler.HandleObtainLeaseFailed(ctx, resource, origErr)
+	})
+}
+
+func (r *reconcileContext) handleDeleted(ctx context.Context, u *unstructured.Unstructured) error {
+	return handleWithLifecycleHandler(u, k8s.Deleted, func(resource *k8s.Resource) error {
+		// A failure to release the lease is not fatal: the lease expires on its own.
+		resourceID, err := leaseResourceID(u, true)
+		if err == nil {
+			err = r.Reconciler.resourceLeaser.ReleaseExternalIfNecessary(ctx, resource, resourceID)
+		}
+		if err != nil {
+			log.FromContext(ctx).Error(err, "error releasing lease", "resource", k8s.GetNamespacedName(u))
+		}
+		return r.Reconciler.HandleDeleted(ctx, resource)
+	})
+}
+
+func (r *reconcileContext) handleDeletionBlockedByPlanMode(ctx context.Context, u *unstructured.Unstructured) error {
+	return handleWithLifecycleHandler(u, k8s.DeletionBlockedByPlanMode, func(resource *k8s.Resource) error {
+		return r.Reconciler.HandleDeletionBlockedByPlanMode(ctx, resource)
+	})
+}
+
+func (r *reconcileContext) handleDeleteFailed(ctx context.Context, u *unstructured.Unstructured, origErr error) error {
+	return handleWithLifecycleHandler(u, k8s.DeleteFailed, func(resource *k8s.Resource) error {
+		return r.Reconciler.HandleDeleteFailed(ctx, resource, origErr)
+	})
+}
+
+// handleUnresolvableDepsCondition surfaces the unresolvable dependencies of the resource in the ready condition.
+func (r *reconcileContext) handleUnresolvableDepsCondition(ctx context.Context, u *unstructured.Unstructured, origErr error) error {
+	return handleWithLifecycleHandler(u, "unresolvable dependencies", func(resource *k8s.Resource) error {
+		return r.Reconciler.HandleUnresolvableDeps(ctx, resource, origErr)
+	})
 }
 
 func (r *DirectReconciler) supportsImmediateReconciliations() bool {
@@ -391,7 +683,7 @@ func (r *reconcileContext) handleUnresolvableDeps(ctx context.Context, policy *u
 	refGVK, refNN, ok := lifecyclehandler.CausedByUnreadyOrNonexistentResourceRefs(origErr)
 	if !ok || !r.Reconciler.supportsImmediateReconciliations() {
 		// Requeue resource for reconciliation with exponential backoff applied
-		return true, r.Reconciler.HandleUnresolvableDeps(ctx, resource, origErr)
+		return true, r.handleUnresolvableDepsCondition(ctx, policy, origErr)
 	}
 	// Check that the number of active resource watches
 	// does not exceed the controller's cap. If the
@@ -400,7 +692,7 @@ func (r *reconcileContext) handleUnresolvableDeps(ctx context.Context, policy *u
 	// is started
 	if !r.Reconciler.resourceWatcherRoutines.TryAcquire(1) {
 		// Requeue resource for reconciliation with exponential backoff applied
-		return true, r.Reconciler.HandleUnresolvableDeps(ctx, resource, origErr)
+		return true, r.handleUnresolvableDepsCondition(ctx, policy, origErr)
 	}
 	// Create a logger for ResourceWatcher that contains info
 	// about the referencing resource. This is done since the
@@ -411,7 +703,7 @@ func (r *reconcileContext) handleUnresolvableDeps(ctx context.Context, policy *u
 		"referencingResourceGVK", resource.GroupVersionKind())
 	watcher, err := resourcewatcher.New(r.Reconciler.config, watcherLogger)
 	if err != nil {
-		return false, r.Reconciler.HandleUpdateFailed(ctx, resource, fmt.Errorf("error initializing new resourcewatcher: %w", err))
+		return false, r.handleUpdateFailed(ctx, policy, fmt.Errorf("error initializing new resourcewatcher: %w", err))
 	}
 
 	logger = logger.WithValues(
@@ -438,7 +730,7 @@ func (r *reconcileContext) handleUnresolvableDeps(ctx context.Context, policy *u
 	// Do not requeue resource for immediate reconciliation. Wait for either
 	// the next periodic reconciliation or for the referenced resource to be ready (which
 	// triggers a reconciliation), whichever comes first.
-	return false, r.Reconciler.HandleUnresolvableDeps(ctx, resource, origErr)
+	return false, r.handleUnresolvableDepsCondition(ctx, policy, origErr)
 }
 
 // enqueueForImmediateReconciliation enqueues the given resource for immediate
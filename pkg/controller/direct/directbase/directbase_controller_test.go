@@ -0,0 +1,166 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directbase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// unexpectedModel fails the test if the reconciler builds an adapter, i.e. if it would call GCP.
+type unexpectedModel struct {
+	t *testing.T
+}
+
+func (m *unexpectedModel) AdapterForObject(_ context.Context, _ client.Reader, u *unstructured.Unstructured) (Adapter, error) {
+	m.t.Fatalf("unexpected adapter for %v", u.GetName())
+	return nil, nil
+}
+
+func (m *unexpectedModel) AdapterForURL(_ context.Context, url string) (Adapter, error) {
+	m.t.Fatalf("unexpected adapter for %v", url)
+	return nil, nil
+}
+
+func TestReconcilePaused(t *testing.T) {
+	ctx := context.TODO()
+
+	gvk := schema.GroupVersionKind{Group: "test.cnrm.cloud.google.com", Version: "v1beta1", Kind: "TestKind"}
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetNamespace("ns")
+	u.SetName("test")
+	u.SetGeneration(2)
+	u.SetAnnotations(map[string]string{k8s.ReconcilePausedAnnotation: "true"})
+	u.Object["spec"] = map[string]interface{}{"field": "changed"}
+	u.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{
+				"type":               "Ready",
+				"status":             "True",
+				"reason":             k8s.UpToDate,
+				"message":            k8s.UpToDateMessage,
+				"lastTransitionTime": "2024-01-01T00:00:00Z",
+			},
+		},
+		"observedGeneration": int64(1),
+	}
+	c := fake.NewClientBuilder().WithObjects(u).WithStatusSubresource(u).
+		WithInterceptorFuncs(interceptor.Funcs{SubResourcePatch: applyAsMergePatch}).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &DirectReconciler{
+		LifecycleHandler: newLifecycleHandler(c, recorder),
+		Client:           c,
+		gvk:              gvk,
+		model:            &unexpectedModel{t: t},
+	}
+
+	result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "test"}})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.Requeue || result.RequeueAfter != 0 {
+		t.Errorf("got result %+v, want no requeue until the annotation is removed", result)
+	}
+
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(gvk)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(u), got); err != nil {
+		t.Fatalf("getting object: %v", err)
+	}
+	resource, err := toK8sResource(got)
+	if err != nil {
+		t.Fatalf("converting object: %v", err)
+	}
+	// The Ready status is preserved, so that the dependents of the resource are not blocked.
+	if !k8s.ReadyConditionMatches(resource, corev1.ConditionTrue, k8s.Paused, k8s.PausedMessage) {
+		t.Errorf("got conditions %+v, want a paused Ready condition", resource.Status["conditions"])
+	}
+	if got, _, _ := unstructured.NestedInt64(got.Object, "status", "observedGeneration"); got != 1 {
+		t.Errorf("got observed generation %v, want 1 as the changed spec is not actuated", got)
+	}
+	if len(got.GetFinalizers()) != 0 {
+		t.Errorf("got finalizers %v, want none", got.GetFinalizers())
+	}
+	select {
+	case event := <-recorder.Events:
+		if want := corev1.EventTypeNormal + " " + k8s.Paused + " " + k8s.PausedMessage; event != want {
+			t.Errorf("got event %q, want %q", event, want)
+		}
+	default:
+		t.Errorf("got no event, want a %v event", k8s.Paused)
+	}
+}
+
+func TestLeaseResourceID(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          map[string]interface{}
+		status        map[string]interface{}
+		existsAlready bool
+		want          string
+	}{
+		{
+			name:          "external reference",
+			spec:          map[string]interface{}{"resourceID": "my-resource"},
+			status:        map[string]interface{}{"externalRef": "projects/p/locations/l/things/generated-id"},
+			existsAlready: true,
+			want:          "generated-id",
+		},
+		{
+			name: "resource ID before creation",
+			spec: map[string]interface{}{"resourceID": "my-resource"},
+			want: "my-resource",
+		},
+		{
+			name: "no resource ID before creation",
+		},
+		{
+			name:          "name of an existing GCP object",
+			existsAlready: true,
+			want:          "test",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			u.SetName("test")
+			if tc.spec != nil {
+				u.Object["spec"] = tc.spec
+			}
+			if tc.status != nil {
+				u.Object["status"] = tc.status
+			}
+			got, err := leaseResourceID(u, tc.existsAlready)
+			if err != nil {
+				t.Fatalf("leaseResourceID() error = %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("leaseResourceID() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
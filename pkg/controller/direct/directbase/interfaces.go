@@ -60,3 +60,21 @@ type Adapter interface {
 	// Assumes Find has previously returned true.
 	Export(ctx context.Context) (*unstructured.Unstructured, error)
 }
+
+// Planner is an optional interface that an Adapter can implement to support plan mode.
+// In plan mode, the reconciler calls Find followed by Plan, and never calls Create, Update or Delete.
+type Planner interface {
+	// Plan computes the changes that Create or Update would apply to the GCP object,
+	// without making any mutating calls.
+	// Assumes Find has previously been called.
+	Plan(ctx context.Context, op *PlanOperation) (*Plan, error)
+}
+
+// Lister is an optional interface that a Model can implement to support discovery of existing GCP objects,
+// for example by bulk-export when Cloud Asset Inventory is not available.
+type Lister interface {
+	// ListURLs returns the URLs of the GCP objects of this kind under parent (for example "projects/my-project").
+	// The URLs use the Cloud Asset Inventory format, and can be passed to AdapterForURL.
+	// If the model does not support listing under this type of parent, it returns (nil, nil).
+	ListURLs(ctx context.Context, parent string) ([]string, error)
+}
@@ -0,0 +1,143 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directbase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
+	"github.com/googleapis/gax-go/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PendingOperationPollInterval is how often the reconciler polls a pending long-running operation.
+const PendingOperationPollInterval = 30 * time.Second
+
+// OperationPoller is an optional interface that an Adapter can implement to wait for long-running operations
+// across reconciliations, rather than blocking in Create or Update until they are done.
+//
+// Create or Update start the operation and call TrackOperation. The reconciler then records the operation
+// in status.pendingOperation and returns. Later reconciliations call PollOperation before Find, and only
+// continue with Find, Create and Update once the operation is done, so the call which started it is not
+// issued again, even after a restart of the controller.
+type OperationPoller interface {
+	// PollOperation checks once whether the operation has completed.
+	// It returns (true, nil) if the operation succeeded, and (true, err) if the operation failed.
+	// It returns (false, err) if the operation could not be polled; the operation is polled again later.
+	PollOperation(ctx context.Context, op *common.PendingOperation) (done bool, err error)
+}
+
+// GAPICOperation is a long-running operation of a GAPIC client, e.g. *CreateClusterOperation.
+type GAPICOperation[T any] interface {
+	Poll(ctx context.Context, opts ...gax.CallOption) (T, error)
+	Done() bool
+}
+
+// PollGAPICOperation implements OperationPoller.PollOperation for a GAPIC operation,
+// typically one resumed by name, e.g. with client.CreateClusterOperation(op.Name).
+func PollGAPICOperation[T any](ctx context.Context, op GAPICOperation[T]) (done bool, err error) {
+	if _, err := op.Poll(ctx); err != nil {
+		return op.Done(), err
+	}
+	return op.Done(), nil
+}
+
+// TrackOperation records a long-running operation started by Create or Update, instead of waiting for it.
+// The adapter must implement OperationPoller.
+func (o *operationBase) TrackOperation(operationType, operationName string) {
+	o.pendingOperation = &common.PendingOperation{
+		Name:      operationName,
+		Type:      operationType,
+		StartTime: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// getPendingOperation returns the operation recorded in status.pendingOperation, or nil if there is none.
+func getPendingOperation(u *unstructured.Unstructured) (*common.PendingOperation, error) {
+	m, found, err := unstructured.NestedMap(u.Object, "status", "pendingOperation")
+	if err != nil {
+		return nil, fmt.Errorf("reading status.pendingOperation: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	op := &common.PendingOperation{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, op); err != nil {
+		return nil, fmt.Errorf("converting status.pendingOperation: %w", err)
+	}
+	if op.Name == "" {
+		return nil, nil
+	}
+	return op, nil
+}
+
+// setPendingOperation records op in status.pendingOperation of u.
+func setPendingOperation(u *unstructured.Unstructured, op *common.PendingOperation) error {
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(op)
+	if err != nil {
+		return fmt.Errorf("converting pending operation to unstructured: %w", err)
+	}
+	if err := unstructured.SetNestedMap(u.Object, m, "status", "pendingOperation"); err != nil {
+		return fmt.Errorf("setting status.pendingOperation: %w", err)
+	}
+	return nil
+}
+
+// writePendingOperation records op in status.pendingOperation, both on the API server and in u.
+// It is written immediately with a targeted patch rather than with the ready condition, as the operation
+// would be started again by the next reconciliation if the record was lost.
+func writePendingOperation(ctx context.Context, c client.Client, u *unstructured.Unstructured, op *common.PendingOperation) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{"pendingOperation": op},
+	})
+	if err != nil {
+		return fmt.Errorf("converting pending operation to json: %w", err)
+	}
+	if err := patchStatusWithRetry(ctx, c, u, client.RawPatch(types.MergePatchType, data)); err != nil {
+		return fmt.Errorf("writing status.pendingOperation: %w", err)
+	}
+	return setPendingOperation(u, op)
+}
+
+// clearPendingOperation removes status.pendingOperation from the object, both on the API server and in u.
+// It is written immediately, so that a failure later in the reconciliation does not poll the operation again.
+func clearPendingOperation(ctx context.Context, c client.Client, u *unstructured.Unstructured) error {
+	patch := client.RawPatch(types.MergePatchType, []byte(`{"status":{"pendingOperation":null}}`))
+	if err := patchStatusWithRetry(ctx, c, u, patch); err != nil {
+		return fmt.Errorf("clearing status.pendingOperation: %w", err)
+	}
+	unstructured.RemoveNestedField(u.Object, "status", "pendingOperation")
+	return nil
+}
+
+// patchStatusWithRetry patches the status subresource of u, retrying the errors which are expected
+// to be transient, e.g. the conflicts with concurrent writes.
+func patchStatusWithRetry(ctx context.Context, c client.Client, u *unstructured.Unstructured, patch client.Patch) error {
+	return retry.OnError(retry.DefaultBackoff, isTransientWriteError, func() error {
+		return c.Status().Patch(ctx, u, patch, client.FieldOwner(FieldManager))
+	})
+}
+
+func isTransientWriteError(err error) bool {
+	return apierrors.IsConflict(err) || apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) || apierrors.IsTooManyRequests(err)
+}
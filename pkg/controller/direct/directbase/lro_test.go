@@ -0,0 +1,225 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directbase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// fakeAdapter is an Adapter which does not implement OperationPoller.
+type fakeAdapter struct {
+	Adapter
+}
+
+type fakePollerAdapter struct {
+	Adapter
+
+	done bool
+	err  error
+}
+
+func (a *fakePollerAdapter) PollOperation(ctx context.Context, op *common.PendingOperation) (bool, error) {
+	return a.done, a.err
+}
+
+func TestPollPendingOperation(t *testing.T) {
+	pending := &common.PendingOperation{
+		Name:      "projects/p/locations/l/operations/o",
+		Type:      "create",
+		StartTime: "2024-01-01T00:00:00Z",
+	}
+	tests := []struct {
+		name             string
+		adapter          Adapter
+		wantDone         bool
+		wantErr          bool
+		wantRequeueAfter bool
+		wantPending      bool
+		wantReason       string
+		deleting         bool
+	}{
+		{
+			name:             "operation in progress",
+			adapter:          &fakePollerAdapter{done: false},
+			wantRequeueAfter: true,
+			wantPending:      true,
+			wantReason:       k8s.Updating,
+		},
+		{
+			name:     "operation succeeded",
+			adapter:  &fakePollerAdapter{done: true},
+			wantDone: true,
+		},
+		{
+			name:       "operation failed",
+			adapter:    &fakePollerAdapter{done: true, err: errors.New("quota exceeded")},
+			wantErr:    true,
+			wantReason: k8s.UpdateFailed,
+		},
+		{
+			name:        "operation cannot be polled",
+			adapter:     &fakePollerAdapter{done: false, err: errors.New("connection reset")},
+			wantErr:     true,
+			wantPending: true,
+			wantReason:  k8s.UpdateFailed,
+		},
+		{
+			name:     "operation failed while deleting",
+			adapter:  &fakePollerAdapter{done: true, err: errors.New("quota exceeded")},
+			deleting: true,
+			wantDone: true,
+		},
+		{
+			name:     "adapter does not poll operations",
+			adapter:  &fakeAdapter{},
+			wantDone: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.TODO()
+
+			u := &unstructured.Unstructured{}
+			u.SetGroupVersionKind(schema.GroupVersionKind{Group: "test.cnrm.cloud.google.com", Version: "v1beta1", Kind: "TestKind"})
+			u.SetNamespace("ns")
+			u.SetName("test")
+			if err := setPendingOperation(u, pending); err != nil {
+				t.Fatalf("setting pending operation: %v", err)
+			}
+			if tc.deleting {
+				now := metav1.Now()
+				u.SetDeletionTimestamp(&now)
+				u.SetFinalizers([]string{k8s.ControllerFinalizerName})
+			}
+			c := fake.NewClientBuilder().WithObjects(u).WithStatusSubresource(u).
+				WithInterceptorFuncs(interceptor.Funcs{SubResourcePatch: applyAsMergePatch}).Build()
+
+			r := &reconcileContext{
+				Reconciler: &DirectReconciler{
+					LifecycleHandler: newLifecycleHandler(c, record.NewFakeRecorder(10)),
+					Client:           c,
+				},
+				NamespacedName: types.NamespacedName{Namespace: "ns", Name: "test"},
+			}
+			done, err := r.pollPendingOperation(ctx, u, tc.adapter)
+			if done != tc.wantDone {
+				t.Errorf("got done %v, want %v", done, tc.wantDone)
+			}
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("got error %v, want error %v", err, tc.wantErr)
+			}
+			if gotRequeueAfter := r.requeueAfter != 0; gotRequeueAfter != tc.wantRequeueAfter {
+				t.Errorf("got requeueAfter %v, want requeueAfter %v", r.requeueAfter, tc.wantRequeueAfter)
+			}
+
+			got := &unstructured.Unstructured{}
+			got.SetGroupVersionKind(u.GroupVersionKind())
+			if err := c.Get(ctx, client.ObjectKeyFromObject(u), got); err != nil {
+				t.Fatalf("getting object: %v", err)
+			}
+			gotPending, err := getPendingOperation(got)
+			if err != nil {
+				t.Fatalf("getting pending operation: %v", err)
+			}
+			var wantPending *common.PendingOperation
+			if tc.wantPending {
+				wantPending = pending
+			}
+			if diff := cmp.Diff(wantPending, gotPending); diff != "" {
+				t.Errorf("unexpected pending operation (-want +got):\n%s", diff)
+			}
+
+			conditions, _, _ := unstructured.NestedSlice(got.Object, "status", "conditions")
+			gotReason := ""
+			if len(conditions) != 0 {
+				gotReason, _, _ = unstructured.NestedString(conditions[0].(map[string]interface{}), "reason")
+			}
+			if gotReason != tc.wantReason {
+				t.Errorf("got ready condition reason %q, want %q", gotReason, tc.wantReason)
+			}
+		})
+	}
+}
+
+func TestHandleOperationPending(t *testing.T) {
+	ctx := context.TODO()
+	op := &common.PendingOperation{
+		Name:      "projects/p/locations/l/operations/o",
+		Type:      "create",
+		StartTime: "2024-01-01T00:00:00Z",
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(schema.GroupVersionKind{Group: "test.cnrm.cloud.google.com", Version: "v1beta1", Kind: "TestKind"})
+	u.SetNamespace("ns")
+	u.SetName("test")
+
+	// The first write of the operation conflicts with a concurrent write, and the ready condition can't be written.
+	var patches int
+	c := fake.NewClientBuilder().WithObjects(u).WithStatusSubresource(u).WithInterceptorFuncs(interceptor.Funcs{
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			if patch.Type() == types.ApplyPatchType {
+				return errors.New("apply failed")
+			}
+			patches++
+			if patches == 1 {
+				return apierrors.NewConflict(schema.GroupResource{}, "test", errors.New("object was modified"))
+			}
+			return c.SubResource(subResourceName).Patch(ctx, obj, patch)
+		},
+	}).Build()
+
+	r := &reconcileContext{
+		Reconciler: &DirectReconciler{
+			LifecycleHandler: newLifecycleHandler(c, record.NewFakeRecorder(10)),
+			Client:           c,
+		},
+		NamespacedName: types.NamespacedName{Namespace: "ns", Name: "test"},
+	}
+	if err := r.handleOperationPending(ctx, u, op); err == nil {
+		t.Fatalf("got no error, want the error writing the ready condition")
+	}
+	if patches != 2 {
+		t.Errorf("got %d patches of the pending operation, want 2", patches)
+	}
+
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(u.GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(u), got); err != nil {
+		t.Fatalf("getting object: %v", err)
+	}
+	gotPending, err := getPendingOperation(got)
+	if err != nil {
+		t.Fatalf("getting pending operation: %v", err)
+	}
+	if diff := cmp.Diff(op, gotPending); diff != "" {
+		t.Errorf("unexpected pending operation (-want +got):\n%s", diff)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/k8s/v1alpha1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/lifecyclehandler"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
@@ -40,6 +41,9 @@ type operationBase struct {
 
 	// RequeueRequested tracks whether we need a re-reconciliation
 	RequeueRequested bool
+
+	// pendingOperation is the long-running operation recorded by TrackOperation, if any
+	pendingOperation *common.PendingOperation
 }
 
 // Operation defines some functionality supported by all operation types.
@@ -48,8 +52,14 @@ type Operation interface {
 	// We split out the readyCondition so that we will not write it from the reconcile loop if we wrote it here.
 	UpdateStatus(ctx context.Context, typedStatus any, readyCondition *v1alpha1.Condition) error
 
+	// ApplySpec merges the given spec fields, e.g. the values defaulted by GCP, into the object's spec.
+	ApplySpec(ctx context.Context, spec map[string]interface{}) error
+
 	// RequestRequeue requests a requeue of the operation, by returning Requeue = true from the reconcile loop.
 	RequestRequeue()
+
+	// TrackOperation records a long-running operation, which later reconciliations wait for, rather than waiting for it now.
+	TrackOperation(operationType, operationName string)
 }
 
 // GetUnstructured returns the object being reconciled, in unstructured format.
@@ -107,6 +117,9 @@ func (o *DeleteOperation) GetUnstructured() *unstructured.Unstructured {
 
 // UpdateStatus writes the status and ready condition to the object's status subresource.
 // We split out the readyCondition so that we will not write it from the reconcile loop if we wrote it here.
+//
+// The status is written with a server-side apply, so that the status fields of other field managers are kept.
+// If they conflict with the status, a FieldManagerConflictError is returned.
 func (o *operationBase) UpdateStatus(ctx context.Context, typedStatus any, readyCondition *v1alpha1.Condition) error {
 	status, err := runtime.DefaultUnstructuredConverter.ToUnstructured(typedStatus)
 	if err != nil {
@@ -147,12 +160,36 @@ func (o *operationBase) UpdateStatus(ctx context.Context, typedStatus any, ready
 		status["conditions"] = unstructuredStatusWithConditions["conditions"]
 	}
 
-	u := o.object
-	u.Object["status"] = status
+	// An unset field is left out of the apply, rather than applied as null.
+	for k, v := range status {
+		if v == nil {
+			delete(status, k)
+		}
+	}
 
-	if err := o.client.Status().Update(ctx, u); err != nil {
+	patch := newApplyPatch(o.object)
+	patch.Object["status"] = status
+	if err := serverSideApply(ctx, o.client, patch, true); err != nil {
 		return fmt.Errorf("updating object status: %w", err)
 	}
+	syncFromApplied(o.object, patch, "status")
+
+	return nil
+}
+
+// ApplySpec merges the given spec fields, e.g. the values defaulted by GCP, into the object's spec.
+//
+// The fields are written with a server-side apply, so they are owned by FieldManager rather than by the user.
+// A spec field which was applied by a previous call but is not in spec is removed, unless another field manager
+// also manages it. If the fields conflict with the ones of another field manager, e.g. a user changed the value,
+// a FieldManagerConflictError is returned and the spec is left unchanged.
+func (o *operationBase) ApplySpec(ctx context.Context, spec map[string]interface{}) error {
+	patch := newApplyPatch(o.object)
+	patch.Object["spec"] = spec
+	if err := serverSideApply(ctx, o.client, patch, false); err != nil {
+		return fmt.Errorf("applying object spec: %w", err)
+	}
+	syncFromApplied(o.object, patch, "spec")
 
 	return nil
 }
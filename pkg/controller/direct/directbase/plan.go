@@ -0,0 +1,181 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directbase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// PlanAction is the kind of GCP call that a reconciliation would make.
+type PlanAction string
+
+const (
+	PlanActionNone   PlanAction = "None"
+	PlanActionCreate PlanAction = "Create"
+	PlanActionUpdate PlanAction = "Update"
+	PlanActionDelete PlanAction = "Delete"
+)
+
+// Plan describes the changes that a reconciliation would apply to the GCP object.
+type Plan struct {
+	// Action is the GCP call that would be made.
+	Action PlanAction
+
+	// Changes holds the field-level differences that would be sent to GCP.
+	// It is only populated for updates.
+	Changes []FieldChange
+}
+
+// FieldChange is a single field that differs between the desired and the actual GCP object.
+type FieldChange struct {
+	// Path is the field path as it would appear in the update mask.
+	Path string
+	// Actual is the current value of the field in GCP.
+	Actual string
+	// Desired is the value that would be sent to GCP.
+	Desired string
+}
+
+// String returns a human-readable summary of the plan, suitable for events.
+func (p *Plan) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "plan: %s", p.Action)
+	if p.Action != PlanActionUpdate {
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, " (%d fields)", len(p.Changes))
+	for _, c := range p.Changes {
+		fmt.Fprintf(&sb, "\n  %s: %s -> %s", c.Path, c.Actual, c.Desired)
+	}
+	return sb.String()
+}
+
+// PlanOperation is the operation passed to Planner.Plan.
+type PlanOperation struct {
+	object *unstructured.Unstructured
+}
+
+func NewPlanOperation(object *unstructured.Unstructured) *PlanOperation {
+	return &PlanOperation{
+		object: object,
+	}
+}
+
+func (o *PlanOperation) GetUnstructured() *unstructured.Unstructured {
+	return o.object
+}
+
+// NewUpdatePlan builds an update plan from the update mask paths computed by the adapter,
+// rendering the values of each path from the desired and actual protos.
+// If there are no paths, the plan action is PlanActionNone.
+func NewUpdatePlan(desired, actual proto.Message, paths []string) *Plan {
+	if len(paths) == 0 {
+		return &Plan{Action: PlanActionNone}
+	}
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+
+	plan := &Plan{Action: PlanActionUpdate}
+	for _, path := range sorted {
+		plan.Changes = append(plan.Changes, FieldChange{
+			Path:    path,
+			Actual:  protoValueAtPath(actual, path),
+			Desired: protoValueAtPath(desired, path),
+		})
+	}
+	return plan
+}
+
+// protoValueAtPath renders the value of the (dot-separated) field path in msg. The
+// segments of the path are either proto field names, e.g. "display_name", or their
+// camelCase JSON names, e.g. "displayName", as the update masks of some APIs use them.
+func protoValueAtPath(msg proto.Message, path string) string {
+	if msg == nil {
+		return "<unset>"
+	}
+	m := msg.ProtoReflect()
+	tokens := strings.Split(path, ".")
+	for i, token := range tokens {
+		fields := m.Descriptor().Fields()
+		field := fields.ByName(protoreflect.Name(token))
+		if field == nil {
+			field = fields.ByJSONName(token)
+		}
+		if field == nil {
+			return "<unknown>"
+		}
+		if !m.Has(field) {
+			return "<unset>"
+		}
+		v := m.Get(field)
+		if i == len(tokens)-1 {
+			return formatProtoValue(field, v)
+		}
+		if field.Kind() != protoreflect.MessageKind || field.IsList() || field.IsMap() {
+			return "<unknown>"
+		}
+		m = v.Message()
+	}
+	return "<unknown>"
+}
+
+func formatProtoValue(field protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch {
+	case field.IsList():
+		list := v.List()
+		var items []string
+		for i := 0; i < list.Len(); i++ {
+			items = append(items, formatProtoSingular(field, list.Get(i)))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case field.IsMap():
+		var items []string
+		v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			items = append(items, k.String()+": "+formatProtoSingular(field.MapValue(), mv))
+			return true
+		})
+		sort.Strings(items)
+		return "{" + strings.Join(items, ", ") + "}"
+	default:
+		return formatProtoSingular(field, v)
+	}
+}
+
+func formatProtoSingular(field protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		b, err := protojson.Marshal(v.Message().Interface())
+		if err != nil {
+			return "<error: " + err.Error() + ">"
+		}
+		return string(b)
+	case protoreflect.EnumKind:
+		if ev := field.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return fmt.Sprintf("%d", v.Enum())
+	case protoreflect.StringKind:
+		return fmt.Sprintf("%q", v.String())
+	default:
+		return fmt.Sprintf("%v", v.Interface())
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directbase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestNewUpdatePlan(t *testing.T) {
+	actual := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String("foo"),
+		Number: proto.Int32(1),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	desired := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String("foo"),
+		Number: proto.Int32(2),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+		Options: &descriptorpb.FieldOptions{
+			Deprecated: proto.Bool(true),
+		},
+		JsonName: proto.String("fooBar"),
+	}
+
+	got := NewUpdatePlan(desired, actual, []string{"options.deprecated", "number", "label", "jsonName"})
+	want := &Plan{
+		Action: PlanActionUpdate,
+		Changes: []FieldChange{
+			{Path: "jsonName", Actual: "<unset>", Desired: "\"fooBar\""},
+			{Path: "label", Actual: "LABEL_OPTIONAL", Desired: "LABEL_REPEATED"},
+			{Path: "number", Actual: "1", Desired: "2"},
+			{Path: "options.deprecated", Actual: "<unset>", Desired: "true"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected plan (-want +got):\n%s", diff)
+	}
+
+	if got := NewUpdatePlan(desired, actual, nil); got.Action != PlanActionNone {
+		t.Errorf("expected action %q for empty paths, got %q", PlanActionNone, got.Action)
+	}
+}
+
+func TestDeletesGCPObject(t *testing.T) {
+	tests := []struct {
+		name          string
+		finalizers    []string
+		annotations   map[string]string
+		existsAlready bool
+		want          bool
+	}{
+		{
+			name:          "deletes the GCP object",
+			finalizers:    []string{k8s.ControllerFinalizerName},
+			existsAlready: true,
+			want:          true,
+		},
+		{
+			name:       "GCP object not found",
+			finalizers: []string{k8s.ControllerFinalizerName},
+		},
+		{
+			name:          "no controller finalizer",
+			existsAlready: true,
+		},
+		{
+			name:          "abandoned",
+			finalizers:    []string{k8s.ControllerFinalizerName},
+			annotations:   map[string]string{k8s.DeletionPolicyAnnotation: k8s.DeletionPolicyAbandon},
+			existsAlready: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			u := &unstructured.Unstructured{}
+			u.SetFinalizers(tc.finalizers)
+			u.SetAnnotations(tc.annotations)
+			if got := deletesGCPObject(u, tc.existsAlready); got != tc.want {
+				t.Errorf("deletesGCPObject() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHandlePlanWarnsOncePerGeneration(t *testing.T) {
+	ctx := context.Background()
+	u := &unstructured.Unstructured{}
+	u.SetUID("uid")
+	u.SetGeneration(1)
+	recorder := record.NewFakeRecorder(10)
+	r := &reconcileContext{
+		gvk:        schema.GroupVersionKind{Group: "test.cnrm.cloud.google.com", Version: "v1beta1", Kind: "TestKind"},
+		Reconciler: &DirectReconciler{LifecycleHandler: newLifecycleHandler(nil, recorder)},
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := r.handlePlan(ctx, u, &fakeAdapter{}, true); err != nil {
+			t.Fatalf("handlePlan() error = %v", err)
+		}
+	}
+	if got := len(recorder.Events); got != 1 {
+		t.Fatalf("got %d events for the first generation, want 1", got)
+	}
+	<-recorder.Events
+
+	u.SetGeneration(2)
+	if err := r.handlePlan(ctx, u, &fakeAdapter{}, true); err != nil {
+		t.Fatalf("handlePlan() error = %v", err)
+	}
+	if got := len(recorder.Events); got != 1 {
+		t.Fatalf("got %d events for the second generation, want 1", got)
+	}
+}
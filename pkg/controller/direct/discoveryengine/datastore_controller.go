@@ -17,7 +17,6 @@ package discoveryengine
 import (
 	"context"
 	"fmt"
-	"reflect"
 	"strings"
 
 	gcp "cloud.google.com/go/discoveryengine/apiv1"
@@ -33,6 +32,7 @@ import (
 	refs "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 )
@@ -190,9 +190,15 @@ func (a *dataStoreAdapter) Update(ctx context.Context, updateOp *directbase.Upda
 	desired := direct.ProtoClone(a.desired)
 	desired.Name = a.id.String()
 
+	// The fields which are not set are left to the service, e.g. the industry vertical.
+	diff, err := common.DiffProtoMessages(desired, a.actual, common.IgnoreUnsetFields())
+	if err != nil {
+		return err
+	}
+
 	// TODO(user): Update the field if applicable.
 	updateMask := &fieldmaskpb.FieldMask{}
-	if !reflect.DeepEqual(a.desired.DisplayName, a.actual.DisplayName) {
+	if diff.HasField("display_name") {
 		updateMask.Paths = append(updateMask.Paths, "display_name")
 	}
 
@@ -16,11 +16,12 @@ package discoveryengine
 
 import (
 	pb "cloud.google.com/go/discoveryengine/apiv1/discoveryenginepb"
+	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/discoveryengine/v1alpha1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/fuzztesting"
 )
 
 func init() {
-	fuzztesting.RegisterKRMFuzzer(engineFuzzer())
+	fuzztesting.RegisterKRMFuzzer(krm.DiscoveryEngineEngineGVK, engineFuzzer())
 }
 
 func engineFuzzer() fuzztesting.KRMFuzzer {
@@ -17,12 +17,12 @@ package firestore
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/firestore/v1beta1"
 	refs "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 
@@ -235,20 +235,20 @@ func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 
 	newDb := proto.Clone(a.actual).(*firestorepb.Database)
 
+	// Skip the update of the fields which are unspecified, e.g. concurrency_mode.
+	diff, err := common.DiffProtoMessages(resource, a.actual, common.IgnoreUnsetFields())
+	if err != nil {
+		return err
+	}
+
 	updateMask := &fieldmaskpb.FieldMask{}
-	if !reflect.DeepEqual(resource.ConcurrencyMode, a.actual.ConcurrencyMode) {
-		// Skip update if concurrency_mode is unspecified
-		if resource.ConcurrencyMode != firestorepb.Database_CONCURRENCY_MODE_UNSPECIFIED {
-			newDb.ConcurrencyMode = resource.ConcurrencyMode
-			updateMask.Paths = append(updateMask.Paths, "concurrency_mode")
-		}
+	if diff.HasField("concurrency_mode") {
+		newDb.ConcurrencyMode = resource.ConcurrencyMode
+		updateMask.Paths = append(updateMask.Paths, "concurrency_mode")
 	}
-	if !reflect.DeepEqual(resource.PointInTimeRecoveryEnablement, a.actual.PointInTimeRecoveryEnablement) {
-		// Skip update if point_in_time_recovery_enablement is unspecified
-		if resource.PointInTimeRecoveryEnablement != firestorepb.Database_POINT_IN_TIME_RECOVERY_ENABLEMENT_UNSPECIFIED {
-			newDb.PointInTimeRecoveryEnablement = resource.PointInTimeRecoveryEnablement
-			updateMask.Paths = append(updateMask.Paths, "point_in_time_recovery_enablement")
-		}
+	if diff.HasField("point_in_time_recovery_enablement") {
+		newDb.PointInTimeRecoveryEnablement = resource.PointInTimeRecoveryEnablement
+		updateMask.Paths = append(updateMask.Paths, "point_in_time_recovery_enablement")
 	}
 
 	if len(updateMask.Paths) == 0 {
@@ -16,6 +16,7 @@ package direct
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 
 	"cloud.google.com/go/iam/apiv1/iampb"
@@ -121,6 +122,8 @@ func SetIAMPolicyMember(ctx context.Context, reader client.Reader, want *v1beta1
 	}
 
 	actual.Spec.Member = memberID
+	// The etag is base64 encoded, like in the IAM REST API.
+	actual.Status.Etag = base64.StdEncoding.EncodeToString(latest.Etag)
 
 	for _, binding := range latest.Bindings {
 		if binding.Role != want.Spec.Role {
@@ -17,12 +17,12 @@ package kmsautokeyconfig
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/kms/v1beta1"
 	refs "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 
@@ -173,8 +173,12 @@ func (a *Adapter) updateAutokeyConfig(ctx context.Context, resource *kmspb.Autok
 	if err != nil {
 		return nil, err
 	}
+	diff, err := common.DiffProtoMessages(resource, a.actual, common.IgnoreUnsetFields())
+	if err != nil {
+		return nil, err
+	}
 	updateMask := &fieldmaskpb.FieldMask{}
-	if resource.KeyProject != "" && !reflect.DeepEqual(resource.KeyProject, a.actual.KeyProject) {
+	if diff.HasField("key_project") {
 		updateMask.Paths = append(updateMask.Paths, "key_project")
 	}
 
@@ -53,6 +53,9 @@ type logMetricModel struct {
 // model implements the Model interface.
 var _ directbase.Model = &logMetricModel{}
 
+// model implements the Lister interface.
+var _ directbase.Lister = &logMetricModel{}
+
 type logMetricAdapter struct {
 	resourceID string
 	projectID  string
@@ -138,6 +141,34 @@ func (m *logMetricModel) AdapterForURL(ctx context.Context, url string) (directb
 	return nil, nil
 }
 
+// ListURLs implements the Lister interface.
+func (m *logMetricModel) ListURLs(ctx context.Context, parent string) ([]string, error) {
+	// Log metrics are only supported under projects.
+	if !strings.HasPrefix(parent, "projects/") {
+		return nil, nil
+	}
+
+	gcpClient, err := newGCPClient(ctx, m.config)
+	if err != nil {
+		return nil, err
+	}
+	projectMetricsService, err := gcpClient.newProjectMetricsService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	if err := projectMetricsService.List(parent).Pages(ctx, func(page *api.ListLogMetricsResponse) error {
+		for _, metric := range page.Metrics {
+			urls = append(urls, "//logging.googleapis.com/"+parent+"/metrics/"+metric.Name)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("listing log metrics in %q: %w", parent, err)
+	}
+	return urls, nil
+}
+
 func (a *logMetricAdapter) Find(ctx context.Context) (bool, error) {
 	if a.resourceID == "" {
 		return false, nil
@@ -216,7 +247,7 @@ func (a *logMetricAdapter) Create(ctx context.Context, createOp *directbase.Crea
 	log.V(2).Info("created logMetric", "logMetric", created)
 
 	resourceID := created.Name
-	if err := unstructured.SetNestedField(u.Object, resourceID, "spec", "resourceID"); err != nil {
+	if err := createOp.ApplySpec(ctx, map[string]interface{}{"resourceID": resourceID}); err != nil {
 		return fmt.Errorf("setting spec.resourceID: %w", err)
 	}
 
@@ -21,6 +21,7 @@ import (
 
 	api "cloud.google.com/go/monitoring/dashboard/apiv1"
 	pb "cloud.google.com/go/monitoring/dashboard/apiv1/dashboardpb"
+	"google.golang.org/api/iterator"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
@@ -50,6 +51,9 @@ type dashboardModel struct {
 // model implements the Model interface.
 var _ directbase.Model = &dashboardModel{}
 
+// model implements the Lister interface.
+var _ directbase.Lister = &dashboardModel{}
+
 type dashboardAdapter struct {
 	projectID  string
 	resourceID string
@@ -144,6 +148,39 @@ func (m *dashboardModel) AdapterForURL(ctx context.Context, url string) (directb
 
 }
 
+// ListURLs implements the Lister interface.
+func (m *dashboardModel) ListURLs(ctx context.Context, parent string) ([]string, error) {
+	// Dashboards are only supported under projects.
+	if !strings.HasPrefix(parent, "projects/") {
+		return nil, nil
+	}
+
+	gcpClient, err := newGCPClient(m.config)
+	if err != nil {
+		return nil, fmt.Errorf("building gcp client: %w", err)
+	}
+	dashboardsClient, err := gcpClient.newDashboardsClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	it := dashboardsClient.ListDashboards(ctx, &pb.ListDashboardsRequest{Parent: parent})
+	for {
+		dashboard, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("listing dashboards in %q: %w", parent, err)
+		}
+		// The returned name uses the project number, build the URL from the parent so that it uses the project ID.
+		tokens := strings.Split(dashboard.Name, "/")
+		urls = append(urls, "//monitoring.googleapis.com/"+parent+"/dashboards/"+tokens[len(tokens)-1])
+	}
+	return urls, nil
+}
+
 // Find implements the Adapter interface.
 func (a *dashboardAdapter) Find(ctx context.Context) (bool, error) {
 	if a.resourceID == "" {
@@ -216,7 +253,7 @@ func (a *dashboardAdapter) Create(ctx context.Context, createOp *directbase.Crea
 	log.V(2).Info("created dashboard", "dashboard", created)
 
 	resourceID := lastComponent(created.Name)
-	if err := unstructured.SetNestedField(u.Object, resourceID, "spec", "resourceID"); err != nil {
+	if err := createOp.ApplySpec(ctx, map[string]interface{}{"resourceID": resourceID}); err != nil {
 		return fmt.Errorf("setting spec.resourceID: %w", err)
 	}
 
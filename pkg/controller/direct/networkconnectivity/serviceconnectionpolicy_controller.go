@@ -254,7 +254,7 @@ func (a *serviceConnectionPolicyAdapter) Create(ctx context.Context, createOp *d
 	log.V(2).Info("created organization", "serviceConnectionPolicy", created)
 
 	resourceID := lastComponent(created.Name)
-	if err := unstructured.SetNestedField(u.Object, resourceID, "spec", "resourceID"); err != nil {
+	if err := createOp.ApplySpec(ctx, map[string]interface{}{"resourceID": resourceID}); err != nil {
 		return fmt.Errorf("setting spec.resourceID: %w", err)
 	}
 
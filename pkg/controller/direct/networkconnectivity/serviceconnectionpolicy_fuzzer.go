@@ -0,0 +1,53 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkconnectivity
+
+import (
+	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/networkconnectivity/v1alpha1"
+	pb "github.com/GoogleCloudPlatform/k8s-config-connector/mockgcp/generated/mockgcp/cloud/networkconnectivity/v1"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/fuzztesting"
+)
+
+func init() {
+	fuzztesting.RegisterKRMFuzzer(krm.NetworkConnectivityServiceConnectionPolicyGVK, serviceConnectionPolicyFuzzer())
+}
+
+func serviceConnectionPolicyFuzzer() fuzztesting.KRMFuzzer {
+	f := fuzztesting.NewKRMTypedFuzzer(&pb.ServiceConnectionPolicy{},
+		NetworkConnectivityServiceConnectionPolicySpec_FromProto, NetworkConnectivityServiceConnectionPolicySpec_ToProto,
+		NetworkConnectivityServiceConnectionPolicyObservedState_FromProto, NetworkConnectivityServiceConnectionPolicyObservedState_ToProto,
+	)
+
+	f.UnimplementedFields.Insert(".labels")
+	f.UnimplementedFields.Insert(".name")
+	f.UnimplementedFields.Insert(".psc_config.allowed_google_producers_resource_hierarchy_level")
+	f.UnimplementedFields.Insert(".psc_connections[].error.details")
+	f.UnimplementedFields.Insert(".psc_connections[].ip_version")
+	f.UnimplementedFields.Insert(".psc_connections[].producer_instance_metadata")
+	f.UnimplementedFields.Insert(".psc_connections[].service_class")
+
+	f.SpecFields.Insert(".description")
+	f.SpecFields.Insert(".network")
+	f.SpecFields.Insert(".psc_config")
+	f.SpecFields.Insert(".service_class")
+
+	f.StatusFields.Insert(".create_time")
+	f.StatusFields.Insert(".etag")
+	f.StatusFields.Insert(".infrastructure")
+	f.StatusFields.Insert(".psc_connections")
+	f.StatusFields.Insert(".update_time")
+
+	return f
+}
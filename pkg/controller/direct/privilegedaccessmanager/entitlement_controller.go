@@ -24,12 +24,12 @@ import (
 	refs "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 
 	gcp "cloud.google.com/go/privilegedaccessmanager/apiv1"
 	privilegedaccessmanagerpb "cloud.google.com/go/privilegedaccessmanager/apiv1/privilegedaccessmanagerpb"
-	"github.com/google/go-cmp/cmp"
 	"google.golang.org/api/option"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -306,8 +306,13 @@ func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 	log.V(2).Info("updating PrivilegedAccessManagerEntitlement", "name", a.id.FullyQualifiedName())
 	mapCtx := &direct.MapContext{}
 
-	updateMask := &fieldmaskpb.FieldMask{}
+	resourceType, resource, err := getResourceTypeAndResourceFromContainer(a.id.Parent.Container)
+	if err != nil {
+		return fmt.Errorf("error getting resourceType and resource from container: %w", err)
+	}
+	hiddenFields := gcpIAMAccessResource{resourceType: resourceType, resource: resource}
 
+	// The principals are compared regardless of their order.
 	parsedActual := PrivilegedAccessManagerEntitlementSpec_FromProto(mapCtx, a.actual)
 	if mapCtx.Err() != nil {
 		return fmt.Errorf("error generating update mask: %w", mapCtx.Err())
@@ -315,30 +320,28 @@ func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 	sortPrincipalsInSpec(parsedActual)
 	parsedDesired := a.desired.DeepCopy()
 	sortPrincipalsInSpec(&parsedDesired.Spec)
-
-	if !reflect.DeepEqual(parsedActual.AdditionalNotificationTargets, parsedDesired.Spec.AdditionalNotificationTargets) {
-		log.V(2).Info("'spec.additionalNotificationTargets' field is updated (-old +new)", cmp.Diff(parsedActual.AdditionalNotificationTargets, parsedDesired.Spec.AdditionalNotificationTargets))
-		updateMask.Paths = append(updateMask.Paths, "additional_notification_targets")
-	}
-	if !reflect.DeepEqual(parsedActual.ApprovalWorkflow, parsedDesired.Spec.ApprovalWorkflow) {
-		log.V(2).Info("'spec.approvalWorkflow' field is updated (-old +new)", cmp.Diff(parsedActual.ApprovalWorkflow, parsedDesired.Spec.ApprovalWorkflow))
-		updateMask.Paths = append(updateMask.Paths, "approval_workflow")
-	}
-	if !reflect.DeepEqual(parsedActual.EligibleUsers, parsedDesired.Spec.EligibleUsers) {
-		log.V(2).Info("'spec.eligibleUsers' field is updated (-old +new)", cmp.Diff(parsedActual.EligibleUsers, parsedDesired.Spec.EligibleUsers))
-		updateMask.Paths = append(updateMask.Paths, "eligible_users")
-	}
-	if !reflect.DeepEqual(a.actual.MaxRequestDuration.AsDuration(), direct.StringDuration_ToProto(mapCtx, parsedDesired.Spec.MaxRequestDuration).AsDuration()) {
-		log.V(2).Info("'spec.maxRequestDuration' field is updated (-old +new)", cmp.Diff(a.actual.MaxRequestDuration.AsDuration(), direct.StringDuration_ToProto(mapCtx, parsedDesired.Spec.MaxRequestDuration).AsDuration()))
-		updateMask.Paths = append(updateMask.Paths, "max_request_duration")
+	sortedActual := PrivilegedAccessManagerEntitlementSpec_ToProto(mapCtx, parsedActual, hiddenFields)
+	sortedDesired := PrivilegedAccessManagerEntitlementSpec_ToProto(mapCtx, &parsedDesired.Spec, hiddenFields)
+	if mapCtx.Err() != nil {
+		return fmt.Errorf("error generating update mask: %w", mapCtx.Err())
 	}
-	if !reflect.DeepEqual(parsedActual.PrivilegedAccess, parsedDesired.Spec.PrivilegedAccess) {
-		log.V(2).Info("'spec.privilegedAccess' field is updated (-old +new)", cmp.Diff(parsedActual.PrivilegedAccess, parsedDesired.Spec.PrivilegedAccess))
-		updateMask.Paths = append(updateMask.Paths, "privileged_access")
+	diff, err := common.DiffProtoMessages(sortedDesired, sortedActual)
+	if err != nil {
+		return err
 	}
-	if !reflect.DeepEqual(parsedActual.RequesterJustificationConfig, parsedDesired.Spec.RequesterJustificationConfig) {
-		log.V(2).Info("'spec.requesterJustificationConfig' field is updated (-old +new)", cmp.Diff(parsedActual.RequesterJustificationConfig, parsedDesired.Spec.RequesterJustificationConfig))
-		updateMask.Paths = append(updateMask.Paths, "requester_justification_config")
+
+	updateMask := &fieldmaskpb.FieldMask{}
+	for _, field := range []string{
+		"additional_notification_targets",
+		"approval_workflow",
+		"eligible_users",
+		"max_request_duration",
+		"privileged_access",
+		"requester_justification_config",
+	} {
+		if diff.HasField(field) {
+			updateMask.Paths = append(updateMask.Paths, field)
+		}
 	}
 	if len(updateMask.Paths) == 0 {
 		log.V(2).Info("underlying PrivilegedAccessManagerEntitlement already up to date", "name", a.id.FullyQualifiedName())
@@ -351,13 +354,9 @@ func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 		status.ObservedState = observedState
 		return setStatus(u, status)
 	}
+	log.V(2).Info("updating fields", "name", a.id.FullyQualifiedName(), "diff", diff.String())
 
 	desired := a.desired.DeepCopy()
-	resourceType, resource, err := getResourceTypeAndResourceFromContainer(a.id.Parent.Container)
-	if err != nil {
-		return fmt.Errorf("error getting resourceType and resource from container: %w", err)
-	}
-	hiddenFields := gcpIAMAccessResource{resourceType: resourceType, resource: resource}
 	entitlement := PrivilegedAccessManagerEntitlementSpec_ToProto(mapCtx, &desired.Spec, hiddenFields)
 	if mapCtx.Err() != nil {
 		return mapCtx.Err()
@@ -17,18 +17,17 @@ package cluster
 import (
 	"context"
 	"fmt"
-	"reflect"
 	"strings"
 
 	api "cloud.google.com/go/redis/cluster/apiv1"
 	pb "cloud.google.com/go/redis/cluster/apiv1/clusterpb"
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	commonv1 "github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/redis/v1beta1"
 	refs "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
@@ -67,6 +66,9 @@ type redisClusterAdapter struct {
 // adapter implements the Adapter interface.
 var _ directbase.Adapter = &redisClusterAdapter{}
 
+// adapter implements the OperationPoller interface.
+var _ directbase.OperationPoller = &redisClusterAdapter{}
+
 // AdapterForObject implements the Model interface.
 func (m *redisClusterModel) AdapterForObject(ctx context.Context, kube client.Reader, u *unstructured.Unstructured) (directbase.Adapter, error) {
 	gcpClient, err := newGCPClient(ctx, m.config)
@@ -226,12 +228,10 @@ func (a *redisClusterAdapter) Create(ctx context.Context, createOp *directbase.C
 		return fmt.Errorf("creating cluster: %w", err)
 	}
 
-	created, err := op.Wait(ctx)
-	if err != nil {
-		return fmt.Errorf("waiting for redisCluster create %s: %w", a.fullyQualifiedName(), err)
-	}
-
-	log.V(0).Info("created redisCluster", "redisCluster", created)
+	// Creating a cluster takes a long time, so we don't wait for it here.
+	// The next reconciliations poll the operation, and write the observed state once it is done.
+	log.V(0).Info("started redisCluster create", "operation", op.Name())
+	createOp.TrackOperation(operationTypeCreate, op.Name())
 
 	/* TODO: Any reason to write resourceID back?  It's required anyway
 	resourceID := lastComponent(created.Name)
@@ -240,12 +240,7 @@ func (a *redisClusterAdapter) Create(ctx context.Context, createOp *directbase.C
 	}
 	*/
 
-	mapCtx := &direct.MapContext{}
-	observedState := RedisClusterObservedState_FromProto(mapCtx, created)
-	if mapCtx.Err() != nil {
-		return mapCtx.Err()
-	}
-	return setObservedState(u, observedState)
+	return nil
 }
 
 // Update implements the Adapter interface.
@@ -255,71 +250,72 @@ func (a *redisClusterAdapter) Update(ctx context.Context, updateOp *directbase.U
 	log := klog.FromContext(ctx)
 	log.V(0).Info("updating object", "u", u)
 
-	updateMask := &fieldmaskpb.FieldMask{}
-
-	// TODO: What if a different field (immutability again)
-
-	if direct.ValueOf(a.desired.ReplicaCount) != direct.ValueOf(a.actual.ReplicaCount) {
-		updateMask.Paths = append(updateMask.Paths, "replica_count")
-	}
-
-	if direct.ValueOf(a.desired.ShardCount) != direct.ValueOf(a.actual.ShardCount) {
-		updateMask.Paths = append(updateMask.Paths, "shard_count")
+	diff, err := common.DiffProtoMessages(a.desired, a.actual)
+	if err != nil {
+		return err
 	}
 
-	if direct.ValueOf(a.desired.DeletionProtectionEnabled) != direct.ValueOf(a.actual.DeletionProtectionEnabled) {
-		updateMask.Paths = append(updateMask.Paths, "deletion_protection_enabled")
-	}
+	updateMask := &fieldmaskpb.FieldMask{}
 
-	if !proto.Equal(a.desired.PersistenceConfig, a.actual.PersistenceConfig) {
-		updateMask.Paths = append(updateMask.Paths, "persistence_config")
-	}
+	// TODO: What if a different field (immutability again)
 
-	if !reflect.DeepEqual(a.desired.GetRedisConfigs(), a.actual.GetRedisConfigs()) {
-		updateMask.Paths = append(updateMask.Paths, "redis_configs")
+	for _, field := range []string{"replica_count", "shard_count", "deletion_protection_enabled", "persistence_config", "redis_configs"} {
+		if diff.HasField(field) {
+			updateMask.Paths = append(updateMask.Paths, field)
+		}
 	}
 
-	var latest *pb.Cluster
 	if len(updateMask.Paths) != 0 {
+		// exactly 1 update_mask field must be specified per update request,
+		// so we update one field per reconciliation, and the next reconciliation updates the next one
+		// once the operation is done.
+		req := &pb.UpdateClusterRequest{
+			Cluster: a.desired,
+		}
+		req.UpdateMask = &fieldmaskpb.FieldMask{
+			Paths: []string{updateMask.Paths[0]},
+		}
 
-		// exactly 1 update_mask field must be specified per update request
-		for _, path := range updateMask.Paths {
-			req := &pb.UpdateClusterRequest{
-				Cluster: a.desired,
-			}
-			req.UpdateMask = &fieldmaskpb.FieldMask{
-				Paths: []string{path},
-			}
-
-			req.Cluster.Name = a.fullyQualifiedName()
-
-			log.V(0).Info("making redis UpdateCluster call", "request", req)
-
-			op, err := a.clustersClient.UpdateCluster(ctx, req)
-			if err != nil {
-				return err
-			}
+		req.Cluster.Name = a.fullyQualifiedName()
 
-			updated, err := op.Wait(ctx)
-			if err != nil {
-				return fmt.Errorf("waiting for redisCluster update %s: %w", a.fullyQualifiedName(), err)
-			}
-			log.V(0).Info("updated redisCluster", "redisCluster", updated)
+		log.V(0).Info("making redis UpdateCluster call", "request", req)
 
-			latest = updated
+		op, err := a.clustersClient.UpdateCluster(ctx, req)
+		if err != nil {
+			return err
 		}
-	} else {
-		latest = a.actual
+
+		log.V(0).Info("started redisCluster update", "operation", op.Name())
+		updateOp.TrackOperation(operationTypeUpdate, op.Name())
+		return nil
 	}
 
 	mapCtx := &direct.MapContext{}
-	observedState := RedisClusterObservedState_FromProto(mapCtx, latest)
+	observedState := RedisClusterObservedState_FromProto(mapCtx, a.actual)
 	if mapCtx.Err() != nil {
 		return mapCtx.Err()
 	}
 	return setObservedState(u, observedState)
 }
 
+const (
+	operationTypeCreate = "create"
+	operationTypeUpdate = "update"
+)
+
+// PollOperation implements the OperationPoller interface.
+func (a *redisClusterAdapter) PollOperation(ctx context.Context, op *commonv1.PendingOperation) (bool, error) {
+	switch op.Type {
+	case operationTypeCreate:
+		return directbase.PollGAPICOperation(ctx, a.clustersClient.CreateClusterOperation(op.Name))
+	case operationTypeUpdate:
+		return directbase.PollGAPICOperation(ctx, a.clustersClient.UpdateClusterOperation(op.Name))
+	default:
+		// We cannot poll the operation, so fall back to Find.
+		return true, fmt.Errorf("unknown type %q of operation %s", op.Type, op.Name)
+	}
+}
+
 func (a *redisClusterAdapter) fullyQualifiedName() string {
 	return fmt.Sprintf("projects/%s/locations/%s/clusters/%s", a.projectID, a.location, a.resourceID)
 }
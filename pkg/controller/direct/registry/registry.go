@@ -17,6 +17,7 @@ package registry
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
@@ -80,6 +81,55 @@ func AdapterForURL(ctx context.Context, url string) (directbase.Adapter, error)
 	return nil, nil
 }
 
+// ListableGroupKinds returns the GroupKinds whose model implements directbase.Lister, sorted by group and kind.
+func ListableGroupKinds() ([]schema.GroupKind, error) {
+	var gks []schema.GroupKind
+	for gk, registration := range singleton.registrations {
+		if registration.model == nil {
+			return nil, fmt.Errorf("registry was not initialized (must call registry.Init)")
+		}
+		if _, ok := registration.model.(directbase.Lister); ok {
+			gks = append(gks, gk)
+		}
+	}
+	sortGroupKinds(gks)
+	return gks, nil
+}
+
+// RegisteredGroupKinds returns the GroupKinds of all registered models, sorted by group and kind.
+// Unlike ListableGroupKinds, it does not require registry.Init.
+func RegisteredGroupKinds() []schema.GroupKind {
+	var gks []schema.GroupKind
+	for gk := range singleton.registrations {
+		gks = append(gks, gk)
+	}
+	sortGroupKinds(gks)
+	return gks
+}
+
+func sortGroupKinds(gks []schema.GroupKind) {
+	sort.Slice(gks, func(i, j int) bool {
+		if gks[i].Group != gks[j].Group {
+			return gks[i].Group < gks[j].Group
+		}
+		return gks[i].Kind < gks[j].Kind
+	})
+}
+
+// ListURLs returns the URLs of the GCP objects of kind gk under parent, or (nil, nil)
+// if the model for gk does not support listing.
+func ListURLs(ctx context.Context, gk schema.GroupKind, parent string) ([]string, error) {
+	model, err := GetModel(gk)
+	if err != nil {
+		return nil, err
+	}
+	lister, ok := model.(directbase.Lister)
+	if !ok {
+		return nil, nil
+	}
+	return lister.ListURLs(ctx, parent)
+}
+
 func Init(ctx context.Context, config *config.ControllerConfig) error {
 	for _, registration := range singleton.registrations {
 		model, err := registration.factory(ctx, config)
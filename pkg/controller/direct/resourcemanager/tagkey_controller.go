@@ -189,7 +189,7 @@ func (a *tagKeyAdapter) Create(ctx context.Context, createOp *directbase.CreateO
 	log.V(2).Info("created tagkey", "tagkey", created)
 
 	resourceID := created.Name
-	if err := unstructured.SetNestedField(u.Object, resourceID, "spec", "resourceID"); err != nil {
+	if err := createOp.ApplySpec(ctx, map[string]interface{}{"resourceID": resourceID}); err != nil {
 		return fmt.Errorf("setting spec.resourceID: %w", err)
 	}
 
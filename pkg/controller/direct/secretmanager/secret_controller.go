@@ -17,7 +17,6 @@ package secretmanager
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	gcp "cloud.google.com/go/secretmanager/apiv1"
 	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
@@ -226,19 +225,6 @@ func (a *Adapter) Create(ctx context.Context, op *directbase.CreateOperation) er
 	return nil
 }
 
-func topicsEqual(desired []*krm.TopicRef, actual []*secretmanagerpb.Topic) bool {
-
-	externalsDesired := sets.Set[string]{}
-	externalsActual := sets.Set[string]{}
-	for _, topicRef := range desired {
-		externalsDesired.Insert(topicRef.PubSubTopicRef.External)
-	}
-	for _, topic := range actual {
-		externalsActual.Insert(topic.GetName())
-	}
-	return reflect.DeepEqual(sets.List(externalsDesired), sets.List(externalsActual))
-}
-
 func (a *Adapter) Update(ctx context.Context, op *directbase.UpdateOperation) error {
 	log := klog.FromContext(ctx).WithName(ctrlName)
 	log.V(2).Info("updating Secret", "name", a.id)
@@ -254,10 +240,11 @@ func (a *Adapter) Update(ctx context.Context, op *directbase.UpdateOperation) er
 	// the GCP service use *name* to identify the resource.
 	resource.Name = a.id.String()
 	resource.Etag = a.actual.Etag
-	paths, err := common.CompareProtoMessage(resource, a.actual, common.BasicDiff)
+	diff, err := common.DiffProtoMessages(resource, a.actual)
 	if err != nil {
 		return err
 	}
+	paths := diff.Paths()
 	if paths.Has("ttl") {
 		paths = paths.Delete("ttl")
 		resource.Expiration = a.actual.Expiration
@@ -266,6 +253,7 @@ func (a *Adapter) Update(ctx context.Context, op *directbase.UpdateOperation) er
 		log.V(2).Info("no field needs update", "name", a.id)
 		return nil
 	}
+	log.V(2).Info("updating fields", "name", a.id, "diff", diff.String())
 
 	req := &secretmanagerpb.UpdateSecretRequest{
 		UpdateMask: &fieldmaskpb.FieldMask{Paths: sets.List(paths)},
@@ -0,0 +1,45 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretmanager
+
+import (
+	pb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/secretmanager/v1beta1"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/fuzztesting"
+)
+
+func init() {
+	fuzztesting.RegisterKRMFuzzer(krm.SecretManagerSecretVersionGVK, secretVersionFuzzer())
+}
+
+func secretVersionFuzzer() fuzztesting.KRMFuzzer {
+	f := fuzztesting.NewKRMTypedFuzzer(&pb.SecretVersion{},
+		SecretManagerSecretVersionSpec_FromProto, SecretManagerSecretVersionSpec_ToProto,
+		SecretManagerSecretVersionObservedState_FromProto, SecretManagerSecretVersionObservedState_ToProto,
+	)
+
+	f.UnimplementedFields.Insert(".state")
+	f.UnimplementedFields.Insert(".etag")
+
+	f.StatusFields.Insert(".name")
+	f.StatusFields.Insert(".create_time")
+	f.StatusFields.Insert(".destroy_time")
+	f.StatusFields.Insert(".replication_status")
+	f.StatusFields.Insert(".client_specified_payload_checksum")
+	f.StatusFields.Insert(".scheduled_destroy_time")
+	f.StatusFields.Insert(".customer_managed_encryption")
+
+	return f
+}
@@ -28,7 +28,9 @@ import (
 
 	gcp "cloud.google.com/go/securesourcemanager/apiv1"
 	pb "cloud.google.com/go/securesourcemanager/apiv1/securesourcemanagerpb"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
+	locationpb "google.golang.org/genproto/googleapis/cloud/location"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -46,6 +48,9 @@ func NewSecureSourceManagerInstanceModel(ctx context.Context, config *config.Con
 
 var _ directbase.Model = &secureSourceManagerInstanceModel{}
 
+// model implements the Lister interface.
+var _ directbase.Lister = &secureSourceManagerInstanceModel{}
+
 type secureSourceManagerInstanceModel struct {
 	config config.ControllerConfig
 }
@@ -122,6 +127,46 @@ func (m *secureSourceManagerInstanceModel) AdapterForURL(ctx context.Context, ur
 	}, nil
 }
 
+// ListURLs implements the Lister interface.
+func (m *secureSourceManagerInstanceModel) ListURLs(ctx context.Context, parent string) ([]string, error) {
+	// Instances are only supported under projects.
+	if !strings.HasPrefix(parent, "projects/") {
+		return nil, nil
+	}
+
+	gcpClient, err := m.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	locations := gcpClient.ListLocations(ctx, &locationpb.ListLocationsRequest{Name: parent})
+	for {
+		location, err := locations.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("listing locations in %q: %w", parent, err)
+		}
+		locationParent := parent + "/locations/" + location.LocationId
+		instances := gcpClient.ListInstances(ctx, &pb.ListInstancesRequest{Parent: locationParent})
+		for {
+			instance, err := instances.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("listing instances in %q: %w", locationParent, err)
+			}
+			// The returned name may use the project number, build the URL from the parent so that it uses the project ID.
+			tokens := strings.Split(instance.Name, "/")
+			urls = append(urls, "//securesourcemanager.googleapis.com/"+locationParent+"/instances/"+tokens[len(tokens)-1])
+		}
+	}
+	return urls, nil
+}
+
 type secureSourceManagerInstanceAdapter struct {
 	id        *krm.SecureSourceManagerInstanceRef
 	gcpClient *gcp.Client
@@ -0,0 +1,46 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package securesourcemanager
+
+import (
+	pb "cloud.google.com/go/securesourcemanager/apiv1/securesourcemanagerpb"
+	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/securesourcemanager/v1alpha1"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/fuzztesting"
+)
+
+func init() {
+	fuzztesting.RegisterKRMFuzzer(krm.SecureSourceManagerInstanceGVK, instanceFuzzer())
+}
+
+func instanceFuzzer() fuzztesting.KRMFuzzer {
+	f := fuzztesting.NewKRMTypedFuzzer(&pb.Instance{},
+		SecureSourceManagerInstanceSpec_FromProto, SecureSourceManagerInstanceSpec_ToProto,
+		SecureSourceManagerInstanceObservedState_FromProto, SecureSourceManagerInstanceObservedState_ToProto,
+	)
+
+	f.UnimplementedFields.Insert(".name")
+	f.UnimplementedFields.Insert(".create_time")
+	f.UnimplementedFields.Insert(".update_time")
+	f.UnimplementedFields.Insert(".labels")
+	f.UnimplementedFields.Insert(".private_config")
+
+	f.SpecFields.Insert(".kms_key")
+
+	f.StatusFields.Insert(".state")
+	f.StatusFields.Insert(".state_note")
+	f.StatusFields.Insert(".host_config")
+
+	return f
+}
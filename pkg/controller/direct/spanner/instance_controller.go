@@ -17,11 +17,11 @@ package spanner
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/spanner/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 
@@ -192,20 +192,25 @@ func (a *SpannerInstanceAdapter) Update(ctx context.Context, updateOp *directbas
 		return mapCtx.Err()
 	}
 
-	updateMask := &fieldmaskpb.FieldMask{}
-	if !reflect.DeepEqual(a.desired.Spec.DisplayName, a.actual.DisplayName) {
-		updateMask.Paths = append(updateMask.Paths, "display_name")
-	}
+	var unmanaged []string
 	// If node count is unset, the field become unmanaged.
-	if a.desired.Spec.NumNodes != nil && !reflect.DeepEqual(a.desired.Spec.NumNodes, a.actual.NodeCount) {
-		updateMask.Paths = append(updateMask.Paths, "node_count")
+	if a.desired.Spec.NumNodes == nil {
+		unmanaged = append(unmanaged, "node_count")
 	}
 	// If processing unit is unset, the field become unmanaged.
-	if a.desired.Spec.ProcessingUnits != nil && !reflect.DeepEqual(a.desired.Spec.ProcessingUnits, a.actual.ProcessingUnits) {
-		updateMask.Paths = append(updateMask.Paths, "processing_units")
+	if a.desired.Spec.ProcessingUnits == nil {
+		unmanaged = append(unmanaged, "processing_units")
+	}
+	diff, err := common.DiffProtoMessages(resource, a.actual, common.IgnoreFields(unmanaged...))
+	if err != nil {
+		return err
 	}
-	if !reflect.DeepEqual(a.desired.ObjectMeta.Labels, a.actual.Labels) {
-		updateMask.Paths = append(updateMask.Paths, "labels")
+
+	updateMask := &fieldmaskpb.FieldMask{}
+	for _, field := range []string{"display_name", "node_count", "processing_units", "labels"} {
+		if diff.HasField(field) {
+			updateMask.Paths = append(updateMask.Paths, field)
+		}
 	}
 
 	if len(updateMask.Paths) == 0 {
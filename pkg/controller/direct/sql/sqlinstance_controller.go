@@ -26,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	commonv1 "github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
 	refs "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/sql/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
@@ -86,6 +87,9 @@ type sqlInstanceAdapter struct {
 
 var _ directbase.Adapter = &sqlInstanceAdapter{}
 
+// adapter implements the OperationPoller interface.
+var _ directbase.OperationPoller = &sqlInstanceAdapter{}
+
 func (m *sqlInstanceModel) AdapterForObject(ctx context.Context, kube client.Reader, u *unstructured.Unstructured) (directbase.Adapter, error) {
 	obj := &krm.SQLInstance{}
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &obj); err != nil {
@@ -146,8 +150,6 @@ func (a *sqlInstanceAdapter) Find(ctx context.Context) (bool, error) {
 }
 
 func (a *sqlInstanceAdapter) Create(ctx context.Context, createOp *directbase.CreateOperation) error {
-	u := createOp.GetUnstructured()
-
 	log := klog.FromContext(ctx).WithName(ctrlName)
 	log.V(2).Info("creating SQLInstance", "desired", a.desired)
 
@@ -159,13 +161,13 @@ func (a *sqlInstanceAdapter) Create(ctx context.Context, createOp *directbase.Cr
 	}
 
 	if a.desired.Spec.CloneSource != nil {
-		return a.cloneInstance(ctx, u, log)
+		return a.cloneInstance(ctx, createOp, log)
 	} else {
-		return a.insertInstance(ctx, u, log)
+		return a.insertInstance(ctx, createOp, log)
 	}
 }
 
-func (a *sqlInstanceAdapter) cloneInstance(ctx context.Context, u *unstructured.Unstructured, log klog.Logger) error {
+func (a *sqlInstanceAdapter) cloneInstance(ctx context.Context, createOp *directbase.CreateOperation, log klog.Logger) error {
 	desiredGCP, err := SQLInstanceCloneKRMToGCP(a.desired)
 	if err != nil {
 		return err
@@ -176,25 +178,14 @@ func (a *sqlInstanceAdapter) cloneInstance(ctx context.Context, u *unstructured.
 	if err != nil {
 		return fmt.Errorf("cloning SQLInstance %s failed: %w", a.desired.Name, err)
 	}
-	if err := a.pollForLROCompletion(ctx, op, "clone"); err != nil {
-		return err
-	}
 
-	created, err := a.sqlInstancesClient.Get(a.projectID, a.resourceID).Context(ctx).Do()
-	if err != nil {
-		return fmt.Errorf("getting SQLInstance %s failed: %w", a.desired.Name, err)
-	}
-
-	log.V(2).Info("instance cloned", "op", op, "instance", created)
+	log.V(2).Info("started SQLInstance clone", "op", op)
 
-	status, err := SQLInstanceStatusGCPToKRM(created)
-	if err != nil {
-		return fmt.Errorf("updating SQLInstance status failed: %w", err)
-	}
-	return setStatus(u, status)
+	createOp.TrackOperation(operationTypeClone, op.Name)
+	return nil
 }
 
-func (a *sqlInstanceAdapter) insertInstance(ctx context.Context, u *unstructured.Unstructured, log klog.Logger) error {
+func (a *sqlInstanceAdapter) insertInstance(ctx context.Context, createOp *directbase.CreateOperation, log klog.Logger) error {
 	desiredGCP, err := SQLInstanceKRMToGCP(a.desired, a.actual)
 	if err != nil {
 		return err
@@ -204,14 +195,22 @@ func (a *sqlInstanceAdapter) insertInstance(ctx context.Context, u *unstructured
 	if err != nil {
 		return fmt.Errorf("creating SQLInstance %s failed: %w", a.desired.Name, err)
 	}
-	if err := a.pollForLROCompletion(ctx, op, "create"); err != nil {
-		return err
-	}
 
+	log.V(2).Info("started SQLInstance creation", "op", op)
+
+	createOp.TrackOperation(operationTypeCreate, op.Name)
+	return nil
+}
+
+// deleteRootUser deletes the "root" user of a newly created MySQL instance.
+func (a *sqlInstanceAdapter) deleteRootUser(ctx context.Context) error {
 	created, err := a.sqlInstancesClient.Get(a.projectID, a.resourceID).Context(ctx).Do()
 	if err != nil {
 		return fmt.Errorf("getting SQLInstance %s failed: %w", a.desired.Name, err)
 	}
+	if !strings.HasPrefix(created.DatabaseVersion, "MYSQL") {
+		return nil
+	}
 
 	users, err := a.sqlUsersClient.List(a.projectID, a.resourceID).Context(ctx).Do()
 	if err != nil {
@@ -220,7 +219,7 @@ func (a *sqlInstanceAdapter) insertInstance(ctx context.Context, u *unstructured
 
 	if users != nil {
 		for _, user := range users.Items {
-			if user.Name == "root" && strings.HasPrefix(created.DatabaseVersion, "MYSQL") {
+			if user.Name == "root" {
 				// Delete "root" user to match Terraform behavior, to improve default security.
 				// Ref: https://registry.terraform.io/providers/hashicorp/google/latest/docs/resources/sql_database_instance
 				op, err := a.sqlUsersClient.Delete(a.projectID, a.resourceID).Context(ctx).Name(user.Name).Host(user.Host).Do()
@@ -233,14 +232,34 @@ func (a *sqlInstanceAdapter) insertInstance(ctx context.Context, u *unstructured
 			}
 		}
 	}
+	return nil
+}
 
-	log.V(2).Info("instance created", "op", op, "instance", created)
+const (
+	operationTypeCreate = "create"
+	operationTypeClone  = "clone"
+	operationTypeUpdate = "update"
+)
 
-	status, err := SQLInstanceStatusGCPToKRM(created)
+// PollOperation implements the OperationPoller interface.
+func (a *sqlInstanceAdapter) PollOperation(ctx context.Context, pending *commonv1.PendingOperation) (bool, error) {
+	op, err := a.sqlOperationsClient.Get(a.projectID, pending.Name).Context(ctx).Do()
 	if err != nil {
-		return fmt.Errorf("updating SQLInstance status failed: %w", err)
+		return false, fmt.Errorf("getting SQLInstance %s %s operation %s failed: %w", a.resourceID, pending.Type, pending.Name, err)
 	}
-	return setStatus(u, status)
+	if op.Status != "DONE" {
+		return false, nil
+	}
+	if op.Error != nil && len(op.Error.Errors) != 0 {
+		return true, fmt.Errorf("SQLInstance %s %s operation %s failed: %s", a.resourceID, pending.Type, pending.Name, op.Error.Errors[0].Message)
+	}
+	if pending.Type == operationTypeCreate {
+		// The operation stays pending until the root user is gone, so that a failed deletion is retried.
+		if err := a.deleteRootUser(ctx); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
 }
 
 func (a *sqlInstanceAdapter) Update(ctx context.Context, updateOp *directbase.UpdateOperation) error {
@@ -258,18 +277,12 @@ func (a *sqlInstanceAdapter) Update(ctx context.Context, updateOp *directbase.Up
 		if err != nil {
 			return fmt.Errorf("patching SQLInstance %s version failed: %w", a.resourceID, err)
 		}
-		if err := a.pollForLROCompletion(ctx, op, "version patch"); err != nil {
-			return err
-		}
 
-		updated, err := a.sqlInstancesClient.Get(a.projectID, a.resourceID).Context(ctx).Do()
-		if err != nil {
-			return fmt.Errorf("getting SQLInstance %s failed: %w", a.resourceID, err)
-		}
-
-		log.V(2).Info("instance version updated", "op", op, "instance", updated)
+		log.V(2).Info("started SQLInstance version patch", "op", op)
 
-		a.actual = updated
+		// The remaining fields are updated once the operation is done.
+		updateOp.TrackOperation(operationTypeUpdate, op.Name)
+		return nil
 	}
 
 	// Next, handle database edition updates
@@ -287,18 +300,12 @@ func (a *sqlInstanceAdapter) Update(ctx context.Context, updateOp *directbase.Up
 		if err != nil {
 			return fmt.Errorf("patching SQLInstance %s edition failed: %w", a.resourceID, err)
 		}
-		if err := a.pollForLROCompletion(ctx, op, "edition patch"); err != nil {
-			return err
-		}
 
-		updated, err := a.sqlInstancesClient.Get(a.projectID, a.resourceID).Context(ctx).Do()
-		if err != nil {
-			return fmt.Errorf("getting SQLInstance %s failed: %w", a.resourceID, err)
-		}
+		log.V(2).Info("started SQLInstance edition patch", "op", op)
 
-		log.V(2).Info("instance edition updated", "op", op, "instance", updated)
-
-		a.actual = updated
+		// The remaining fields are updated once the operation is done.
+		updateOp.TrackOperation(operationTypeUpdate, op.Name)
+		return nil
 	}
 
 	// Finally, update rest of the fields
@@ -314,25 +321,18 @@ func (a *sqlInstanceAdapter) Update(ctx context.Context, updateOp *directbase.Up
 		if err != nil {
 			return fmt.Errorf("updating SQLInstance %s failed: %w", desiredGCP.Name, err)
 		}
-		if err := a.pollForLROCompletion(ctx, op, "update"); err != nil {
-			return err
-		}
-
-		updated, err := a.sqlInstancesClient.Get(a.projectID, a.resourceID).Context(ctx).Do()
-		if err != nil {
-			return fmt.Errorf("getting SQLInstance %s failed: %w", desiredGCP.Name, err)
-		}
 
-		log.V(2).Info("instance updated", "op", op, "instance", updated)
+		log.V(2).Info("started SQLInstance update", "op", op)
 
-		status, err := SQLInstanceStatusGCPToKRM(updated)
-		if err != nil {
-			return fmt.Errorf("updating SQLInstance status failed: %w", err)
-		}
-		return setStatus(u, status)
+		updateOp.TrackOperation(operationTypeUpdate, op.Name)
+		return nil
 	}
 
-	return nil
+	status, err := SQLInstanceStatusGCPToKRM(a.actual)
+	if err != nil {
+		return fmt.Errorf("updating SQLInstance status failed: %w", err)
+	}
+	return setStatus(u, status)
 }
 
 // Delete implements the Adapter interface.
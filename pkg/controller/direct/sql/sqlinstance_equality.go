@@ -21,6 +21,10 @@ import (
 	api "google.golang.org/api/sqladmin/v1beta4"
 )
 
+// InstancesMatch compares the fields of the instances which are supported in
+// the KRM API. It doesn't use common.DiffProtoMessages: the sqladmin v1beta4
+// protos lag behind the REST API, e.g. they have no settings.edition, so a
+// conversion to them would drop fields.
 func InstancesMatch(desired *api.DatabaseInstance, actual *api.DatabaseInstance) bool {
 	if desired == nil && actual == nil {
 		return true
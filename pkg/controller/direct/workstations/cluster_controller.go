@@ -17,12 +17,12 @@ package workstations
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	refs "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/workstations/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/fuzztesting"
@@ -44,7 +44,7 @@ const (
 
 func init() {
 	registry.RegisterModel(krm.WorkstationClusterGVK, NewWorkstationClusterModel)
-	fuzztesting.RegisterKRMFuzzer(workstationclusterFuzzer())
+	fuzztesting.RegisterKRMFuzzer(krm.WorkstationClusterGVK, workstationclusterFuzzer())
 }
 
 func workstationclusterFuzzer() fuzztesting.KRMFuzzer {
@@ -250,11 +250,18 @@ func (a *Adapter) Update(ctx context.Context, updateOp *directbase.UpdateOperati
 	}
 	resource.Name = a.id.FullyQualifiedName()
 
+	diff, err := common.DiffProtoMessages(resource, a.actual,
+		common.WithFieldComparator("network", common.SameResourceName),
+		common.WithFieldComparator("subnetwork", common.SameResourceName))
+	if err != nil {
+		return err
+	}
+
 	updateMask := &fieldmaskpb.FieldMask{}
-	if !reflect.DeepEqual(resource.Annotations, a.actual.Annotations) {
+	if diff.HasField("annotations") {
 		updateMask.Paths = append(updateMask.Paths, "annotations")
 	}
-	if !reflect.DeepEqual(resource.Labels, a.actual.Labels) {
+	if diff.HasField("labels") {
 		updateMask.Paths = append(updateMask.Paths, "labels")
 	}
 
@@ -29,7 +29,6 @@ import (
 	gcp "cloud.google.com/go/workstations/apiv1"
 	pb "cloud.google.com/go/workstations/apiv1/workstationspb"
 	"google.golang.org/api/option"
-	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -40,7 +39,7 @@ import (
 
 func init() {
 	registry.RegisterModel(krm.WorkstationConfigGVK, NewWorkstationConfigModel)
-	fuzztesting.RegisterKRMFuzzer(workstationConfigFuzzer())
+	fuzztesting.RegisterKRMFuzzer(krm.WorkstationConfigGVK, workstationConfigFuzzer())
 }
 
 func workstationConfigFuzzer() fuzztesting.KRMFuzzer {
@@ -137,6 +136,7 @@ type WorkstationConfigAdapter struct {
 }
 
 var _ directbase.Adapter = &WorkstationConfigAdapter{}
+var _ directbase.Planner = &WorkstationConfigAdapter{}
 
 func (a *WorkstationConfigAdapter) Find(ctx context.Context) (bool, error) {
 	log := klog.FromContext(ctx)
@@ -205,28 +205,16 @@ func (a *WorkstationConfigAdapter) Update(ctx context.Context, updateOp *directb
 	log.V(2).Info("updating WorkstationConfig", "name", a.id.String())
 	mapCtx := &direct.MapContext{}
 
-	desired := a.desired.DeepCopy()
-
-	// Resolve references
-	if err := ResolveWorkstationConfigRefs(ctx, a.k8sClient, desired); err != nil {
+	resource, err := a.desiredForUpdate(ctx)
+	if err != nil {
 		return err
 	}
-	// Convert to proto
-	resource := WorkstationConfigSpec_ToProto(mapCtx, &desired.Spec)
-	ApplyWorkstationConfigGCPDefaults(mapCtx, &desired.Spec, resource, a.actual)
-	if mapCtx.Err() != nil {
-		return mapCtx.Err()
-	}
-
-	// Set name and etag manually, because they are not filled-in by WorkstationConfigSpec_ToProto.
-	resource.Name = a.id.String()
-	resource.Etag = a.actual.Etag
 
-	paths, err := common.CompareProtoMessage(resource, a.actual, common.BasicDiff)
+	diff, err := common.DiffProtoMessages(resource, a.actual)
 	if err != nil {
 		return err
 	}
-	if len(paths) == 0 {
+	if diff.IsEmpty() {
 		log.V(2).Info("no field needs update", "name", a.id.String())
 		status := &krm.WorkstationConfigStatus{}
 		status.ObservedState = WorkstationConfigObservedState_FromProto(mapCtx, a.actual)
@@ -235,9 +223,10 @@ func (a *WorkstationConfigAdapter) Update(ctx context.Context, updateOp *directb
 		}
 		return updateOp.UpdateStatus(ctx, status, nil)
 	}
+	log.V(2).Info("updating fields", "name", a.id.String(), "diff", diff.String())
 	req := &pb.UpdateWorkstationConfigRequest{
 		WorkstationConfig: resource,
-		UpdateMask:        &fieldmaskpb.FieldMask{Paths: sets.List(paths)},
+		UpdateMask:        diff.UpdateMask(),
 	}
 
 	op, err := a.gcpClient.UpdateWorkstationConfig(ctx, req)
@@ -258,6 +247,47 @@ func (a *WorkstationConfigAdapter) Update(ctx context.Context, updateOp *directb
 	return updateOp.UpdateStatus(ctx, status, nil)
 }
 
+// Plan implements the Planner interface.
+func (a *WorkstationConfigAdapter) Plan(ctx context.Context, planOp *directbase.PlanOperation) (*directbase.Plan, error) {
+	if a.actual == nil {
+		return &directbase.Plan{Action: directbase.PlanActionCreate}, nil
+	}
+
+	resource, err := a.desiredForUpdate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := common.DiffProtoMessages(resource, a.actual)
+	if err != nil {
+		return nil, err
+	}
+	return directbase.NewUpdatePlan(resource, a.actual, sets.List(diff.Paths())), nil
+}
+
+// desiredForUpdate builds the WorkstationConfig proto that Update would send to GCP.
+func (a *WorkstationConfigAdapter) desiredForUpdate(ctx context.Context) (*pb.WorkstationConfig, error) {
+	mapCtx := &direct.MapContext{}
+
+	desired := a.desired.DeepCopy()
+
+	// Resolve references
+	if err := ResolveWorkstationConfigRefs(ctx, a.k8sClient, desired); err != nil {
+		return nil, err
+	}
+	// Convert to proto
+	resource := WorkstationConfigSpec_ToProto(mapCtx, &desired.Spec)
+	ApplyWorkstationConfigGCPDefaults(mapCtx, &desired.Spec, resource, a.actual)
+	if mapCtx.Err() != nil {
+		return nil, mapCtx.Err()
+	}
+
+	// Set name and etag manually, because they are not filled-in by WorkstationConfigSpec_ToProto.
+	resource.Name = a.id.String()
+	resource.Etag = a.actual.Etag
+	return resource, nil
+}
+
 func (a *WorkstationConfigAdapter) Export(ctx context.Context) (*unstructured.Unstructured, error) {
 	if a.actual == nil {
 		return nil, fmt.Errorf("Find() not called")
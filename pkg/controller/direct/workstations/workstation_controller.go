@@ -18,12 +18,12 @@ import (
 	"context"
 	"fmt"
 
+	commonv1 "github.com/GoogleCloudPlatform/k8s-config-connector/apis/common"
 	krm "github.com/GoogleCloudPlatform/k8s-config-connector/apis/workstations/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
-	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/common"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/aip"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/directbase"
-	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/fuzztesting"
 
 	gcp "cloud.google.com/go/workstations/apiv1"
@@ -31,16 +31,19 @@ import (
 	"google.golang.org/api/option"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/sets"
-	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func init() {
-	registry.RegisterModel(krm.WorkstationGVK, NewWorkstationModel)
-	fuzztesting.RegisterKRMFuzzer(workstationFuzzer())
+	aip.Register(&aip.Resource[krm.Workstation, *workstationspb.Workstation]{
+		GVK:             krm.WorkstationGVK,
+		NewClient:       newWorkstationClient,
+		Identity:        workstationIdentity,
+		ToProto:         workstationToProto,
+		FromProto:       workstationFromProto,
+		StatusFromProto: workstationStatusFromProto,
+	})
+	fuzztesting.RegisterKRMFuzzer(krm.WorkstationGVK, workstationFuzzer())
 }
 
 func workstationFuzzer() fuzztesting.KRMFuzzer {
@@ -68,223 +71,93 @@ func workstationFuzzer() fuzztesting.KRMFuzzer {
 	return f
 }
 
-func NewWorkstationModel(ctx context.Context, config *config.ControllerConfig) (directbase.Model, error) {
-	return &modelWorkstation{config: *config}, nil
-}
-
-var _ directbase.Model = &modelWorkstation{}
-
-type modelWorkstation struct {
-	config config.ControllerConfig
-}
-
-func (m *modelWorkstation) client(ctx context.Context) (*gcp.Client, error) {
-	var opts []option.ClientOption
-	opts, err := m.config.RESTClientOptions()
+func workstationIdentity(ctx context.Context, reader client.Reader, obj *krm.Workstation) (aip.Identity, error) {
+	id, err := krm.NewWorkstationIdentity(ctx, reader, obj)
 	if err != nil {
 		return nil, err
 	}
-	gcpClient, err := gcp.NewRESTClient(ctx, opts...)
-	if err != nil {
-		return nil, fmt.Errorf("building Workstation client: %w", err)
-	}
-	return gcpClient, err
+	return id, nil
 }
 
-func (m *modelWorkstation) AdapterForObject(ctx context.Context, reader client.Reader, u *unstructured.Unstructured) (directbase.Adapter, error) {
-	obj := &krm.Workstation{}
-	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &obj); err != nil {
-		return nil, fmt.Errorf("error converting to %T: %w", obj, err)
-	}
-
-	id, err := krm.NewWorkstationIdentity(ctx, reader, obj)
-	if err != nil {
-		return nil, err
-	}
+func workstationToProto(mapCtx *direct.MapContext, obj *krm.Workstation) *workstationspb.Workstation {
+	return WorkstationSpec_ToProto(mapCtx, &obj.DeepCopy().Spec)
+}
 
-	// Get workstations GCP client
-	gcpClient, err := m.client(ctx)
-	if err != nil {
-		return nil, err
-	}
-	return &WorkstationAdapter{
-		id:        id,
-		gcpClient: gcpClient,
-		desired:   obj,
-	}, nil
+func workstationFromProto(mapCtx *direct.MapContext, id aip.Identity, actual *workstationspb.Workstation) *krm.Workstation {
+	obj := &krm.Workstation{}
+	obj.Spec = direct.ValueOf(WorkstationSpec_FromProto(mapCtx, actual))
+	obj.Spec.Parent = &krm.WorkstationConfigRef{External: id.(*krm.WorkstationIdentity).Parent().String()}
+	return obj
 }
 
-func (m *modelWorkstation) AdapterForURL(ctx context.Context, url string) (directbase.Adapter, error) {
-	// TODO: Support URLs
-	return nil, nil
+func workstationStatusFromProto(mapCtx *direct.MapContext, actual *workstationspb.Workstation) any {
+	status := &krm.WorkstationStatus{}
+	status.ObservedState = WorkstationObservedState_FromProto(mapCtx, actual)
+	return status
 }
 
-type WorkstationAdapter struct {
-	id        *krm.WorkstationIdentity
+// workstationClient calls the standard methods of Workstation.
+// Create and Update return a long-running operation, which is polled by later reconciliations.
+type workstationClient struct {
 	gcpClient *gcp.Client
-	desired   *krm.Workstation
-	actual    *workstationspb.Workstation
 }
 
-var _ directbase.Adapter = &WorkstationAdapter{}
-
-// Find retrieves the GCP resource.
-// Return true means the object is found. This triggers Adapter `Update` call.
-// Return false means the object is not found. This triggers Adapter `Create` call.
-// Return a non-nil error requeues the requests.
-func (a *WorkstationAdapter) Find(ctx context.Context) (bool, error) {
-	log := klog.FromContext(ctx)
-	log.V(2).Info("getting Workstation", "name", a.id)
+var _ aip.Client[*workstationspb.Workstation] = &workstationClient{}
+var _ aip.OperationClient = &workstationClient{}
 
-	req := &workstationspb.GetWorkstationRequest{Name: a.id.String()}
-	workstationpb, err := a.gcpClient.GetWorkstation(ctx, req)
+func newWorkstationClient(ctx context.Context, config *config.ControllerConfig) (aip.Client[*workstationspb.Workstation], error) {
+	var opts []option.ClientOption
+	opts, err := config.RESTClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	gcpClient, err := gcp.NewRESTClient(ctx, opts...)
 	if err != nil {
-		if direct.IsNotFound(err) {
-			return false, nil
-		}
-		return false, fmt.Errorf("getting Workstation %q: %w", a.id, err)
+		return nil, fmt.Errorf("building Workstation client: %w", err)
 	}
-
-	a.actual = workstationpb
-	return true, nil
+	return &workstationClient{gcpClient: gcpClient}, nil
 }
 
-// Create creates the resource in GCP based on `spec` and update the Config Connector object `status` based on the GCP response.
-func (a *WorkstationAdapter) Create(ctx context.Context, createOp *directbase.CreateOperation) error {
-	log := klog.FromContext(ctx)
-	log.V(2).Info("creating Workstation", "name", a.id)
-	mapCtx := &direct.MapContext{}
-
-	desired := a.desired.DeepCopy()
-	resource := WorkstationSpec_ToProto(mapCtx, &desired.Spec)
-	if mapCtx.Err() != nil {
-		return mapCtx.Err()
-	}
-
-	// Set name manually, because it is not filled-in by WorkstationSpec_ToProto.
-	resource.Name = a.id.String()
+func (c *workstationClient) Get(ctx context.Context, name string) (*workstationspb.Workstation, error) {
+	req := &workstationspb.GetWorkstationRequest{Name: name}
+	return c.gcpClient.GetWorkstation(ctx, req)
+}
 
+func (c *workstationClient) Create(ctx context.Context, parent, id string, resource *workstationspb.Workstation) (*aip.Result[*workstationspb.Workstation], error) {
 	req := &workstationspb.CreateWorkstationRequest{
-		Parent:        a.id.Parent().String(),
-		WorkstationId: a.id.ID(),
+		Parent:        parent,
+		WorkstationId: id,
 		Workstation:   resource,
 	}
-	op, err := a.gcpClient.CreateWorkstation(ctx, req)
-	if err != nil {
-		return fmt.Errorf("creating Workstation %s: %w", a.id, err)
-	}
-	created, err := op.Wait(ctx)
-	if err != nil {
-		return fmt.Errorf("waiting Workstation %s creation: %w", a.id.String(), err)
-	}
-	log.V(2).Info("successfully created Workstation", "name", a.id)
-
-	status := &krm.WorkstationStatus{}
-	status.ObservedState = WorkstationObservedState_FromProto(mapCtx, created)
-	if mapCtx.Err() != nil {
-		return mapCtx.Err()
-	}
-	status.ExternalRef = direct.PtrTo(a.id.String())
-	return createOp.UpdateStatus(ctx, status, nil)
+	op, err := c.gcpClient.CreateWorkstation(ctx, req)
+	return aip.Started[*workstationspb.Workstation](op, err)
 }
 
-// Update updates the resource in GCP based on `spec` and update the Config Connector object `status` based on the GCP response.
-func (a *WorkstationAdapter) Update(ctx context.Context, updateOp *directbase.UpdateOperation) error {
-	log := klog.FromContext(ctx)
-	log.V(2).Info("updating Workstation", "name", a.id)
-	mapCtx := &direct.MapContext{}
-
-	desiredPb := WorkstationSpec_ToProto(mapCtx, &a.desired.DeepCopy().Spec)
-	if mapCtx.Err() != nil {
-		return mapCtx.Err()
-	}
-
-	// Set name and etag manually, because they are not filled-in by WorkstationConfigSpec_ToProto.
-	desiredPb.Name = a.id.String()
-	desiredPb.Etag = a.actual.Etag
-
-	paths, err := common.CompareProtoMessage(desiredPb, a.actual, common.BasicDiff)
-	if err != nil {
-		return err
-	}
-	if len(paths) == 0 {
-		log.V(2).Info("no field needs update", "name", a.id.String())
-		status := &krm.WorkstationStatus{}
-		status.ObservedState = WorkstationObservedState_FromProto(mapCtx, a.actual)
-		if mapCtx.Err() != nil {
-			return mapCtx.Err()
-		}
-		return updateOp.UpdateStatus(ctx, status, nil)
-	}
-	updateMask := &fieldmaskpb.FieldMask{Paths: sets.List(paths)}
-
+func (c *workstationClient) Update(ctx context.Context, resource *workstationspb.Workstation, updateMask *fieldmaskpb.FieldMask) (*aip.Result[*workstationspb.Workstation], error) {
 	req := &workstationspb.UpdateWorkstationRequest{
-		Workstation: desiredPb,
+		Workstation: resource,
 		UpdateMask:  updateMask,
 	}
-	op, err := a.gcpClient.UpdateWorkstation(ctx, req)
-	if err != nil {
-		return fmt.Errorf("updating Workstation %s: %w", a.id.String(), err)
-	}
-	updated, err := op.Wait(ctx)
-	if err != nil {
-		return fmt.Errorf("waiting Workstation %s update: %w", a.id.String(), err)
-	}
-	log.V(2).Info("successfully updated Workstation", "name", a.id.String())
-
-	status := &krm.WorkstationStatus{}
-	status.ObservedState = WorkstationObservedState_FromProto(mapCtx, updated)
-	if mapCtx.Err() != nil {
-		return mapCtx.Err()
-	}
-	return updateOp.UpdateStatus(ctx, status, nil)
+	op, err := c.gcpClient.UpdateWorkstation(ctx, req)
+	return aip.Started[*workstationspb.Workstation](op, err)
 }
 
-// Export maps the GCP object to a Config Connector resource `spec`.
-func (a *WorkstationAdapter) Export(ctx context.Context) (*unstructured.Unstructured, error) {
-	if a.actual == nil {
-		return nil, fmt.Errorf("Find() not called")
-	}
-	u := &unstructured.Unstructured{}
-
-	obj := &krm.Workstation{}
-	mapCtx := &direct.MapContext{}
-	obj.Spec = direct.ValueOf(WorkstationSpec_FromProto(mapCtx, a.actual))
-	if mapCtx.Err() != nil {
-		return nil, mapCtx.Err()
-	}
-	obj.Spec.Parent = &krm.WorkstationConfigRef{External: a.id.Parent().String()}
-	uObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
-	if err != nil {
-		return nil, err
-	}
-
-	u.SetName(a.id.ID())
-	u.SetGroupVersionKind(krm.WorkstationGVK)
-
-	u.Object = uObj
-	return u, nil
+func (c *workstationClient) Delete(ctx context.Context, name string) error {
+	req := &workstationspb.DeleteWorkstationRequest{Name: name}
+	op, err := c.gcpClient.DeleteWorkstation(ctx, req)
+	_, err = aip.WaitOperation(ctx, op, err)
+	return err
 }
 
-// Delete the resource from GCP service when the corresponding Config Connector resource is deleted.
-func (a *WorkstationAdapter) Delete(ctx context.Context, deleteOp *directbase.DeleteOperation) (bool, error) {
-	log := klog.FromContext(ctx)
-	log.V(2).Info("deleting Workstation", "name", a.id)
-
-	req := &workstationspb.DeleteWorkstationRequest{Name: a.id.String()}
-	op, err := a.gcpClient.DeleteWorkstation(ctx, req)
-	if err != nil {
-		if direct.IsNotFound(err) {
-			// Return success if workstation is not found (assume it was already deleted).
-			return true, nil
-		}
-		return false, fmt.Errorf("deleting Workstation %s: %w", a.id, err)
-	}
-	log.V(2).Info("successfully deleted Workstation", "name", a.id)
-
-	_, err = op.Wait(ctx)
-	if err != nil {
-		return false, fmt.Errorf("waiting delete Workstation %s: %w", a.id, err)
+// PollOperation implements the OperationClient interface.
+func (c *workstationClient) PollOperation(ctx context.Context, op *commonv1.PendingOperation) (bool, error) {
+	switch op.Type {
+	case aip.OperationTypeCreate:
+		return directbase.PollGAPICOperation(ctx, c.gcpClient.CreateWorkstationOperation(op.Name))
+	case aip.OperationTypeUpdate:
+		return directbase.PollGAPICOperation(ctx, c.gcpClient.UpdateWorkstationOperation(op.Name))
+	default:
+		// We cannot poll the operation, so fall back to Find.
+		return true, fmt.Errorf("unknown type %q of operation %s", op.Type, op.Name)
 	}
-	return true, nil
 }
@@ -190,7 +190,7 @@ func newTestReconciler(t *testing.T, mgr manager.Manager, crdPath string, provid
 	var resourceWatcherRoutines *semaphore.Weighted = nil
 
 	stateIntoSpecDefaulter := k8s.NewStateIntoSpecDefaulter(mgr.GetClient())
-	reconciler, err := tf.NewReconciler(mgr, crd, provider, smLoader, immediateReconcileRequests, resourceWatcherRoutines, []k8s.Defaulter{stateIntoSpecDefaulter}, &testjitter.TestJitterGenerator{})
+	reconciler, err := tf.NewReconciler(mgr, crd, provider, smLoader, immediateReconcileRequests, resourceWatcherRoutines, []k8s.Defaulter{stateIntoSpecDefaulter}, &testjitter.TestJitterGenerator{}, nil)
 	if err != nil {
 		t.Fatalf("error creating reconciler: %v", err)
 	}
@@ -30,12 +30,14 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/lifecyclehandler"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/metrics"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/predicate"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/priorityqueue"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/ratelimiter"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourceactuation"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourcewatcher"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/conversion"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/metadata"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/execution"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/credentials"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/util"
@@ -102,14 +104,14 @@ func NewReconciler(mgr manager.Manager, provider *tfschema.Provider, smLoader *s
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler.
 func add(mgr manager.Manager, r *Reconciler) error {
-	obj := &iamv1beta1.IAMAuditConfig{}
+	gate := priorityqueue.NewGate(iamv1beta1.IAMAuditConfigGVK, k8s.ControllerMaxConcurrentReconciles)
 	_, err := builder.
 		ControllerManagedBy(mgr).
 		Named(controllerName).
 		WithOptions(controller.Options{MaxConcurrentReconciles: k8s.ControllerMaxConcurrentReconciles, RateLimiter: ratelimiter.NewRateLimiter()}).
-		WatchesRawSource(&source.Channel{Source: r.immediateReconcileRequests}, &handler.EnqueueRequestForObject{}).
-		For(obj, builder.OnlyMetadata, builder.WithPredicates(predicate.UnderlyingResourceOutOfSyncPredicate{})).
-		Build(r)
+		WatchesRawSource(gate.Source(&source.Channel{Source: r.immediateReconcileRequests}), &handler.EnqueueRequestForObject{}).
+		WatchesRawSource(gate.Kind(mgr.GetCache(), iamv1beta1.IAMAuditConfigGVK), &handler.EnqueueRequestForObject{}, builder.WithPredicates(predicate.UnderlyingResourceOutOfSyncPredicate{})).
+		Build(gate.Reconciler(r))
 	if err != nil {
 		return fmt.Errorf("error creating new controller: %w", err)
 	}
@@ -137,6 +139,8 @@ type reconcileContext struct {
 	Reconciler     *Reconciler
 	Ctx            context.Context
 	NamespacedName types.NamespacedName
+	// actuation is the actuation decision made by doReconcile.
+	actuation resourceactuation.ActuationDecision
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (result reconcile.Result, err error) {
@@ -144,6 +148,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	startTime := time.Now()
 	ctx, cancel := context.WithTimeout(ctx, k8s.ReconcileDeadline)
 	defer cancel()
+	ctx = credentials.WithKind(ctx, iamv1beta1.IAMAuditConfigGVK.Kind)
 	r.RecordReconcileWorkers(ctx, iamv1beta1.IAMAuditConfigGVK)
 	defer r.AfterReconcile()
 	defer r.RecordReconcileMetrics(ctx, iamv1beta1.IAMAuditConfigGVK, request.Namespace, request.Name, startTime, &err)
@@ -167,6 +172,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		Ctx:            ctx,
 		NamespacedName: request.NamespacedName,
 	}
+	if k8s.HasReconcilePausedAnnotation(&auditConfig) {
+		logger.Info("Skipping reconcile as the resource has the reconcile-paused annotation", "resource", request.NamespacedName)
+		return reconcile.Result{}, reconcileContext.handlePaused(&auditConfig)
+	}
 	requeue, err := reconcileContext.doReconcile(&auditConfig)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -178,8 +187,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	logger.Info("successfully finished reconcile", "resource", request.NamespacedName, "time to next reconciliation", jitteredPeriod)
-	return reconcile.Result{RequeueAfter: jitteredPeriod}, nil
+	requeueAfter := reconcileContext.actuation.RequeueAfter(jitteredPeriod, time.Now())
+	logger.Info("successfully finished reconcile", "resource", request.NamespacedName, "time to next reconciliation", requeueAfter)
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *Reconciler) handleDefaults(ctx context.Context, auditConfig *iamv1beta1.IAMAuditConfig) error {
@@ -199,9 +209,21 @@ func (r *reconcileContext) doReconcile(auditConfig *iamv1beta1.IAMAuditConfig) (
 		return true, err
 	}
 
-	am := resourceactuation.DecideActuationMode(cc, ccc)
-	switch am {
+	decision, err := resourceactuation.DecideActuationMode(cc, ccc, time.Now())
+	if err != nil {
+		return false, err
+	}
+	r.actuation = decision
+	switch am := decision.Mode; am {
 	case v1beta1.Reconciling:
+		if decision.PendingActuationWindow {
+			ensureFinalizers := func() error {
+				k8s.EnsureFinalizers(auditConfig, k8s.ControllerFinalizerName, k8s.DeletionDefenderFinalizerName)
+				return nil
+			}
+			toResource := func() (*k8s.Resource, error) { return ToK8sResource(auditConfig) }
+			return false, decision.HandlePendingActuationWindow(r.Ctx, r.Reconciler, auditConfig, ensureFinalizers, toResource)
+		}
 		logger.V(2).Info("Actuating a resource as actuation mode is \"Reconciling\"", "resource", r.NamespacedName)
 	case v1beta1.Paused:
 		logger.Info("Skipping actuation of resource as actuation mode is \"Paused\"", "resource", r.NamespacedName)
@@ -258,14 +280,17 @@ func (r *reconcileContext) doReconcile(auditConfig *iamv1beta1.IAMAuditConfig) (
 			return false, r.handleUpdateFailed(auditConfig, err)
 		}
 	}
-	if _, err := r.Reconciler.iamClient.SetAuditConfig(r.Ctx, auditConfig); err != nil {
+	appliedAuditConfig, err := r.Reconciler.iamClient.SetAuditConfig(r.Ctx, auditConfig)
+	if err != nil {
 		if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
 			logger.Info(unwrappedErr.Error(), "resource", k8s.GetNamespacedName(auditConfig))
 			return r.handleUnresolvableDeps(auditConfig, unwrappedErr)
 		}
 		return false, r.handleUpdateFailed(auditConfig, fmt.Errorf("error setting audit config: %w", err))
 	}
-	if isAPIServerUpdateRequired(auditConfig) {
+	etag := appliedAuditConfig.Status.Etag
+	if isAPIServerUpdateRequired(auditConfig, etag) {
+		auditConfig.Status.Etag = etag
 		return false, r.handleUpToDate(auditConfig)
 	}
 	return false, nil
@@ -286,6 +311,14 @@ func (r *reconcileContext) handleUpToDate(auditConfig *iamv1beta1.IAMAuditConfig
 	return r.Reconciler.HandleUpToDate(r.Ctx, resource)
 }
 
+func (r *reconcileContext) handlePaused(auditConfig *iamv1beta1.IAMAuditConfig) error {
+	resource, err := ToK8sResource(auditConfig)
+	if err != nil {
+		return fmt.Errorf("error converting IAMAuditConfig to k8s resource while handling %v event: %w", k8s.Paused, err)
+	}
+	return r.Reconciler.HandlePaused(r.Ctx, resource)
+}
+
 func (r *reconcileContext) handleUpdateFailed(auditConfig *iamv1beta1.IAMAuditConfig, origErr error) error {
 	resource, err := ToK8sResource(auditConfig)
 	if err != nil {
@@ -388,7 +421,7 @@ func (r *Reconciler) enqueueForImmediateReconciliation(resourceNN types.Namespac
 	r.immediateReconcileRequests <- genEvent
 }
 
-func isAPIServerUpdateRequired(auditConfig *iamv1beta1.IAMAuditConfig) bool {
+func isAPIServerUpdateRequired(auditConfig *iamv1beta1.IAMAuditConfig, etag string) bool {
 	// TODO: even in the event of an actual update to GCP, this function will
 	// return false because the condition comparison doesn't account for time.
 	conditions := []condition.Condition{
@@ -400,6 +433,9 @@ func isAPIServerUpdateRequired(auditConfig *iamv1beta1.IAMAuditConfig) bool {
 	if auditConfig.Status.ObservedGeneration != auditConfig.GetGeneration() {
 		return true
 	}
+	if auditConfig.Status.Etag != etag {
+		return true
+	}
 	return false
 }
 
@@ -30,6 +30,7 @@ func TestIsAPIServerUpdateRequired(t *testing.T) {
 	tests := []struct {
 		name           string
 		auditConfig    *iamv1beta1.IAMAuditConfig
+		etag           string
 		expectedResult bool
 	}{
 		{
@@ -86,12 +87,29 @@ func TestIsAPIServerUpdateRequired(t *testing.T) {
 			},
 			expectedResult: false,
 		},
+		{
+			name: "conditions and observed generation are up to date, etag is stale",
+			auditConfig: &iamv1beta1.IAMAuditConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 2,
+				},
+				Status: iamv1beta1.IAMAuditConfigStatus{
+					Conditions: []condition.Condition{
+						k8s.NewCustomReadyCondition(corev1.ConditionTrue, k8s.UpToDate, k8s.UpToDateMessage),
+					},
+					ObservedGeneration: 2,
+					Etag:               "BwXhqDmkuR0=",
+				},
+			},
+			etag:           "BwXhqDuy4Ys=",
+			expectedResult: true,
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			actual := isAPIServerUpdateRequired(tc.auditConfig)
+			actual := isAPIServerUpdateRequired(tc.auditConfig, tc.etag)
 			if actual != tc.expectedResult {
 				t.Fatalf("got %v, want %v", actual, tc.expectedResult)
 			}
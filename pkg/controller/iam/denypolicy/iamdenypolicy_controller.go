@@ -0,0 +1,147 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package denypolicy
+
+import (
+	"context"
+	"fmt"
+
+	iamv1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/iam/v1beta1"
+	condition "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/k8s/v1alpha1"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
+	kontroller "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/iam/iambase"
+	kcciamclient "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/iam/iamclient"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/jitter"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/conversion"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/metadata"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/util"
+
+	iamv2pb "cloud.google.com/go/iam/apiv2/iampb"
+	mmdcl "github.com/GoogleCloudPlatform/declarative-resource-client-library/dcl"
+	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/sync/semaphore"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const controllerName = "iamdenypolicy-controller"
+
+// Add creates a new IAM Deny Policy Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and start it when the Manager is started.
+func Add(mgr manager.Manager, deps *kontroller.Deps, config *config.ControllerConfig) error {
+	if deps.JitterGen == nil {
+		var dclML metadata.ServiceMetadataLoader
+		if deps.DclConverter != nil {
+			dclML = deps.DclConverter.MetadataLoader
+		}
+		deps.JitterGen = jitter.NewDefaultGenerator(deps.TfLoader, dclML)
+	}
+
+	immediateReconcileRequests := make(chan event.GenericEvent, k8s.ImmediateReconcileRequestsBufferSize)
+	resourceWatcherRoutines := semaphore.NewWeighted(k8s.MaxNumResourceWatcherRoutines)
+	reconciler, err := NewReconciler(mgr, deps.TfProvider, deps.TfLoader, deps.DclConverter, deps.DclConfig, config, immediateReconcileRequests, resourceWatcherRoutines, deps.Defaulters, deps.JitterGen)
+	if err != nil {
+		return err
+	}
+	return iambase.Add(mgr, controllerName, reconciler)
+}
+
+// ReconcileIAMDenyPolicy is a reconciler for handling IAM deny policies.
+type ReconcileIAMDenyPolicy = iambase.Reconciler[*iamv1beta1.IAMDenyPolicy]
+
+// NewReconciler returns a new reconcile.Reconciler.
+func NewReconciler(mgr manager.Manager, provider *tfschema.Provider, smLoader *servicemappingloader.ServiceMappingLoader, converter *conversion.Converter, dclConfig *mmdcl.Config, controllerConfig *config.ControllerConfig, immediateReconcileRequests chan event.GenericEvent, resourceWatcherRoutines *semaphore.Weighted, defaulters []k8s.Defaulter, jg jitter.Generator) (*ReconcileIAMDenyPolicy, error) {
+	iamClient := kcciamclient.New(provider, smLoader, mgr.GetClient(), converter, dclConfig)
+	adapter := &denyPolicyAdapter{
+		denyPolicyClient: kcciamclient.NewDenyPolicyClient(iamClient, controllerConfig),
+	}
+	newObject := func() *iamv1beta1.IAMDenyPolicy { return &iamv1beta1.IAMDenyPolicy{} }
+	return iambase.NewReconciler(mgr, controllerName, iamv1beta1.IAMDenyPolicyGVK, newObject, adapter, immediateReconcileRequests, resourceWatcherRoutines, defaulters, jg), nil
+}
+
+// denyPolicyClient is the subset of kcciamclient.DenyPolicyClient used by the controller.
+type denyPolicyClient interface {
+	ResolveDenyPolicyName(ctx context.Context, denyPolicy *iamv1beta1.IAMDenyPolicy) (string, error)
+	SetDenyPolicy(ctx context.Context, denyPolicy *iamv1beta1.IAMDenyPolicy) (*iamv2pb.Policy, error)
+	DeleteDenyPolicy(ctx context.Context, denyPolicy *iamv1beta1.IAMDenyPolicy) error
+}
+
+// denyPolicyAdapter applies IAMDenyPolicies to GCP with the IAM v2 API.
+type denyPolicyAdapter struct {
+	denyPolicyClient denyPolicyClient
+}
+
+var _ iambase.Adapter[*iamv1beta1.IAMDenyPolicy] = &denyPolicyAdapter{}
+
+func (a *denyPolicyAdapter) Get(ctx context.Context, denyPolicy *iamv1beta1.IAMDenyPolicy) error {
+	// Only the references are resolved here: Set reads the live deny policy, whose
+	// etag it carries in the update, and creates the deny policy if it does not exist.
+	_, err := a.denyPolicyClient.ResolveDenyPolicyName(ctx, denyPolicy)
+	return err
+}
+
+func (a *denyPolicyAdapter) Set(ctx context.Context, denyPolicy *iamv1beta1.IAMDenyPolicy) (bool, error) {
+	appliedDenyPolicy, err := a.denyPolicyClient.SetDenyPolicy(ctx, denyPolicy)
+	if err != nil {
+		return false, fmt.Errorf("error setting deny policy: %w", err)
+	}
+	etag := appliedDenyPolicy.GetEtag()
+	uid := appliedDenyPolicy.GetUid()
+	if !isAPIServerUpdateRequired(denyPolicy, etag, uid) {
+		return false, nil
+	}
+	denyPolicy.Status.Etag = etag
+	denyPolicy.Status.UID = uid
+	return true, nil
+}
+
+func (a *denyPolicyAdapter) Delete(ctx context.Context, denyPolicy *iamv1beta1.IAMDenyPolicy) error {
+	return a.denyPolicyClient.DeleteDenyPolicy(ctx, denyPolicy)
+}
+
+func (a *denyPolicyAdapter) ToK8sResource(denyPolicy *iamv1beta1.IAMDenyPolicy) (*k8s.Resource, error) {
+	return toK8sResource(denyPolicy)
+}
+
+func isAPIServerUpdateRequired(denyPolicy *iamv1beta1.IAMDenyPolicy, etag, uid string) bool {
+	// TODO: even in the event of an actual update to GCP, this function will
+	// return false because the condition comparison doesn't account for time.
+	conditions := []condition.Condition{
+		k8s.NewCustomReadyCondition(corev1.ConditionTrue, k8s.UpToDate, k8s.UpToDateMessage),
+	}
+	if !k8s.ConditionSlicesEqual(denyPolicy.Status.Conditions, conditions) {
+		return true
+	}
+	if denyPolicy.Status.ObservedGeneration != denyPolicy.GetGeneration() {
+		return true
+	}
+	if denyPolicy.Status.Etag != etag || denyPolicy.Status.UID != uid {
+		return true
+	}
+	return false
+}
+
+func toK8sResource(denyPolicy *iamv1beta1.IAMDenyPolicy) (*k8s.Resource, error) {
+	kcciamclient.SetGVK(denyPolicy)
+	resource := k8s.Resource{}
+	if err := util.Marshal(denyPolicy, &resource); err != nil {
+		return nil, fmt.Errorf("error marshalling IAMDenyPolicy to k8s resource: %w", err)
+	}
+	return &resource, nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package denypolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	iamv1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/iam/v1beta1"
+	condition "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/k8s/v1alpha1"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+
+	iamv2pb "cloud.google.com/go/iam/apiv2/iampb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsAPIServerUpdateRequired(t *testing.T) {
+	t.Parallel()
+	upToDate := []condition.Condition{
+		k8s.NewCustomReadyCondition(corev1.ConditionTrue, k8s.UpToDate, k8s.UpToDateMessage),
+	}
+	tests := []struct {
+		name           string
+		denyPolicy     *iamv1beta1.IAMDenyPolicy
+		etag           string
+		uid            string
+		expectedResult bool
+	}{
+		{
+			name: "no previous conditions and observed generation",
+			denyPolicy: &iamv1beta1.IAMDenyPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 1,
+				},
+			},
+			expectedResult: true,
+		},
+		{
+			name: "conditions are up to date, observed generation is stale",
+			denyPolicy: &iamv1beta1.IAMDenyPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 2,
+				},
+				Status: iamv1beta1.IAMDenyPolicyStatus{
+					Conditions:         upToDate,
+					ObservedGeneration: 1,
+				},
+			},
+			expectedResult: true,
+		},
+		{
+			name: "conditions and observed generation are up to date, etag is stale",
+			denyPolicy: &iamv1beta1.IAMDenyPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 2,
+				},
+				Status: iamv1beta1.IAMDenyPolicyStatus{
+					Conditions:         upToDate,
+					ObservedGeneration: 2,
+					Etag:               "W/\"1\"",
+					UID:                "uid",
+				},
+			},
+			etag:           "W/\"2\"",
+			uid:            "uid",
+			expectedResult: true,
+		},
+		{
+			name: "conditions, observed generation, etag and uid are up to date",
+			denyPolicy: &iamv1beta1.IAMDenyPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 2,
+				},
+				Status: iamv1beta1.IAMDenyPolicyStatus{
+					Conditions:         upToDate,
+					ObservedGeneration: 2,
+					Etag:               "W/\"2\"",
+					UID:                "uid",
+				},
+			},
+			etag:           "W/\"2\"",
+			uid:            "uid",
+			expectedResult: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			actual := isAPIServerUpdateRequired(tc.denyPolicy, tc.etag, tc.uid)
+			if actual != tc.expectedResult {
+				t.Fatalf("got %v, want %v", actual, tc.expectedResult)
+			}
+		})
+	}
+}
+
+type fakeDenyPolicyClient struct {
+	resolveErr error
+	applied    *iamv2pb.Policy
+	sets       int
+}
+
+func (c *fakeDenyPolicyClient) ResolveDenyPolicyName(_ context.Context, _ *iamv1beta1.IAMDenyPolicy) (string, error) {
+	if c.resolveErr != nil {
+		return "", c.resolveErr
+	}
+	return "policies/cloudresourcemanager.googleapis.com%2Fprojects%2Fmy-project/denypolicies/my-policy", nil
+}
+
+func (c *fakeDenyPolicyClient) SetDenyPolicy(_ context.Context, _ *iamv1beta1.IAMDenyPolicy) (*iamv2pb.Policy, error) {
+	c.sets++
+	return c.applied, nil
+}
+
+func (c *fakeDenyPolicyClient) DeleteDenyPolicy(_ context.Context, _ *iamv1beta1.IAMDenyPolicy) error {
+	return nil
+}
+
+func TestDenyPolicyAdapter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Get only resolves the references", func(t *testing.T) {
+		client := &fakeDenyPolicyClient{}
+		adapter := &denyPolicyAdapter{denyPolicyClient: client}
+		if err := adapter.Get(ctx, &iamv1beta1.IAMDenyPolicy{}); err != nil {
+			t.Fatalf("Get() error = %v, want nil", err)
+		}
+		if client.sets != 0 {
+			t.Fatalf("got %v calls to SetDenyPolicy, want none", client.sets)
+		}
+	})
+
+	t.Run("errors resolving the references are returned by Get", func(t *testing.T) {
+		adapter := &denyPolicyAdapter{denyPolicyClient: &fakeDenyPolicyClient{resolveErr: errors.New("boom")}}
+		if err := adapter.Get(ctx, &iamv1beta1.IAMDenyPolicy{}); err == nil {
+			t.Fatalf("Get() error = nil, want an error")
+		}
+	})
+
+	t.Run("Set records the applied etag and uid", func(t *testing.T) {
+		applied := &iamv2pb.Policy{Etag: "W/\"2\"", Uid: "uid"}
+		adapter := &denyPolicyAdapter{denyPolicyClient: &fakeDenyPolicyClient{applied: applied}}
+		denyPolicy := &iamv1beta1.IAMDenyPolicy{}
+		updateRequired, err := adapter.Set(ctx, denyPolicy)
+		if err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if !updateRequired {
+			t.Fatalf("Set() = false, want true")
+		}
+		if denyPolicy.Status.Etag != applied.Etag || denyPolicy.Status.UID != applied.Uid {
+			t.Fatalf("got status etag %q and uid %q, want %q and %q", denyPolicy.Status.Etag, denyPolicy.Status.UID, applied.Etag, applied.Uid)
+		}
+	})
+}
@@ -0,0 +1,408 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package iambase is the reconcile loop shared by the IAM controllers which apply a
+// whole IAM resource to GCP, e.g. IAMPolicy and IAMDenyPolicy. The GCP operations
+// specific to each kind are implemented by an Adapter.
+package iambase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/v1beta1"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/kccstate"
+	kcciamclient "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/iam/iamclient"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/jitter"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/lifecyclehandler"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/metrics"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/predicate"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/priorityqueue"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/ratelimiter"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourceactuation"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourcewatcher"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/execution"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/credentials"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/sync/semaphore"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	klog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Adapter performs the GCP operations of one IAM kind for the Reconciler.
+type Adapter[T client.Object] interface {
+	// Get reads the underlying GCP resource of obj, which checks that the references
+	// of obj can be resolved before the finalizers are added.
+	Get(ctx context.Context, obj T) error
+	// Set applies obj to GCP and records the applied state, e.g. the etag, in the status
+	// of obj. It returns whether the status of obj must be updated in the API server.
+	Set(ctx context.Context, obj T) (statusUpdateRequired bool, err error)
+	// Delete deletes the underlying GCP resource of obj. It returns
+	// kcciamclient.ErrNotFound if the underlying GCP resource does not exist.
+	Delete(ctx context.Context, obj T) error
+	// ToK8sResource converts obj to the k8s resource on which the lifecycle events are surfaced.
+	ToK8sResource(obj T) (*k8s.Resource, error)
+}
+
+// Reconciler reconciles the IAM resources of one kind.
+type Reconciler[T client.Object] struct {
+	lifecyclehandler.LifecycleHandler
+	client.Client
+	metrics.ReconcilerMetrics
+	gvk        schema.GroupVersionKind
+	newObject  func() T
+	adapter    Adapter[T]
+	logger     logr.Logger
+	config     *rest.Config
+	defaulters []k8s.Defaulter
+	// Fields used for triggering reconciliations when dependencies are ready
+	immediateReconcileRequests chan event.GenericEvent
+	resourceWatcherRoutines    *semaphore.Weighted // Used to cap number of goroutines watching unready dependencies
+
+	jitterGen jitter.Generator
+}
+
+var _ reconcile.Reconciler = &Reconciler[client.Object]{}
+
+// NewReconciler returns a new Reconciler for the IAM resources of the given GVK. newObject
+// returns an empty object of that kind.
+func NewReconciler[T client.Object](mgr manager.Manager, controllerName string, gvk schema.GroupVersionKind, newObject func() T, adapter Adapter[T],
+	immediateReconcileRequests chan event.GenericEvent, resourceWatcherRoutines *semaphore.Weighted, defaulters []k8s.Defaulter, jg jitter.Generator) *Reconciler[T] {
+	return &Reconciler[T]{
+		LifecycleHandler: lifecyclehandler.NewLifecycleHandler(
+			mgr.GetClient(),
+			mgr.GetEventRecorderFor(controllerName),
+		),
+		Client:                     mgr.GetClient(),
+		gvk:                        gvk,
+		newObject:                  newObject,
+		adapter:                    adapter,
+		logger:                     klog.Log.WithName(controllerName),
+		config:                     mgr.GetConfig(),
+		defaulters:                 defaulters,
+		immediateReconcileRequests: immediateReconcileRequests,
+		resourceWatcherRoutines:    resourceWatcherRoutines,
+		jitterGen:                  jg,
+	}
+}
+
+// Add adds a new Controller to mgr with r as the reconcile.Reconciler.
+func Add[T client.Object](mgr manager.Manager, controllerName string, r *Reconciler[T]) error {
+	gate := priorityqueue.NewGate(r.gvk, k8s.ControllerMaxConcurrentReconciles)
+	_, err := builder.
+		ControllerManagedBy(mgr).
+		Named(controllerName).
+		WithOptions(controller.Options{MaxConcurrentReconciles: k8s.ControllerMaxConcurrentReconciles, RateLimiter: ratelimiter.NewRateLimiter()}).
+		WatchesRawSource(gate.Source(&source.Channel{Source: r.immediateReconcileRequests}), &handler.EnqueueRequestForObject{}).
+		WatchesRawSource(gate.Kind(mgr.GetCache(), r.gvk), &handler.EnqueueRequestForObject{}, builder.WithPredicates(predicate.UnderlyingResourceOutOfSyncPredicate{})).
+		Build(gate.Reconciler(r))
+	if err != nil {
+		return fmt.Errorf("error creating new controller: %w", err)
+	}
+	return nil
+}
+
+type reconcileContext[T client.Object] struct {
+	Reconciler     *Reconciler[T]
+	Ctx            context.Context
+	NamespacedName types.NamespacedName
+	// actuation is the actuation decision made by doReconcile.
+	actuation resourceactuation.ActuationDecision
+}
+
+// Reconcile checks k8s for the current state of the resource.
+func (r *Reconciler[T]) Reconcile(ctx context.Context, request reconcile.Request) (result reconcile.Result, err error) {
+	logger := r.logger
+	logger.Info("Running reconcile", "resource", request.NamespacedName)
+	startTime := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, k8s.ReconcileDeadline)
+	defer cancel()
+	ctx = credentials.WithKind(ctx, r.gvk.Kind)
+	r.RecordReconcileWorkers(ctx, r.gvk)
+	defer r.AfterReconcile()
+	defer r.RecordReconcileMetrics(ctx, r.gvk, request.Namespace, request.Name, startTime, &err)
+
+	obj := r.newObject()
+	if err := r.Get(ctx, request.NamespacedName, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Object not found, return.  Created objects are automatically garbage collected.
+			// For additional cleanup logic use finalizers.
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+	// r.Get() overrides the TypeMeta to empty value, so need to configure it
+	// after r.Get().
+	obj.GetObjectKind().SetGroupVersionKind(r.gvk)
+	if err := r.handleDefaults(ctx, obj); err != nil {
+		return reconcile.Result{}, fmt.Errorf("error handling default values for %v '%v': %w", r.gvk.Kind, k8s.GetNamespacedName(obj), err)
+	}
+	runCtx := &reconcileContext[T]{
+		Reconciler:     r,
+		Ctx:            ctx,
+		NamespacedName: request.NamespacedName,
+	}
+	if k8s.HasReconcilePausedAnnotation(obj) {
+		logger.Info("Skipping reconcile as the resource has the reconcile-paused annotation", "resource", request.NamespacedName)
+		return reconcile.Result{}, runCtx.handlePaused(obj)
+	}
+	requeue, err := runCtx.doReconcile(obj)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if requeue {
+		return reconcile.Result{Requeue: true}, nil
+	}
+	jitteredPeriod, err := r.jitterGen.JitteredReenqueue(r.gvk, obj)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	requeueAfter := runCtx.actuation.RequeueAfter(jitteredPeriod, time.Now())
+	logger.Info("successfully finished reconcile", "resource", request.NamespacedName, "time to next reconciliation", requeueAfter)
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+func (r *Reconciler[T]) handleDefaults(ctx context.Context, obj T) error {
+	for _, defaulter := range r.defaulters {
+		if _, err := defaulter.ApplyDefaults(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reconcileContext[T]) doReconcile(obj T) (requeue bool, err error) {
+	defer execution.RecoverWithInternalError(&err)
+	logger := r.Reconciler.logger
+	adapter := r.Reconciler.adapter
+
+	cc, ccc, err := kccstate.FetchLiveKCCState(r.Ctx, r.Reconciler.Client, r.NamespacedName)
+	if err != nil {
+		return true, err
+	}
+
+	decision, err := resourceactuation.DecideActuationMode(cc, ccc, time.Now())
+	if err != nil {
+		return false, err
+	}
+	r.actuation = decision
+	switch am := decision.Mode; am {
+	case v1beta1.Reconciling:
+		if decision.PendingActuationWindow {
+			ensureFinalizers := func() error {
+				k8s.EnsureFinalizers(obj, k8s.ControllerFinalizerName, k8s.DeletionDefenderFinalizerName)
+				return nil
+			}
+			toResource := func() (*k8s.Resource, error) { return adapter.ToK8sResource(obj) }
+			return false, decision.HandlePendingActuationWindow(r.Ctx, r.Reconciler, obj, ensureFinalizers, toResource)
+		}
+		logger.V(2).Info("Actuating a resource as actuation mode is \"Reconciling\"", "resource", r.NamespacedName)
+	case v1beta1.Paused:
+		logger.Info("Skipping actuation of resource as actuation mode is \"Paused\"", "resource", r.NamespacedName)
+
+		// add finalizers for deletion defender to make sure we don't delete cloud provider resources when uninstalling
+		if obj.GetDeletionTimestamp().IsZero() {
+			k8s.EnsureFinalizers(obj, k8s.ControllerFinalizerName, k8s.DeletionDefenderFinalizerName)
+		}
+
+		return false, nil
+	default:
+		return false, fmt.Errorf("unknown actuation mode %v", am)
+	}
+
+	if !obj.GetDeletionTimestamp().IsZero() {
+		if !k8s.HasFinalizer(obj, k8s.ControllerFinalizerName) {
+			// Resource has no controller finalizer; no finalization necessary
+			return false, nil
+		}
+		if k8s.HasFinalizer(obj, k8s.DeletionDefenderFinalizerName) {
+			// deletion defender has not yet been finalized; requeuing
+			logger.Info("deletion defender has not yet been finalized; requeuing", "resource", k8s.GetNamespacedName(obj))
+			return true, nil
+		}
+		if !k8s.HasAbandonAnnotation(obj) {
+			if err := adapter.Delete(r.Ctx, obj); err != nil {
+				if !errors.Is(err, kcciamclient.ErrNotFound) && !k8s.IsReferenceNotFoundError(err) {
+					if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
+						logger.Info(unwrappedErr.Error(), "resource", k8s.GetNamespacedName(obj))
+						resource, err := adapter.ToK8sResource(obj)
+						if err != nil {
+							return false, fmt.Errorf("error converting %v to k8s resource while handling unresolvable dependencies event: %w", r.Reconciler.gvk.Kind, err)
+						}
+						// Requeue resource for reconciliation with exponential backoff applied
+						return true, r.Reconciler.HandleUnresolvableDeps(r.Ctx, resource, unwrappedErr)
+					}
+					return false, r.handleDeleteFailed(obj, err)
+				}
+			}
+		}
+		return false, r.handleDeleted(obj)
+	}
+	if err := adapter.Get(r.Ctx, obj); err != nil {
+		if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
+			logger.Info(unwrappedErr.Error(), "resource", k8s.GetNamespacedName(obj))
+			return r.handleUnresolvableDeps(obj, unwrappedErr)
+		}
+		return false, r.handleUpdateFailed(obj, err)
+	}
+	k8s.EnsureFinalizers(obj, k8s.ControllerFinalizerName, k8s.DeletionDefenderFinalizerName)
+	statusUpdateRequired, err := adapter.Set(r.Ctx, obj)
+	if err != nil {
+		if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
+			logger.Info(unwrappedErr.Error(), "resource", k8s.GetNamespacedName(obj))
+			return r.handleUnresolvableDeps(obj, unwrappedErr)
+		}
+		return false, r.handleUpdateFailed(obj, err)
+	}
+	if statusUpdateRequired {
+		return false, r.handleUpToDate(obj)
+	}
+	return false, nil
+}
+
+func (r *reconcileContext[T]) handleUpToDate(obj T) error {
+	resource, err := r.Reconciler.adapter.ToK8sResource(obj)
+	if err != nil {
+		return fmt.Errorf("error converting %v to k8s resource while handling %v event: %w", r.Reconciler.gvk.Kind, k8s.UpToDate, err)
+	}
+	return r.Reconciler.HandleUpToDate(r.Ctx, resource)
+}
+
+func (r *reconcileContext[T]) handlePaused(obj T) error {
+	resource, err := r.Reconciler.adapter.ToK8sResource(obj)
+	if err != nil {
+		return fmt.Errorf("error converting %v to k8s resource while handling %v event: %w", r.Reconciler.gvk.Kind, k8s.Paused, err)
+	}
+	return r.Reconciler.HandlePaused(r.Ctx, resource)
+}
+
+func (r *reconcileContext[T]) handleUpdateFailed(obj T, origErr error) error {
+	resource, err := r.Reconciler.adapter.ToK8sResource(obj)
+	if err != nil {
+		r.Reconciler.logger.Error(err, "error converting to k8s resource while handling event",
+			"resource", k8s.GetNamespacedName(obj), "event", k8s.UpdateFailed)
+		return fmt.Errorf("update call failed: %w", origErr)
+	}
+	return r.Reconciler.HandleUpdateFailed(r.Ctx, resource, origErr)
+}
+
+func (r *reconcileContext[T]) handleDeleted(obj T) error {
+	resource, err := r.Reconciler.adapter.ToK8sResource(obj)
+	if err != nil {
+		return fmt.Errorf("error converting %v to k8s resource while handling %v event: %w", r.Reconciler.gvk.Kind, k8s.Deleted, err)
+	}
+	return r.Reconciler.HandleDeleted(r.Ctx, resource)
+}
+
+func (r *reconcileContext[T]) handleDeleteFailed(obj T, origErr error) error {
+	resource, err := r.Reconciler.adapter.ToK8sResource(obj)
+	if err != nil {
+		r.Reconciler.logger.Error(err, "error converting to k8s resource while handling event",
+			"resource", k8s.GetNamespacedName(obj), "event", k8s.DeleteFailed)
+		return fmt.Errorf(k8s.DeleteFailedMessageTmpl, origErr)
+	}
+	return r.Reconciler.HandleDeleteFailed(r.Ctx, resource, origErr)
+}
+
+func (r *Reconciler[T]) supportsImmediateReconciliations() bool {
+	return r.immediateReconcileRequests != nil
+}
+
+func (r *reconcileContext[T]) handleUnresolvableDeps(obj T, origErr error) (requeue bool, err error) {
+	resource, err := r.Reconciler.adapter.ToK8sResource(obj)
+	if err != nil {
+		return false, fmt.Errorf("error converting %v to k8s resource while handling unresolvable dependencies event: %w", r.Reconciler.gvk.Kind, err)
+	}
+	refGVK, refNN, ok := lifecyclehandler.CausedByUnreadyOrNonexistentResourceRefs(origErr)
+	if !ok || !r.Reconciler.supportsImmediateReconciliations() {
+		// Requeue resource for reconciliation with exponential backoff applied
+		return true, r.Reconciler.HandleUnresolvableDeps(r.Ctx, resource, origErr)
+	}
+	// Check that the number of active resource watches
+	// does not exceed the controller's cap. If the
+	// capacity is not exceeded, The number of active
+	// resource watches is incremented by one and a watch
+	// is started
+	if !r.Reconciler.resourceWatcherRoutines.TryAcquire(1) {
+		// Requeue resource for reconciliation with exponential backoff applied
+		return true, r.Reconciler.HandleUnresolvableDeps(r.Ctx, resource, origErr)
+	}
+	// Create a logger for ResourceWatcher that contains info
+	// about the referencing resource. This is done since the
+	// messages logged by ResourceWatcher only include the
+	// information of the resource it is watching by default.
+	watcherLogger := r.Reconciler.logger.WithValues(
+		"referencingResource", resource.GetNamespacedName(),
+		"referencingResourceGVK", resource.GroupVersionKind())
+	watcher, err := resourcewatcher.New(r.Reconciler.config, watcherLogger)
+	if err != nil {
+		r.Reconciler.resourceWatcherRoutines.Release(1)
+		return false, r.Reconciler.HandleUpdateFailed(r.Ctx, resource, fmt.Errorf("error initializing new resourcewatcher: %w", err))
+	}
+
+	logger := r.Reconciler.logger.WithValues(
+		"resource", resource.GetNamespacedName(),
+		"resourceGVK", resource.GroupVersionKind(),
+		"reference", refNN,
+		"referenceGVK", refGVK)
+	go func() {
+		// Decrement the count of active resource watches after
+		// the watch finishes
+		defer r.Reconciler.resourceWatcherRoutines.Release(1)
+		timeoutPeriod := r.Reconciler.jitterGen.WatchJitteredTimeout()
+		ctx, cancel := context.WithTimeout(context.TODO(), timeoutPeriod)
+		defer cancel()
+		logger.Info("starting wait with timeout on resource's reference", "timeout", timeoutPeriod)
+		if err := watcher.WaitForResourceToBeReady(ctx, refNN, refGVK); err != nil {
+			logger.Error(err, "error while waiting for resource's reference to be ready")
+			return
+		}
+		logger.Info("enqueuing resource for immediate reconciliation now that its reference is ready")
+		r.Reconciler.enqueueForImmediateReconciliation(resource.GetNamespacedName())
+	}()
+
+	// Do not requeue resource for immediate reconciliation. Wait for either
+	// the next periodic reconciliation or for the referenced resource to be ready (which
+	// triggers a reconciliation), whichever comes first.
+	return false, r.Reconciler.HandleUnresolvableDeps(r.Ctx, resource, origErr)
+}
+
+// enqueueForImmediateReconciliation enqueues the given resource for immediate
+// reconciliation. Note that this function only takes in the name and namespace
+// of the resource and not its GVK since the controller instance that this
+// reconcile instance belongs to can only reconcile resources of one GVK.
+func (r *Reconciler[T]) enqueueForImmediateReconciliation(resourceNN types.NamespacedName) {
+	genEvent := event.GenericEvent{}
+	genEvent.Object = &unstructured.Unstructured{}
+	genEvent.Object.SetNamespace(resourceNN.Namespace)
+	genEvent.Object.SetName(resourceNN.Name)
+	r.immediateReconcileRequests <- genEvent
+}
@@ -0,0 +1,296 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iambase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	opv1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/v1beta1"
+	opk8s "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/k8s"
+	iamv1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/iam/v1beta1"
+	kcciamclient "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/iam/iamclient"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/lifecyclehandler"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/util"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const testReenqueuePeriod = 10 * time.Minute
+
+type fakeAdapter struct {
+	getErr    error
+	setErr    error
+	deleteErr error
+	etag      string
+
+	sets    int
+	deletes int
+}
+
+func (a *fakeAdapter) Get(_ context.Context, _ *iamv1beta1.IAMDenyPolicy) error {
+	return a.getErr
+}
+
+func (a *fakeAdapter) Set(_ context.Context, obj *iamv1beta1.IAMDenyPolicy) (bool, error) {
+	a.sets++
+	if a.setErr != nil {
+		return false, a.setErr
+	}
+	if obj.Status.Etag == a.etag && obj.Status.ObservedGeneration == obj.GetGeneration() {
+		return false, nil
+	}
+	obj.Status.Etag = a.etag
+	return true, nil
+}
+
+func (a *fakeAdapter) Delete(_ context.Context, _ *iamv1beta1.IAMDenyPolicy) error {
+	a.deletes++
+	return a.deleteErr
+}
+
+func (a *fakeAdapter) ToK8sResource(obj *iamv1beta1.IAMDenyPolicy) (*k8s.Resource, error) {
+	kcciamclient.SetGVK(obj)
+	resource := k8s.Resource{}
+	if err := util.Marshal(obj, &resource); err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+type fixedJitterGenerator struct{}
+
+func (fixedJitterGenerator) JitteredReenqueue(_ schema.GroupVersionKind, _ metav1.Object) (time.Duration, error) {
+	return testReenqueuePeriod, nil
+}
+
+func (fixedJitterGenerator) WatchJitteredTimeout() time.Duration {
+	return testReenqueuePeriod
+}
+
+func newTestReconciler(t *testing.T, adapter *fakeAdapter, objs ...client.Object) (*Reconciler[*iamv1beta1.IAMDenyPolicy], client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := iamv1beta1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("error building scheme: %v", err)
+	}
+	if err := opv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("error building scheme: %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(&iamv1beta1.IAMDenyPolicy{}).Build()
+	r := &Reconciler[*iamv1beta1.IAMDenyPolicy]{
+		LifecycleHandler: lifecyclehandler.NewLifecycleHandler(c, record.NewFakeRecorder(100)),
+		Client:           c,
+		gvk:              iamv1beta1.IAMDenyPolicyGVK,
+		newObject:        func() *iamv1beta1.IAMDenyPolicy { return &iamv1beta1.IAMDenyPolicy{} },
+		adapter:          adapter,
+		logger:           logr.Discard(),
+		jitterGen:        fixedJitterGenerator{},
+	}
+	return r, c
+}
+
+func newDenyPolicy(mutate func(*iamv1beta1.IAMDenyPolicy)) *iamv1beta1.IAMDenyPolicy {
+	denyPolicy := &iamv1beta1.IAMDenyPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "ns",
+			Name:       "deny",
+			Generation: 1,
+		},
+	}
+	if mutate != nil {
+		mutate(denyPolicy)
+	}
+	return denyPolicy
+}
+
+func TestReconcile(t *testing.T) {
+	pausedCC := &opv1beta1.ConfigConnector{
+		ObjectMeta: metav1.ObjectMeta{Name: opk8s.ConfigConnectorAllowedName},
+		Spec: opv1beta1.ConfigConnectorSpec{
+			Mode:      opk8s.ClusterMode,
+			Actuation: opv1beta1.Paused,
+		},
+	}
+	deleting := func(finalizers ...string) func(*iamv1beta1.IAMDenyPolicy) {
+		return func(denyPolicy *iamv1beta1.IAMDenyPolicy) {
+			denyPolicy.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			denyPolicy.Finalizers = finalizers
+		}
+	}
+	tests := []struct {
+		name        string
+		denyPolicy  *iamv1beta1.IAMDenyPolicy
+		objs        []client.Object
+		adapter     *fakeAdapter
+		wantResult  reconcile.Result
+		wantErr     bool
+		wantSets    int
+		wantDeletes int
+		// wantDeleted is true if the object must be gone from the API server.
+		wantDeleted    bool
+		wantReason     string
+		wantEtag       string
+		wantFinalizers []string
+	}{
+		{
+			name:           "applies the resource and records the applied state",
+			denyPolicy:     newDenyPolicy(nil),
+			adapter:        &fakeAdapter{etag: "etag-1"},
+			wantResult:     reconcile.Result{RequeueAfter: testReenqueuePeriod},
+			wantSets:       1,
+			wantReason:     k8s.UpToDate,
+			wantEtag:       "etag-1",
+			wantFinalizers: []string{k8s.ControllerFinalizerName, k8s.DeletionDefenderFinalizerName},
+		},
+		{
+			name:       "surfaces the errors setting the resource",
+			denyPolicy: newDenyPolicy(nil),
+			adapter:    &fakeAdapter{setErr: errors.New("boom")},
+			wantErr:    true,
+			wantSets:   1,
+			wantReason: k8s.UpdateFailed,
+		},
+		{
+			name:       "surfaces the errors reading the resource before adding the finalizers",
+			denyPolicy: newDenyPolicy(nil),
+			adapter:    &fakeAdapter{getErr: errors.New("boom")},
+			wantErr:    true,
+			wantReason: k8s.UpdateFailed,
+		},
+		{
+			name:       "does not actuate paused resources",
+			denyPolicy: newDenyPolicy(nil),
+			objs:       []client.Object{pausedCC},
+			adapter:    &fakeAdapter{},
+			wantResult: reconcile.Result{RequeueAfter: testReenqueuePeriod},
+		},
+		{
+			name:        "deletes the resource once the deletion defender is finalized",
+			denyPolicy:  newDenyPolicy(deleting(k8s.ControllerFinalizerName)),
+			adapter:     &fakeAdapter{},
+			wantResult:  reconcile.Result{RequeueAfter: testReenqueuePeriod},
+			wantDeletes: 1,
+			wantDeleted: true,
+		},
+		{
+			name:        "ignores resources which do not exist in GCP anymore when deleting",
+			denyPolicy:  newDenyPolicy(deleting(k8s.ControllerFinalizerName)),
+			adapter:     &fakeAdapter{deleteErr: kcciamclient.ErrNotFound},
+			wantResult:  reconcile.Result{RequeueAfter: testReenqueuePeriod},
+			wantDeletes: 1,
+			wantDeleted: true,
+		},
+		{
+			name:           "waits for the deletion defender",
+			denyPolicy:     newDenyPolicy(deleting(k8s.ControllerFinalizerName, k8s.DeletionDefenderFinalizerName)),
+			adapter:        &fakeAdapter{},
+			wantResult:     reconcile.Result{Requeue: true},
+			wantFinalizers: []string{k8s.ControllerFinalizerName, k8s.DeletionDefenderFinalizerName},
+		},
+		{
+			name: "abandons the resource",
+			denyPolicy: newDenyPolicy(func(denyPolicy *iamv1beta1.IAMDenyPolicy) {
+				deleting(k8s.ControllerFinalizerName)(denyPolicy)
+				denyPolicy.Annotations = map[string]string{k8s.DeletionPolicyAnnotation: k8s.DeletionPolicyAbandon}
+			}),
+			adapter:     &fakeAdapter{},
+			wantResult:  reconcile.Result{RequeueAfter: testReenqueuePeriod},
+			wantDeleted: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r, c := newTestReconciler(t, tc.adapter, append(tc.objs, tc.denyPolicy)...)
+			nn := types.NamespacedName{Namespace: tc.denyPolicy.Namespace, Name: tc.denyPolicy.Name}
+
+			result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: nn})
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Reconcile() error = %v, want error %t", err, tc.wantErr)
+			}
+			if result != tc.wantResult {
+				t.Errorf("Reconcile() = %+v, want %+v", result, tc.wantResult)
+			}
+			if tc.adapter.sets != tc.wantSets || tc.adapter.deletes != tc.wantDeletes {
+				t.Errorf("got %d sets and %d deletes, want %d and %d", tc.adapter.sets, tc.adapter.deletes, tc.wantSets, tc.wantDeletes)
+			}
+
+			got := &iamv1beta1.IAMDenyPolicy{}
+			if err := c.Get(context.Background(), nn, got); err != nil {
+				if tc.wantDeleted && apierrors.IsNotFound(err) {
+					return
+				}
+				t.Fatalf("error getting the resource: %v", err)
+			}
+			if tc.wantDeleted {
+				t.Fatalf("got resource with finalizers %v, want it deleted", got.Finalizers)
+			}
+			reason := ""
+			for _, cond := range got.Status.Conditions {
+				if cond.Type == "Ready" {
+					reason = cond.Reason
+				}
+			}
+			if reason != tc.wantReason {
+				t.Errorf("got Ready reason %q, want %q", reason, tc.wantReason)
+			}
+			if tc.wantReason == k8s.UpToDate {
+				if got.Status.Conditions[0].Status != corev1.ConditionTrue {
+					t.Errorf("got Ready status %v, want %v", got.Status.Conditions[0].Status, corev1.ConditionTrue)
+				}
+			}
+			if got.Status.Etag != tc.wantEtag {
+				t.Errorf("got status etag %q, want %q", got.Status.Etag, tc.wantEtag)
+			}
+			if !slices.Equal(got.Finalizers, tc.wantFinalizers) {
+				t.Errorf("got finalizers %v, want %v", got.Finalizers, tc.wantFinalizers)
+			}
+		})
+	}
+}
+
+func TestReconcileIsIdempotent(t *testing.T) {
+	adapter := &fakeAdapter{etag: "etag-1"}
+	r, c := newTestReconciler(t, adapter, newDenyPolicy(nil))
+	nn := types.NamespacedName{Namespace: "ns", Name: "deny"}
+	for i := 0; i < 2; i++ {
+		if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: nn}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+	got := &iamv1beta1.IAMDenyPolicy{}
+	if err := c.Get(context.Background(), nn, got); err != nil {
+		t.Fatalf("error getting the resource: %v", err)
+	}
+	if got.Status.Etag != "etag-1" || got.Status.ObservedGeneration != got.Generation {
+		t.Errorf("got status %+v, want etag %q and observed generation %d", got.Status, "etag-1", got.Generation)
+	}
+}
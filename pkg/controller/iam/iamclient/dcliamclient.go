@@ -59,11 +59,15 @@ func (d *DCLIAMClient) SetPolicyMember(ctx context.Context, tfIAMClient *TFIAMCl
 	}
 	dclPolicyMemberResource := dclunstructiam.MemberToUnstructured(dclPolicyMember)
 	// DCL's SetPolicyMember returns a Policy not a Member, which is not what this function should return
-	_, err = dclunstruct.SetPolicyMember(ctx, d.dclClient.Config, dclResource, dclPolicyMemberResource)
+	dclPolicyResource, err := dclunstruct.SetPolicyMember(ctx, d.dclClient.Config, dclResource, dclPolicyMemberResource)
 	if err != nil {
 		return nil, fmt.Errorf("error setting IAMPolicyMember for resource %v: %w", nn, err)
 	}
-	return policyMember, nil
+	applied := policyMember.DeepCopy()
+	if etag, ok := dclPolicyResource.Object["etag"].(string); ok {
+		applied.Status.Etag = etag
+	}
+	return applied, nil
 }
 
 func (d *DCLIAMClient) GetPolicyMember(ctx context.Context, tfIAMClient *TFIAMClient, policyMember *v1beta1.IAMPolicyMember) (*v1beta1.IAMPolicyMember, error) {
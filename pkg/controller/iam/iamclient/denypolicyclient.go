@@ -0,0 +1,288 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iamclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/iam/v1beta1"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+
+	iamv2 "cloud.google.com/go/iam/apiv2"
+	iamv2pb "cloud.google.com/go/iam/apiv2/iampb"
+	"google.golang.org/genproto/googleapis/type/expr"
+	"google.golang.org/protobuf/proto"
+)
+
+// DenyPolicyClient manages IAM v2 deny policies. The resources the deny policies are
+// attached to are resolved with the TFIAMClient.
+type DenyPolicyClient struct {
+	config      config.ControllerConfig
+	tfIAMClient *TFIAMClient
+
+	// mu guards gcpClient, which is built on first use and then shared by all reconciliations.
+	mu        sync.Mutex
+	gcpClient *iamv2.PoliciesClient
+}
+
+func NewDenyPolicyClient(iamClient *IAMClient, config *config.ControllerConfig) *DenyPolicyClient {
+	return &DenyPolicyClient{
+		config:      *config,
+		tfIAMClient: iamClient.TFIAMClient,
+	}
+}
+
+func (c *DenyPolicyClient) policiesClient(ctx context.Context) (*iamv2.PoliciesClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gcpClient != nil {
+		return c.gcpClient, nil
+	}
+	opts, err := c.config.RESTClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	// The client outlives the reconciliation which builds it, so it must not be bound to
+	// the cancellation of its context.
+	client, err := iamv2.NewPoliciesRESTClient(context.WithoutCancel(ctx), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("building IAM policies client: %w", err)
+	}
+	c.gcpClient = client
+	return client, nil
+}
+
+// ResolveDenyPolicyName returns the full name of the deny policy, which resolves the
+// reference to the resource the deny policy is attached to.
+func (c *DenyPolicyClient) ResolveDenyPolicyName(ctx context.Context, denyPolicy *v1beta1.IAMDenyPolicy) (string, error) {
+	_, name, err := c.resolveDenyPolicyName(ctx, denyPolicy)
+	return name, err
+}
+
+// SetDenyPolicy creates the deny policy in GCP if it does not exist, or updates it if it
+// differs from the desired state. It returns the resulting deny policy.
+func (c *DenyPolicyClient) SetDenyPolicy(ctx context.Context, denyPolicy *v1beta1.IAMDenyPolicy) (*iamv2pb.Policy, error) {
+	attachmentPoint, name, err := c.resolveDenyPolicyName(ctx, denyPolicy)
+	if err != nil {
+		return nil, err
+	}
+	gcpClient, err := c.policiesClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := &iamv2pb.Policy{
+		DisplayName: denyPolicy.Spec.DisplayName,
+		Rules:       newDenyPolicyRules(denyPolicy.Spec.Rules),
+	}
+	live, err := gcpClient.GetPolicy(ctx, &iamv2pb.GetPolicyRequest{Name: name})
+	if err != nil {
+		if !direct.IsNotFound(err) {
+			return nil, fmt.Errorf("error getting IAM deny policy %q: %w", name, err)
+		}
+		op, err := gcpClient.CreatePolicy(ctx, &iamv2pb.CreatePolicyRequest{
+			Parent:   denyPoliciesParent(attachmentPoint),
+			PolicyId: denyPolicyID(denyPolicy),
+			Policy:   desired,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error creating IAM deny policy %q: %w", name, err)
+		}
+		created, err := op.Wait(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error waiting for the creation of IAM deny policy %q: %w", name, err)
+		}
+		return created, nil
+	}
+	if isDenyPolicyUpToDate(desired, live) {
+		logger.Info("underlying resource is already up to date", "resource", k8s.GetNamespacedName(denyPolicy))
+		return live, nil
+	}
+	desired.Name = name
+	// Carry the etag of the live deny policy, so that the update fails if the deny policy
+	// is modified by another actor in the meantime.
+	desired.Etag = live.Etag
+	op, err := gcpClient.UpdatePolicy(ctx, &iamv2pb.UpdatePolicyRequest{Policy: desired})
+	if err != nil {
+		return nil, fmt.Errorf("error updating IAM deny policy %q: %w", name, err)
+	}
+	updated, err := op.Wait(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error waiting for the update of IAM deny policy %q: %w", name, err)
+	}
+	return updated, nil
+}
+
+// DeleteDenyPolicy deletes the deny policy in GCP, or returns ErrNotFound if it does not exist.
+func (c *DenyPolicyClient) DeleteDenyPolicy(ctx context.Context, denyPolicy *v1beta1.IAMDenyPolicy) error {
+	_, name, err := c.resolveDenyPolicyName(ctx, denyPolicy)
+	if err != nil {
+		return err
+	}
+	gcpClient, err := c.policiesClient(ctx)
+	if err != nil {
+		return err
+	}
+	op, err := gcpClient.DeletePolicy(ctx, &iamv2pb.DeletePolicyRequest{Name: name})
+	if err != nil {
+		if direct.IsNotFound(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("error deleting IAM deny policy %q: %w", name, err)
+	}
+	if _, err := op.Wait(ctx); err != nil {
+		return fmt.Errorf("error waiting for the deletion of IAM deny policy %q: %w", name, err)
+	}
+	return nil
+}
+
+// resolveDenyPolicyName returns the attachment point and the full name of the deny policy.
+func (c *DenyPolicyClient) resolveDenyPolicyName(ctx context.Context, denyPolicy *v1beta1.IAMDenyPolicy) (attachmentPoint, name string, err error) {
+	resourceRef := denyPolicy.Spec.ResourceReference
+	id, err := c.tfIAMClient.getResourceID(ctx, resourceRef, denyPolicy.GetNamespace())
+	if err != nil {
+		return "", "", err
+	}
+	attachmentPoint, err = denyPolicyAttachmentPoint(resourceRef.Kind, id)
+	if err != nil {
+		return "", "", err
+	}
+	return attachmentPoint, denyPoliciesParent(attachmentPoint) + "/" + denyPolicyID(denyPolicy), nil
+}
+
+// denyPolicyAttachmentPoint returns the full resource name of the resource a deny policy
+// is attached to, e.g. "cloudresourcemanager.googleapis.com/projects/my-project".
+func denyPolicyAttachmentPoint(kind, id string) (string, error) {
+	var collection string
+	switch kind {
+	case ProjectKind:
+		collection = "projects"
+	case "Folder":
+		collection = "folders"
+	case "Organization":
+		collection = "organizations"
+	default:
+		return "", fmt.Errorf("invalid resource reference: IAM deny policies can only be attached to a Project, Folder or Organization, got kind %v", kind)
+	}
+	id = strings.TrimPrefix(id, collection+"/")
+	if id == "" || strings.Contains(id, "/") {
+		return "", fmt.Errorf("invalid resource reference: unexpected ID %q for kind %v", id, kind)
+	}
+	return "cloudresourcemanager.googleapis.com/" + collection + "/" + id, nil
+}
+
+// denyPoliciesParent returns the parent of the deny policies attached to the given
+// attachment point, which must be URL-encoded.
+func denyPoliciesParent(attachmentPoint string) string {
+	return "policies/" + url.PathEscape(attachmentPoint) + "/denypolicies"
+}
+
+func denyPolicyID(denyPolicy *v1beta1.IAMDenyPolicy) string {
+	if id := direct.ValueOf(denyPolicy.Spec.ResourceID); id != "" {
+		return id
+	}
+	return denyPolicy.GetName()
+}
+
+func newDenyPolicyRules(rules []v1beta1.IAMDenyPolicyRule) []*iamv2pb.PolicyRule {
+	var res []*iamv2pb.PolicyRule
+	for _, rule := range rules {
+		denyRule := &iamv2pb.DenyRule{
+			DeniedPrincipals:     rule.DenyRule.DeniedPrincipals,
+			ExceptionPrincipals:  rule.DenyRule.ExceptionPrincipals,
+			DeniedPermissions:    rule.DenyRule.DeniedPermissions,
+			ExceptionPermissions: rule.DenyRule.ExceptionPermissions,
+		}
+		if c := rule.DenyRule.DenialCondition; c != nil {
+			denyRule.DenialCondition = &expr.Expr{
+				Title:       c.Title,
+				Description: c.Description,
+				Expression:  c.Expression,
+			}
+		}
+		res = append(res, &iamv2pb.PolicyRule{
+			Description: rule.Description,
+			Kind:        &iamv2pb.PolicyRule_DenyRule{DenyRule: denyRule},
+		})
+	}
+	return res
+}
+
+// isDenyPolicyUpToDate returns whether the live deny policy matches the desired one. The
+// principals and permissions of the rules are sets, which GCP may return in another order
+// and, for the principals, with the email addresses of Google accounts lowercased.
+func isDenyPolicyUpToDate(desired, live *iamv2pb.Policy) bool {
+	if desired.DisplayName != live.DisplayName {
+		return false
+	}
+	if len(desired.Rules) != len(live.Rules) {
+		return false
+	}
+	for i := range desired.Rules {
+		if !proto.Equal(normalizeDenyPolicyRule(desired.Rules[i]), normalizeDenyPolicyRule(live.Rules[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeDenyPolicyRule(rule *iamv2pb.PolicyRule) *iamv2pb.PolicyRule {
+	rule = proto.Clone(rule).(*iamv2pb.PolicyRule)
+	denyRule := rule.GetDenyRule()
+	if denyRule == nil {
+		return rule
+	}
+	denyRule.DeniedPrincipals = normalizeSet(denyRule.DeniedPrincipals, normalizeDenyPolicyPrincipal)
+	denyRule.ExceptionPrincipals = normalizeSet(denyRule.ExceptionPrincipals, normalizeDenyPolicyPrincipal)
+	denyRule.DeniedPermissions = normalizeSet(denyRule.DeniedPermissions, nil)
+	denyRule.ExceptionPermissions = normalizeSet(denyRule.ExceptionPermissions, nil)
+	if c := denyRule.DenialCondition; c != nil && proto.Equal(c, &expr.Expr{}) {
+		denyRule.DenialCondition = nil
+	}
+	return rule
+}
+
+// normalizeDenyPolicyPrincipal lowercases the email address of the Google accounts, e.g.
+// "principal://goog/subject/Alice@Example.com". Other principal identifiers, like the
+// subjects of workforce identity pools, are case-sensitive.
+func normalizeDenyPolicyPrincipal(principal string) string {
+	for _, prefix := range []string{"principal://goog/subject/", "principalSet://goog/group/"} {
+		if email, ok := strings.CutPrefix(principal, prefix); ok {
+			return prefix + strings.ToLower(email)
+		}
+	}
+	return principal
+}
+
+// normalizeSet returns the sorted and deduplicated values, after applying normalize if it is not nil.
+func normalizeSet(values []string, normalize func(string) string) []string {
+	var res []string
+	for _, v := range values {
+		if normalize != nil {
+			v = normalize(v)
+		}
+		res = append(res, v)
+	}
+	sort.Strings(res)
+	return slices.Compact(res)
+}
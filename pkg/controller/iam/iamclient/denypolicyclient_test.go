@@ -0,0 +1,187 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iamclient
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/iam/v1beta1"
+
+	iamv2pb "cloud.google.com/go/iam/apiv2/iampb"
+)
+
+func TestDenyPolicyAttachmentPoint(t *testing.T) {
+	tests := []struct {
+		name        string
+		kind        string
+		id          string
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name:     "project ID",
+			kind:     "Project",
+			id:       "my-project",
+			expected: "cloudresourcemanager.googleapis.com/projects/my-project",
+		},
+		{
+			name:     "project name",
+			kind:     "Project",
+			id:       "projects/my-project",
+			expected: "cloudresourcemanager.googleapis.com/projects/my-project",
+		},
+		{
+			name:     "folder name",
+			kind:     "Folder",
+			id:       "folders/1234",
+			expected: "cloudresourcemanager.googleapis.com/folders/1234",
+		},
+		{
+			name:     "organization ID",
+			kind:     "Organization",
+			id:       "5678",
+			expected: "cloudresourcemanager.googleapis.com/organizations/5678",
+		},
+		{
+			name:        "unsupported kind",
+			kind:        "StorageBucket",
+			id:          "my-bucket",
+			expectedErr: true,
+		},
+		{
+			name:        "mismatched collection",
+			kind:        "Folder",
+			id:          "projects/my-project",
+			expectedErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			actual, err := denyPolicyAttachmentPoint(tc.kind, tc.id)
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatalf("got nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != tc.expected {
+				t.Fatalf("got %q, want %q", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestDenyPoliciesParent(t *testing.T) {
+	actual := denyPoliciesParent("cloudresourcemanager.googleapis.com/projects/my-project")
+	expected := "policies/cloudresourcemanager.googleapis.com%2Fprojects%2Fmy-project/denypolicies"
+	if actual != expected {
+		t.Fatalf("got %q, want %q", actual, expected)
+	}
+}
+
+func TestIsDenyPolicyUpToDate(t *testing.T) {
+	rules := []v1beta1.IAMDenyPolicyRule{
+		{
+			Description: "deny role listing",
+			DenyRule: v1beta1.IAMDenyRule{
+				DeniedPrincipals:  []string{"principalSet://goog/public:all"},
+				DeniedPermissions: []string{"iam.googleapis.com/roles.list"},
+				DenialCondition: &v1beta1.IAMCondition{
+					Title:      "tagged",
+					Expression: "resource.matchTag('12345678/env', 'prod')",
+				},
+			},
+		},
+	}
+	desired := &iamv2pb.Policy{DisplayName: "foo", Rules: newDenyPolicyRules(rules)}
+	live := &iamv2pb.Policy{Name: "policies/x/denypolicies/y", Etag: "abc", DisplayName: "foo", Rules: newDenyPolicyRules(rules)}
+	if !isDenyPolicyUpToDate(desired, live) {
+		t.Fatalf("got false, want true for identical rules")
+	}
+	live.Rules[0].GetDenyRule().DenialCondition.Expression = "true"
+	if isDenyPolicyUpToDate(desired, live) {
+		t.Fatalf("got true, want false for a changed denial condition")
+	}
+	live = &iamv2pb.Policy{DisplayName: "bar", Rules: newDenyPolicyRules(rules)}
+	if isDenyPolicyUpToDate(desired, live) {
+		t.Fatalf("got true, want false for a changed display name")
+	}
+}
+
+func TestIsDenyPolicyUpToDateNormalizesRules(t *testing.T) {
+	desired := &iamv2pb.Policy{DisplayName: "foo", Rules: newDenyPolicyRules([]v1beta1.IAMDenyPolicyRule{
+		{
+			DenyRule: v1beta1.IAMDenyRule{
+				DeniedPrincipals:    []string{"principal://goog/subject/Alice@Example.com", "principalSet://goog/public:all"},
+				ExceptionPrincipals: []string{"principalSet://goog/group/Admins@Example.com"},
+				DeniedPermissions:   []string{"iam.googleapis.com/roles.list", "iam.googleapis.com/roles.delete"},
+				DenialCondition:     &v1beta1.IAMCondition{},
+			},
+		},
+	})}
+	tests := []struct {
+		name     string
+		rule     v1beta1.IAMDenyRule
+		expected bool
+	}{
+		{
+			name: "principals and permissions in another order, emails lowercased",
+			rule: v1beta1.IAMDenyRule{
+				DeniedPrincipals:    []string{"principalSet://goog/public:all", "principal://goog/subject/alice@example.com"},
+				ExceptionPrincipals: []string{"principalSet://goog/group/admins@example.com"},
+				DeniedPermissions:   []string{"iam.googleapis.com/roles.delete", "iam.googleapis.com/roles.list", "iam.googleapis.com/roles.list"},
+			},
+			expected: true,
+		},
+		{
+			name: "different principal",
+			rule: v1beta1.IAMDenyRule{
+				DeniedPrincipals:    []string{"principalSet://goog/public:all", "principal://goog/subject/bob@example.com"},
+				ExceptionPrincipals: []string{"principalSet://goog/group/admins@example.com"},
+				DeniedPermissions:   []string{"iam.googleapis.com/roles.delete", "iam.googleapis.com/roles.list"},
+			},
+			expected: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			live := &iamv2pb.Policy{DisplayName: "foo", Rules: newDenyPolicyRules([]v1beta1.IAMDenyPolicyRule{{DenyRule: tc.rule}})}
+			if actual := isDenyPolicyUpToDate(desired, live); actual != tc.expected {
+				t.Fatalf("got %t, want %t", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeDenyPolicyPrincipal(t *testing.T) {
+	tests := map[string]string{
+		"principal://goog/subject/Alice@Example.com":                                        "principal://goog/subject/alice@example.com",
+		"principalSet://goog/group/Admins@Example.com":                                      "principalSet://goog/group/admins@example.com",
+		"principalSet://goog/public:all":                                                    "principalSet://goog/public:all",
+		"principal://iam.googleapis.com/locations/global/workforcePools/pool/subject/Alice": "principal://iam.googleapis.com/locations/global/workforcePools/pool/subject/Alice",
+	}
+	for principal, expected := range tests {
+		if actual := normalizeDenyPolicyPrincipal(principal); actual != expected {
+			t.Errorf("normalizeDenyPolicyPrincipal(%q) = %q, want %q", principal, actual, expected)
+		}
+	}
+}
@@ -162,6 +162,8 @@ func SetGVK(iamInterface interface{}) {
 		setPolicyMemberGVK(iamObject)
 	case *v1beta1.IAMAuditConfig:
 		setAuditConfigGVK(iamObject)
+	case *v1beta1.IAMDenyPolicy:
+		setDenyPolicyGVK(iamObject)
 	default:
 		panic(fmt.Errorf("unknown type: %v", reflect.TypeOf(iamInterface).Name()))
 	}
@@ -183,6 +185,10 @@ func setAuditConfigGVK(auditConfig *v1beta1.IAMAuditConfig) {
 	auditConfig.SetGroupVersionKind(v1beta1.IAMAuditConfigGVK)
 }
 
+func setDenyPolicyGVK(denyPolicy *v1beta1.IAMDenyPolicy) {
+	denyPolicy.SetGroupVersionKind(v1beta1.IAMDenyPolicyGVK)
+}
+
 func resourceSupportsIAMPolicy(rc *corekccv1alpha1.ResourceConfig) bool {
 	return rc.IAMConfig.PolicyName != ""
 }
@@ -195,6 +201,24 @@ func resourceSupportsIAMAuditConfigs(rc *corekccv1alpha1.ResourceConfig) bool {
 	return rc.IAMConfig.AuditConfigName != ""
 }
 
+const (
+	// PolicyVersionWithoutConditions is the version of IAM policies without conditional bindings.
+	PolicyVersionWithoutConditions = 1
+	// PolicyVersionWithConditions is the version of IAM policies with conditional bindings.
+	PolicyVersionWithConditions = 3
+)
+
+// PolicyVersion returns the version of an IAM policy with the given bindings. Policies with
+// conditional bindings must be written with version 3; an empty condition does not count.
+func PolicyVersion(bindings []v1beta1.IAMPolicyBinding) int64 {
+	for _, b := range bindings {
+		if b.Condition != nil && *b.Condition != (v1beta1.IAMCondition{}) {
+			return PolicyVersionWithConditions
+		}
+	}
+	return PolicyVersionWithoutConditions
+}
+
 func useIfNonEmptyElseDefaultTo(str, backup string) string {
 	if str != "" {
 		return str
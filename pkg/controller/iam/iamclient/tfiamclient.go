@@ -67,7 +67,7 @@ func (t *TFIAMClient) SetPolicyMember(ctx context.Context, policyMember *v1beta1
 	if err != nil {
 		return nil, fmt.Errorf("error creating resource config: %w", err)
 	}
-	diff, err := resource.TFResource.Diff(ctx, liveState, cfg, t.provider.Meta())
+	diff, err := resource.TFResource.Diff(ctx, liveState, cfg, krmtotf.MetaWithContext(ctx, t.provider))
 	if err != nil {
 		return nil, fmt.Errorf("error calculating diff: %w", err)
 	}
@@ -76,9 +76,11 @@ func (t *TFIAMClient) SetPolicyMember(ctx context.Context, policyMember *v1beta1
 	}
 	if diff.Empty() {
 		logger.Info("underlying resource is already up to date", "resource", k8s.GetNamespacedName(policyMember))
-		return policyMember, nil
+		upToDate := policyMember.DeepCopy()
+		upToDate.Status.Etag = krmtotf.GetEtagFromState(resource, liveState)
+		return upToDate, nil
 	}
-	newState, diagnostics := resource.TFResource.Apply(ctx, liveState, diff, t.provider.Meta())
+	newState, diagnostics := resource.TFResource.Apply(ctx, liveState, diff, krmtotf.MetaWithContext(ctx, t.provider))
 	if err := krmtotf.NewErrorFromDiagnostics(diagnostics); err != nil {
 		return nil, fmt.Errorf("error applying changes: %w", err)
 	}
@@ -126,7 +128,7 @@ func (t *TFIAMClient) DeletePolicyMember(ctx context.Context, policyMember *v1be
 	if liveState.Empty() {
 		return ErrNotFound
 	}
-	_, diagnostics := resource.TFResource.Apply(ctx, liveState, &terraform.InstanceDiff{Destroy: true}, t.provider.Meta())
+	_, diagnostics := resource.TFResource.Apply(ctx, liveState, &terraform.InstanceDiff{Destroy: true}, krmtotf.MetaWithContext(ctx, t.provider))
 	if err := krmtotf.NewErrorFromDiagnostics(diagnostics); err != nil {
 		return fmt.Errorf("error deleting IAMPolicyMember: %w", err)
 	}
@@ -156,7 +158,7 @@ func (t *TFIAMClient) SetPolicy(ctx context.Context, policy *v1beta1.IAMPolicy)
 	if err != nil {
 		return nil, fmt.Errorf("error creating resource config: %w", err)
 	}
-	diff, err := resource.TFResource.Diff(ctx, liveState, cfg, t.provider.Meta())
+	diff, err := resource.TFResource.Diff(ctx, liveState, cfg, krmtotf.MetaWithContext(ctx, t.provider))
 	if err != nil {
 		return nil, fmt.Errorf("error calculating diff: %w", err)
 	}
@@ -167,7 +169,7 @@ func (t *TFIAMClient) SetPolicy(ctx context.Context, policy *v1beta1.IAMPolicy)
 		logger.Info("underlying resource is already up to date", "resource", k8s.GetNamespacedName(policy))
 		return policy, nil
 	}
-	newState, diagnostics := resource.TFResource.Apply(ctx, liveState, diff, t.provider.Meta())
+	newState, diagnostics := resource.TFResource.Apply(ctx, liveState, diff, krmtotf.MetaWithContext(ctx, t.provider))
 	if err := krmtotf.NewErrorFromDiagnostics(diagnostics); err != nil {
 		return nil, fmt.Errorf("error applying changes: %w", err)
 	}
@@ -221,7 +223,7 @@ func (t *TFIAMClient) DeletePolicy(ctx context.Context, policy *v1beta1.IAMPolic
 	if liveState.Empty() {
 		return ErrNotFound
 	}
-	_, diagnostics := resource.TFResource.Apply(ctx, liveState, &terraform.InstanceDiff{Destroy: true}, t.provider.Meta())
+	_, diagnostics := resource.TFResource.Apply(ctx, liveState, &terraform.InstanceDiff{Destroy: true}, krmtotf.MetaWithContext(ctx, t.provider))
 	if err := krmtotf.NewErrorFromDiagnostics(diagnostics); err != nil {
 		return fmt.Errorf("error deleting IAMPolicy: %w", err)
 	}
@@ -248,7 +250,7 @@ func (t *TFIAMClient) SetAuditConfig(ctx context.Context, auditConfig *v1beta1.I
 	if err != nil {
 		return nil, fmt.Errorf("error creating resource config: %w", err)
 	}
-	diff, err := resource.TFResource.Diff(ctx, liveState, cfg, t.provider.Meta())
+	diff, err := resource.TFResource.Diff(ctx, liveState, cfg, krmtotf.MetaWithContext(ctx, t.provider))
 	if err != nil {
 		return nil, fmt.Errorf("error calculating diff: %w", err)
 	}
@@ -257,9 +259,11 @@ func (t *TFIAMClient) SetAuditConfig(ctx context.Context, auditConfig *v1beta1.I
 	}
 	if diff.Empty() {
 		logger.Info("underlying resource is already up to date", "resource", k8s.GetNamespacedName(auditConfig))
-		return auditConfig, nil
+		upToDate := auditConfig.DeepCopy()
+		upToDate.Status.Etag = krmtotf.GetEtagFromState(resource, liveState)
+		return upToDate, nil
 	}
-	newState, diagnostics := resource.TFResource.Apply(ctx, liveState, diff, t.provider.Meta())
+	newState, diagnostics := resource.TFResource.Apply(ctx, liveState, diff, krmtotf.MetaWithContext(ctx, t.provider))
 	if err := krmtotf.NewErrorFromDiagnostics(diagnostics); err != nil {
 		return nil, fmt.Errorf("error applying changes: %w", err)
 	}
@@ -307,7 +311,7 @@ func (t *TFIAMClient) DeleteAuditConfig(ctx context.Context, auditConfig *v1beta
 	if liveState.Empty() {
 		return ErrNotFound
 	}
-	_, diagnostics := resource.TFResource.Apply(ctx, liveState, &terraform.InstanceDiff{Destroy: true}, t.provider.Meta())
+	_, diagnostics := resource.TFResource.Apply(ctx, liveState, &terraform.InstanceDiff{Destroy: true}, krmtotf.MetaWithContext(ctx, t.provider))
 	if err := krmtotf.NewErrorFromDiagnostics(diagnostics); err != nil {
 		return fmt.Errorf("error deleting IAMAuditConfig: %w", err)
 	}
@@ -731,6 +735,7 @@ func newIAMPolicyMemberFromTFState(resource *krmtotf.Resource, state *terraform.
 	}
 	iamPolicyMember.Spec.ResourceReference = origPolicyMember.Spec.ResourceReference
 	iamPolicyMember.ObjectMeta = origPolicyMember.ObjectMeta
+	iamPolicyMember.Status = v1beta1.IAMPolicyMemberStatus{Etag: krmtotf.GetEtagFromState(resource, state)}
 	return &iamPolicyMember, nil
 }
 
@@ -750,6 +755,7 @@ func newIAMAuditConfigFromTFState(resource *krmtotf.Resource, state *terraform.I
 	}
 	iamAuditConfig.Spec.ResourceReference = origAuditConfig.Spec.ResourceReference
 	iamAuditConfig.ObjectMeta = origAuditConfig.ObjectMeta
+	iamAuditConfig.Status = v1beta1.IAMAuditConfigStatus{Etag: krmtotf.GetEtagFromState(resource, state)}
 	return &iamAuditConfig, nil
 }
 
@@ -25,6 +25,9 @@ type MemberIdentityResolver interface {
 	Resolve(v1beta1.Member, *v1beta1.MemberSource, string) (string, error)
 }
 
+// iamBindingKey identifies a binding of an IAM policy. IAM treats the bindings of a role
+// under different conditions as different bindings, even if they share members, so the
+// condition is part of the key. Unconditional bindings have the zero condition.
 type iamBindingKey struct {
 	Role      string
 	Condition v1beta1.IAMCondition
@@ -124,13 +127,19 @@ func mergeBindingSlices(bindingSlice1, bindingSlice2 []v1beta1.IAMPolicyBinding)
 	return mergeBindingsWithSameRoleAndCondition(mergedBindings)
 }
 
+// mergeBindings merges the members of the bindings with the same {role, condition} key.
+// The merged bindings have deduplicated members and a canonical condition.
 func mergeBindings(bindings []v1beta1.IAMPolicyBinding) map[iamBindingKey]v1beta1.IAMPolicyBinding {
 	bindingMap := make(map[iamBindingKey]v1beta1.IAMPolicyBinding)
 	for _, a := range bindings {
 		k := getIamBindingKey(a)
 		b, ok := bindingMap[k]
 		if !ok {
-			bindingMap[k] = *a.DeepCopy()
+			bindingMap[k] = v1beta1.IAMPolicyBinding{
+				Role:      a.Role,
+				Condition: canonicalCondition(a.Condition),
+				Members:   removeDuplicateMembers(a.Members),
+			}
 			continue
 		}
 		b.Members = mergeMembers(b.Members, a.Members)
@@ -139,6 +148,19 @@ func mergeBindings(bindings []v1beta1.IAMPolicyBinding) map[iamBindingKey]v1beta
 	return bindingMap
 }
 
+// removeDuplicateMembers returns the given members without duplicates, in their original order.
+func removeDuplicateMembers(members []v1beta1.Member) []v1beta1.Member {
+	memberMap := make(map[v1beta1.Member]bool)
+	res := make([]v1beta1.Member, 0, len(members))
+	for _, m := range members {
+		if !memberMap[m] {
+			memberMap[m] = true
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
 func computeDeletedMembersPerBinding(bindings, lastAppliedBindings []v1beta1.IAMPolicyBinding) []v1beta1.IAMPolicyBinding {
 	res := make([]v1beta1.IAMPolicyBinding, 0)
 	bindingMap := mergeBindings(bindings)
@@ -161,12 +183,22 @@ func computeDeletedMembersPerBinding(bindings, lastAppliedBindings []v1beta1.IAM
 func getIamBindingKey(binding v1beta1.IAMPolicyBinding) iamBindingKey {
 	k := iamBindingKey{}
 	k.Role = binding.Role
-	if binding.Condition != nil {
-		k.Condition = *binding.Condition
+	if c := canonicalCondition(binding.Condition); c != nil {
+		k.Condition = *c
 	}
 	return k
 }
 
+// canonicalCondition returns a copy of the given condition, or nil if the condition is
+// empty. An empty condition is equivalent to no condition: the binding always applies,
+// and IAM does not return a condition for it when reading the policy back.
+func canonicalCondition(condition *v1beta1.IAMCondition) *v1beta1.IAMCondition {
+	if condition == nil || *condition == (v1beta1.IAMCondition{}) {
+		return nil
+	}
+	return condition.DeepCopy()
+}
+
 func removeMembersPerBinding(bindings, deletedBindings []v1beta1.IAMPolicyBinding) []v1beta1.IAMPolicyBinding {
 	bindingMap := mergeBindings(bindings)
 	for _, a := range deletedBindings {
@@ -874,6 +874,215 @@ func TestComputePartialPolicyWithMergedBindings(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "empty condition is equivalent to no condition",
+			partialPolicy: &iamv1beta1.IAMPartialPolicy{
+				Spec: iamv1beta1.IAMPartialPolicySpec{
+					Bindings: []iamv1beta1.IAMPartialPolicyBinding{
+						{
+							Role:      "roles/editor",
+							Condition: &iamv1beta1.IAMCondition{},
+							Members: []iamv1beta1.IAMPartialPolicyMember{
+								{
+									Member: "user:foo@example.com",
+								},
+							},
+						},
+					},
+				},
+			},
+			livePolicy: &iamv1beta1.IAMPolicy{
+				Spec: iamv1beta1.IAMPolicySpec{
+					Bindings: []iamv1beta1.IAMPolicyBinding{
+						{
+							Role: "roles/editor",
+							Members: []iamv1beta1.Member{
+								"user:bar@example.com",
+							},
+						},
+					},
+				},
+			},
+			mergedPolicy: &iamv1beta1.IAMPartialPolicy{
+				Spec: iamv1beta1.IAMPartialPolicySpec{
+					Bindings: []iamv1beta1.IAMPartialPolicyBinding{
+						{
+							Role:      "roles/editor",
+							Condition: &iamv1beta1.IAMCondition{},
+							Members: []iamv1beta1.IAMPartialPolicyMember{
+								{
+									Member: "user:foo@example.com",
+								},
+							},
+						},
+					},
+				},
+				Status: iamv1beta1.IAMPartialPolicyStatus{
+					LastAppliedBindings: []iamv1beta1.IAMPolicyBinding{
+						{
+							Role: "roles/editor",
+							Members: []iamv1beta1.Member{
+								"user:foo@example.com",
+							},
+						},
+					},
+					AllBindings: []iamv1beta1.IAMPolicyBinding{
+						{
+							Role: "roles/editor",
+							Members: []iamv1beta1.Member{
+								"user:bar@example.com",
+								"user:foo@example.com",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "remove member from conditional binding overlapping with unconditional binding",
+			partialPolicy: &iamv1beta1.IAMPartialPolicy{
+				Spec: iamv1beta1.IAMPartialPolicySpec{
+					Bindings: []iamv1beta1.IAMPartialPolicyBinding{
+						{
+							Role: "roles/editor",
+							Members: []iamv1beta1.IAMPartialPolicyMember{
+								{
+									Member: "user:foo@example.com",
+								},
+								{
+									Member: "user:foo@example.com",
+								},
+							},
+						},
+						{
+							Role:      "roles/editor",
+							Condition: condition2,
+							Members: []iamv1beta1.IAMPartialPolicyMember{
+								{
+									Member: "user:foo@example.com",
+								},
+							},
+						},
+					},
+				},
+				Status: iamv1beta1.IAMPartialPolicyStatus{
+					LastAppliedBindings: []iamv1beta1.IAMPolicyBinding{
+						{
+							Role: "roles/editor",
+							Members: []iamv1beta1.Member{
+								"user:foo@example.com",
+							},
+						},
+						{
+							Role:      "roles/editor",
+							Condition: condition1,
+							Members: []iamv1beta1.Member{
+								"user:foo@example.com",
+							},
+						},
+						{
+							Role:      "roles/editor",
+							Condition: condition2,
+							Members: []iamv1beta1.Member{
+								"user:foo@example.com",
+							},
+						},
+					},
+				},
+			},
+			livePolicy: &iamv1beta1.IAMPolicy{
+				Spec: iamv1beta1.IAMPolicySpec{
+					Bindings: []iamv1beta1.IAMPolicyBinding{
+						{
+							Role: "roles/editor",
+							Members: []iamv1beta1.Member{
+								"user:foo@example.com",
+							},
+						},
+						{
+							Role:      "roles/editor",
+							Condition: condition1,
+							Members: []iamv1beta1.Member{
+								"user:bar@example.com",
+								"user:foo@example.com",
+							},
+						},
+						{
+							Role:      "roles/editor",
+							Condition: condition2,
+							Members: []iamv1beta1.Member{
+								"user:foo@example.com",
+							},
+						},
+					},
+				},
+			},
+			mergedPolicy: &iamv1beta1.IAMPartialPolicy{
+				Spec: iamv1beta1.IAMPartialPolicySpec{
+					Bindings: []iamv1beta1.IAMPartialPolicyBinding{
+						{
+							Role: "roles/editor",
+							Members: []iamv1beta1.IAMPartialPolicyMember{
+								{
+									Member: "user:foo@example.com",
+								},
+								{
+									Member: "user:foo@example.com",
+								},
+							},
+						},
+						{
+							Role:      "roles/editor",
+							Condition: condition2,
+							Members: []iamv1beta1.IAMPartialPolicyMember{
+								{
+									Member: "user:foo@example.com",
+								},
+							},
+						},
+					},
+				},
+				Status: iamv1beta1.IAMPartialPolicyStatus{
+					LastAppliedBindings: []iamv1beta1.IAMPolicyBinding{
+						{
+							Role: "roles/editor",
+							Members: []iamv1beta1.Member{
+								"user:foo@example.com",
+							},
+						},
+						{
+							Role:      "roles/editor",
+							Condition: condition2,
+							Members: []iamv1beta1.Member{
+								"user:foo@example.com",
+							},
+						},
+					},
+					AllBindings: []iamv1beta1.IAMPolicyBinding{
+						{
+							Role: "roles/editor",
+							Members: []iamv1beta1.Member{
+								"user:foo@example.com",
+							},
+						},
+						{
+							Role:      "roles/editor",
+							Condition: condition1,
+							Members: []iamv1beta1.Member{
+								"user:bar@example.com",
+							},
+						},
+						{
+							Role:      "roles/editor",
+							Condition: condition2,
+							Members: []iamv1beta1.Member{
+								"user:foo@example.com",
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
@@ -31,12 +31,14 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/lifecyclehandler"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/metrics"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/predicate"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/priorityqueue"
 	kccratelimiter "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/ratelimiter"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourceactuation"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourcewatcher"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/conversion"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/metadata"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/execution"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/credentials"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/util"
@@ -58,7 +60,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	klog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -101,25 +102,21 @@ func NewReconciler(mgr manager.Manager, provider *tfschema.Provider, smLoader *s
 		defaulters:                 defaulters,
 		immediateReconcileRequests: immediateReconcileRequests,
 		resourceWatcherRoutines:    resourceWatcherRoutines,
-		ReconcilerMetrics: metrics.ReconcilerMetrics{
-			ResourceNameLabel: metrics.ResourceNameLabel,
-		},
-		requeueRateLimiter: kccratelimiter.RequeueRateLimiter(),
-		jitterGen:          jg,
+		jitterGen:                  jg,
 	}
 	return &r, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler.
 func add(mgr manager.Manager, r *ReconcileIAMPartialPolicy) error {
-	obj := &iamv1beta1.IAMPartialPolicy{}
+	gate := priorityqueue.NewGate(iamv1beta1.IAMPartialPolicyGVK, k8s.ControllerMaxConcurrentReconciles)
 	_, err := builder.
 		ControllerManagedBy(mgr).
 		Named(controllerName).
 		WithOptions(controller.Options{MaxConcurrentReconciles: k8s.ControllerMaxConcurrentReconciles, RateLimiter: kccratelimiter.NewRateLimiter()}).
-		WatchesRawSource(&source.Channel{Source: r.immediateReconcileRequests}, &handler.EnqueueRequestForObject{}).
-		For(obj, builder.OnlyMetadata, builder.WithPredicates(predicate.UnderlyingResourceOutOfSyncPredicate{})).
-		Build(r)
+		WatchesRawSource(gate.Source(&source.Channel{Source: r.immediateReconcileRequests}), &handler.EnqueueRequestForObject{}).
+		WatchesRawSource(gate.Kind(mgr.GetCache(), iamv1beta1.IAMPartialPolicyGVK), &handler.EnqueueRequestForObject{}, builder.WithPredicates(predicate.UnderlyingResourceOutOfSyncPredicate{})).
+		Build(gate.Reconciler(r))
 	if err != nil {
 		return fmt.Errorf("error creating new controller: %w", err)
 	}
@@ -141,15 +138,15 @@ type ReconcileIAMPartialPolicy struct {
 	immediateReconcileRequests chan event.GenericEvent
 	resourceWatcherRoutines    *semaphore.Weighted // Used to cap number of goroutines watching unready dependencies
 
-	// rate limit requeues (periodic re-reconciliation), so we don't use the whole rate limit on re-reconciles
-	requeueRateLimiter ratelimiter.RateLimiter
-	jitterGen          jitter.Generator
+	jitterGen jitter.Generator
 }
 
 type reconcileContext struct {
 	Reconciler     *ReconcileIAMPartialPolicy
 	Ctx            context.Context
 	NamespacedName types.NamespacedName
+	// actuation is the actuation decision made by doReconcile.
+	actuation resourceactuation.ActuationDecision
 }
 
 func (r *ReconcileIAMPartialPolicy) Reconcile(ctx context.Context, request reconcile.Request) (result reconcile.Result, err error) {
@@ -157,6 +154,7 @@ func (r *ReconcileIAMPartialPolicy) Reconcile(ctx context.Context, request recon
 	startTime := time.Now()
 	ctx, cancel := context.WithTimeout(ctx, k8s.ReconcileDeadline)
 	defer cancel()
+	ctx = credentials.WithKind(ctx, iamv1beta1.IAMPartialPolicyGVK.Kind)
 	r.RecordReconcileWorkers(ctx, iamv1beta1.IAMPartialPolicyGVK)
 	defer r.AfterReconcile()
 	defer r.RecordReconcileMetrics(ctx, iamv1beta1.IAMPartialPolicyGVK, request.Namespace, request.Name, startTime, &err)
@@ -182,6 +180,10 @@ func (r *ReconcileIAMPartialPolicy) Reconcile(ctx context.Context, request recon
 		Ctx:            ctx,
 		NamespacedName: request.NamespacedName,
 	}
+	if k8s.HasReconcilePausedAnnotation(policy) {
+		logger.Info("Skipping reconcile as the resource has the reconcile-paused annotation", "resource", request.NamespacedName)
+		return reconcile.Result{}, runCtx.handlePaused(policy)
+	}
 	requeue, err := runCtx.doReconcile(policy)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -193,8 +195,7 @@ func (r *ReconcileIAMPartialPolicy) Reconcile(ctx context.Context, request recon
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	requeueDelay := r.requeueRateLimiter.When(request)
-	requeueAfter := jitteredPeriod + requeueDelay
+	requeueAfter := runCtx.actuation.RequeueAfter(jitteredPeriod, time.Now())
 	logger.Info("successfully finished reconcile", "resource", request.NamespacedName, "time to next reconciliation", requeueAfter)
 	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
@@ -216,9 +217,21 @@ func (r *reconcileContext) doReconcile(pp *iamv1beta1.IAMPartialPolicy) (requeue
 		return true, err
 	}
 
-	am := resourceactuation.DecideActuationMode(cc, ccc)
-	switch am {
+	decision, err := resourceactuation.DecideActuationMode(cc, ccc, time.Now())
+	if err != nil {
+		return false, err
+	}
+	r.actuation = decision
+	switch am := decision.Mode; am {
 	case v1beta1.Reconciling:
+		if decision.PendingActuationWindow {
+			ensureFinalizers := func() error {
+				k8s.EnsureFinalizers(pp, k8s.ControllerFinalizerName, k8s.DeletionDefenderFinalizerName)
+				return nil
+			}
+			toResource := func() (*k8s.Resource, error) { return toK8sResource(pp) }
+			return false, decision.HandlePendingActuationWindow(r.Ctx, r.Reconciler, pp, ensureFinalizers, toResource)
+		}
 		logger.V(2).Info("Actuating a resource as actuation mode is \"Reconciling\"", "resource", r.NamespacedName)
 	case v1beta1.Paused:
 		logger.Info("Skipping actuation of resource as actuation mode is \"Paused\"", "resource", r.NamespacedName)
@@ -256,13 +269,16 @@ func (r *reconcileContext) doReconcile(pp *iamv1beta1.IAMPartialPolicy) (requeue
 		return false, r.handleUpdateFailed(pp, fmt.Errorf("error computing partial policy: %w", err))
 	}
 	desiredPolicy := toDesiredPolicy(desiredPartialPolicy, iamPolicy)
-	if _, err = r.Reconciler.iamClient.SetPolicy(r.Ctx, desiredPolicy); err != nil {
+	appliedPolicy, err := r.Reconciler.iamClient.SetPolicy(r.Ctx, desiredPolicy)
+	if err != nil {
 		if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
 			logger.Info(unwrappedErr.Error(), "resource", k8s.GetNamespacedName(pp))
 			return r.handleUnresolvableDeps(pp, unwrappedErr)
 		}
 		return false, r.handleUpdateFailed(pp, fmt.Errorf("error setting policy: %w", err))
 	}
+	desiredPartialPolicy.Status.Etag = appliedPolicy.Spec.Etag
+	desiredPartialPolicy.Status.Version = kcciamclient.PolicyVersion(appliedPolicy.Spec.Bindings)
 	if isAPIServerUpdateRequired(desiredPartialPolicy, pp) {
 		return false, r.handleUpToDate(desiredPartialPolicy)
 	}
@@ -322,6 +338,14 @@ func (r *reconcileContext) handleUpToDate(policy *iamv1beta1.IAMPartialPolicy) e
 	return r.Reconciler.HandleUpToDate(r.Ctx, resource)
 }
 
+func (r *reconcileContext) handlePaused(policy *iamv1beta1.IAMPartialPolicy) error {
+	resource, err := toK8sResource(policy)
+	if err != nil {
+		return fmt.Errorf("error converting IAMPartialPolicy to k8s resource while handling %v event: %w", k8s.Paused, err)
+	}
+	return r.Reconciler.HandlePaused(r.Ctx, resource)
+}
+
 func (r *reconcileContext) handleUpdateFailed(policy *iamv1beta1.IAMPartialPolicy, origErr error) error {
 	resource, err := toK8sResource(policy)
 	if err != nil {
@@ -452,6 +476,9 @@ func isAPIServerUpdateRequired(desired, original *iamv1beta1.IAMPartialPolicy) b
 	if !reflect.DeepEqual(desired.Status.AllBindings, original.Status.AllBindings) {
 		return true
 	}
+	if desired.Status.Etag != original.Status.Etag || desired.Status.Version != original.Status.Version {
+		return true
+	}
 	return false
 }
 
@@ -30,6 +30,8 @@ func TestIsAPIServerUpdateRequired(t *testing.T) {
 	tests := []struct {
 		name           string
 		policy         *iamv1beta1.IAMPolicy
+		etag           string
+		version        int64
 		expectedResult bool
 	}{
 		{
@@ -86,12 +88,50 @@ func TestIsAPIServerUpdateRequired(t *testing.T) {
 			},
 			expectedResult: false,
 		},
+		{
+			name: "conditions and observed generation are up to date, etag is stale",
+			policy: &iamv1beta1.IAMPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 2,
+				},
+				Status: iamv1beta1.IAMPolicyStatus{
+					Conditions: []condition.Condition{
+						k8s.NewCustomReadyCondition(corev1.ConditionTrue, k8s.UpToDate, k8s.UpToDateMessage),
+					},
+					ObservedGeneration: 2,
+					Etag:               "BwXhqDmkuR0=",
+					Version:            1,
+				},
+			},
+			etag:           "BwXhqDuy4Ys=",
+			version:        3,
+			expectedResult: true,
+		},
+		{
+			name: "conditions, observed generation and etag are up to date",
+			policy: &iamv1beta1.IAMPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 2,
+				},
+				Status: iamv1beta1.IAMPolicyStatus{
+					Conditions: []condition.Condition{
+						k8s.NewCustomReadyCondition(corev1.ConditionTrue, k8s.UpToDate, k8s.UpToDateMessage),
+					},
+					ObservedGeneration: 2,
+					Etag:               "BwXhqDuy4Ys=",
+					Version:            3,
+				},
+			},
+			etag:           "BwXhqDuy4Ys=",
+			version:        3,
+			expectedResult: false,
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			actual := isAPIServerUpdateRequired(tc.policy)
+			actual := isAPIServerUpdateRequired(tc.policy, tc.etag, tc.version)
 			if actual != tc.expectedResult {
 				t.Fatalf("got %v, want %v", actual, tc.expectedResult)
 			}
@@ -30,12 +30,14 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/lifecyclehandler"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/metrics"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/predicate"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/priorityqueue"
 	kccratelimiter "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/ratelimiter"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourceactuation"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourcewatcher"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/conversion"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/metadata"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/execution"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/credentials"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/util"
@@ -56,7 +58,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	klog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -99,7 +100,6 @@ func NewReconciler(mgr manager.Manager, provider *tfschema.Provider, smLoader *s
 		defaulters:                 defaulters,
 		immediateReconcileRequests: immediateReconcileRequests,
 		resourceWatcherRoutines:    resourceWatcherRoutines,
-		requeueRateLimiter:         kccratelimiter.RequeueRateLimiter(),
 		jitterGen:                  jg,
 	}
 
@@ -108,14 +108,14 @@ func NewReconciler(mgr manager.Manager, provider *tfschema.Provider, smLoader *s
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler.
 func add(mgr manager.Manager, r *Reconciler) error {
-	obj := &iamv1beta1.IAMPolicyMember{}
+	gate := priorityqueue.NewGate(iamv1beta1.IAMPolicyMemberGVK, k8s.ControllerMaxConcurrentReconciles)
 	_, err := builder.
 		ControllerManagedBy(mgr).
 		Named(controllerName).
 		WithOptions(controller.Options{MaxConcurrentReconciles: k8s.ControllerMaxConcurrentReconciles, RateLimiter: kccratelimiter.NewRateLimiter()}).
-		WatchesRawSource(&source.Channel{Source: r.immediateReconcileRequests}, &handler.EnqueueRequestForObject{}).
-		For(obj, builder.OnlyMetadata, builder.WithPredicates(predicate.UnderlyingResourceOutOfSyncPredicate{})).
-		Build(r)
+		WatchesRawSource(gate.Source(&source.Channel{Source: r.immediateReconcileRequests}), &handler.EnqueueRequestForObject{}).
+		WatchesRawSource(gate.Kind(mgr.GetCache(), iamv1beta1.IAMPolicyMemberGVK), &handler.EnqueueRequestForObject{}, builder.WithPredicates(predicate.UnderlyingResourceOutOfSyncPredicate{})).
+		Build(gate.Reconciler(r))
 	if err != nil {
 		return fmt.Errorf("error creating new controller: %w", err)
 	}
@@ -136,15 +136,15 @@ type Reconciler struct {
 	immediateReconcileRequests chan event.GenericEvent
 	resourceWatcherRoutines    *semaphore.Weighted // Used to cap number of goroutines watching unready dependencies
 
-	// rate limit requeues (periodic re-reconciliation), so we don't use the whole rate limit on re-reconciles
-	requeueRateLimiter ratelimiter.RateLimiter
-	jitterGen          jitter.Generator
+	jitterGen jitter.Generator
 }
 
 type reconcileContext struct {
 	Reconciler     *Reconciler
 	Ctx            context.Context
 	NamespacedName types.NamespacedName
+	// actuation is the actuation decision made by doReconcile.
+	actuation resourceactuation.ActuationDecision
 }
 
 // Reconcile checks k8s for the current state of the resource.
@@ -153,6 +153,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	startTime := time.Now()
 	ctx, cancel := context.WithTimeout(ctx, k8s.ReconcileDeadline)
 	defer cancel()
+	ctx = credentials.WithKind(ctx, iamv1beta1.IAMPolicyMemberGVK.Kind)
 	r.RecordReconcileWorkers(ctx, iamv1beta1.IAMPolicyMemberGVK)
 	defer r.AfterReconcile()
 	defer r.RecordReconcileMetrics(ctx, iamv1beta1.IAMPolicyMemberGVK, request.Namespace, request.Name, startTime, &err)
@@ -175,6 +176,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		Ctx:            ctx,
 		NamespacedName: request.NamespacedName,
 	}
+	if k8s.HasReconcilePausedAnnotation(&memberPolicy) {
+		logger.Info("Skipping reconcile as the resource has the reconcile-paused annotation", "resource", request.NamespacedName)
+		return reconcile.Result{}, reconcileContext.handlePaused(&memberPolicy)
+	}
 	requeue, err := reconcileContext.doReconcile(&memberPolicy)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -187,8 +192,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	requeueDelay := r.requeueRateLimiter.When(request)
-	requeueAfter := jitteredPeriod + requeueDelay
+	requeueAfter := reconcileContext.actuation.RequeueAfter(jitteredPeriod, time.Now())
 	logger.Info("successfully finished reconcile", "resource", request.NamespacedName, "time to next reconciliation", requeueAfter)
 	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
@@ -210,9 +214,21 @@ func (r *reconcileContext) doReconcile(policyMember *iamv1beta1.IAMPolicyMember)
 		return true, err
 	}
 
-	am := resourceactuation.DecideActuationMode(cc, ccc)
-	switch am {
+	decision, err := resourceactuation.DecideActuationMode(cc, ccc, time.Now())
+	if err != nil {
+		return false, err
+	}
+	r.actuation = decision
+	switch am := decision.Mode; am {
 	case opcorev1beta1.Reconciling:
+		if decision.PendingActuationWindow {
+			ensureFinalizers := func() error {
+				k8s.EnsureFinalizers(policyMember, k8s.ControllerFinalizerName, k8s.DeletionDefenderFinalizerName)
+				return nil
+			}
+			toResource := func() (*k8s.Resource, error) { return ToK8sResource(policyMember) }
+			return false, decision.HandlePendingActuationWindow(r.Ctx, r.Reconciler, policyMember, ensureFinalizers, toResource)
+		}
 		logger.V(2).Info("Actuating a resource as actuation mode is \"Reconciling\"", "resource", r.NamespacedName)
 	case opcorev1beta1.Paused:
 		logger.Info("Skipping actuation of resource as actuation mode is \"Paused\"", "resource", r.NamespacedName)
@@ -269,14 +285,19 @@ func (r *reconcileContext) doReconcile(policyMember *iamv1beta1.IAMPolicyMember)
 			return false, r.handleUpdateFailed(policyMember, err)
 		}
 	}
-	if _, err := r.Reconciler.iamClient.SetPolicyMember(r.Ctx, policyMember); err != nil {
+	appliedPolicyMember, err := r.Reconciler.iamClient.SetPolicyMember(r.Ctx, policyMember)
+	if err != nil {
 		if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
 			logger.Info(unwrappedErr.Error(), "resource", k8s.GetNamespacedName(policyMember))
 			return r.handleUnresolvableDeps(policyMember, unwrappedErr)
 		}
 		return false, r.handleUpdateFailed(policyMember, fmt.Errorf("error setting policy member: %w", err))
 	}
-	if isAPIServerUpdateRequired(policyMember) {
+	etag := appliedPolicyMember.Status.Etag
+	version := kcciamclient.PolicyVersion([]iamv1beta1.IAMPolicyBinding{{Condition: policyMember.Spec.Condition}})
+	if isAPIServerUpdateRequired(policyMember, etag, version) {
+		policyMember.Status.Etag = etag
+		policyMember.Status.Version = version
 		return false, r.handleUpToDate(policyMember)
 	}
 	return false, nil
@@ -297,6 +318,14 @@ func (r *reconcileContext) handleUpToDate(policyMember *iamv1beta1.IAMPolicyMemb
 	return r.Reconciler.HandleUpToDate(r.Ctx, resource)
 }
 
+func (r *reconcileContext) handlePaused(policyMember *iamv1beta1.IAMPolicyMember) error {
+	resource, err := ToK8sResource(policyMember)
+	if err != nil {
+		return fmt.Errorf("error converting IAMPolicyMember to k8s resource while handling %v event: %w", k8s.Paused, err)
+	}
+	return r.Reconciler.HandlePaused(r.Ctx, resource)
+}
+
 func (r *reconcileContext) handleUpdateFailed(policyMember *iamv1beta1.IAMPolicyMember, origErr error) error {
 	resource, err := ToK8sResource(policyMember)
 	if err != nil {
@@ -399,7 +428,7 @@ func (r *Reconciler) enqueueForImmediateReconciliation(resourceNN types.Namespac
 	r.immediateReconcileRequests <- genEvent
 }
 
-func isAPIServerUpdateRequired(policyMember *iamv1beta1.IAMPolicyMember) bool {
+func isAPIServerUpdateRequired(policyMember *iamv1beta1.IAMPolicyMember, etag string, version int64) bool {
 	// TODO: even in the event of an actual update to GCP, this function will
 	// return false because the condition comparison doesn't account for time.
 	conditions := []condition.Condition{
@@ -411,6 +440,9 @@ func isAPIServerUpdateRequired(policyMember *iamv1beta1.IAMPolicyMember) bool {
 	if policyMember.Status.ObservedGeneration != policyMember.GetGeneration() {
 		return true
 	}
+	if policyMember.Status.Etag != etag || policyMember.Status.Version != version {
+		return true
+	}
 	return false
 }
 
@@ -18,11 +18,13 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
 
 	operatorv1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/config"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/credentialrules"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/kccmanager/nocache"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/registration"
@@ -31,9 +33,15 @@ import (
 	dclmetadata "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/metadata"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/schema/dclschemaloader"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/credentials"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/ratelimit"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/lease/leaser"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
 	tfprovider "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tf/provider"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tracing"
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+	"golang.org/x/oauth2/google"
 	"google.golang.org/grpc"
 
 	corev1 "k8s.io/api/core/v1"
@@ -78,6 +86,19 @@ type Config struct {
 	// StateIntoSpecUserOverride is an optional field. If specified, it is used
 	// as the default value for 'state-into-spec' annotation if unset.
 	StateIntoSpecUserOverride *string
+
+	// GCPRateLimit is an optional field. If specified, the requests made to GCP APIs by
+	// the TF, DCL and direct controllers are throttled per (project, service).
+	GCPRateLimit *ratelimit.Config
+
+	// LeaseBackends configures the external backends of the "gcs" and "kubernetes-lease"
+	// management conflict prevention policies. The backends which are not configured are unavailable.
+	LeaseBackends leaser.BackendsConfig
+
+	// GCPTracing is an optional field. If set, the requests made to GCP APIs by the TF, DCL
+	// and direct controllers are recorded as OpenTelemetry spans. The spans are exported by
+	// the global tracer provider, see tracing.Setup.
+	GCPTracing bool
 }
 
 // Creates a new controller-runtime manager.Manager and starts all of the KCC controllers pointed at the
@@ -107,13 +128,45 @@ func New(ctx context.Context, restConfig *rest.Config, cfg Config) (manager.Mana
 	if err != nil {
 		return nil, fmt.Errorf("error creating new manager: %w", err)
 	}
+	var credentialSelector *credentials.Selector
+	if opts.Namespace != "" {
+		// In namespaced mode, the credentials can be selected per kind and project with the
+		// credentialRules of the namespace's ConfigConnectorContext.
+		credentialSelector = credentials.NewSelector(ctx)
+		if err := credentialrules.Load(ctx, mgr.GetAPIReader(), opts.Namespace, credentialSelector); err != nil {
+			return nil, err
+		}
+		if err := credentialrules.Add(mgr, credentialSelector); err != nil {
+			return nil, fmt.Errorf("error adding credential rules controller: %w", err)
+		}
+	}
+	// Without a rate limit, the requests still go through an unlimited Limiter, which
+	// records the GCP request metrics.
+	rateLimiter := ratelimit.New(ratelimit.Config{})
+	if cfg.GCPRateLimit.IsEnabled() {
+		rateLimiter = ratelimit.New(*cfg.GCPRateLimit)
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		// The GCP request metrics, rate limit, credential rules and tracing are applied by
+		// wrapping the http client, so the DCL and direct controllers always need an explicit
+		// (default) http client to wrap. The default credentials are only looked up on the
+		// first request, so that creating the manager doesn't require them.
+		httpClient = &http.Client{Transport: &defaultCredentialsTransport{ctx: ctx}}
+	}
+
 	// Bootstrap the Google Terraform provider
 	tfCfg := tfprovider.NewConfig()
 	tfCfg.UserProjectOverride = cfg.UserProjectOverride
 	tfCfg.BillingProject = cfg.BillingProject
 	tfCfg.GCPAccessToken = cfg.GCPAccessToken
 
-	provider, err := tfprovider.New(ctx, tfCfg)
+	tfTransportCtx := withTFTransport(ctx, &tfTransport{
+		credentialSelector: credentialSelector,
+		rateLimiter:        rateLimiter,
+		tracing:            cfg.GCPTracing,
+	})
+	provider, err := tfprovider.New(tfTransportCtx, tfCfg)
 	if err != nil {
 		return nil, fmt.Errorf("error creating TF provider: %w", err)
 	}
@@ -132,7 +185,10 @@ func New(ctx context.Context, restConfig *rest.Config, cfg Config) (manager.Mana
 	dclOptions := clientconfig.Options{}
 	dclOptions.UserProjectOverride = cfg.UserProjectOverride
 	dclOptions.BillingProject = cfg.BillingProject
-	dclOptions.HTTPClient = cfg.HTTPClient
+	dclOptions.HTTPClient = httpClient
+	dclOptions.GCPRateLimiter = rateLimiter
+	dclOptions.GCPCredentialSelector = credentialSelector
+	dclOptions.GCPTracing = cfg.GCPTracing
 	dclOptions.UserAgent = gcp.KCCUserAgent
 
 	dclConfig, err := clientconfig.New(ctx, dclOptions)
@@ -144,9 +200,12 @@ func New(ctx context.Context, restConfig *rest.Config, cfg Config) (manager.Mana
 	controllerConfig := &config.ControllerConfig{
 		UserProjectOverride:        cfg.UserProjectOverride,
 		BillingProject:             cfg.BillingProject,
-		HTTPClient:                 cfg.HTTPClient,
+		HTTPClient:                 httpClient,
 		GRPCUnaryClientInterceptor: cfg.GRPCUnaryClientInterceptor,
 		UserAgent:                  gcp.KCCUserAgent,
+		GCPRateLimiter:             rateLimiter,
+		GCPCredentialSelector:      credentialSelector,
+		GCPTracing:                 cfg.GCPTracing,
 	}
 
 	// Initialize direct controllers
@@ -154,12 +213,22 @@ func New(ctx context.Context, restConfig *rest.Config, cfg Config) (manager.Mana
 		return nil, err
 	}
 
+	gcpOpts, err := controllerConfig.RESTClientOptions()
+	if err != nil {
+		return nil, fmt.Errorf("error building GCP client options: %w", err)
+	}
+	leaseBackends, err := leaser.NewBackends(ctx, cfg.LeaseBackends, restConfig, gcpOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating lease backends: %w", err)
+	}
+
 	rd := controller.Deps{
-		TfProvider:   provider,
-		TfLoader:     smLoader,
-		DclConfig:    dclConfig,
-		DclConverter: dclConverter,
-		Defaulters:   []k8s.Defaulter{stateIntoSpecDefaulter},
+		TfProvider:    provider,
+		TfLoader:      smLoader,
+		DclConfig:     dclConfig,
+		DclConverter:  dclConverter,
+		Defaulters:    []k8s.Defaulter{stateIntoSpecDefaulter},
+		LeaseBackends: leaseBackends,
 	}
 	// Register the registration controller, which will dynamically create controllers for
 	// all our resources.
@@ -170,6 +239,95 @@ func New(ctx context.Context, restConfig *rest.Config, cfg Config) (manager.Mana
 	return mgr, nil
 }
 
+// defaultCredentialsTransport sends the requests with the transport of the default http client,
+// which is created on the first request.
+type defaultCredentialsTransport struct {
+	ctx context.Context
+
+	mutex     sync.Mutex
+	transport http.RoundTripper
+}
+
+func (t *defaultCredentialsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport, err := t.defaultTransport()
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return transport.RoundTrip(req)
+}
+
+// defaultTransport returns the transport of the default http client. It is created again on the
+// next request if it cannot be created, e.g. while the metadata server is unavailable.
+func (t *defaultCredentialsTransport) defaultTransport() (http.RoundTripper, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.transport == nil {
+		client, err := google.DefaultClient(t.ctx, gcp.ClientScopes...)
+		if err != nil {
+			return nil, fmt.Errorf("error creating the default http client: %w", err)
+		}
+		t.transport = client.Transport
+	}
+	return t.transport, nil
+}
+
+// tfTransport configures the http client of the TF provider of a manager.
+type tfTransport struct {
+	// credentialSelector selects the credentials of the requests made by the TF provider.
+	credentialSelector *credentials.Selector
+	// rateLimiter throttles the requests made by the TF provider.
+	rateLimiter *ratelimit.Limiter
+	// tracing records the requests made by the TF provider as spans.
+	tracing bool
+}
+
+type tfTransportKey struct{}
+
+var installTFHTTPClientTransformer sync.Once
+
+// withTFTransport returns a copy of ctx recording t, which is applied to the http client of a
+// TF provider configured with the returned context.
+//
+// The TF provider only allows customizing its http client through a global transformer, which
+// is shared by all the managers of the process. So the transformer is only installed once, and
+// looks up the configuration of each manager from the context the TF provider is configured with.
+// Any transformer which is already installed (e.g. by a test harness) is kept.
+func withTFTransport(ctx context.Context, t *tfTransport) context.Context {
+	installTFHTTPClientTransformer.Do(func() {
+		previous := transport_tpg.DefaultHTTPClientTransformer
+		transport_tpg.DefaultHTTPClientTransformer = func(ctx context.Context, inner *http.Client) *http.Client {
+			if previous != nil {
+				inner = previous(ctx, inner)
+			}
+			if t, ok := ctx.Value(tfTransportKey{}).(*tfTransport); ok {
+				inner = t.wrapHTTPClient(inner)
+			}
+			return inner
+		}
+	})
+	return context.WithValue(ctx, tfTransportKey{}, t)
+}
+
+func (t *tfTransport) wrapHTTPClient(client *http.Client) *http.Client {
+	// The credentials are selected first, so that the requests sent with the credentials of a
+	// rule are still throttled.
+	if t.credentialSelector != nil {
+		client = t.credentialSelector.WrapHTTPClient(client)
+	}
+	if t.rateLimiter != nil {
+		client = t.rateLimiter.WrapHTTPClient(client)
+	}
+	// The spans are recorded last, so that they include the time spent waiting for the rate limit.
+	if t.tracing {
+		client = tracing.WrapHTTPClient(client)
+	}
+	return client
+}
+
 func addSchemes(scheme *runtime.Scheme) error {
 	if err := corev1.AddToScheme(scheme); err != nil {
 		return fmt.Errorf("error adding 'corev1' resources to the scheme: %w", err)
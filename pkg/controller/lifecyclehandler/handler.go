@@ -17,6 +17,7 @@ package lifecyclehandler
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corekccv1alpha1 "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/core/v1alpha1"
 	k8sv1alpha1 "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/apis/k8s/v1alpha1"
@@ -25,6 +26,7 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/label"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/lease/leaser"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/resourceoverrides"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tracing"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/util"
 
 	corev1 "k8s.io/api/core/v1"
@@ -41,10 +43,14 @@ import (
 // The LifecycleHandler contains common methods to handle the lifecycle of the reconciliation
 type LifecycleHandler struct {
 	client.Client
-	Recorder   record.EventRecorder
-	fieldOwner string
+	Recorder     record.EventRecorder
+	fieldOwner   string
+	statusWriter StatusWriter
 }
 
+// StatusWriter writes the status of u to the API server, and syncs u with the written object.
+type StatusWriter func(ctx context.Context, u *unstructured.Unstructured) error
+
 func NewLifecycleHandler(c client.Client, r record.EventRecorder) LifecycleHandler {
 	return NewLifecycleHandlerWithFieldOwner(c, r, k8s.ControllerManagedFieldManager)
 }
@@ -57,12 +63,26 @@ func NewLifecycleHandlerWithFieldOwner(c client.Client, r record.EventRecorder,
 	}
 }
 
-func (r *LifecycleHandler) updateStatus(ctx context.Context, resource *k8s.Resource) error {
+// NewLifecycleHandlerWithStatusWriter returns a LifecycleHandler which writes the status of the
+// resources with w, e.g. with a server-side apply, rather than with a status update.
+func NewLifecycleHandlerWithStatusWriter(c client.Client, r record.EventRecorder, w StatusWriter) LifecycleHandler {
+	h := NewLifecycleHandler(c, r)
+	h.statusWriter = w
+	return h
+}
+
+func (r *LifecycleHandler) updateStatus(ctx context.Context, resource *k8s.Resource) (err error) {
+	ctx, span := tracing.Start(ctx, "UpdateStatus")
+	defer func() { tracing.End(span, err) }()
 	u, err := resource.MarshalAsUnstructured()
 	if err != nil {
 		return err
 	}
-	if err := r.Client.Status().Update(ctx, u, client.FieldOwner(r.fieldOwner)); err != nil {
+	if r.statusWriter != nil {
+		if err := r.statusWriter(ctx, u); err != nil {
+			return fmt.Errorf("error writing status to API server: %w", err)
+		}
+	} else if err := r.Client.Status().Update(ctx, u, client.FieldOwner(r.fieldOwner)); err != nil {
 		if apierrors.IsConflict(err) {
 			return fmt.Errorf("couldn't update the API server due to conflict. Re-enqueue the request for another reconciliation attempt: %w", err)
 		}
@@ -82,7 +102,9 @@ func (r *LifecycleHandler) updateStatus(ctx context.Context, resource *k8s.Resou
 
 // WARNING: This function should NOT be exported and invoked directly outside the package.
 // Controllers are supposed to call exported functions to handle lifecycle transitions.
-func (r *LifecycleHandler) updateAPIServer(ctx context.Context, resource *k8s.Resource) error {
+func (r *LifecycleHandler) updateAPIServer(ctx context.Context, resource *k8s.Resource) (err error) {
+	ctx, span := tracing.Start(ctx, "UpdateAPIServer")
+	defer func() { tracing.End(span, err) }()
 	// Preserve the intended status, as the client.Update call will ignore the given status
 	// and return the stale existing status.
 	status := deepcopy.MapStringInterface(resource.Status)
@@ -183,7 +205,8 @@ func CausedByUnresolvableDeps(err error) (unwrappedErr error, ok bool) { //nolin
 	return nil, false
 }
 
-func reasonForUnresolvableDeps(err error) (string, error) {
+// ReasonForUnresolvableDeps returns the reason of the ready condition for an error caused by unresolvable dependencies.
+func ReasonForUnresolvableDeps(err error) (string, error) {
 	switch {
 	case k8s.IsReferenceNotReadyError(err) || k8s.IsTransitiveDependencyNotReadyError(err):
 		return k8s.DependencyNotReady, nil
@@ -230,7 +253,7 @@ func (r *LifecycleHandler) HandleUpToDate(ctx context.Context, resource *k8s.Res
 }
 
 func (r *LifecycleHandler) HandleUnresolvableDeps(ctx context.Context, resource *k8s.Resource, originErr error) error {
-	reason, err := reasonForUnresolvableDeps(originErr)
+	reason, err := ReasonForUnresolvableDeps(originErr)
 	if err != nil {
 		return r.HandleUpdateFailed(ctx, resource, err)
 	}
@@ -249,6 +272,16 @@ func (r *LifecycleHandler) HandleUnresolvableDeps(ctx context.Context, resource
 }
 
 func (r *LifecycleHandler) HandleObtainLeaseFailed(ctx context.Context, resource *k8s.Resource, err error) error {
+	return r.handleManagementConflict(ctx, resource, err)
+}
+
+// HandleFieldManagerConflict handles a write to the resource which conflicts with the fields managed by
+// another field manager, e.g. a server-side apply of the status.
+func (r *LifecycleHandler) HandleFieldManagerConflict(ctx context.Context, resource *k8s.Resource, err error) error {
+	return r.handleManagementConflict(ctx, resource, err)
+}
+
+func (r *LifecycleHandler) handleManagementConflict(ctx context.Context, resource *k8s.Resource, err error) error {
 	msg := err.Error()
 	// Only update the API server if there's new information
 	if !k8s.ReadyConditionMatches(resource, corev1.ConditionFalse, k8s.ManagementConflict, msg) {
@@ -304,6 +337,24 @@ func (r *LifecycleHandler) HandleUpdating(ctx context.Context, resource *k8s.Res
 	return nil
 }
 
+// HandleOperationPending handles a GCP long-running operation which the controller is waiting for
+// across reconciliations. The operation is expected to be recorded in the status of the resource.
+func (r *LifecycleHandler) HandleOperationPending(ctx context.Context, resource *k8s.Resource, operationType, operationName string) error {
+	msg := fmt.Sprintf(k8s.OperationPendingMessageTmpl, operationType, operationName)
+	// Only update the API server if there's new information
+	if k8s.ReadyConditionMatches(resource, corev1.ConditionFalse, k8s.Updating, msg) {
+		return nil
+	}
+	setCondition(resource, corev1.ConditionFalse, k8s.Updating, msg)
+	setObservedGeneration(resource, resource.GetGeneration())
+	if err := r.updateStatus(ctx, resource); err != nil {
+		return err
+	}
+
+	r.recordEvent(ctx, resource, corev1.EventTypeNormal, k8s.Updating, msg)
+	return nil
+}
+
 func (r *LifecycleHandler) HandleUpdateFailed(ctx context.Context, resource *k8s.Resource, err error) error {
 	msg := fmt.Errorf("Update call failed: %w", err).Error()
 	setCondition(resource, corev1.ConditionFalse, k8s.UpdateFailed, msg)
@@ -342,6 +393,22 @@ func (r *LifecycleHandler) HandleDeleted(ctx context.Context, resource *k8s.Reso
 	return r.updateAPIServer(ctx, resource)
 }
 
+// HandleDeletionBlockedByPlanMode handles the deletion of a resource in plan mode, which would delete the GCP resource.
+func (r *LifecycleHandler) HandleDeletionBlockedByPlanMode(ctx context.Context, resource *k8s.Resource) error {
+	// Only update the API server if there's new information
+	if k8s.ReadyConditionMatches(resource, corev1.ConditionFalse, k8s.DeletionBlockedByPlanMode, k8s.DeletionBlockedByPlanModeMessage) {
+		return nil
+	}
+	setCondition(resource, corev1.ConditionFalse, k8s.DeletionBlockedByPlanMode, k8s.DeletionBlockedByPlanModeMessage)
+	setObservedGeneration(resource, resource.GetGeneration())
+	if err := r.updateStatus(ctx, resource); err != nil {
+		return err
+	}
+
+	r.recordEvent(ctx, resource, corev1.EventTypeWarning, k8s.DeletionBlockedByPlanMode, k8s.DeletionBlockedByPlanModeMessage)
+	return nil
+}
+
 func (r *LifecycleHandler) HandleDeleteFailed(ctx context.Context, resource *k8s.Resource, err error) error {
 	msg := fmt.Sprintf(k8s.DeleteFailedMessageTmpl, err)
 	setCondition(resource, corev1.ConditionFalse, k8s.DeleteFailed, msg)
@@ -366,6 +433,54 @@ func (r *LifecycleHandler) HandleUnmanaged(ctx context.Context, resource *k8s.Re
 	return nil
 }
 
+// HandlePaused surfaces that reconciliation of the resource is paused by the
+// reconcile-paused annotation. The Ready status is preserved, so that dependent
+// resources are not blocked by a paused resource, and the observed generation
+// is left unchanged as the current spec has not been reconciled.
+func (r *LifecycleHandler) HandlePaused(ctx context.Context, resource *k8s.Resource) error {
+	status := corev1.ConditionFalse
+	if currentReadyCondition, found := k8s.GetReadyCondition(resource); found && currentReadyCondition.Status == corev1.ConditionTrue {
+		status = corev1.ConditionTrue
+	}
+	// Only update the API server if there's new information
+	if k8s.ReadyConditionMatches(resource, status, k8s.Paused, k8s.PausedMessage) {
+		return nil
+	}
+	setCondition(resource, status, k8s.Paused, k8s.PausedMessage)
+	if err := r.updateStatus(ctx, resource); err != nil {
+		return err
+	}
+
+	r.recordEvent(ctx, resource, corev1.EventTypeNormal, k8s.Paused, k8s.PausedMessage)
+	return nil
+}
+
+// HandlePendingActuationWindow surfaces that actuation of the resource is waiting
+// for the next actuation window, starting at nextWindow (the zero time if there is
+// none). Like for HandlePaused, the Ready status and the observed generation are
+// preserved.
+func (r *LifecycleHandler) HandlePendingActuationWindow(ctx context.Context, resource *k8s.Resource, nextWindow time.Time) error {
+	status := corev1.ConditionFalse
+	if currentReadyCondition, found := k8s.GetReadyCondition(resource); found && currentReadyCondition.Status == corev1.ConditionTrue {
+		status = corev1.ConditionTrue
+	}
+	msg := k8s.NoActuationWindowMessage
+	if !nextWindow.IsZero() {
+		msg = fmt.Sprintf(k8s.PendingActuationWindowMessageTmpl, nextWindow.UTC().Format(time.RFC3339))
+	}
+	// Only update the API server if there's new information
+	if k8s.ReadyConditionMatches(resource, status, k8s.PendingActuationWindow, msg) {
+		return nil
+	}
+	setCondition(resource, status, k8s.PendingActuationWindow, msg)
+	if err := r.updateStatus(ctx, resource); err != nil {
+		return err
+	}
+
+	r.recordEvent(ctx, resource, corev1.EventTypeNormal, k8s.PendingActuationWindow, msg)
+	return nil
+}
+
 func setCondition(resource *k8s.Resource, status corev1.ConditionStatus, reason, msg string) {
 	if resource.Status == nil {
 		resource.Status = make(map[string]interface{})
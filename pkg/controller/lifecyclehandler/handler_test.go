@@ -241,19 +241,19 @@ func Test_reasonForUnresolvableDeps(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			got, err := reasonForUnresolvableDeps(test.err)
+			got, err := ReasonForUnresolvableDeps(test.err)
 			if test.wantErr {
 				if err == nil {
-					t.Errorf("reasonForUnresolvableDeps() error = nil, want err")
+					t.Errorf("ReasonForUnresolvableDeps() error = nil, want err")
 				}
 				return
 			}
 			if err != nil {
-				t.Errorf("reasonForUnresolvableDeps() error = %v, want nil", err)
+				t.Errorf("ReasonForUnresolvableDeps() error = %v, want nil", err)
 				return
 			}
 			if got != test.want {
-				t.Errorf("reasonForUnresolvableDeps() got = %v, want %v", got, test.want)
+				t.Errorf("ReasonForUnresolvableDeps() got = %v, want %v", got, test.want)
 			}
 		})
 	}
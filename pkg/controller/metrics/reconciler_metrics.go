@@ -23,24 +23,19 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/errors"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/metrics"
-	"go.opencensus.io/stats"
-	"go.opencensus.io/tag"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
-var ResourceNameLabel bool
-
 type ReconcilerMetrics struct {
 	// atomic counter for occupied workers
-	occupiedWorkers   int64
-	ResourceNameLabel bool
+	occupiedWorkers int64
 }
 
 func (r *ReconcilerMetrics) RecordReconcileWorkers(ctx context.Context, gvk schema.GroupVersionKind) {
 	atomic.AddInt64(&r.occupiedWorkers, 1)
-	openCensusContext, _ := tag.New(ctx, tag.Insert(metrics.KindTag, gvk.GroupKind().String()))
-	stats.Record(openCensusContext, metrics.MReconcileTotalWorkers.M(k8s.ControllerMaxConcurrentReconciles))
-	stats.Record(openCensusContext, metrics.MReconcileOccupiedWorkers.M(atomic.LoadInt64(&r.occupiedWorkers)))
+	labels := metrics.Labels{metrics.KindLabel: gvk.GroupKind().String()}
+	metrics.MReconcileTotalWorkers.Set(k8s.ControllerMaxConcurrentReconciles, labels)
+	metrics.MReconcileOccupiedWorkers.Set(float64(atomic.LoadInt64(&r.occupiedWorkers)), labels)
 }
 
 func (r *ReconcilerMetrics) RecordReconcileMetrics(ctx context.Context, gvk schema.GroupVersionKind, ns, name string, startTime time.Time, reconcileErr *error) {
@@ -52,11 +47,16 @@ func (r *ReconcilerMetrics) RecordReconcileMetrics(ctx context.Context, gvk sche
 	if *reconcileErr != nil {
 		status = "ERROR"
 	}
-	openCensusContext, _ := tag.New(ctx, tag.Insert(metrics.KindTag, gvk.GroupKind().String()), tag.Insert(metrics.NamespaceTag, ns), tag.Insert(metrics.StatusTag, status))
-	if r.ResourceNameLabel {
-		openCensusContext, _ = tag.New(openCensusContext, tag.Insert(metrics.ResourceNameTag, name))
+	labels := metrics.Labels{
+		metrics.KindLabel:         gvk.GroupKind().String(),
+		metrics.NamespaceLabel:    ns,
+		metrics.StatusLabel:       status,
+		metrics.ResourceNameLabel: name,
 	}
-	stats.Record(openCensusContext, metrics.MReconcileRequests.M(1), metrics.MReconcileDuration.M(time.Since(startTime).Seconds()))
+	duration := time.Since(startTime).Seconds()
+	metrics.MReconcileRequests.Inc(labels)
+	metrics.MReconcileDuration.Observe(duration, labels)
+	metrics.MReconcileLatency.Observe(duration, labels)
 	r.RecordInternalErrors(ctx, gvk, ns, reconcileErr)
 }
 
@@ -72,8 +72,7 @@ func (r *ReconcilerMetrics) RecordInternalErrors(ctx context.Context, gvk schema
 	if _, ok := errors.AsInternalError(err); !ok {
 		return
 	}
-	openCensusContext, _ := tag.New(ctx, tag.Insert(metrics.KindTag, gvk.GroupKind().String()), tag.Insert(metrics.NamespaceTag, ns))
-	stats.Record(openCensusContext, metrics.MInternalErrors.M(1))
+	metrics.MInternalErrors.Inc(metrics.Labels{metrics.KindLabel: gvk.GroupKind().String(), metrics.NamespaceLabel: ns})
 }
 
 func (r *ReconcilerMetrics) AfterReconcile() {
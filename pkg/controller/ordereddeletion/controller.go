@@ -0,0 +1,249 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ordereddeletion
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	operatorv1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/v1beta1"
+	operatork8s "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/k8s"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/crd/crdgeneration"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/resourcegraph"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	klog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	controllerName = "ordered-deletion-controller"
+
+	// RequeuePeriod is how often the progress of an ordered deletion is
+	// checked, and how long a blocked resource waits before checking again.
+	RequeuePeriod = 10 * time.Second
+
+	PhaseInProgress = "InProgress"
+	PhaseCompleted  = "Completed"
+)
+
+var logger = klog.Log.WithName(controllerName)
+
+type Reconciler struct {
+	client.Client
+	// cache reads the namespaces, the CRDs and the metadata of the resources from the
+	// informers of the manager, which the deletion defender controllers already watch the
+	// metadata of the resources with. The client of the deletion defender is uncached.
+	cache   client.Reader
+	tracker *Tracker
+
+	mu sync.Mutex
+	// objects are the resources of the namespaces under ordered deletion, by namespace. A
+	// resource is only read again from the API server when its resourceVersion changes.
+	objects map[string]map[resourcegraph.NodeID]*unstructured.Unstructured
+}
+
+func newReconciler(c client.Client, cache client.Reader, tracker *Tracker) *Reconciler {
+	return &Reconciler{
+		Client:  c,
+		cache:   cache,
+		tracker: tracker,
+		objects: make(map[string]map[resourcegraph.NodeID]*unstructured.Unstructured),
+	}
+}
+
+// Add registers a controller which computes the blockers of the resources of
+// the namespaces under ordered deletion into tracker, deletes the unblocked
+// resources of the namespaces with the ordered deletion annotation, and
+// reports the progress on the namespaces' ConfigConnectorContexts.
+func Add(mgr manager.Manager, tracker *Tracker) error {
+	r := newReconciler(mgr.GetClient(), mgr.GetCache(), tracker)
+	_, err := builder.
+		ControllerManagedBy(mgr).
+		Named(controllerName).
+		For(&corev1.Namespace{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool { return isActive(e.Object) },
+			// Also react to the namespaces which are no longer under ordered
+			// deletion, so that their blockers are forgotten.
+			UpdateFunc:  func(e event.UpdateEvent) bool { return isActive(e.ObjectOld) || isActive(e.ObjectNew) },
+			DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+			GenericFunc: func(e event.GenericEvent) bool { return isActive(e.Object) },
+		})).
+		Build(r)
+	if err != nil {
+		return fmt.Errorf("error creating new controller: %w", err)
+	}
+	return nil
+}
+
+func isActive(obj client.Object) bool {
+	ns, ok := obj.(*corev1.Namespace)
+	return ok && IsActive(ns)
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ns := &corev1.Namespace{}
+	if err := r.cache.Get(ctx, req.NamespacedName, ns); err != nil {
+		if errors.IsNotFound(err) {
+			r.forget(req.Name)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if !IsActive(ns) {
+		r.forget(ns.Name)
+		return reconcile.Result{}, nil
+	}
+
+	objects, err := r.listResources(ctx, ns.Name)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("error listing the resources of namespace %v: %w", ns.Name, err)
+	}
+	g, err := resourcegraph.Build(objects)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("error building the resource graph of namespace %v: %w", ns.Name, err)
+	}
+	blockers := blockersFromGraph(g)
+	r.tracker.set(ns.Name, blockers)
+
+	// When the namespace is deleted, all its resources are already being
+	// deleted, and the deletion defender holds back the blocked ones. With
+	// the annotation, the unblocked resources are deleted here.
+	if ns.GetDeletionTimestamp().IsZero() {
+		for _, id := range g.Nodes() {
+			u := g.Object(id)
+			if len(blockers[id]) > 0 || !u.GetDeletionTimestamp().IsZero() {
+				continue
+			}
+			logger.Info("deleting resource", "namespace", ns.Name, "resource", id.String())
+			if err := r.Delete(ctx, u); err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, fmt.Errorf("error deleting %v: %w", id, err)
+			}
+		}
+	}
+
+	status := &operatorv1beta1.OrderedDeletionStatus{
+		Phase:     PhaseInProgress,
+		Remaining: len(g.Nodes()),
+		Blocked:   len(blockers),
+		Deleting:  len(g.Nodes()) - len(blockers),
+	}
+	if status.Remaining == 0 {
+		status.Phase = PhaseCompleted
+	}
+	if err := r.updateStatus(ctx, ns.Name, status); err != nil {
+		return reconcile.Result{}, err
+	}
+	logger.Info("ordered deletion in progress", "namespace", ns.Name, "remaining", status.Remaining, "blocked", status.Blocked)
+	if status.Remaining == 0 && ns.GetDeletionTimestamp().IsZero() {
+		// Check once in a while for resources created while the annotation
+		// is still set.
+		return reconcile.Result{RequeueAfter: 6 * RequeuePeriod}, nil
+	}
+	return reconcile.Result{RequeueAfter: RequeuePeriod}, nil
+}
+
+func (r *Reconciler) forget(namespace string) {
+	r.tracker.forget(namespace)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.objects, namespace)
+}
+
+// listResources lists the resources of all the KCC kinds in the given
+// namespace. The resources are listed from the cached metadata, and only the
+// resources which are new or changed since the last reconciliation of the
+// namespace are read from the API server.
+func (r *Reconciler) listResources(ctx context.Context, namespace string) ([]*unstructured.Unstructured, error) {
+	crds := &apiextensions.CustomResourceDefinitionList{}
+	if err := r.cache.List(ctx, crds, client.MatchingLabels{crdgeneration.ManagedByKCCLabel: "true"}); err != nil {
+		return nil, fmt.Errorf("error listing CRDs: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	previous := r.objects[namespace]
+	current := make(map[resourcegraph.NodeID]*unstructured.Unstructured)
+	var objects []*unstructured.Unstructured
+	for _, crd := range crds.Items {
+		if crd.Spec.Scope != apiextensions.NamespaceScoped || k8s.IgnoredKindList[crd.Spec.Names.Kind] {
+			continue
+		}
+		gvk := schema.GroupVersionKind{
+			Group:   crd.Spec.Group,
+			Version: k8s.GetVersionFromCRD(&crd),
+			Kind:    crd.Spec.Names.Kind,
+		}
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(crd.Spec.Names.ListKind))
+		if err := r.cache.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("error listing %v: %w", crd.Spec.Names.Kind, err)
+		}
+		for _, item := range list.Items {
+			id := resourcegraph.NodeID{Group: gvk.Group, Kind: gvk.Kind, Namespace: namespace, Name: item.Name}
+			u, ok := previous[id]
+			if !ok || u.GetResourceVersion() != item.ResourceVersion {
+				u = &unstructured.Unstructured{}
+				u.SetGroupVersionKind(gvk)
+				if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: item.Name}, u); err != nil {
+					if errors.IsNotFound(err) {
+						continue
+					}
+					return nil, fmt.Errorf("error getting %v: %w", id, err)
+				}
+			}
+			current[id] = u
+			objects = append(objects, u)
+		}
+	}
+	r.objects[namespace] = current
+	return objects, nil
+}
+
+// updateStatus reports the progress on the ConfigConnectorContext of the
+// namespace, if there is one, i.e. in namespaced mode.
+func (r *Reconciler) updateStatus(ctx context.Context, namespace string, status *operatorv1beta1.OrderedDeletionStatus) error {
+	ccc := &operatorv1beta1.ConfigConnectorContext{}
+	key := client.ObjectKey{Namespace: namespace, Name: operatork8s.ConfigConnectorContextAllowedName}
+	if err := r.Get(ctx, key, ccc); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error getting ConfigConnectorContext %v: %w", key, err)
+	}
+	if current := ccc.Status.OrderedDeletion; current != nil && current.Phase == status.Phase &&
+		current.Remaining == status.Remaining && current.Blocked == status.Blocked && current.Deleting == status.Deleting {
+		return nil
+	}
+	status.LastUpdateTime = metav1.Now()
+	patch := client.MergeFrom(ccc.DeepCopy())
+	ccc.Status.OrderedDeletion = status
+	if err := r.Status().Patch(ctx, ccc, patch); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("error updating the status of ConfigConnectorContext %v: %w", key, err)
+	}
+	return nil
+}
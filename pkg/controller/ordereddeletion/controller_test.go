@@ -0,0 +1,206 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ordereddeletion
+
+import (
+	"context"
+	"testing"
+
+	operatorv1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/v1beta1"
+	operatork8s "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/k8s"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/crd/crdgeneration"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newTestCRD(kind string) *apiextensions.CustomResourceDefinition {
+	return &apiextensions.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   kind + "s.test.cnrm.cloud.google.com",
+			Labels: map[string]string{crdgeneration.ManagedByKCCLabel: "true"},
+		},
+		Spec: apiextensions.CustomResourceDefinitionSpec{
+			Group: "test.cnrm.cloud.google.com",
+			Names: apiextensions.CustomResourceDefinitionNames{Kind: kind, ListKind: kind + "List"},
+			Scope: apiextensions.NamespaceScoped,
+			Versions: []apiextensions.CustomResourceDefinitionVersion{
+				{Name: "v1beta1", Served: true, Storage: true},
+			},
+		},
+	}
+}
+
+// newTestClient returns a fake client with a namespace "ns" under ordered deletion, its
+// ConfigConnectorContext and the given resources, and counts the reads of resources.
+func newTestClient(t *testing.T, resourceGets *int, resources ...*unstructured.Unstructured) client.Client {
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{corev1.AddToScheme, apiextensions.AddToScheme, operatorv1beta1.AddToScheme} {
+		if err := addToScheme(scheme); err != nil {
+			t.Fatalf("error building scheme: %v", err)
+		}
+	}
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	restMapper.Add(apiextensions.SchemeGroupVersion.WithKind("CustomResourceDefinition"), meta.RESTScopeRoot)
+	restMapper.Add(operatorv1beta1.ConfigConnectorContextGroupVersionKind, meta.RESTScopeNamespace)
+	objects := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "ns",
+			Annotations: map[string]string{k8s.OrderedDeletionAnnotation: "true"},
+		}},
+		&operatorv1beta1.ConfigConnectorContext{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      operatork8s.ConfigConnectorContextAllowedName,
+		}},
+	}
+	kinds := make(map[string]bool)
+	for _, u := range resources {
+		gvk := schema.GroupVersionKind{Group: "test.cnrm.cloud.google.com", Version: "v1beta1", Kind: u.GetKind()}
+		if !kinds[gvk.Kind] {
+			kinds[gvk.Kind] = true
+			restMapper.Add(gvk, meta.RESTScopeNamespace)
+			// The fake client can only list the metadata of the kinds registered in its scheme.
+			scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+			scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+			objects = append(objects, newTestCRD(gvk.Kind))
+		}
+		objects = append(objects, u)
+	}
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(restMapper).
+		WithObjects(objects...).
+		WithStatusSubresource(&operatorv1beta1.ConfigConnectorContext{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*unstructured.Unstructured); ok {
+					*resourceGets++
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).
+		Build()
+}
+
+func exists(t *testing.T, c client.Client, u *unstructured.Unstructured) bool {
+	t.Helper()
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(u.GroupVersionKind())
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(u), got); err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		t.Fatalf("error getting %v: %v", u.GetName(), err)
+	}
+	return true
+}
+
+func TestReconcileDeletesInReverseReferenceOrder(t *testing.T) {
+	ctx := context.Background()
+	network := newResource("TestNetwork", "net", nil)
+	subnet := newResource("TestSubnetwork", "subnet", map[string]string{"networkRef": "net"})
+	instance := newResource("TestInstance", "vm", map[string]string{"subnetworkRef": "subnet"})
+	var resourceGets int
+	c := newTestClient(t, &resourceGets, network, subnet, instance)
+	tracker := NewTracker()
+	r := newReconciler(c, c, tracker)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "ns"}}
+
+	// The instance is not referenced, so it is deleted first.
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("error reconciling: %v", err)
+	}
+	if exists(t, c, instance) || !exists(t, c, subnet) || !exists(t, c, network) {
+		t.Fatalf("got the referenced resources deleted, or the instance not deleted")
+	}
+	if blockers, ok := tracker.Blockers(id("TestNetwork", "net")); !ok || len(blockers) != 1 {
+		t.Fatalf("got blockers %v, %v for the network, want the subnetwork", blockers, ok)
+	}
+	ccc := &operatorv1beta1.ConfigConnectorContext{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "ns", Name: operatork8s.ConfigConnectorContextAllowedName}, ccc); err != nil {
+		t.Fatalf("error getting ConfigConnectorContext: %v", err)
+	}
+	if got := ccc.Status.OrderedDeletion; got == nil || got.Phase != PhaseInProgress || got.Remaining != 3 || got.Blocked != 2 {
+		t.Fatalf("got ordered deletion status %+v, want 3 remaining and 2 blocked resources", got)
+	}
+
+	// The unchanged resources are not read again from the API server.
+	resourceGets = 0
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("error reconciling: %v", err)
+	}
+	if resourceGets != 0 {
+		t.Fatalf("got %v reads of unchanged resources, want none", resourceGets)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("error reconciling: %v", err)
+		}
+	}
+	if exists(t, c, subnet) || exists(t, c, network) {
+		t.Fatalf("got the resources not deleted once they are no longer referenced")
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("error reconciling: %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "ns", Name: operatork8s.ConfigConnectorContextAllowedName}, ccc); err != nil {
+		t.Fatalf("error getting ConfigConnectorContext: %v", err)
+	}
+	if got := ccc.Status.OrderedDeletion; got == nil || got.Phase != PhaseCompleted {
+		t.Fatalf("got ordered deletion status %+v, want phase %v", got, PhaseCompleted)
+	}
+}
+
+func TestReconcileForgetsInactiveNamespaces(t *testing.T) {
+	ctx := context.Background()
+	var resourceGets int
+	c := newTestClient(t, &resourceGets, newResource("TestNetwork", "net", nil))
+	tracker := NewTracker()
+	r := newReconciler(c, c, tracker)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "ns"}}
+
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, req.NamespacedName, ns); err != nil {
+		t.Fatalf("error getting namespace: %v", err)
+	}
+	ns.Annotations = nil
+	if err := c.Update(ctx, ns); err != nil {
+		t.Fatalf("error updating namespace: %v", err)
+	}
+	tracker.set("ns", nil)
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("error reconciling: %v", err)
+	}
+	if _, ok := tracker.Blockers(id("TestNetwork", "net")); ok {
+		t.Fatalf("got blockers for a namespace which is no longer under ordered deletion")
+	}
+	if resourceGets != 0 || !exists(t, c, newResource("TestNetwork", "net", nil)) {
+		t.Fatalf("got the resources of a namespace which is not under ordered deletion read or deleted")
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ordereddeletion deletes the KCC resources of a namespace in reverse
+// reference order when the namespace is deleted or has the
+// 'cnrm.cloud.google.com/ordered-deletion' annotation, e.g. the
+// ComputeSubnetworks before the ComputeNetwork they reference. The deletion of
+// a resource is blocked by the deletion defender until the resources which
+// reference it are gone.
+package ordereddeletion
+
+import (
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/resourcegraph"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IsActive returns whether the resources of the given namespace are deleted in
+// reverse reference order.
+func IsActive(ns *corev1.Namespace) bool {
+	if !ns.GetDeletionTimestamp().IsZero() {
+		return true
+	}
+	val, ok := k8s.GetAnnotation(k8s.OrderedDeletionAnnotation, ns)
+	return ok && val == "true"
+}
+
+// Tracker holds the resources which block the deletion of each resource in
+// the namespaces under ordered deletion. It is shared by the ordered deletion
+// controller, which computes it, and the deletion defender controllers, which
+// consult it.
+type Tracker struct {
+	mu         sync.RWMutex
+	namespaces map[string]map[resourcegraph.NodeID][]resourcegraph.NodeID
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		namespaces: make(map[string]map[resourcegraph.NodeID][]resourcegraph.NodeID),
+	}
+}
+
+// Blockers returns the resources which must be gone before the given resource
+// can be deleted. The returned bool is false if the blockers of the
+// resource's namespace have not been computed yet.
+func (t *Tracker) Blockers(id resourcegraph.NodeID) ([]resourcegraph.NodeID, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	blockers, ok := t.namespaces[id.Namespace]
+	if !ok {
+		return nil, false
+	}
+	return blockers[id], true
+}
+
+func (t *Tracker) set(namespace string, blockers map[resourcegraph.NodeID][]resourcegraph.NodeID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.namespaces[namespace] = blockers
+}
+
+func (t *Tracker) forget(namespace string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.namespaces, namespace)
+}
+
+// blockersFromGraph returns, for each resource of the graph which is
+// referenced by other resources, the resources referencing it. The
+// references within a cycle are ignored, as the resources of a cycle can
+// only be deleted together.
+func blockersFromGraph(g *resourcegraph.Graph) map[resourcegraph.NodeID][]resourcegraph.NodeID {
+	cycleOf := make(map[resourcegraph.NodeID]int)
+	for i, cycle := range g.Cycles() {
+		for _, id := range cycle {
+			cycleOf[id] = i + 1
+		}
+	}
+	blockers := make(map[resourcegraph.NodeID][]resourcegraph.NodeID)
+	for _, id := range g.Nodes() {
+		for _, dependent := range g.Dependents(id) {
+			if c := cycleOf[id]; c != 0 && c == cycleOf[dependent] {
+				continue
+			}
+			blockers[id] = append(blockers[id], dependent)
+		}
+	}
+	return blockers
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ordereddeletion
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/resourcegraph"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newResource(kind, name string, refs map[string]string) *unstructured.Unstructured {
+	spec := make(map[string]interface{})
+	for field, refName := range refs {
+		spec[field] = map[string]interface{}{"name": refName}
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "test.cnrm.cloud.google.com/v1beta1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": "ns"},
+		"spec":       spec,
+	}}
+}
+
+func id(kind, name string) resourcegraph.NodeID {
+	return resourcegraph.NodeID{Group: "test.cnrm.cloud.google.com", Kind: kind, Namespace: "ns", Name: name}
+}
+
+func TestBlockersFromGraph(t *testing.T) {
+	objects := []*unstructured.Unstructured{
+		newResource("TestNetwork", "net", nil),
+		newResource("TestSubnetwork", "subnet-a", map[string]string{"networkRef": "net"}),
+		newResource("TestSubnetwork", "subnet-b", map[string]string{"networkRef": "net"}),
+		newResource("TestInstance", "vm", map[string]string{"subnetworkRef": "subnet-a"}),
+		// A cycle, which doesn't block the deletion of its resources.
+		newResource("TestFoo", "foo", map[string]string{"testBarRef": "bar"}),
+		newResource("TestBar", "bar", map[string]string{"testFooRef": "foo"}),
+	}
+	g, err := resourcegraph.Build(objects)
+	if err != nil {
+		t.Fatalf("error building graph: %v", err)
+	}
+	expected := map[resourcegraph.NodeID][]resourcegraph.NodeID{
+		id("TestNetwork", "net"):         {id("TestSubnetwork", "subnet-a"), id("TestSubnetwork", "subnet-b")},
+		id("TestSubnetwork", "subnet-a"): {id("TestInstance", "vm")},
+	}
+	if diff := cmp.Diff(expected, blockersFromGraph(g)); diff != "" {
+		t.Fatalf("unexpected blockers (-want +got):\n%v", diff)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+	if _, ok := tracker.Blockers(id("TestNetwork", "net")); ok {
+		t.Fatalf("got blockers for an untracked namespace")
+	}
+	tracker.set("ns", map[resourcegraph.NodeID][]resourcegraph.NodeID{
+		id("TestNetwork", "net"): {id("TestSubnetwork", "subnet")},
+	})
+	blockers, ok := tracker.Blockers(id("TestNetwork", "net"))
+	if !ok || len(blockers) != 1 {
+		t.Fatalf("got blockers %v, %v, want one blocker", blockers, ok)
+	}
+	if blockers, ok := tracker.Blockers(id("TestSubnetwork", "subnet")); !ok || len(blockers) != 0 {
+		t.Fatalf("got blockers %v, %v, want no blockers", blockers, ok)
+	}
+	tracker.forget("ns")
+	if _, ok := tracker.Blockers(id("TestNetwork", "net")); ok {
+		t.Fatalf("got blockers for a forgotten namespace")
+	}
+}
@@ -65,6 +65,11 @@ func (UnderlyingResourceOutOfSyncPredicate) Update(e event.UpdateEvent) bool {
 		return true
 	}
 
+	// Pausing or resuming reconciliation should take effect immediately
+	if e.ObjectOld.GetAnnotations()[k8s.ReconcilePausedAnnotation] != e.ObjectNew.GetAnnotations()[k8s.ReconcilePausedAnnotation] {
+		return true
+	}
+
 	// The object's generation will increment when the spec is updated, so a different
 	// generation implies potential work to be done on the underlying API.
 	if e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration() {
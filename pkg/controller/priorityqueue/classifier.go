@@ -0,0 +1,80 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// EventClassifier decides on the lane of a reconcile request from the watch
+// event that triggered it. Deletions and generation changes go in the high
+// priority lane; status and metadata-only updates go in the low priority lane.
+//
+// Objects that are "created" before the classifier was, i.e. the initial
+// listing of all objects when the informer starts, are treated as drift
+// correction so that they do not hold up changes made after startup.
+type EventClassifier struct {
+	startTime time.Time
+}
+
+// NewEventClassifier constructs an EventClassifier.
+func NewEventClassifier() *EventClassifier {
+	return &EventClassifier{startTime: time.Now()}
+}
+
+// Create returns the lane for a create event.
+func (c *EventClassifier) Create(evt event.CreateEvent) Lane {
+	if isDeleting(evt.Object) {
+		return LaneHigh
+	}
+	if evt.Object != nil && evt.Object.GetCreationTimestamp().Time.Before(c.startTime) {
+		return LaneLow
+	}
+	return LaneHigh
+}
+
+// Update returns the lane for an update event.
+func (c *EventClassifier) Update(evt event.UpdateEvent) Lane {
+	if isDeleting(evt.ObjectNew) {
+		return LaneHigh
+	}
+	if evt.ObjectOld == nil || evt.ObjectNew == nil {
+		return LaneHigh
+	}
+	if evt.ObjectOld.GetUID() != evt.ObjectNew.GetUID() {
+		return LaneHigh
+	}
+	if evt.ObjectNew.GetGeneration() != evt.ObjectOld.GetGeneration() {
+		return LaneHigh
+	}
+	return LaneLow
+}
+
+// Delete returns the lane for a delete event.
+func (c *EventClassifier) Delete(_ event.DeleteEvent) Lane {
+	return LaneHigh
+}
+
+// Generic returns the lane for a generic event, i.e. an immediate reconcile request.
+func (c *EventClassifier) Generic(_ event.GenericEvent) Lane {
+	return LaneHigh
+}
+
+func isDeleting(obj client.Object) bool {
+	return obj != nil && !obj.GetDeletionTimestamp().IsZero()
+}
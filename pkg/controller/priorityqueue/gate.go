@@ -0,0 +1,211 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// pollInterval is how often the gate checks whether the controller queue has room.
+const pollInterval = 10 * time.Millisecond
+
+// Gate holds back the reconcile requests of a controller in a Queue, and hands
+// them over to the work queue of the controller in priority order.
+//
+// The controller-runtime version we use does not allow for a custom work
+// queue, so instead the gate wraps the watch sources of the controller (see
+// Source): a source is started with the controller queue, and the events it
+// delivers are classified and queued in the gate. The gate keeps the
+// controller queue short by only moving a request over when fewer than
+// maxWaiting requests are waiting there.
+type Gate struct {
+	queue      *Queue
+	delaying   workqueue.DelayingInterface
+	classifier *EventClassifier
+	maxWaiting int
+
+	once sync.Once
+}
+
+// NewGate constructs a Gate for a controller of objects of the given kind. The
+// gate lets at most maxWaiting requests wait in the controller queue, which
+// should be about the number of concurrent reconciles of the controller.
+func NewGate(gvk schema.GroupVersionKind, maxWaiting int) *Gate {
+	// Items that are added without a lane are delayed requeues (see
+	// Reconciler), i.e. drift correction.
+	q := New(gvk, func(interface{}) Lane { return LaneLow })
+	if maxWaiting < 1 {
+		maxWaiting = 1
+	}
+	return &Gate{
+		queue:      q,
+		delaying:   workqueue.NewDelayingQueueWithCustomQueue(q, ""),
+		classifier: NewEventClassifier(),
+		maxWaiting: maxWaiting,
+	}
+}
+
+// Source wraps src so that the requests for the events it delivers are queued
+// in the gate.
+func (g *Gate) Source(src source.Source) source.SyncingSource {
+	return &gatedSource{gate: g, source: src}
+}
+
+// Kind returns a source for the metadata of objects of the given kind, like
+// builder.OnlyMetadata does for the object passed to For, that queues its
+// requests in the gate.
+func (g *Gate) Kind(cache cache.Cache, gvk schema.GroupVersionKind) source.SyncingSource {
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	return g.Source(source.Kind(cache, obj))
+}
+
+// Reconciler wraps r so that periodic requeues, i.e. results with a
+// RequeueAfter and no error, wait in the low priority lane of the gate rather
+// than in the controller queue. Retries after errors are still rate limited by
+// the controller.
+func (g *Gate) Reconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		result, err := r.Reconcile(ctx, req)
+		if err == nil && result.RequeueAfter > 0 {
+			g.delaying.AddAfter(req, result.RequeueAfter)
+			return reconcile.Result{}, nil
+		}
+		return result, err
+	})
+}
+
+// start starts moving requests from the gate to the controller queue. All the
+// sources of a controller are started with the same queue, so only the first
+// call has any effect.
+func (g *Gate) start(ctx context.Context, target workqueue.RateLimitingInterface) {
+	g.once.Do(func() {
+		go func() {
+			<-ctx.Done()
+			g.delaying.ShutDown()
+		}()
+		go g.pump(ctx, target)
+	})
+}
+
+func (g *Gate) pump(ctx context.Context, target workqueue.RateLimitingInterface) {
+	for {
+		item, shutdown := g.delaying.Get()
+		if shutdown {
+			return
+		}
+		if !g.waitForRoom(ctx, target) {
+			g.delaying.Done(item)
+			return
+		}
+		target.Add(item)
+		g.delaying.Done(item)
+	}
+}
+
+// waitForRoom blocks until fewer than maxWaiting requests are waiting in the
+// controller queue; it returns false if the controller is stopping.
+func (g *Gate) waitForRoom(ctx context.Context, target workqueue.RateLimitingInterface) bool {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for target.Len() >= g.maxWaiting {
+		if target.ShuttingDown() {
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
+
+type gatedSource struct {
+	gate   *Gate
+	source source.Source
+}
+
+var _ source.SyncingSource = &gatedSource{}
+
+func (s *gatedSource) Start(ctx context.Context, h handler.EventHandler, q workqueue.RateLimitingInterface, prct ...predicate.Predicate) error {
+	s.gate.start(ctx, q)
+	return s.source.Start(ctx, &gatedHandler{gate: s.gate, handler: h}, q, prct...)
+}
+
+func (s *gatedSource) WaitForSync(ctx context.Context) error {
+	if syncing, ok := s.source.(source.SyncingSource); ok {
+		return syncing.WaitForSync(ctx)
+	}
+	return nil
+}
+
+func (s *gatedSource) String() string {
+	return fmt.Sprintf("priority queued %v", s.source)
+}
+
+// gatedHandler classifies each event, and redirects the requests that the
+// wrapped handler adds to the controller queue into that lane of the gate.
+type gatedHandler struct {
+	gate    *Gate
+	handler handler.EventHandler
+}
+
+var _ handler.EventHandler = &gatedHandler{}
+
+func (h *gatedHandler) Create(ctx context.Context, evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.handler.Create(ctx, evt, h.gate.laneQueue(q, h.gate.classifier.Create(evt)))
+}
+
+func (h *gatedHandler) Update(ctx context.Context, evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.handler.Update(ctx, evt, h.gate.laneQueue(q, h.gate.classifier.Update(evt)))
+}
+
+func (h *gatedHandler) Delete(ctx context.Context, evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.handler.Delete(ctx, evt, h.gate.laneQueue(q, h.gate.classifier.Delete(evt)))
+}
+
+func (h *gatedHandler) Generic(ctx context.Context, evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.handler.Generic(ctx, evt, h.gate.laneQueue(q, h.gate.classifier.Generic(evt)))
+}
+
+func (g *Gate) laneQueue(q workqueue.RateLimitingInterface, lane Lane) workqueue.RateLimitingInterface {
+	return &laneQueue{RateLimitingInterface: q, gate: g, lane: lane}
+}
+
+// laneQueue is the controller queue, except that added items are queued in a
+// lane of the gate instead.
+type laneQueue struct {
+	workqueue.RateLimitingInterface
+	gate *Gate
+	lane Lane
+}
+
+func (q *laneQueue) Add(item interface{}) {
+	q.gate.queue.AddWithLane(item, q.lane)
+}
@@ -0,0 +1,261 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package priorityqueue implements a work queue with separate lanes, so that
+// user-initiated changes (spec edits, deletions) are reconciled before the
+// periodic drift-correction reconciles of the same kind.
+package priorityqueue
+
+import (
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/metrics"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Lane is the priority lane that a queued item waits in.
+// Lower values are dequeued first.
+type Lane int
+
+const (
+	// LaneHigh is for generation changes and deletions.
+	LaneHigh Lane = iota
+	// LaneLow is for periodic requeues and retries, i.e. drift correction.
+	LaneLow
+
+	numLanes = int(LaneLow) + 1
+)
+
+func (l Lane) String() string {
+	switch l {
+	case LaneHigh:
+		return "high"
+	case LaneLow:
+		return "low"
+	default:
+		return "unknown"
+	}
+}
+
+// ClassifyFunc returns the lane that an item should be queued in.
+// It is called without holding the queue lock.
+type ClassifyFunc func(item interface{}) Lane
+
+// Queue is a workqueue.Interface that dequeues items from the highest priority
+// lane first. It has the same guarantees as workqueue.Type: an item is never
+// processed concurrently, and an item added while it is being processed is
+// re-queued when it is done. An item that is added again with a higher
+// priority while it is waiting is promoted to the higher priority lane.
+type Queue struct {
+	// cond guards all the fields below, and signals changes to them.
+	cond *sync.Cond
+
+	// lanes holds the items waiting to be processed, in FIFO order per lane.
+	lanes [numLanes][]interface{}
+
+	// dirty holds the items that need processing, and the lane they should wait in.
+	dirty map[interface{}]Lane
+
+	// processing holds the items that are currently being processed.
+	processing map[interface{}]struct{}
+
+	shuttingDown bool
+	drain        bool
+
+	classify ClassifyFunc
+
+	// gvk is used to tag the queue depth metrics; it may be empty.
+	gvk schema.GroupVersionKind
+}
+
+var _ workqueue.Interface = &Queue{}
+
+// New constructs a Queue, which uses classify to decide on the lane of each added item.
+// The depth of each lane is reported in the reconcile_queue_depth metric, tagged with gvk.
+func New(gvk schema.GroupVersionKind, classify ClassifyFunc) *Queue {
+	return &Queue{
+		cond:       sync.NewCond(&sync.Mutex{}),
+		dirty:      make(map[interface{}]Lane),
+		processing: make(map[interface{}]struct{}),
+		classify:   classify,
+		gvk:        gvk,
+	}
+}
+
+// Add marks item as needing processing, in the lane chosen by the classifier.
+func (q *Queue) Add(item interface{}) {
+	q.AddWithLane(item, q.classify(item))
+}
+
+// AddWithLane marks item as needing processing in the given lane.
+func (q *Queue) AddWithLane(item interface{}, lane Lane) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	if q.shuttingDown {
+		return
+	}
+
+	if current, found := q.dirty[item]; found {
+		if lane >= current {
+			return
+		}
+		q.dirty[item] = lane
+		if _, found := q.processing[item]; found {
+			// Will be queued in the new lane when processing is done.
+			return
+		}
+		q.remove(current, item)
+		q.lanes[lane] = append(q.lanes[lane], item)
+		q.recordDepth()
+		return
+	}
+
+	q.dirty[item] = lane
+	if _, found := q.processing[item]; found {
+		return
+	}
+	q.lanes[lane] = append(q.lanes[lane], item)
+	q.recordDepth()
+	q.cond.Signal()
+}
+
+// Len returns the number of items waiting to be processed, across all lanes.
+func (q *Queue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.len()
+}
+
+// LaneLen returns the number of items waiting to be processed in the given lane.
+func (q *Queue) LaneLen(lane Lane) int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return len(q.lanes[lane])
+}
+
+// Get blocks until it can return an item to be processed, taking the item from the
+// highest priority lane that is not empty. If shutdown = true, the caller should end
+// their goroutine. You must call Done with item when you have finished processing it.
+func (q *Queue) Get() (item interface{}, shutdown bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	for q.len() == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.len() == 0 {
+		// We must be shutting down.
+		return nil, true
+	}
+
+	for lane := range q.lanes {
+		if len(q.lanes[lane]) == 0 {
+			continue
+		}
+		item = q.lanes[lane][0]
+		// The underlying array still exists and references this object, so the object will not be garbage collected.
+		q.lanes[lane][0] = nil
+		q.lanes[lane] = q.lanes[lane][1:]
+		break
+	}
+
+	q.processing[item] = struct{}{}
+	delete(q.dirty, item)
+	q.recordDepth()
+	return item, false
+}
+
+// Done marks item as done processing; if it has been marked as dirty again
+// while it was being processed, it will be re-added to the queue.
+func (q *Queue) Done(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	delete(q.processing, item)
+	if lane, found := q.dirty[item]; found {
+		q.lanes[lane] = append(q.lanes[lane], item)
+		q.recordDepth()
+		q.cond.Signal()
+	} else if len(q.processing) == 0 {
+		q.cond.Signal()
+	}
+}
+
+// ShutDown causes Get to return shutdown = true, once the queue is empty.
+// It does not wait for items that are being processed.
+func (q *Queue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	q.drain = false
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+// ShutDownWithDrain is like ShutDown, but also blocks until all the items that
+// are being processed are marked as Done.
+func (q *Queue) ShutDownWithDrain() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	q.drain = true
+	q.shuttingDown = true
+	q.cond.Broadcast()
+
+	for len(q.processing) != 0 && q.drain {
+		q.cond.Wait()
+	}
+}
+
+// ShuttingDown returns true if ShutDown or ShutDownWithDrain has been called.
+func (q *Queue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	return q.shuttingDown
+}
+
+func (q *Queue) len() int {
+	n := 0
+	for lane := range q.lanes {
+		n += len(q.lanes[lane])
+	}
+	return n
+}
+
+// remove removes item from the given lane, preserving the order of the other items.
+func (q *Queue) remove(lane Lane, item interface{}) {
+	items := q.lanes[lane]
+	for i := range items {
+		if items[i] == item {
+			copy(items[i:], items[i+1:])
+			items[len(items)-1] = nil
+			q.lanes[lane] = items[:len(items)-1]
+			return
+		}
+	}
+}
+
+func (q *Queue) recordDepth() {
+	if q.gvk.Empty() {
+		return
+	}
+	for lane := range q.lanes {
+		labels := metrics.Labels{metrics.KindLabel: q.gvk.GroupKind().String(), metrics.QueueLaneLabel: Lane(lane).String()}
+		metrics.MReconcileQueueDepth.Set(float64(len(q.lanes[lane])), labels)
+	}
+}
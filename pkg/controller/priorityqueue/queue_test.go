@@ -0,0 +1,249 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// laneByName classifies string items by looking them up in a map.
+func laneByName(lanes map[string]Lane) ClassifyFunc {
+	return func(item interface{}) Lane {
+		return lanes[item.(string)]
+	}
+}
+
+func getAll(t *testing.T, q *Queue) []string {
+	t.Helper()
+	var got []string
+	for q.Len() != 0 {
+		item, shutdown := q.Get()
+		if shutdown {
+			t.Fatalf("unexpected shutdown")
+		}
+		got = append(got, item.(string))
+		q.Done(item)
+	}
+	return got
+}
+
+func assertItems(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got items %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("got items %v, want %v", got, want)
+		}
+	}
+}
+
+func TestQueueDequeuesHighPriorityFirst(t *testing.T) {
+	q := New(schema.GroupVersionKind{}, laneByName(map[string]Lane{
+		"drift-1": LaneLow,
+		"drift-2": LaneLow,
+		"edit-1":  LaneHigh,
+		"edit-2":  LaneHigh,
+	}))
+	q.Add("drift-1")
+	q.Add("edit-1")
+	q.Add("drift-2")
+	q.Add("edit-2")
+
+	if got, want := q.LaneLen(LaneHigh), 2; got != want {
+		t.Errorf("got high lane length %d, want %d", got, want)
+	}
+	if got, want := q.LaneLen(LaneLow), 2; got != want {
+		t.Errorf("got low lane length %d, want %d", got, want)
+	}
+	assertItems(t, getAll(t, q), []string{"edit-1", "edit-2", "drift-1", "drift-2"})
+}
+
+func TestQueuePromotesWaitingItem(t *testing.T) {
+	q := New(schema.GroupVersionKind{}, laneByName(nil))
+	q.AddWithLane("a", LaneLow)
+	q.AddWithLane("b", LaneLow)
+	q.AddWithLane("c", LaneHigh)
+
+	// Re-adding in the same or a lower priority lane does not move the item.
+	q.AddWithLane("a", LaneLow)
+	q.AddWithLane("c", LaneLow)
+	// Re-adding in a higher priority lane promotes the item.
+	q.AddWithLane("b", LaneHigh)
+
+	if got, want := q.Len(), 3; got != want {
+		t.Errorf("got length %d, want %d", got, want)
+	}
+	assertItems(t, getAll(t, q), []string{"c", "b", "a"})
+}
+
+func TestQueueRequeuesItemAddedWhileProcessing(t *testing.T) {
+	q := New(schema.GroupVersionKind{}, laneByName(nil))
+	q.AddWithLane("a", LaneLow)
+	q.AddWithLane("b", LaneLow)
+
+	item, _ := q.Get()
+	if item != "a" {
+		t.Fatalf("got item %v, want a", item)
+	}
+	// The item must not be handed out again until it is done.
+	q.AddWithLane("a", LaneHigh)
+	if got, want := q.Len(), 1; got != want {
+		t.Errorf("got length %d, want %d", got, want)
+	}
+	q.Done("a")
+
+	assertItems(t, getAll(t, q), []string{"a", "b"})
+}
+
+func TestQueueShutDown(t *testing.T) {
+	q := New(schema.GroupVersionKind{}, laneByName(nil))
+	q.AddWithLane("a", LaneLow)
+	item, _ := q.Get()
+
+	done := make(chan struct{})
+	go func() {
+		q.ShutDownWithDrain()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatalf("ShutDownWithDrain returned before processing was done")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if !q.ShuttingDown() {
+		t.Errorf("expected queue to be shutting down")
+	}
+	q.Done(item)
+	<-done
+
+	q.AddWithLane("b", LaneHigh)
+	if _, shutdown := q.Get(); !shutdown {
+		t.Errorf("expected Get to report shutdown")
+	}
+}
+
+func TestEventClassifier(t *testing.T) {
+	classifier := NewEventClassifier()
+	before := metav1.NewTime(classifier.startTime.Add(-time.Hour))
+	after := metav1.NewTime(classifier.startTime.Add(time.Second))
+	now := metav1.Now()
+
+	existing := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "existing", UID: "1", Generation: 3, CreationTimestamp: before}}
+	created := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "created", UID: "2", Generation: 1, CreationTimestamp: after}}
+	changed := existing.DeepCopy()
+	changed.Generation = 4
+	deleting := existing.DeepCopy()
+	deleting.DeletionTimestamp = &now
+	recreated := existing.DeepCopy()
+	recreated.UID = "3"
+
+	tests := []struct {
+		name string
+		got  Lane
+		want Lane
+	}{
+		{name: "existing object on startup", got: classifier.Create(event.CreateEvent{Object: existing}), want: LaneLow},
+		{name: "object created after startup", got: classifier.Create(event.CreateEvent{Object: created}), want: LaneHigh},
+		{name: "status update", got: classifier.Update(event.UpdateEvent{ObjectOld: existing, ObjectNew: existing.DeepCopy()}), want: LaneLow},
+		{name: "generation change", got: classifier.Update(event.UpdateEvent{ObjectOld: existing, ObjectNew: changed}), want: LaneHigh},
+		{name: "deletion", got: classifier.Update(event.UpdateEvent{ObjectOld: existing, ObjectNew: deleting}), want: LaneHigh},
+		{name: "re-created object", got: classifier.Update(event.UpdateEvent{ObjectOld: existing, ObjectNew: recreated}), want: LaneHigh},
+		{name: "delete", got: classifier.Delete(event.DeleteEvent{Object: existing}), want: LaneHigh},
+		{name: "immediate reconcile request", got: classifier.Generic(event.GenericEvent{Object: existing}), want: LaneHigh},
+	}
+	for _, tc := range tests {
+		if tc.got != tc.want {
+			t.Errorf("%v: got lane %v, want %v", tc.name, tc.got, tc.want)
+		}
+	}
+}
+
+func TestGateHandsOverHighPriorityFirst(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	target := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer target.ShutDown()
+	// Keep the controller queue full, so that the events wait in the gate.
+	blocker := reconcile.Request{NamespacedName: types.NamespacedName{Name: "blocker"}}
+	target.Add(blocker)
+
+	object := func(name string, generation int64) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name), Generation: generation}}
+	}
+	src := source.Func(func(ctx context.Context, h handler.EventHandler, q workqueue.RateLimitingInterface, _ ...predicate.Predicate) error {
+		h.Update(ctx, event.UpdateEvent{ObjectOld: object("low-1", 1), ObjectNew: object("low-1", 1)}, q)
+		h.Update(ctx, event.UpdateEvent{ObjectOld: object("low-2", 1), ObjectNew: object("low-2", 1)}, q)
+		h.Update(ctx, event.UpdateEvent{ObjectOld: object("high", 1), ObjectNew: object("high", 2)}, q)
+		return nil
+	})
+	gate := NewGate(schema.GroupVersionKind{}, 1)
+	if err := gate.Source(src).Start(ctx, &handler.EnqueueRequestForObject{}, target); err != nil {
+		t.Fatalf("error starting source: %v", err)
+	}
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		item, shutdown := target.Get()
+		if shutdown {
+			t.Fatalf("unexpected shutdown")
+		}
+		got = append(got, item.(reconcile.Request).Name)
+		target.Done(item)
+	}
+	if got[0] != "blocker" {
+		t.Fatalf("got %v first, want blocker", got[0])
+	}
+	// The gate may already have taken the first low priority item before the
+	// high priority item arrived, but the high priority item must overtake the rest.
+	if got[3] != "low-2" {
+		t.Errorf("got order %v, want high before low-2", got)
+	}
+}
+
+func TestGateReconcilerRequeuesInLowLane(t *testing.T) {
+	gate := NewGate(schema.GroupVersionKind{}, 1)
+	defer gate.delaying.ShutDown()
+	r := gate.Reconciler(reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{RequeueAfter: time.Millisecond}, nil
+	}))
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}}
+	result, err := r.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RequeueAfter != 0 {
+		t.Errorf("got RequeueAfter %v, want the requeue to be handled by the gate", result.RequeueAfter)
+	}
+	item, _ := gate.delaying.Get()
+	if item != req {
+		t.Errorf("got item %v, want %v", item, req)
+	}
+}
@@ -49,7 +49,7 @@ func MeanReconcileReenqueuePeriod(gvk schema.GroupVersionKind,
 			return time.Duration(*resourceMetadata.ReconciliationIntervalInSeconds) * time.Second
 		}
 	}
-	// Check if the resource belongs to IAMPolicy/IAMPartialPolicy/IAMPolicyMember/IAMAuditConfig.
+	// Check if the resource belongs to IAMPolicy/IAMPartialPolicy/IAMPolicyMember/IAMAuditConfig/IAMDenyPolicy.
 	switch gvk.Kind {
 	case "IAMPolicy":
 		return v1beta1.IAMPolicyReconcileInterval
@@ -59,6 +59,8 @@ func MeanReconcileReenqueuePeriod(gvk schema.GroupVersionKind,
 		return v1beta1.IAMPolicyMemberReconcileInterval
 	case "IAMAuditConfig":
 		return v1beta1.IAMAuditConfigReconcileInterval
+	case "IAMDenyPolicy":
+		return v1beta1.IAMDenyPolicyReconcileInterval
 	}
 	// If no GVK specific reconcile interval configured, return default value.
 	return k8s.MeanReconcileReenqueuePeriod
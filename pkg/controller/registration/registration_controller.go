@@ -28,10 +28,12 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/gsakeysecretgenerator"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/iam/auditconfig"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/iam/denypolicy"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/iam/partialpolicy"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/iam/policy"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/iam/policymember"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/jitter"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/ordereddeletion"
 	kccpredicate "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/predicate"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/tf"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/unmanageddetector"
@@ -40,6 +42,7 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/metadata"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/kccfeatureflags"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/lease/leaser"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
 
 	"github.com/GoogleCloudPlatform/declarative-resource-client-library/dcl"
@@ -85,6 +88,8 @@ func Add(mgr manager.Manager, rd *controller.Deps, regFunc registrationFunc) err
 		registrationFunc: regFunc,
 		defaulters:       rd.Defaulters,
 		jitterGenerator:  rd.JitterGen,
+		leaseBackends:    rd.LeaseBackends,
+		orderedDeletion:  rd.OrderedDeletion,
 	}
 	c, err := crcontroller.New(controllerName, mgr,
 		crcontroller.Options{
@@ -111,6 +116,8 @@ type ReconcileRegistration struct {
 	registrationFunc registrationFunc
 	defaulters       []k8s.Defaulter
 	jitterGenerator  jitter.Generator
+	leaseBackends    leaser.Backends
+	orderedDeletion  *ordereddeletion.Tracker
 
 	mu sync.Mutex
 }
@@ -189,12 +196,13 @@ func registerDefaultController(r *ReconcileRegistration, config *config.Controll
 		return nil, nil
 	}
 	cds := controller.Deps{
-		TfProvider:   r.provider,
-		TfLoader:     r.smLoader,
-		DclConfig:    r.dclConfig,
-		DclConverter: r.dclConverter,
-		JitterGen:    r.jitterGenerator,
-		Defaulters:   r.defaulters,
+		TfProvider:    r.provider,
+		TfLoader:      r.smLoader,
+		DclConfig:     r.dclConfig,
+		DclConverter:  r.dclConverter,
+		JitterGen:     r.jitterGenerator,
+		Defaulters:    r.defaulters,
+		LeaseBackends: r.leaseBackends,
 	}
 	var schemaUpdater k8s.SchemaReferenceUpdater
 	if kccfeatureflags.UseDirectReconciler(gvk.GroupKind()) {
@@ -205,7 +213,7 @@ func registerDefaultController(r *ReconcileRegistration, config *config.Controll
 			return nil, err
 		}
 
-		if err := directbase.AddController(r.mgr, gvk, model, directbase.Deps{JitterGenerator: r.jitterGenerator}); err != nil {
+		if err := directbase.AddController(r.mgr, gvk, model, directbase.Deps{JitterGenerator: r.jitterGenerator, LeaseBackends: r.leaseBackends}); err != nil {
 			return nil, fmt.Errorf("error adding direct controller for %v to a manager: %w", crd.Spec.Names.Kind, err)
 		}
 		return schemaUpdater, nil
@@ -229,6 +237,10 @@ func registerDefaultController(r *ReconcileRegistration, config *config.Controll
 		if err := auditconfig.Add(r.mgr, &cds); err != nil {
 			return nil, err
 		}
+	case "IAMDenyPolicy":
+		if err := denypolicy.Add(r.mgr, &cds, config); err != nil {
+			return nil, err
+		}
 
 	default:
 		// register the controller to automatically create secrets for GSA keys
@@ -268,6 +280,7 @@ func registerDefaultController(r *ReconcileRegistration, config *config.Controll
 			deps := directbase.Deps{
 				JitterGenerator:    r.jitterGenerator,
 				ReconcilePredicate: useDirectReconcilerPredicate,
+				LeaseBackends:      r.leaseBackends,
 			}
 			if err := directbase.AddController(r.mgr, gvk, model, deps); err != nil {
 				return nil, fmt.Errorf("error adding direct controller for %v to a manager: %w", crd.Spec.Names.Kind, err)
@@ -275,7 +288,7 @@ func registerDefaultController(r *ReconcileRegistration, config *config.Controll
 		}
 		// register controllers for dcl-based CRDs
 		if hasDCLController {
-			su, err := dclcontroller.Add(r.mgr, crd, r.dclConverter, r.dclConfig, r.smLoader, r.defaulters, r.jitterGenerator, useLegacyPredicate)
+			su, err := dclcontroller.Add(r.mgr, crd, r.dclConverter, r.dclConfig, r.smLoader, r.defaulters, r.jitterGenerator, r.leaseBackends, useLegacyPredicate)
 			if err != nil {
 				return nil, fmt.Errorf("error adding dcl controller for %v to a manager: %w", crd.Spec.Names.Kind, err)
 			}
@@ -283,7 +296,7 @@ func registerDefaultController(r *ReconcileRegistration, config *config.Controll
 		}
 		// register controllers for tf-based CRDs
 		if hasTerraformController {
-			su, err := tf.Add(r.mgr, crd, r.provider, r.smLoader, r.defaulters, r.jitterGenerator, useLegacyPredicate)
+			su, err := tf.Add(r.mgr, crd, r.provider, r.smLoader, r.defaulters, r.jitterGenerator, r.leaseBackends, useLegacyPredicate)
 			if err != nil {
 				return nil, fmt.Errorf("error adding terraform controller for %v to a manager: %w", crd.Spec.Names.Kind, err)
 			}
@@ -303,7 +316,7 @@ func RegisterDeletionDefenderController(r *ReconcileRegistration, crd *apiextens
 	if _, ok := k8s.IgnoredKindList[crd.Spec.Names.Kind]; ok {
 		return nil, nil
 	}
-	if err := deletiondefender.Add(r.mgr, crd); err != nil {
+	if err := deletiondefender.Add(r.mgr, crd, r.orderedDeletion); err != nil {
 		return nil, fmt.Errorf("error registering deletion defender controller for '%v': %w", crd.GetName(), err)
 	}
 	return nil, nil
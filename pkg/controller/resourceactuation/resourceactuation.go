@@ -15,23 +15,64 @@
 package resourceactuation
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/actuationwindow"
 	opv1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/v1beta1"
 	opk8s "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/k8s"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/reconciliationinterval"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// ActuationDecision is the result of DecideActuationMode.
+type ActuationDecision struct {
+	// Mode is the actuation mode of the resource.
+	Mode opv1beta1.ActuationMode
+	// PendingActuationWindow is true if Mode is 'Reconciling' but the resource is
+	// outside of its actuation windows, in which case it must not be actuated yet.
+	PendingActuationWindow bool
+	// NextActuationWindow is the start of the next actuation window if
+	// PendingActuationWindow is true. It is the zero time if no window starts again.
+	NextActuationWindow time.Time
+}
+
 // DecideActuationMode looks at CC and CCC to see if they specify an actuationMode.
 // - If both CC & CCC specify a actuationMode in Namespaced mode, we defer to the CCC's value.
 //   - If only CC specifies a actuationMode in Namespaced mode, we defer to the CC's value.
 //
 // - If both CC & CCC specify an actuationMode in cluster mode, the CCC specification is irrelevant.
 // - If neither CC nor CCC specify a actuationMode, we defer to the default value defined in apis.
-func DecideActuationMode(cc opv1beta1.ConfigConnector, ccc opv1beta1.ConfigConnectorContext) opv1beta1.ActuationMode {
+//
+// The actuation windows are picked the same way. If the actuation mode is 'Reconciling' and now is
+// outside of the actuation windows, the decision is pending the next actuation window.
+func DecideActuationMode(cc opv1beta1.ConfigConnector, ccc opv1beta1.ConfigConnectorContext, now time.Time) (ActuationDecision, error) {
+	decision := ActuationDecision{Mode: decideActuationMode(cc, ccc)}
+	if decision.Mode != opv1beta1.Reconciling {
+		return decision, nil
+	}
+
+	windows := cc.Spec.ActuationWindows
+	if len(ccc.Spec.ActuationWindows) != 0 && cc.Spec.Mode == opk8s.NamespacedMode {
+		windows = ccc.Spec.ActuationWindows
+	}
+	open, nextStart, err := actuationwindow.Evaluate(windows, now)
+	if err != nil {
+		return ActuationDecision{}, err
+	}
+	if !open {
+		decision.PendingActuationWindow = true
+		decision.NextActuationWindow = nextStart
+	}
+	return decision, nil
+}
+
+func decideActuationMode(cc opv1beta1.ConfigConnector, ccc opv1beta1.ConfigConnectorContext) opv1beta1.ActuationMode {
 	if ccc.Spec.Actuation != "" && cc.Spec.Mode == opk8s.NamespacedMode {
 		return ccc.Spec.Actuation
 	}
@@ -44,6 +85,28 @@ func DecideActuationMode(cc opv1beta1.ConfigConnector, ccc opv1beta1.ConfigConne
 	return opv1beta1.DefaultActuationMode()
 }
 
+// minWindowRequeue is the shortest wait before reconciling a resource pending an actuation
+// window again. A window which started since the decision was made must still requeue the
+// resource, as controller-runtime doesn't requeue a result with a non-positive RequeueAfter.
+const minWindowRequeue = time.Second
+
+// RequeueAfter returns how long to wait before reconciling a resource again, given the
+// jittered reconciliation period. Resources pending an actuation window are reconciled
+// again when the window starts, if that is earlier.
+func (d ActuationDecision) RequeueAfter(jitteredPeriod time.Duration, now time.Time) time.Duration {
+	if !d.PendingActuationWindow || d.NextActuationWindow.IsZero() {
+		return jitteredPeriod
+	}
+	untilNextWindow := d.NextActuationWindow.Sub(now)
+	if untilNextWindow < minWindowRequeue {
+		untilNextWindow = minWindowRequeue
+	}
+	if jitteredPeriod == 0 || untilNextWindow < jitteredPeriod {
+		return untilNextWindow
+	}
+	return jitteredPeriod
+}
+
 // ShouldSkip skips a resource actuatation if the ReconcileIntervalInSecondsAnnotation = 0 and the KRM resource has not changed since its last UpToDate.
 // This will disable drift correction on corresponding GCP resources since the reconcileInterval is set to 0.
 func ShouldSkip(u *unstructured.Unstructured) (bool, error) {
@@ -93,3 +156,31 @@ func ShouldSkip(u *unstructured.Unstructured) (bool, error) {
 	}
 	return false, nil
 }
+
+// PendingActuationWindowHandler surfaces on a resource that its actuation is waiting for
+// the next actuation window. It is implemented by the lifecycle handler.
+type PendingActuationWindowHandler interface {
+	HandlePendingActuationWindow(ctx context.Context, resource *k8s.Resource, nextWindow time.Time) error
+}
+
+// HandlePendingActuationWindow skips the actuation of obj, which is outside of its actuation
+// windows. Unless obj is being deleted, ensureFinalizers is called first so that uninstalling
+// never deletes the underlying cloud provider resource. toResource then converts obj, with its
+// finalizers, to the k8s resource on which the pending actuation window is surfaced.
+func (d ActuationDecision) HandlePendingActuationWindow(ctx context.Context, h PendingActuationWindowHandler, obj metav1.Object,
+	ensureFinalizers func() error, toResource func() (*k8s.Resource, error)) error {
+	logger := log.FromContext(ctx)
+	logger.Info("Skipping actuation of resource as it is outside of the actuation windows", "resource", k8s.GetNamespacedName(obj), "next actuation window", d.NextActuationWindow)
+
+	// add finalizers for deletion defender to make sure we don't delete cloud provider resources when uninstalling
+	if obj.GetDeletionTimestamp().IsZero() {
+		if err := ensureFinalizers(); err != nil {
+			return err
+		}
+	}
+	resource, err := toResource()
+	if err != nil {
+		return fmt.Errorf("error converting to k8s resource while handling %v event: %w", k8s.PendingActuationWindow, err)
+	}
+	return h.HandlePendingActuationWindow(ctx, resource, d.NextActuationWindow)
+}
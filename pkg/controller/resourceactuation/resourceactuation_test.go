@@ -15,13 +15,17 @@
 package resourceactuation_test
 
 import (
+	"context"
+	"errors"
 	"strconv"
 	"testing"
+	"time"
 
 	opv1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/v1beta1"
 	opk8s "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/k8s"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourceactuation"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -85,9 +89,228 @@ func TestDecideActuationMode(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			actualMode := resourceactuation.DecideActuationMode(test.cc, test.ccc)
-			if test.expectedActuationMode != actualMode {
-				t.Errorf("DecideActuationMode failed; got %v, want %v", actualMode, test.expectedActuationMode)
+			decision, err := resourceactuation.DecideActuationMode(test.cc, test.ccc, time.Now())
+			if err != nil {
+				t.Fatalf("DecideActuationMode failed: %v", err)
+			}
+			if test.expectedActuationMode != decision.Mode {
+				t.Errorf("DecideActuationMode failed; got %v, want %v", decision.Mode, test.expectedActuationMode)
+			}
+			if decision.PendingActuationWindow {
+				t.Errorf("DecideActuationMode failed; got pending actuation window without actuation windows")
+			}
+		})
+	}
+}
+
+func TestDecideActuationModeWithActuationWindows(t *testing.T) {
+	// 10pm to 2am every day.
+	nightly := []opv1beta1.ActuationWindow{
+		{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+	}
+	// 12pm to 1pm every day.
+	lunch := []opv1beta1.ActuationWindow{
+		{Schedule: "0 12 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+	}
+	noon := time.Date(2024, 6, 5, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name                    string
+		cc                      opv1beta1.ConfigConnector
+		ccc                     opv1beta1.ConfigConnectorContext
+		expectedActuationMode   opv1beta1.ActuationMode
+		expectedPending         bool
+		expectedNextWindowStart time.Time
+	}{
+		{
+			name: "outside of CC windows",
+			cc: opv1beta1.ConfigConnector{
+				Spec: opv1beta1.ConfigConnectorSpec{
+					Mode:             opk8s.ClusterMode,
+					ActuationWindows: nightly,
+				},
+			},
+			expectedActuationMode:   opv1beta1.Reconciling,
+			expectedPending:         true,
+			expectedNextWindowStart: time.Date(2024, 6, 5, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "CCC windows take precedence in namespaced mode",
+			cc: opv1beta1.ConfigConnector{
+				Spec: opv1beta1.ConfigConnectorSpec{
+					Mode:             opk8s.NamespacedMode,
+					ActuationWindows: nightly,
+				},
+			},
+			ccc: opv1beta1.ConfigConnectorContext{
+				Spec: opv1beta1.ConfigConnectorContextSpec{
+					ActuationWindows: lunch,
+				},
+			},
+			expectedActuationMode: opv1beta1.Reconciling,
+		},
+		{
+			name: "CCC windows are ignored in cluster mode",
+			cc: opv1beta1.ConfigConnector{
+				Spec: opv1beta1.ConfigConnectorSpec{
+					Mode: opk8s.ClusterMode,
+				},
+			},
+			ccc: opv1beta1.ConfigConnectorContext{
+				Spec: opv1beta1.ConfigConnectorContextSpec{
+					ActuationWindows: nightly,
+				},
+			},
+			expectedActuationMode: opv1beta1.Reconciling,
+		},
+		{
+			name: "paused mode is not pending",
+			cc: opv1beta1.ConfigConnector{
+				Spec: opv1beta1.ConfigConnectorSpec{
+					Mode:             opk8s.ClusterMode,
+					Actuation:        opv1beta1.Paused,
+					ActuationWindows: nightly,
+				},
+			},
+			expectedActuationMode: opv1beta1.Paused,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decision, err := resourceactuation.DecideActuationMode(test.cc, test.ccc, noon)
+			if err != nil {
+				t.Fatalf("DecideActuationMode failed: %v", err)
+			}
+			if test.expectedActuationMode != decision.Mode {
+				t.Errorf("DecideActuationMode failed; got mode %v, want %v", decision.Mode, test.expectedActuationMode)
+			}
+			if test.expectedPending != decision.PendingActuationWindow {
+				t.Errorf("DecideActuationMode failed; got pending %v, want %v", decision.PendingActuationWindow, test.expectedPending)
+			}
+			if !test.expectedNextWindowStart.Equal(decision.NextActuationWindow) {
+				t.Errorf("DecideActuationMode failed; got next window %v, want %v", decision.NextActuationWindow, test.expectedNextWindowStart)
+			}
+		})
+	}
+}
+
+func TestRequeueAfter(t *testing.T) {
+	now := time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC)
+	pending := resourceactuation.ActuationDecision{
+		Mode:                   opv1beta1.Reconciling,
+		PendingActuationWindow: true,
+		NextActuationWindow:    now.Add(time.Hour),
+	}
+	tests := []struct {
+		name           string
+		decision       resourceactuation.ActuationDecision
+		now            time.Time
+		jitteredPeriod time.Duration
+		want           time.Duration
+	}{
+		{name: "not pending", decision: resourceactuation.ActuationDecision{Mode: opv1beta1.Reconciling}, jitteredPeriod: 10 * time.Minute, want: 10 * time.Minute},
+		{name: "period before window", decision: pending, jitteredPeriod: 10 * time.Minute, want: 10 * time.Minute},
+		{name: "window before period", decision: pending, jitteredPeriod: 2 * time.Hour, want: time.Hour},
+		{name: "no periodic reconciliation", decision: pending, jitteredPeriod: 0, want: time.Hour},
+		{name: "window started since the decision", decision: pending, now: now.Add(time.Hour + time.Millisecond), jitteredPeriod: 10 * time.Minute, want: time.Second},
+		{name: "window starting now", decision: pending, now: now.Add(time.Hour), jitteredPeriod: 0, want: time.Second},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requeueNow := now
+			if !test.now.IsZero() {
+				requeueNow = test.now
+			}
+			if got := test.decision.RequeueAfter(test.jitteredPeriod, requeueNow); got != test.want {
+				t.Errorf("RequeueAfter failed; got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+type recordingPendingActuationWindowHandler struct {
+	resource   *k8s.Resource
+	nextWindow time.Time
+}
+
+func (h *recordingPendingActuationWindowHandler) HandlePendingActuationWindow(_ context.Context, resource *k8s.Resource, nextWindow time.Time) error {
+	h.resource = resource
+	h.nextWindow = nextWindow
+	return nil
+}
+
+func TestHandlePendingActuationWindow(t *testing.T) {
+	nextWindow := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	decision := resourceactuation.ActuationDecision{
+		Mode:                   opv1beta1.Reconciling,
+		PendingActuationWindow: true,
+		NextActuationWindow:    nextWindow,
+	}
+	errFinalizers := errors.New("finalizers")
+	tests := []struct {
+		name                 string
+		deleting             bool
+		ensureFinalizersErr  error
+		toResourceErr        error
+		wantEnsureFinalizers bool
+		wantHandled          bool
+		wantErr              bool
+	}{
+		{
+			name:                 "finalizers are ensured before surfacing the pending window",
+			wantEnsureFinalizers: true,
+			wantHandled:          true,
+		},
+		{
+			name:        "finalizers are not added to a resource being deleted",
+			deleting:    true,
+			wantHandled: true,
+		},
+		{
+			name:                 "error ensuring the finalizers",
+			ensureFinalizersErr:  errFinalizers,
+			wantEnsureFinalizers: true,
+			wantErr:              true,
+		},
+		{
+			name:                 "error converting the resource",
+			toResourceErr:        errors.New("conversion"),
+			wantEnsureFinalizers: true,
+			wantErr:              true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var obj metav1.ObjectMeta
+			obj.SetNamespace("ns")
+			obj.SetName("name")
+			if tc.deleting {
+				obj.SetDeletionTimestamp(&metav1.Time{Time: nextWindow})
+			}
+			ensuredFinalizers := false
+			ensureFinalizers := func() error {
+				ensuredFinalizers = true
+				return tc.ensureFinalizersErr
+			}
+			resource := &k8s.Resource{}
+			toResource := func() (*k8s.Resource, error) { return resource, tc.toResourceErr }
+			h := &recordingPendingActuationWindowHandler{}
+
+			err := decision.HandlePendingActuationWindow(context.Background(), h, &obj, ensureFinalizers, toResource)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("HandlePendingActuationWindow() error = %v, want error %t", err, tc.wantErr)
+			}
+			if tc.ensureFinalizersErr != nil && !errors.Is(err, tc.ensureFinalizersErr) {
+				t.Errorf("HandlePendingActuationWindow() error = %v, want %v", err, tc.ensureFinalizersErr)
+			}
+			if ensuredFinalizers != tc.wantEnsureFinalizers {
+				t.Errorf("finalizers ensured = %t, want %t", ensuredFinalizers, tc.wantEnsureFinalizers)
+			}
+			if handled := h.resource != nil; handled != tc.wantHandled {
+				t.Fatalf("pending actuation window handled = %t, want %t", handled, tc.wantHandled)
+			}
+			if tc.wantHandled && (h.resource != resource || !h.nextWindow.Equal(nextWindow)) {
+				t.Errorf("handler got (%p, %v), want (%p, %v)", h.resource, h.nextWindow, resource, nextWindow)
 			}
 		})
 	}
@@ -28,10 +28,12 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/lifecyclehandler"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/metrics"
 	kccpredicate "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/predicate"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/priorityqueue"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/ratelimiter"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourceactuation"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/resourcewatcher"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/execution"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp/credentials"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/krmtotf"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/lease/leaser"
@@ -39,6 +41,7 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/resourceoverrides/operations"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/servicemapping/servicemappingloader"
 	tfresource "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tf/resource"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tracing"
 	"github.com/go-logr/logr"
 	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -79,13 +82,13 @@ type Reconciler struct {
 	resourceWatcherRoutines    *semaphore.Weighted // Used to cap number of goroutines watching unready dependencies
 }
 
-func Add(mgr manager.Manager, crd *apiextensions.CustomResourceDefinition, provider *tfschema.Provider, smLoader *servicemappingloader.ServiceMappingLoader, defaulters []k8s.Defaulter, jitterGenerator jitter.Generator, additionalPredicate predicate.Predicate) (k8s.SchemaReferenceUpdater, error) {
+func Add(mgr manager.Manager, crd *apiextensions.CustomResourceDefinition, provider *tfschema.Provider, smLoader *servicemappingloader.ServiceMappingLoader, defaulters []k8s.Defaulter, jitterGenerator jitter.Generator, leaseBackends leaser.Backends, additionalPredicate predicate.Predicate) (k8s.SchemaReferenceUpdater, error) {
 	kind := crd.Spec.Names.Kind
 	apiVersion := k8s.GetAPIVersionFromCRD(crd)
 	controllerName := fmt.Sprintf("%v-controller", strings.ToLower(kind))
 	immediateReconcileRequests := make(chan event.GenericEvent, k8s.ImmediateReconcileRequestsBufferSize)
 	resourceWatcherRoutines := semaphore.NewWeighted(k8s.MaxNumResourceWatcherRoutines)
-	r, err := NewReconciler(mgr, crd, provider, smLoader, immediateReconcileRequests, resourceWatcherRoutines, defaulters, jitterGenerator)
+	r, err := NewReconciler(mgr, crd, provider, smLoader, immediateReconcileRequests, resourceWatcherRoutines, defaulters, jitterGenerator, leaseBackends)
 	if err != nil {
 		return nil, err
 	}
@@ -99,13 +102,14 @@ func Add(mgr manager.Manager, crd *apiextensions.CustomResourceDefinition, provi
 	if additionalPredicate != nil {
 		predicateList = append(predicateList, additionalPredicate)
 	}
+	gate := priorityqueue.NewGate(obj.GroupVersionKind(), k8s.ControllerMaxConcurrentReconciles)
 	_, err = builder.
 		ControllerManagedBy(mgr).
 		Named(controllerName).
 		WithOptions(controller.Options{MaxConcurrentReconciles: k8s.ControllerMaxConcurrentReconciles, RateLimiter: ratelimiter.NewRateLimiter()}).
-		WatchesRawSource(&source.Channel{Source: immediateReconcileRequests}, &handler.EnqueueRequestForObject{}).
-		For(obj, builder.OnlyMetadata, builder.WithPredicates(predicateList...)).
-		Build(r)
+		WatchesRawSource(gate.Source(&source.Channel{Source: immediateReconcileRequests}), &handler.EnqueueRequestForObject{}).
+		WatchesRawSource(gate.Kind(mgr.GetCache(), obj.GroupVersionKind()), &handler.EnqueueRequestForObject{}, builder.WithPredicates(predicateList...)).
+		Build(gate.Reconciler(r))
 	if err != nil {
 		return nil, fmt.Errorf("error creating new controller: %w", err)
 	}
@@ -121,7 +125,8 @@ func NewReconciler(mgr manager.Manager,
 	immediateReconcileRequests chan event.GenericEvent,
 	resourceWatcherRoutines *semaphore.Weighted,
 	defaulters []k8s.Defaulter,
-	jitterGenerator jitter.Generator) (*Reconciler, error) {
+	jitterGenerator jitter.Generator,
+	leaseBackends leaser.Backends) (*Reconciler, error) {
 
 	if jitterGenerator == nil {
 		return nil, fmt.Errorf("jitterGenerator must not be nil")
@@ -133,7 +138,7 @@ func NewReconciler(mgr manager.Manager,
 			mgr.GetClient(),
 			mgr.GetEventRecorderFor(controllerName),
 		),
-		resourceLeaser: leaser.NewResourceLeaser(p, smLoader, mgr.GetClient()),
+		resourceLeaser: leaser.NewResourceLeaser(p, smLoader, mgr.GetClient(), leaseBackends),
 		defaulters:     defaulters,
 		mgr:            mgr,
 		schemaRef: &k8s.SchemaReference{
@@ -145,9 +150,6 @@ func NewReconciler(mgr manager.Manager,
 				Kind:    crd.Spec.Names.Kind,
 			},
 		},
-		ReconcilerMetrics: metrics.ReconcilerMetrics{
-			ResourceNameLabel: metrics.ResourceNameLabel,
-		},
 		provider:                   p,
 		smLoader:                   smLoader,
 		logger:                     logger.WithName(controllerName),
@@ -162,9 +164,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (res
 	defer r.schemaRefMu.RUnlock()
 	r.logger.Info("starting reconcile", "resource", req.NamespacedName)
 	startTime := time.Now()
+	ctx, span := tracing.StartReconcile(ctx, r.schemaRef.GVK, req.NamespacedName)
+	defer func() { tracing.End(span, err) }()
 	r.RecordReconcileWorkers(ctx, r.schemaRef.GVK)
 	defer r.AfterReconcile()
 	defer r.RecordReconcileMetrics(ctx, r.schemaRef.GVK, req.Namespace, req.Name, startTime, &err)
+	ctx = credentials.WithKind(ctx, r.schemaRef.GVK.Kind)
 
 	u := &unstructured.Unstructured{}
 	u.SetGroupVersionKind(r.schemaRef.GVK)
@@ -176,6 +181,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (res
 		}
 		return reconcile.Result{}, err
 	}
+	if k8s.HasReconcilePausedAnnotation(u) {
+		r.logger.Info("Skipping reconcile as the resource has the reconcile-paused annotation", "resource", req.NamespacedName)
+		resource, err := k8s.NewResource(u)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("could not parse resource %s: %w", req.NamespacedName.String(), err)
+		}
+		return reconcile.Result{}, r.HandlePaused(ctx, resource)
+	}
 	skip, err := resourceactuation.ShouldSkip(u)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -209,9 +222,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (res
 		return reconcile.Result{}, err
 	}
 
-	am := resourceactuation.DecideActuationMode(cc, ccc)
-	switch am {
+	now := time.Now()
+	decision, err := resourceactuation.DecideActuationMode(cc, ccc, now)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	switch am := decision.Mode; am {
 	case v1beta1.Reconciling:
+		if decision.PendingActuationWindow {
+			jitteredPeriod, err := r.jitterGenerator.JitteredReenqueue(r.schemaRef.GVK, u)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+
+			ensureFinalizers := func() error {
+				return r.EnsureFinalizers(ctx, resource.Original, &resource.Resource, k8s.ControllerFinalizerName, k8s.DeletionDefenderFinalizerName)
+			}
+			toResource := func() (*k8s.Resource, error) { return &resource.Resource, nil }
+			requeueAfter := decision.RequeueAfter(jitteredPeriod, now)
+			return reconcile.Result{RequeueAfter: requeueAfter}, decision.HandlePendingActuationWindow(ctx, r, &resource.Resource, ensureFinalizers, toResource)
+		}
 		r.logger.V(2).Info("Actuating a resource as actuation mode is \"Reconciling\"", "resource", req.NamespacedName)
 	case v1beta1.Paused:
 		jitteredPeriod, err := r.jitterGenerator.JitteredReenqueue(r.schemaRef.GVK, u)
@@ -294,7 +324,9 @@ func (r *Reconciler) sync(ctx context.Context, krmResource *krmtotf.Resource) (r
 				}
 			}
 		}
-		liveState, err := krmtotf.FetchLiveStateForDelete(ctx, krmResource, r.provider, r, r.smLoader)
+		fetchCtx, span := tracing.Start(ctx, "FetchLiveState")
+		liveState, err := krmtotf.FetchLiveStateForDelete(fetchCtx, krmResource, r.provider, r, r.smLoader)
+		tracing.End(span, err)
 		if err != nil {
 			return false, r.HandleDeleteFailed(ctx, &krmResource.Resource, fmt.Errorf("error fetching live state: %w", err))
 		}
@@ -306,12 +338,17 @@ func (r *Reconciler) sync(ctx context.Context, krmResource *krmtotf.Resource) (r
 			return false, err
 		}
 		r.logger.Info("deleting underlying resource", "resource", k8s.GetNamespacedName(krmResource))
-		if _, err := krmResource.TFResource.Apply(ctx, liveState, &terraform.InstanceDiff{Destroy: true}, r.provider.Meta()); err != nil {
-			return false, r.HandleDeleteFailed(ctx, &krmResource.Resource, fmt.Errorf("error deleting resource: %v", err))
+		applyCtx, span := tracing.Start(ctx, "TerraformApply")
+		_, diagnostics := krmResource.TFResource.Apply(applyCtx, liveState, &terraform.InstanceDiff{Destroy: true}, krmtotf.MetaWithContext(applyCtx, r.provider))
+		tracing.End(span, krmtotf.NewErrorFromDiagnostics(diagnostics))
+		if diagnostics != nil {
+			return false, r.HandleDeleteFailed(ctx, &krmResource.Resource, fmt.Errorf("error deleting resource: %v", diagnostics))
 		}
 		return false, r.handleDeleted(ctx, krmResource)
 	}
-	liveState, err := krmtotf.FetchLiveStateForCreateAndUpdate(ctx, krmResource, r.provider, r, r.smLoader)
+	fetchCtx, span := tracing.Start(ctx, "FetchLiveState")
+	liveState, err := krmtotf.FetchLiveStateForCreateAndUpdate(fetchCtx, krmResource, r.provider, r, r.smLoader)
+	tracing.End(span, err)
 	if err != nil {
 		if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
 			r.logger.Info(unwrappedErr.Error(), "resource", k8s.GetNamespacedName(krmResource))
@@ -334,9 +371,12 @@ func (r *Reconciler) sync(ctx context.Context, krmResource *krmtotf.Resource) (r
 		return false, r.HandleUpdateFailed(ctx, &krmResource.Resource,
 			fmt.Errorf("underlying resource no longer exists and can't be recreated without creating a brand new resource"))
 	}
+	// Expanding the resource configuration resolves the references of the resource.
+	_, span = tracing.Start(ctx, "ResolveReferences")
 	config, secretVersions, err := krmtotf.KRMResourceToTFResourceConfigFull(
 		krmResource, r, r.smLoader, liveState, r.schemaRef.JSONSchema, true,
 	)
+	tracing.End(span, err)
 	if err != nil {
 		if unwrappedErr, ok := lifecyclehandler.CausedByUnresolvableDeps(err); ok {
 			r.logger.Info(unwrappedErr.Error(), "resource", k8s.GetNamespacedName(krmResource))
@@ -348,7 +388,7 @@ func (r *Reconciler) sync(ctx context.Context, krmResource *krmtotf.Resource) (r
 	if err := resourceoverrides.Handler.PreTerraformApply(ctx, krmResource.GroupVersionKind(), &operations.PreTerraformApply{KRMResource: krmResource, TerraformConfig: config, LiveState: liveState}); err != nil {
 		return false, r.HandleUpdateFailed(ctx, &krmResource.Resource, fmt.Errorf("error applying pre-apply transformation to resource: %w", err))
 	}
-	diff, err := krmResource.TFResource.Diff(ctx, liveState, config, r.provider.Meta())
+	diff, err := krmResource.TFResource.Diff(ctx, liveState, config, krmtotf.MetaWithContext(ctx, r.provider))
 	if err != nil {
 		return false, r.HandleUpdateFailed(ctx, &krmResource.Resource, fmt.Errorf("error calculating diff: %w", err))
 	}
@@ -375,8 +415,11 @@ func (r *Reconciler) sync(ctx context.Context, krmResource *krmtotf.Resource) (r
 			d.RequiresNew = false
 		}
 	}
-	newState, diagnostics := krmResource.TFResource.Apply(ctx, liveState, diff, r.provider.Meta())
-	if err := krmtotf.NewErrorFromDiagnostics(diagnostics); err != nil {
+	applyCtx, span := tracing.Start(ctx, "TerraformApply")
+	newState, diagnostics := krmResource.TFResource.Apply(applyCtx, liveState, diff, krmtotf.MetaWithContext(applyCtx, r.provider))
+	err = krmtotf.NewErrorFromDiagnostics(diagnostics)
+	tracing.End(span, err)
+	if err != nil {
 		r.logger.Error(err, "error applying desired state", "resource", krmResource.GetNamespacedName())
 		return false, r.HandleUpdateFailed(ctx, &krmResource.Resource, fmt.Errorf("error applying desired state: %w", err))
 	}
@@ -485,6 +528,17 @@ func (r *Reconciler) obtainResourceLeaseIfNecessary(ctx context.Context, krmReso
 	if err != nil {
 		return err
 	}
+	if k8s.IsExternalLeasePolicy(conflictPolicy) {
+		resourceID, err := leaseResourceID(krmResource)
+		if err != nil {
+			return err
+		}
+		if err := r.resourceLeaser.ObtainExternal(ctx, conflictPolicy, &krmResource.Resource, resourceID); err != nil {
+			return r.HandleObtainLeaseFailed(ctx, &krmResource.Resource, fmt.Errorf("error obtaining lease on '%v': %w",
+				k8s.GetNamespacedName(krmResource), err))
+		}
+		return nil
+	}
 	if conflictPolicy != k8s.ManagementConflictPreventionPolicyResource {
 		return nil
 	}
@@ -506,10 +560,37 @@ func (r *Reconciler) obtainResourceLeaseIfNecessary(ctx context.Context, krmReso
 	return nil
 }
 
+// leaseResourceID returns the ID of the GCP resource which identifies its external lease, or ""
+// if the ID is generated by the server and the GCP resource has not been created yet.
+func leaseResourceID(resource *krmtotf.Resource) (string, error) {
+	var resourceID string
+	var err error
+	switch {
+	case krmtotf.SupportsResourceIDField(&resource.ResourceConfig):
+		resourceID, err = resource.GetResourceID()
+	case resource.HasServerGeneratedIDField():
+		resourceID, err = resource.GetServerGeneratedID()
+	default:
+		return resource.GetName(), nil
+	}
+	if _, ok := k8s.AsServerGeneratedIDNotFoundError(err); ok {
+		return "", nil
+	}
+	return resourceID, err
+}
+
 func (r *Reconciler) handleDeleted(ctx context.Context, resource *krmtotf.Resource) error {
 	if err := resourceoverrides.Handler.PostActuationTransform(resource.Original, &resource.Resource, nil, nil); err != nil {
 		return r.HandlePostActuationTransformFailed(ctx, &resource.Resource, fmt.Errorf("error applying post-actuation transformation to resource '%v': %w", resource.GetNamespacedName(), err))
 	}
+	// A failure to release the lease is not fatal: the lease expires on its own.
+	resourceID, err := leaseResourceID(resource)
+	if err == nil {
+		err = r.resourceLeaser.ReleaseExternalIfNecessary(ctx, &resource.Resource, resourceID)
+	}
+	if err != nil {
+		r.logger.Error(err, "error releasing lease", "resource", resource.GetNamespacedName())
+	}
 	return r.HandleDeleted(ctx, &resource.Resource)
 }
 
@@ -77,6 +77,7 @@ func GenerateTF2CRD(sm *corekccv1alpha1.ServiceMapping, resourceConfig *corekccv
 		}
 	}
 	addObservedFieldsToObservedState(resourceConfig, specJSONSchema, statusOrObservedStateJSONSchema)
+	addPendingOperationIfSupported(resourceConfig, statusOrObservedStateJSONSchema)
 	removeIgnoredOutputOnlySpecFields(resourceConfig, specJSONSchema)
 
 	if len(specJSONSchema.Properties) > 0 {
@@ -531,6 +532,34 @@ func addObservedFieldsToObservedState(rc *corekccv1alpha1.ResourceConfig, spec *
 	status.Properties[k8s.ObservedStateFieldName] = observedStateJSONSchema
 }
 
+func addPendingOperationIfSupported(rc *corekccv1alpha1.ResourceConfig, status *apiextensions.JSONSchemaProps) {
+	if !rc.TracksOperations {
+		return
+	}
+	if status.Properties == nil {
+		status.Properties = make(map[string]apiextensions.JSONSchemaProps)
+	}
+	status.Properties[k8s.PendingOperationFieldName] = apiextensions.JSONSchemaProps{
+		Type:        "object",
+		Description: "PendingOperation is the GCP long-running operation which the Config Connector controller is waiting for, if any.",
+		Properties: map[string]apiextensions.JSONSchemaProps{
+			"name": {
+				Type:        "string",
+				Description: `The name of the operation, e.g. "projects/p/locations/l/operations/o".`,
+			},
+			"startTime": {
+				Type:        "string",
+				Description: "The time the operation was started, in RFC3339 format.",
+			},
+			"type": {
+				Type:        "string",
+				Description: `The type of the operation, e.g. "create" or "update".`,
+			},
+		},
+		Required: []string{"name"},
+	}
+}
+
 func populateObservedField(observedFieldPath []string, sourceSchema *apiextensions.JSONSchemaProps, observedFieldParent *apiextensions.JSONSchemaProps) apiextensions.JSONSchemaProps {
 	field := text.SnakeCaseToLowerCamelCase(observedFieldPath[0])
 	if len(observedFieldPath) > 1 {
@@ -27,6 +27,7 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/test"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tracing"
 
 	"github.com/GoogleCloudPlatform/declarative-resource-client-library/dcl"
 	"golang.org/x/oauth2/google"
@@ -52,6 +53,15 @@ func newConfigAndClient(ctx context.Context, opt Options) (*dcl.Config, *http.Cl
 		}
 		opt.HTTPClient = httpClient
 	}
+	if opt.GCPCredentialSelector != nil {
+		opt.HTTPClient = opt.GCPCredentialSelector.WrapHTTPClient(opt.HTTPClient)
+	}
+	if opt.GCPRateLimiter != nil {
+		opt.HTTPClient = opt.GCPRateLimiter.WrapHTTPClient(opt.HTTPClient)
+	}
+	if opt.GCPTracing {
+		opt.HTTPClient = tracing.WrapHTTPClient(opt.HTTPClient)
+	}
 
 	configOptions := []dcl.ConfigOption{
 		dcl.WithHTTPClient(opt.HTTPClient),
@@ -24,6 +24,7 @@ import (
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -31,9 +32,20 @@ type FuzzFn func(t *testing.T, seed int64)
 
 var fuzzers []FuzzFn
 
-func RegisterKRMFuzzer(fuzzer KRMFuzzer) {
+// krmFuzzers holds the registered KRM fuzzers by the GroupKind of their KRM type.
+var krmFuzzers = map[schema.GroupKind]KRMFuzzer{}
+
+// RegisterKRMFuzzer registers the fuzzer of the mappers of the KRM type gvk.
+func RegisterKRMFuzzer(gvk schema.GroupVersionKind, fuzzer KRMFuzzer) {
 	RegisterFuzzer(fuzzer.FuzzSpec)
 	RegisterFuzzer(fuzzer.FuzzStatus)
+	krmFuzzers[gvk.GroupKind()] = fuzzer
+}
+
+// GetKRMFuzzer returns the KRM fuzzer registered for gk.
+func GetKRMFuzzer(gk schema.GroupKind) (KRMFuzzer, bool) {
+	fuzzer, ok := krmFuzzers[gk]
+	return fuzzer, ok
 }
 
 func RegisterFuzzer(fuzzer FuzzFn) {
@@ -61,6 +73,9 @@ type KRMTypedFuzzer[ProtoT proto.Message, SpecType any, StatusType any] struct {
 type KRMFuzzer interface {
 	FuzzSpec(t *testing.T, seed int64)
 	FuzzStatus(t *testing.T, seed int64)
+
+	// FieldGaps returns the proto fields which don't survive a round-trip.
+	FieldGaps(t *testing.T, seeds []int64) *FieldGaps
 }
 
 func NewKRMTypedFuzzer[ProtoT proto.Message, SpecType any, StatusType any](
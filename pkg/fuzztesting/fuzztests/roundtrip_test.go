@@ -0,0 +1,122 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fuzztesting
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct/registry"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/fuzztesting"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// knownGapsFile lists the direct kinds without a round-trip fuzzer, and the
+// proto fields which are known to be unimplemented, unmapped or lossy, one per
+// line:
+//
+//	<Kind>
+//	<Kind> unimplemented <field path>
+//	<Kind> unmapped <field path>
+//	<Kind> lossy <field path>
+//
+// Run the test with the WRITE_GOLDEN_OUTPUT env var set to regenerate it.
+const knownGapsFile = "testdata/known_gaps.txt"
+
+// roundTripSeeds is the number of seeds each fuzzer is run with. The seeds
+// are fixed, so that the gaps found don't change from run to run.
+const roundTripSeeds = 50
+
+func TestRoundTripCoverage(t *testing.T) {
+	var seeds []int64
+	for i := int64(0); i < roundTripSeeds; i++ {
+		seeds = append(seeds, i)
+	}
+
+	var gaps []string
+	for _, gk := range registry.RegisteredGroupKinds() {
+		fuzzer, ok := fuzztesting.GetKRMFuzzer(gk)
+		if !ok {
+			gaps = append(gaps, gk.Kind)
+			continue
+		}
+		fieldGaps := fuzzer.FieldGaps(t, seeds)
+		for _, path := range sets.List(fieldGaps.Unimplemented) {
+			gaps = append(gaps, gk.Kind+" unimplemented "+path)
+		}
+		for _, path := range sets.List(fieldGaps.Unmapped) {
+			gaps = append(gaps, gk.Kind+" unmapped "+path)
+		}
+		for _, path := range sets.List(fieldGaps.Lossy) {
+			gaps = append(gaps, gk.Kind+" lossy "+path)
+		}
+	}
+
+	if os.Getenv("WRITE_GOLDEN_OUTPUT") != "" {
+		writeKnownGaps(t, gaps)
+		return
+	}
+
+	known := readKnownGaps(t)
+	for _, gap := range gaps {
+		if known.Has(gap) {
+			continue
+		}
+		if !strings.Contains(gap, " ") {
+			t.Errorf("direct kind %s has no round-trip fuzzer; register one with fuzztesting.RegisterKRMFuzzer", gap)
+		} else {
+			t.Errorf("round-trip gap %q is not in %s; fix the mapper or the field sets of the fuzzer, or add it as a known gap", gap, knownGapsFile)
+		}
+	}
+	for _, gap := range sets.List(known.Difference(sets.New(gaps...))) {
+		t.Errorf("%q is no longer a round-trip gap; remove it from %s", gap, knownGapsFile)
+	}
+}
+
+func readKnownGaps(t *testing.T) sets.Set[string] {
+	f, err := os.Open(knownGapsFile)
+	if err != nil {
+		t.Fatalf("opening %s: %v", knownGapsFile, err)
+	}
+	defer f.Close()
+
+	known := sets.New[string]()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		known.Insert(strings.Join(strings.Fields(line), " "))
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("reading %s: %v", knownGapsFile, err)
+	}
+	return known
+}
+
+func writeKnownGaps(t *testing.T, gaps []string) {
+	var b strings.Builder
+	b.WriteString("# Known gaps in the round-trip of the direct mappers, checked by TestRoundTripCoverage.\n")
+	b.WriteString("# Regenerate with: WRITE_GOLDEN_OUTPUT=1 go test ./pkg/fuzztesting/fuzztests -run TestRoundTripCoverage\n")
+	for _, gap := range gaps {
+		b.WriteString(gap + "\n")
+	}
+	if err := os.WriteFile(knownGapsFile, []byte(b.String()), 0644); err != nil {
+		t.Fatalf("writing %s: %v", knownGapsFile, err)
+	}
+}
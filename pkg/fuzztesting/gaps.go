@@ -0,0 +1,147 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fuzztesting
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/controller/direct"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/test/fuzz"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// FieldGaps are the proto fields which don't survive a round-trip through KRM.
+type FieldGaps struct {
+	// Unimplemented are the fields which the fuzzer declares as not mapped,
+	// and doesn't round-trip.
+	Unimplemented sets.Set[string]
+	// Unmapped are the fields which are dropped by the round-trip.
+	Unmapped sets.Set[string]
+	// Lossy are the fields whose value is changed by the round-trip.
+	Lossy sets.Set[string]
+}
+
+func newFieldGaps() *FieldGaps {
+	return &FieldGaps{
+		Unimplemented: sets.New[string](),
+		Unmapped:      sets.New[string](),
+		Lossy:         sets.New[string](),
+	}
+}
+
+// FieldGaps round-trips the spec and the status fields of the proto with the
+// given seeds, and returns the fields which are unimplemented, unmapped or
+// lossy.
+func (f *KRMTypedFuzzer[ProtoT, SpecType, StatusType]) FieldGaps(t *testing.T, seeds []int64) *FieldGaps {
+	gaps := newFieldGaps()
+	gaps.Unimplemented.Insert(sets.List(f.UnimplementedFields)...)
+	for _, seed := range seeds {
+		spec := NewFuzzTest(f.ProtoType, f.SpecFromProto, f.SpecToProto)
+		spec.IgnoreFields = f.StatusFields
+		spec.UnimplementedFields = f.UnimplementedFields
+		spec.collectGaps(t, seed, gaps)
+
+		status := NewFuzzTest(f.ProtoType, f.StatusFromProto, f.StatusToProto)
+		status.IgnoreFields = f.SpecFields
+		status.UnimplementedFields = f.UnimplementedFields
+		status.collectGaps(t, seed, gaps)
+	}
+	return gaps
+}
+
+// collectGaps is like Fuzz, but adds the fields which differ after the
+// round-trip to gaps instead of failing.
+func (f *FuzzTest[ProtoT, KRMType]) collectGaps(t *testing.T, seed int64, gaps *FieldGaps) {
+	randStream := rand.New(rand.NewSource(seed))
+
+	p1 := proto.Clone(f.ProtoType).(ProtoT)
+	fuzz.FillWithRandom(t, randStream, p1)
+
+	clearFields := &fuzz.ClearFields{
+		Paths: f.IgnoreFields.Union(f.UnimplementedFields),
+	}
+	fuzz.Visit("", p1.ProtoReflect(), nil, clearFields)
+
+	ctx := &direct.MapContext{}
+	krm := f.FromProto(ctx, p1)
+	if ctx.Err() != nil {
+		t.Fatalf("error mapping from proto to krm: %v", ctx.Err())
+	}
+	p2 := f.ToProto(ctx, krm)
+	if ctx.Err() != nil {
+		t.Fatalf("error mapping from krm to proto: %v", ctx.Err())
+	}
+
+	diffFields("", p1.ProtoReflect(), p2.ProtoReflect(), gaps)
+}
+
+// diffFields adds the fields of want which differ in got to gaps. The paths
+// are in the format of UnimplementedFields, e.g. ".host.gce_instance" or
+// ".psc_connections[].error" for the fields of the elements of a list.
+func diffFields(prefix string, want, got protoreflect.Message, gaps *FieldGaps) {
+	want.Range(func(field protoreflect.FieldDescriptor, wantVal protoreflect.Value) bool {
+		path := prefix + "." + string(field.Name())
+		if !got.IsValid() || !got.Has(field) {
+			gaps.Unmapped.Insert(path)
+			return true
+		}
+		gotVal := got.Get(field)
+		switch {
+		case field.IsList():
+			wantList, gotList := wantVal.List(), gotVal.List()
+			if wantList.Len() != gotList.Len() {
+				gaps.Lossy.Insert(path)
+				return true
+			}
+			for i := 0; i < wantList.Len(); i++ {
+				diffValues(path+"[]", field, wantList.Get(i), gotList.Get(i), gaps)
+			}
+		case field.IsMap():
+			wantMap, gotMap := wantVal.Map(), gotVal.Map()
+			wantMap.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				if !gotMap.Has(k) {
+					gaps.Lossy.Insert(path)
+					return false
+				}
+				diffValues(path, field.MapValue(), v, gotMap.Get(k), gaps)
+				return true
+			})
+		default:
+			diffValues(path, field, wantVal, gotVal, gaps)
+		}
+		return true
+	})
+}
+
+func diffValues(path string, field protoreflect.FieldDescriptor, want, got protoreflect.Value, gaps *FieldGaps) {
+	if field.Message() == nil {
+		if !want.Equal(got) {
+			gaps.Lossy.Insert(path)
+		}
+		return
+	}
+	if strings.HasPrefix(string(field.Message().FullName()), "google.protobuf.") {
+		// Well-known types, e.g. Timestamp, are mapped as a whole.
+		if !proto.Equal(want.Message().Interface(), got.Message().Interface()) {
+			gaps.Lossy.Insert(path)
+		}
+		return
+	}
+	diffFields(path, want.Message(), got.Message(), gaps)
+}
@@ -0,0 +1,152 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package credentials selects the Google service account used for the requests made to
+// GCP APIs, based on the kind and project of the resource the requests are made for.
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/impersonate"
+)
+
+// Rule maps the resources of some kinds or projects to a Google service account.
+type Rule struct {
+	// GoogleServiceAccount is the email of the service account which is impersonated for
+	// the requests made for the matching resources.
+	GoogleServiceAccount string
+	// Kinds are the kinds of the matching resources; all kinds match if empty.
+	Kinds []string
+	// Projects are the IDs of the projects of the matching resources; all projects match if empty.
+	Projects []string
+}
+
+// Matches returns true if the rule applies to a resource of the given kind and project.
+// An unknown ("") kind or project only matches rules which do not restrict it.
+func (r *Rule) Matches(kind, project string) bool {
+	if len(r.Kinds) != 0 && !contains(r.Kinds, kind) {
+		return false
+	}
+	if len(r.Projects) != 0 && !contains(r.Projects, project) {
+		return false
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Selector picks the credentials of the requests made to GCP APIs according to a list
+// of rules. Requests which do not match any rule keep their default credentials.
+// The rules can be replaced at any time, e.g. to rotate a service account.
+type Selector struct {
+	// ctx is the context of the token sources, which outlive the requests.
+	ctx            context.Context
+	newTokenSource func(ctx context.Context, serviceAccount string) (oauth2.TokenSource, error)
+
+	mutex        sync.Mutex
+	rules        []Rule
+	tokenSources map[string]oauth2.TokenSource
+}
+
+// NewSelector creates a Selector without rules. The service accounts of the rules are
+// impersonated using the application default credentials, within ctx.
+func NewSelector(ctx context.Context) *Selector {
+	return &Selector{
+		ctx:            ctx,
+		newTokenSource: impersonatedTokenSource,
+		tokenSources:   make(map[string]oauth2.TokenSource),
+	}
+}
+
+func impersonatedTokenSource(ctx context.Context, serviceAccount string) (oauth2.TokenSource, error) {
+	return impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: serviceAccount,
+		Scopes:          gcp.ClientScopes,
+	})
+}
+
+// SetRules replaces the rules of the Selector. The token sources of the service accounts
+// no longer used by any rule are dropped.
+func (s *Selector) SetRules(rules []Rule) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.rules = rules
+	used := make(map[string]bool)
+	for _, rule := range rules {
+		used[rule.GoogleServiceAccount] = true
+	}
+	for serviceAccount := range s.tokenSources {
+		if !used[serviceAccount] {
+			delete(s.tokenSources, serviceAccount)
+		}
+	}
+}
+
+// TokenSource returns the token source of the first rule matching kind and project,
+// or nil if no rule matches.
+func (s *Selector) TokenSource(kind, project string) (oauth2.TokenSource, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	serviceAccount := ""
+	for _, rule := range s.rules {
+		if rule.Matches(kind, project) {
+			serviceAccount = rule.GoogleServiceAccount
+			break
+		}
+	}
+	if serviceAccount == "" {
+		return nil, nil
+	}
+	if ts, found := s.tokenSources[serviceAccount]; found {
+		return ts, nil
+	}
+	ts, err := s.newTokenSource(s.ctx, serviceAccount)
+	if err != nil {
+		return nil, fmt.Errorf("error creating token source for service account %q: %w", serviceAccount, err)
+	}
+	s.tokenSources[serviceAccount] = ts
+	return ts, nil
+}
+
+type kindKey struct{}
+
+// WithKind returns a copy of ctx recording the kind of the resource the requests made
+// within ctx are for.
+func WithKind(ctx context.Context, kind string) context.Context {
+	return context.WithValue(ctx, kindKey{}, kind)
+}
+
+// KindFromContext returns the kind recorded with WithKind, or "" if there is none.
+func KindFromContext(ctx context.Context) string {
+	kind, _ := ctx.Value(kindKey{}).(string)
+	return kind
+}
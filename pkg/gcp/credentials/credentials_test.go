@@ -0,0 +1,157 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/metadata"
+)
+
+func newTestSelector() *Selector {
+	s := NewSelector(context.Background())
+	s.newTokenSource = func(ctx context.Context, serviceAccount string) (oauth2.TokenSource, error) {
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token-for-" + serviceAccount}), nil
+	}
+	return s
+}
+
+func TestRuleMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		kind    string
+		project string
+		want    bool
+	}{
+		{name: "catch-all", rule: Rule{}, kind: "", project: "", want: true},
+		{name: "kind", rule: Rule{Kinds: []string{"IAMPolicyMember"}}, kind: "IAMPolicyMember", project: "p1", want: true},
+		{name: "other kind", rule: Rule{Kinds: []string{"IAMPolicyMember"}}, kind: "PubSubTopic", project: "p1", want: false},
+		{name: "unknown kind", rule: Rule{Kinds: []string{"IAMPolicyMember"}}, kind: "", project: "p1", want: false},
+		{name: "project", rule: Rule{Projects: []string{"p1"}}, kind: "PubSubTopic", project: "p1", want: true},
+		{name: "unknown project", rule: Rule{Projects: []string{"p1"}}, kind: "PubSubTopic", project: "", want: false},
+		{name: "kind and project", rule: Rule{Kinds: []string{"PubSubTopic"}, Projects: []string{"p1"}}, kind: "PubSubTopic", project: "p1", want: true},
+		{name: "kind but not project", rule: Rule{Kinds: []string{"PubSubTopic"}, Projects: []string{"p1"}}, kind: "PubSubTopic", project: "p2", want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.rule.Matches(tc.kind, tc.project); got != tc.want {
+				t.Errorf("Matches(%q, %q): got %v, want %v", tc.kind, tc.project, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	// The default credentials of the client.
+	inner := &oauth2.Transport{
+		Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "default-token"}),
+		Base:   server.Client().Transport,
+	}
+	selector := newTestSelector()
+	client := &http.Client{Transport: selector.RoundTripper(inner)}
+
+	get := func(ctx context.Context, path string) string {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.Fatalf("error building request: %v", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("error reading response: %v", err)
+		}
+		return string(body)
+	}
+
+	iamCtx := WithKind(context.Background(), "IAMPolicyMember")
+	topicCtx := WithKind(context.Background(), "PubSubTopic")
+
+	if got, want := get(iamCtx, "/v1/projects/p1:getIamPolicy"), "Bearer default-token"; got != want {
+		t.Errorf("without rules: got %q, want %q", got, want)
+	}
+
+	selector.SetRules([]Rule{
+		{GoogleServiceAccount: "iam-admin@p0.iam.gserviceaccount.com", Kinds: []string{"IAMPolicyMember"}},
+		{GoogleServiceAccount: "p2@p0.iam.gserviceaccount.com", Projects: []string{"p2"}},
+	})
+	if got, want := get(iamCtx, "/v1/projects/p2:getIamPolicy"), "Bearer token-for-iam-admin@p0.iam.gserviceaccount.com"; got != want {
+		t.Errorf("kind rule: got %q, want %q", got, want)
+	}
+	if got, want := get(topicCtx, "/v1/projects/p2/topics/foo"), "Bearer token-for-p2@p0.iam.gserviceaccount.com"; got != want {
+		t.Errorf("project rule: got %q, want %q", got, want)
+	}
+	if got, want := get(topicCtx, "/v1/projects/p1/topics/foo"), "Bearer default-token"; got != want {
+		t.Errorf("no matching rule: got %q, want %q", got, want)
+	}
+
+	// Rotating the service account of a rule takes effect on the next request.
+	selector.SetRules([]Rule{
+		{GoogleServiceAccount: "iam-admin-2@p0.iam.gserviceaccount.com", Kinds: []string{"IAMPolicyMember"}},
+	})
+	if got, want := get(iamCtx, "/v1/projects/p2:getIamPolicy"), "Bearer token-for-iam-admin-2@p0.iam.gserviceaccount.com"; got != want {
+		t.Errorf("rotated rule: got %q, want %q", got, want)
+	}
+	if _, found := selector.tokenSources["iam-admin@p0.iam.gserviceaccount.com"]; found {
+		t.Errorf("expected the token source of the rotated service account to be dropped")
+	}
+}
+
+func TestPerRPCCredentials(t *testing.T) {
+	selector := newTestSelector()
+	selector.SetRules([]Rule{
+		{GoogleServiceAccount: "iam-admin@p0.iam.gserviceaccount.com", Kinds: []string{"IAMPolicyMember"}},
+		{GoogleServiceAccount: "p2@p0.iam.gserviceaccount.com", Projects: []string{"p2"}},
+	})
+	fallback := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "default-token"})
+	creds := selector.PerRPCCredentials(fallback, "billing-project")
+
+	call := func(kind, requestParams string) string {
+		ctx := WithKind(context.Background(), kind)
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-goog-request-params", requestParams)
+		md, err := creds.GetRequestMetadata(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, want := md["X-goog-user-project"], "billing-project"; got != want {
+			t.Errorf("quota project: got %q, want %q", got, want)
+		}
+		return md["authorization"]
+	}
+
+	if got, want := call("IAMPolicyMember", "resource=projects%2Fp1"), "Bearer token-for-iam-admin@p0.iam.gserviceaccount.com"; got != want {
+		t.Errorf("kind rule: got %q, want %q", got, want)
+	}
+	if got, want := call("PubSubTopic", "name=projects%2Fp2%2Ftopics%2Ffoo"), "Bearer token-for-p2@p0.iam.gserviceaccount.com"; got != want {
+		t.Errorf("project rule: got %q, want %q", got, want)
+	}
+	if got, want := call("PubSubTopic", "name=projects%2Fp1%2Ftopics%2Ffoo"), "Bearer default-token"; got != want {
+		t.Errorf("no matching rule: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	grpccredentials "google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// PerRPCCredentials returns gRPC credentials which authenticate the calls matching a rule with the
+// credentials of the rule, and the other calls with the tokens of fallback. If fallback is nil, the
+// application default credentials are used. The kind of a call is read from its context (see
+// WithKind), and its project from the resource names in its request params.
+//
+// The credentials of a gRPC connection cannot be replaced per call, so the returned credentials
+// should be the only credentials of the connection (see option.WithoutAuthentication). They also
+// send quotaProject, if set, as the quota project of the calls.
+func (s *Selector) PerRPCCredentials(fallback oauth2.TokenSource, quotaProject string) grpccredentials.PerRPCCredentials {
+	return &perRPCCredentials{selector: s, fallback: fallback, quotaProject: quotaProject}
+}
+
+type perRPCCredentials struct {
+	selector     *Selector
+	quotaProject string
+
+	mutex    sync.Mutex
+	fallback oauth2.TokenSource
+}
+
+func (c *perRPCCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	ts, err := c.selector.TokenSource(KindFromContext(ctx), projectFromRequestParams(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if ts == nil {
+		ts, err = c.fallbackTokenSource()
+		if err != nil {
+			return nil, err
+		}
+	}
+	token, err := ts.Token()
+	if err != nil {
+		return nil, err
+	}
+	md := map[string]string{"authorization": token.Type() + " " + token.AccessToken}
+	if c.quotaProject != "" {
+		md["X-goog-user-project"] = c.quotaProject
+	}
+	return md, nil
+}
+
+// RequireTransportSecurity implements the PerRPCCredentials interface; gRPC only sends the
+// tokens over secure connections.
+func (c *perRPCCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+func (c *perRPCCredentials) fallbackTokenSource() (oauth2.TokenSource, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.fallback == nil {
+		ts, err := google.DefaultTokenSource(c.selector.ctx, gcp.ClientScopes...)
+		if err != nil {
+			return nil, fmt.Errorf("error getting the application default credentials: %w", err)
+		}
+		c.fallback = ts
+	}
+	return c.fallback, nil
+}
+
+// projectFromRequestParams returns the project of a resource name in the request params
+// of a gRPC call, which the generated Google API clients send as the x-goog-request-params header.
+func projectFromRequestParams(ctx context.Context) string {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	for _, header := range md.Get("x-goog-request-params") {
+		params, err := url.ParseQuery(header)
+		if err != nil {
+			continue
+		}
+		keys := make([]string, 0, len(params))
+		for key := range params {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			for _, value := range params[key] {
+				if project := gcp.ProjectFromResourceName(value); project != "" {
+					return project
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp"
+
+	"golang.org/x/oauth2"
+)
+
+// WrapHTTPClient returns a copy of client whose requests use the credentials selected by the Selector.
+func (s *Selector) WrapHTTPClient(client *http.Client) *http.Client {
+	wrapped := &http.Client{}
+	*wrapped = *client
+	wrapped.Transport = s.RoundTripper(client.Transport)
+	return wrapped
+}
+
+// RoundTripper returns an http.RoundTripper which passes the requests matching no rule to inner,
+// and sends the requests matching a rule with the credentials of the rule instead. The kind of a
+// request is read from its context (see WithKind), and its project from its URL path.
+//
+// The credentials of inner cannot be replaced, so the requests matching a rule bypass inner: if
+// inner is an *oauth2.Transport, they are sent through its base transport, and otherwise through
+// http.DefaultTransport. If inner is nil, http.DefaultTransport is used.
+func (s *Selector) RoundTripper(inner http.RoundTripper) http.RoundTripper {
+	if inner == nil {
+		inner = http.DefaultTransport
+	}
+	base := http.DefaultTransport
+	if t, ok := inner.(*oauth2.Transport); ok && t.Base != nil {
+		base = t.Base
+	}
+	return &roundTripper{selector: s, inner: inner, base: base}
+}
+
+type roundTripper struct {
+	selector *Selector
+	inner    http.RoundTripper
+	base     http.RoundTripper
+}
+
+func (r *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ts, err := r.selector.TokenSource(KindFromContext(req.Context()), gcp.ProjectFromResourceName(req.URL.Path))
+	if err != nil {
+		return nil, err
+	}
+	if ts == nil {
+		return r.inner.RoundTrip(req)
+	}
+	t := &oauth2.Transport{Source: ts, Base: r.base}
+	return t.RoundTrip(req)
+}
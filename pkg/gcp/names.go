@@ -62,3 +62,15 @@ func FullResourceNameToShortName(fullName string) string {
 	}
 	return fullName[idx+1:]
 }
+
+// ProjectFromResourceName returns the project in a resource name or REST path such as
+// "projects/my-project/topics/foo", or "" if it does not contain a project.
+func ProjectFromResourceName(name string) string {
+	tokens := strings.Split(name, "/")
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i] == "projects" && tokens[i+1] != "" {
+			return tokens[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,181 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit implements a client-side rate limit for the requests made to GCP APIs.
+//
+// Requests are throttled with a token bucket per (project, service) pair, so that one busy
+// project or API does not use up the quota of the others. The same Limiter is shared by the
+// TF, DCL and direct controllers, so the budget covers all of the requests made by a manager.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/metrics"
+
+	"golang.org/x/time/rate"
+)
+
+// UnknownProject is the project used for requests which are not made to a resource within a project,
+// for example requests to storage buckets or organization-level resources.
+const UnknownProject = "unknown"
+
+// Limit is the token bucket rate limit for the requests to a single (project, service) pair.
+type Limit struct {
+	// QPS is the sustained number of requests per second. Zero or less means unlimited.
+	QPS float64
+	// Burst is the maximum number of requests that can be made at once. If zero, the QPS
+	// (rounded up) is used.
+	Burst int
+}
+
+// Config configures the rate limits of a Limiter.
+type Config struct {
+	// Default is the limit for the services not listed in Services.
+	Default Limit
+	// Services overrides the limit for the given services, keyed by the service
+	// hostname (for example "compute.googleapis.com").
+	Services map[string]Limit
+}
+
+// IsEnabled returns true if the config limits the requests to any service.
+func (c *Config) IsEnabled() bool {
+	if c == nil {
+		return false
+	}
+	if c.Default.QPS > 0 {
+		return true
+	}
+	for _, l := range c.Services {
+		if l.QPS > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+type key struct {
+	project string
+	service string
+}
+
+// Limiter throttles requests to GCP, with a separate token bucket for each (project, service) pair.
+type Limiter struct {
+	config Config
+
+	mutex    sync.Mutex
+	limiters map[key]*rate.Limiter
+}
+
+// New returns a Limiter with the given limits.
+func New(config Config) *Limiter {
+	return &Limiter{
+		config:   config,
+		limiters: make(map[key]*rate.Limiter),
+	}
+}
+
+// Wait blocks until a request to service in project is allowed, or ctx is done.
+// An empty project is recorded as UnknownProject.
+func (l *Limiter) Wait(ctx context.Context, project, service string) error {
+	if project == "" {
+		project = UnknownProject
+	}
+	limiter := l.limiterFor(key{project: project, service: service})
+
+	start := time.Now()
+	err := limiter.Wait(ctx)
+	recordWait(project, service, time.Since(start))
+	if err != nil {
+		return fmt.Errorf("error waiting for the rate limit of %v in project %v: %w", service, project, err)
+	}
+	return nil
+}
+
+func (l *Limiter) limiterFor(k key) *rate.Limiter {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if limiter, found := l.limiters[k]; found {
+		return limiter
+	}
+	limit, found := l.config.Services[k.service]
+	if !found {
+		limit = l.config.Default
+	}
+	limiter := newRateLimiter(limit)
+	l.limiters[k] = limiter
+	return limiter
+}
+
+func newRateLimiter(limit Limit) *rate.Limiter {
+	if limit.QPS <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = int(math.Ceil(limit.QPS))
+	}
+	return rate.NewLimiter(rate.Limit(limit.QPS), burst)
+}
+
+func recordWait(project, service string, waited time.Duration) {
+	labels := metrics.Labels{metrics.ProjectLabel: project, metrics.ServiceLabel: service}
+	metrics.MGCPRequests.Inc(labels)
+	metrics.MGCPRateLimitWait.Observe(waited.Seconds(), labels)
+}
+
+// recordRequest records the duration of a request to service in project, which completed
+// with the given HTTP or gRPC status code.
+func recordRequest(project, service, code string, duration time.Duration) {
+	if project == "" {
+		project = UnknownProject
+	}
+	labels := metrics.Labels{metrics.ProjectLabel: project, metrics.ServiceLabel: service, metrics.CodeLabel: code}
+	metrics.MGCPRequestDuration.Observe(duration.Seconds(), labels)
+}
+
+// ParseServiceLimit parses a per-service limit in the format "<service>=<qps>[:<burst>]",
+// for example "compute.googleapis.com=10:20". The qps must be positive, as a service cannot
+// be exempted from the default limit.
+func ParseServiceLimit(s string) (string, Limit, error) {
+	service, value, found := strings.Cut(s, "=")
+	if !found || service == "" {
+		return "", Limit{}, fmt.Errorf("invalid service rate limit %q, expected <service>=<qps>[:<burst>]", s)
+	}
+	qpsString, burstString, hasBurst := strings.Cut(value, ":")
+	var limit Limit
+	qps, err := strconv.ParseFloat(qpsString, 64)
+	if err != nil {
+		return "", Limit{}, fmt.Errorf("invalid qps in service rate limit %q: %w", s, err)
+	}
+	if qps <= 0 {
+		return "", Limit{}, fmt.Errorf("invalid qps in service rate limit %q: must be positive", s)
+	}
+	limit.QPS = qps
+	if hasBurst {
+		burst, err := strconv.Atoi(burstString)
+		if err != nil {
+			return "", Limit{}, fmt.Errorf("invalid burst in service rate limit %q: %w", s, err)
+		}
+		limit.Burst = burst
+	}
+	return service, limit, nil
+}
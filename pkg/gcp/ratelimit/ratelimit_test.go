@@ -0,0 +1,190 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/iam/apiv1/iampb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestParseServiceLimit(t *testing.T) {
+	tests := []struct {
+		input       string
+		wantService string
+		wantLimit   Limit
+		wantErr     bool
+	}{
+		{input: "compute.googleapis.com=10:20", wantService: "compute.googleapis.com", wantLimit: Limit{QPS: 10, Burst: 20}},
+		{input: "pubsub.googleapis.com=0.5", wantService: "pubsub.googleapis.com", wantLimit: Limit{QPS: 0.5}},
+		{input: "compute.googleapis.com", wantErr: true},
+		{input: "=10", wantErr: true},
+		{input: "compute.googleapis.com=ten", wantErr: true},
+		{input: "compute.googleapis.com=0", wantErr: true},
+		{input: "compute.googleapis.com=10:many", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			service, limit, err := ParseServiceLimit(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseServiceLimit(%q): got error %v, want error %v", tc.input, err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if service != tc.wantService || limit != tc.wantLimit {
+				t.Errorf("ParseServiceLimit(%q): got (%q, %+v), want (%q, %+v)", tc.input, service, limit, tc.wantService, tc.wantLimit)
+			}
+		})
+	}
+}
+
+func TestProjectFromPath(t *testing.T) {
+	tests := map[string]string{
+		"/compute/v1/projects/my-project/zones/us-central1-a/instances/foo": "my-project",
+		"/v1/projects/my-project/topics/foo":                                "my-project",
+		"/storage/v1/b/my-bucket":                                           "",
+		"/v1/projects/":                                                     "",
+	}
+	for path, want := range tests {
+		if got := projectFromPath(path); got != want {
+			t.Errorf("projectFromPath(%q): got %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestServiceFromTarget(t *testing.T) {
+	tests := map[string]string{
+		"pubsub.googleapis.com:443":        "pubsub.googleapis.com",
+		"dns:///pubsub.googleapis.com:443": "pubsub.googleapis.com",
+		"pubsub.googleapis.com":            "pubsub.googleapis.com",
+	}
+	for target, want := range tests {
+		if got := serviceFromTarget(target); got != want {
+			t.Errorf("serviceFromTarget(%q): got %q, want %q", target, got, want)
+		}
+	}
+}
+
+func TestProjectFromMessage(t *testing.T) {
+	// Messages without AIP resource fields have no project.
+	noResource, err := structpb.NewStruct(map[string]any{"name": "projects/my-project/topics/foo"})
+	if err != nil {
+		t.Fatalf("error building message: %v", err)
+	}
+	tests := []struct {
+		desc string
+		msg  proto.Message
+		want string
+	}{
+		{
+			desc: "resource field",
+			msg:  &iampb.GetIamPolicyRequest{Resource: "projects/my-project/topics/foo"},
+			want: "my-project",
+		},
+		{
+			desc: "resource field without project",
+			msg:  &iampb.GetIamPolicyRequest{Resource: "organizations/123"},
+			want: "",
+		},
+		{
+			desc: "no resource fields",
+			msg:  noResource,
+			want: "",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := projectFromMessage(tc.msg); got != tc.want {
+				t.Errorf("projectFromMessage: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLimiterIsPerProjectAndService(t *testing.T) {
+	limiter := New(Config{
+		Default: Limit{QPS: 1, Burst: 1},
+		Services: map[string]Limit{
+			"unlimited.googleapis.com": {},
+		},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The first request to each (project, service) uses the burst.
+	for _, k := range []key{
+		{project: "p1", service: "compute.googleapis.com"},
+		{project: "p2", service: "compute.googleapis.com"},
+		{project: "p1", service: "pubsub.googleapis.com"},
+	} {
+		if err := limiter.Wait(ctx, k.project, k.service); err != nil {
+			t.Fatalf("Wait(%v, %v): unexpected error %v", k.project, k.service, err)
+		}
+	}
+
+	// A second request to the same (project, service) must wait for a second, longer than the deadline.
+	if err := limiter.Wait(ctx, "p1", "compute.googleapis.com"); err == nil {
+		t.Errorf("Wait: expected the second request to be throttled")
+	}
+
+	// Services with an unlimited override are never throttled.
+	for i := 0; i < 10; i++ {
+		if err := limiter.Wait(ctx, "p1", "unlimited.googleapis.com"); err != nil {
+			t.Fatalf("Wait: unexpected error for unlimited service: %v", err)
+		}
+	}
+}
+
+func TestRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limiter := New(Config{Default: Limit{QPS: 1, Burst: 1}})
+	client := limiter.WrapHTTPClient(server.Client())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	get := func(path string) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.Fatalf("error building request: %v", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	if err := get("/v1/projects/p1/topics/foo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := get("/v1/projects/p2/topics/foo"); err != nil {
+		t.Fatalf("unexpected error for a different project: %v", err)
+	}
+	if err := get("/v1/projects/p1/topics/bar"); err == nil {
+		t.Errorf("expected the second request to the same project to be throttled")
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/gcp"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// WrapHTTPClient returns a copy of client whose requests are throttled by the Limiter. The
+// duration of the requests is recorded in the gcp_request_duration_seconds metric.
+func (l *Limiter) WrapHTTPClient(client *http.Client) *http.Client {
+	wrapped := &http.Client{}
+	*wrapped = *client
+	wrapped.Transport = l.RoundTripper(client.Transport)
+	return wrapped
+}
+
+// RoundTripper returns an http.RoundTripper which waits for the rate limit before passing each request
+// to inner. If inner is nil, http.DefaultTransport is used.
+func (l *Limiter) RoundTripper(inner http.RoundTripper) http.RoundTripper {
+	if inner == nil {
+		inner = http.DefaultTransport
+	}
+	return &roundTripper{limiter: l, inner: inner}
+}
+
+type roundTripper struct {
+	limiter *Limiter
+	inner   http.RoundTripper
+}
+
+func (r *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	project, service := projectFromPath(req.URL.Path), hostWithoutPort(req.URL.Host)
+	if err := r.limiter.Wait(req.Context(), project, service); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	resp, err := r.inner.RoundTrip(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	recordRequest(project, service, code, time.Since(start))
+	return resp, err
+}
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor which waits for the rate limit before each call.
+// The project is read from the name, parent or resource field of the request.
+func (l *Limiter) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		project := ""
+		if msg, ok := req.(proto.Message); ok {
+			project = projectFromMessage(msg)
+		}
+		service := serviceFromTarget(cc.Target())
+		if err := l.Wait(ctx, project, service); err != nil {
+			return err
+		}
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		recordRequest(project, service, status.Code(err).String(), time.Since(start))
+		return err
+	}
+}
+
+// projectFromPath returns the project in a REST path such as "/compute/v1/projects/my-project/zones/...",
+// or "" if the path does not contain a project.
+func projectFromPath(path string) string {
+	return gcp.ProjectFromResourceName(path)
+}
+
+// projectFromMessage returns the project of the resource a request is for, following the AIP
+// conventions for the name of the fields identifying the resource.
+func projectFromMessage(msg proto.Message) string {
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	for _, fieldName := range []protoreflect.Name{"name", "parent", "resource"} {
+		field := fields.ByName(fieldName)
+		if field == nil || field.Kind() != protoreflect.StringKind || field.IsList() {
+			continue
+		}
+		if project := gcp.ProjectFromResourceName(m.Get(field).String()); project != "" {
+			return project
+		}
+	}
+	if field := fields.ByName("project"); field != nil && field.Kind() == protoreflect.StringKind && !field.IsList() {
+		return strings.TrimPrefix(m.Get(field).String(), "projects/")
+	}
+	return ""
+}
+
+// serviceFromTarget returns the hostname of a gRPC target such as "dns:///pubsub.googleapis.com:443".
+func serviceFromTarget(target string) string {
+	if i := strings.Index(target, ":///"); i >= 0 {
+		target = target[i+len(":///"):]
+	}
+	return hostWithoutPort(target)
+}
+
+func hostWithoutPort(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
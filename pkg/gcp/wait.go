@@ -15,9 +15,15 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"google.golang.org/api/bigtableadmin/v2"
 	"google.golang.org/api/cloudasset/v1"
 	resourcemanager "google.golang.org/api/cloudresourcemanager/v1"
@@ -30,6 +36,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
+// The WaitFor* helpers below block until the operation is done, which suits one-shot callers such as the CLI.
+// Controllers should not use them; direct adapters record their operations with TrackOperation and poll
+// them on later reconciliations instead.
 type AssetInventoryWaitCallback func(operation *cloudasset.Operation) error
 type BigtableWaitCallback func(operation *bigtableadmin.Operation) error
 type RedisWaitCallback func(operation *redis.Operation) error
@@ -40,16 +49,17 @@ type ComputeWaitCallback func(operation *compute.Operation) error
 type ContainerWaitCallback func(operation *container.Operation) error
 type ServiceNetworkingWaitCallback func(operation *servicenetworking.Operation) error
 
-func WaitForAssetInventoryOperationDefaultTimeout(assetClient *cloudasset.Service,
+func WaitForAssetInventoryOperationDefaultTimeout(ctx context.Context, assetClient *cloudasset.Service,
 	operation *cloudasset.Operation, projectNum string, callback AssetInventoryWaitCallback) (*cloudasset.Operation, error) {
-	return WaitForAssetInventoryOperation(assetClient, operation, projectNum, 10*time.Second, 30*time.Minute, callback)
+	return WaitForAssetInventoryOperation(ctx, assetClient, operation, projectNum, 10*time.Second, 30*time.Minute, callback)
 }
 
 // The project number should be the project number for the credentials which are making this request
-func WaitForAssetInventoryOperation(assetClient *cloudasset.Service, operation *cloudasset.Operation, projectNum string,
+func WaitForAssetInventoryOperation(ctx context.Context, assetClient *cloudasset.Service, operation *cloudasset.Operation, projectNum string,
 	interval, timeout time.Duration, callback AssetInventoryWaitCallback) (*cloudasset.Operation, error) {
-	err := wait.PollImmediate(interval, timeout, func() (done bool, err error) {
-		request := assetClient.Operations.Get(operation.Name)
+	ctx, span := startWaitSpan(ctx, "cloudasset", operation.Name)
+	err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (done bool, err error) {
+		request := assetClient.Operations.Get(operation.Name).Context(ctx)
 		// CAI needs this value to use end user credentials instead of a service account
 		//   https://cloud.google.com/asset-inventory/docs/faq
 		// when the export is run on a project, the project number is in the operation name, however, when the operation
@@ -68,18 +78,20 @@ func WaitForAssetInventoryOperation(assetClient *cloudasset.Service, operation *
 		}
 		return operation.Done, nil
 	})
+	tracing.End(span, err)
 	return operation, err
 }
 
-func WaitForResourceManagerOperationDefaultTimeout(rmClient *resourcemanager.Service, operation *resourcemanager.Operation,
+func WaitForResourceManagerOperationDefaultTimeout(ctx context.Context, rmClient *resourcemanager.Service, operation *resourcemanager.Operation,
 	callback ResourceManagerCallback) (*resourcemanager.Operation, error) {
-	return WaitForResourceManagerOperation(rmClient, operation, 10*time.Second, 10*time.Minute, callback)
+	return WaitForResourceManagerOperation(ctx, rmClient, operation, 10*time.Second, 10*time.Minute, callback)
 }
 
-func WaitForResourceManagerOperation(rmClient *resourcemanager.Service, operation *resourcemanager.Operation,
+func WaitForResourceManagerOperation(ctx context.Context, rmClient *resourcemanager.Service, operation *resourcemanager.Operation,
 	interval, timeout time.Duration, callback ResourceManagerCallback) (*resourcemanager.Operation, error) {
-	err := wait.PollImmediate(interval, timeout, func() (done bool, err error) {
-		newOp, err := rmClient.Operations.Get(operation.Name).Do()
+	ctx, span := startWaitSpan(ctx, "cloudresourcemanager", operation.Name)
+	err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (done bool, err error) {
+		newOp, err := rmClient.Operations.Get(operation.Name).Context(ctx).Do()
 		if err != nil {
 			return false, fmt.Errorf("error getting operation %v: %w", operation.Name,
 				err)
@@ -92,26 +104,28 @@ func WaitForResourceManagerOperation(rmClient *resourcemanager.Service, operatio
 		}
 		return operation.Done, nil
 	})
+	tracing.End(span, err)
 	return operation, err
 }
 
-func WaitForComputeOperationDefaultTimeout(computeClient *compute.Service, operation *compute.Operation,
+func WaitForComputeOperationDefaultTimeout(ctx context.Context, computeClient *compute.Service, operation *compute.Operation,
 	projectID string, callback ComputeWaitCallback) (*compute.Operation, error) {
-	return WaitForComputeOperation(computeClient, operation, projectID, 30*time.Second, 30*time.Minute, callback)
+	return WaitForComputeOperation(ctx, computeClient, operation, projectID, 30*time.Second, 30*time.Minute, callback)
 }
 
-func WaitForComputeOperation(computeClient *compute.Service, operation *compute.Operation,
+func WaitForComputeOperation(ctx context.Context, computeClient *compute.Service, operation *compute.Operation,
 	projectID string, interval, timeout time.Duration, callback ComputeWaitCallback) (*compute.Operation, error) {
-	err := wait.PollImmediate(interval, timeout, func() (done bool, err error) {
+	ctx, span := startWaitSpan(ctx, "compute", operation.Name)
+	err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (done bool, err error) {
 		var newOp *compute.Operation
 		if operation.Zone != "" {
 			zone := FullResourceNameToShortName(operation.Zone)
-			newOp, err = computeClient.ZoneOperations.Get(projectID, zone, operation.Name).Do()
+			newOp, err = computeClient.ZoneOperations.Get(projectID, zone, operation.Name).Context(ctx).Do()
 		} else if operation.Region != "" {
 			region := FullResourceNameToShortName(operation.Region)
-			newOp, err = computeClient.RegionOperations.Get(projectID, region, operation.Name).Do()
+			newOp, err = computeClient.RegionOperations.Get(projectID, region, operation.Name).Context(ctx).Do()
 		} else {
-			newOp, err = computeClient.GlobalOperations.Get(projectID, operation.Name).Do()
+			newOp, err = computeClient.GlobalOperations.Get(projectID, operation.Name).Context(ctx).Do()
 		}
 		if err != nil {
 			return false, fmt.Errorf("error getting operation %v/%v: %w", projectID, operation.Name,
@@ -125,5 +139,13 @@ func WaitForComputeOperation(computeClient *compute.Service, operation *compute.
 		}
 		return operation.Status == "DONE", nil
 	})
+	tracing.End(span, err)
 	return operation, err
 }
+
+// startWaitSpan starts a span, in the trace of ctx, for waiting on the given operation.
+func startWaitSpan(ctx context.Context, service, operationName string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "WaitForOperation",
+		attribute.String("gcp.service", service),
+		attribute.String("gcp.operation", operationName))
+}
@@ -2882,6 +2882,16 @@ var SupportedGVKs = map[schema.GroupVersionKind]GVKMetadata{
 			"cnrm.cloud.google.com/tf2crd":          "true",
 		},
 	},
+	{
+		Group:   "iam.cnrm.cloud.google.com",
+		Version: "v1beta1",
+		Kind:    "IAMDenyPolicy",
+	}: {
+		Labels: map[string]string{
+			"cnrm.cloud.google.com/managed-by-kcc": "true",
+			"cnrm.cloud.google.com/system":         "true",
+		},
+	},
 	{
 		Group:   "iam.cnrm.cloud.google.com",
 		Version: "v1beta1",
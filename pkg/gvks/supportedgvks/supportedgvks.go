@@ -128,6 +128,7 @@ func BasedOnHandwrittenIAMTypes() []schema.GroupVersionKind {
 		iamapi.IAMPolicyGVK,
 		iamapi.IAMPolicyMemberGVK,
 		iamapi.IAMPartialPolicyGVK,
+		iamapi.IAMDenyPolicyGVK,
 	}
 }
 
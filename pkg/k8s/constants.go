@@ -44,6 +44,7 @@ const (
 	CreateFailedMessageTmpl              = "Create call failed: %v"
 	Updating                             = "Updating"
 	UpdatingMessage                      = "Update in progress"
+	OperationPendingMessageTmpl          = "Waiting for %v operation %v to complete"
 	UpdateFailed                         = "UpdateFailed"
 	Deleting                             = "Deleting"
 	DeletingMessage                      = "Deletion in progress"
@@ -62,6 +63,15 @@ const (
 	ManagementConflict                   = "ManagementConflict"
 	PreActuationTransformFailed          = "PreActuationTransformFailed"
 	PostActuationTransformFailed         = "PostActuationTransformFailed"
+	Planned                              = "Planned"
+	PlanFailed                           = "PlanFailed"
+	DeletionBlockedByPlanMode            = "DeletionBlockedByPlanMode"
+	DeletionBlockedByPlanModeMessage     = "The GCP resource is not deleted while the plan-mode annotation is set; remove the annotation to delete it, or set the deletion-policy annotation to abandon to keep it"
+	Paused                               = "Paused"
+	PausedMessage                        = "Reconciliation is paused by the reconcile-paused annotation"
+	PendingActuationWindow               = "PendingActuationWindow"
+	PendingActuationWindowMessageTmpl    = "Actuation is waiting for the next actuation window, starting at %v"
+	NoActuationWindowMessage             = "Actuation is waiting for an actuation window, but none of the actuation windows starts again"
 	DeletionPolicyDelete                 = "delete"
 	DeletionPolicyAbandon                = "abandon"
 	AnnotationPrefix                     = CNRMGroup
@@ -76,6 +86,10 @@ const (
 	// Management conflict prevention policies
 	ManagementConflictPreventionPolicyNone     = "none"
 	ManagementConflictPreventionPolicyResource = "resource"
+	// The leases of the "gcs" and "kubernetes-lease" policies are stored outside of the
+	// GCP resource, so they also apply to resources which do not support labels.
+	ManagementConflictPreventionPolicyGCS             = "gcs"
+	ManagementConflictPreventionPolicyKubernetesLease = "kubernetes-lease"
 
 	// State into spec annotation values
 	StateMergeIntoSpec               = "merge"
@@ -90,8 +104,9 @@ const (
 	ResourceIDFieldPath = "spec." + ResourceIDFieldName
 
 	// selfLink may not present in every KRM resource status.
-	SelfLinkFieldName      = "selfLink"
-	ObservedStateFieldName = "observedState"
+	SelfLinkFieldName         = "selfLink"
+	ObservedStateFieldName    = "observedState"
+	PendingOperationFieldName = "pendingOperation"
 
 	StabilityLevelStable = "stable"
 	StabilityLevelAlpha  = "alpha"
@@ -103,6 +118,11 @@ const (
 var (
 	DeletionPolicyAnnotation             = FormatAnnotation("deletion-policy")
 	ReconcileIntervalInSecondsAnnotation = FormatAnnotation("reconcile-interval-in-seconds")
+	PlanModeAnnotation                   = FormatAnnotation("plan-mode")
+	ReconcilePausedAnnotation            = FormatAnnotation("reconcile-paused")
+	// OrderedDeletionAnnotation requests the deletion of all the KCC resources
+	// of the namespace it is set on, in reverse reference order.
+	OrderedDeletionAnnotation = FormatAnnotation("ordered-deletion")
 
 	// Annotations for Container objects
 	ProjectIDAnnotation  = FormatAnnotation("project-id")
@@ -122,6 +142,8 @@ var (
 	ManagementConflictPreventionPolicyValues                   = []string{
 		ManagementConflictPreventionPolicyNone,
 		ManagementConflictPreventionPolicyResource,
+		ManagementConflictPreventionPolicyGCS,
+		ManagementConflictPreventionPolicyKubernetesLease,
 	}
 
 	KCCComponentLabel    = FormatAnnotation("component")
@@ -60,6 +60,22 @@ func HasAbandonAnnotation(obj metav1.Object) bool {
 	return ok && val == DeletionPolicyAbandon
 }
 
+// HasPlanModeAnnotation returns true if the object asks the controller to only
+// compute the changes it would make, without actuating them. The deletion of an
+// object whose GCP resource would be deleted is blocked until the annotation is
+// removed.
+func HasPlanModeAnnotation(obj metav1.Object) bool {
+	val, ok := GetAnnotation(PlanModeAnnotation, obj)
+	return ok && val == "true"
+}
+
+// HasReconcilePausedAnnotation returns true if the object asks all controllers
+// to stop reconciling it (including deletion) until the annotation is removed.
+func HasReconcilePausedAnnotation(obj metav1.Object) bool {
+	val, ok := GetAnnotation(ReconcilePausedAnnotation, obj)
+	return ok && val == "true"
+}
+
 func GVKListContains(gvkList []schema.GroupVersionKind, gvk schema.GroupVersionKind) bool {
 	for _, v := range gvkList {
 		if v == gvk {
@@ -96,7 +112,7 @@ func ToGVR(gvk schema.GroupVersionKind) schema.GroupVersionResource {
 	}
 }
 
-func GetProjectIDForNamespace(ctx context.Context, c client.Client, namespaceName string) (string, error) {
+func GetProjectIDForNamespace(ctx context.Context, c client.Reader, namespaceName string) (string, error) {
 	var ns corev1.Namespace
 	if err := c.Get(ctx, types.NamespacedName{Name: namespaceName}, &ns); err != nil {
 		return "", fmt.Errorf("error getting namespace '%v': %w", namespaceName, err)
@@ -166,6 +182,30 @@ func ValidateOrDefaultManagementConflictPreventionAnnotationForDCLBasedResource(
 	return validateOrDefaultManagementConflictPreventionAnnotation(obj, ns, supportsLeasing)
 }
 
+// ValidateOrDefaultManagementConflictPreventionAnnotationForDirectResource validates the
+// management conflict prevention policy of a direct resource. Direct resources do not support
+// the label-based "resource" policy, and the annotation is only defaulted if the namespace
+// selects an external lease backend, so that direct resources are otherwise left unchanged.
+func ValidateOrDefaultManagementConflictPreventionAnnotationForDirectResource(obj metav1.Object, ns *corev1.Namespace) error {
+	if _, ok := GetAnnotation(ManagementConflictPreventionPolicyFullyQualifiedAnnotation, obj); ok {
+		return validateOrDefaultManagementConflictPreventionAnnotation(obj, ns, false)
+	}
+	policy, err := getDefaultManagementConflictPreventAnnotationForNamespace(ns, false)
+	if err != nil {
+		return err
+	}
+	if policy != ManagementConflictPreventionPolicyNone {
+		SetAnnotation(ManagementConflictPreventionPolicyFullyQualifiedAnnotation, string(policy), obj)
+	}
+	return nil
+}
+
+// IsExternalLeasePolicy returns true if the leases of policy are stored in an external
+// backend rather than in the labels of the GCP resource.
+func IsExternalLeasePolicy(policy ManagementConflictPreventionPolicy) bool {
+	return policy == ManagementConflictPreventionPolicyGCS || policy == ManagementConflictPreventionPolicyKubernetesLease
+}
+
 func validateOrDefaultManagementConflictPreventionAnnotation(obj metav1.Object, ns *corev1.Namespace, supportsLeasing bool) error {
 	value, ok := GetAnnotation(ManagementConflictPreventionPolicyFullyQualifiedAnnotation, obj)
 	if ok {
@@ -207,6 +247,8 @@ func isManagementConflictPolicyValidForResource(policy ManagementConflictPrevent
 		return true
 	case ManagementConflictPreventionPolicyResource:
 		return supportLeasing
+	case ManagementConflictPreventionPolicyGCS, ManagementConflictPreventionPolicyKubernetesLease:
+		return true
 	default:
 		return false
 	}
@@ -234,6 +276,8 @@ func validateManagementConflictPolicyForResource(policy ManagementConflictPreven
 				ManagementConflictPreventionPolicyAnnotation, policy)
 		}
 		return nil
+	case ManagementConflictPreventionPolicyGCS, ManagementConflictPreventionPolicyKubernetesLease:
+		return nil
 	default:
 		return fmt.Errorf("unknown management conflict policy: %v", policy)
 	}
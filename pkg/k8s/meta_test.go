@@ -153,6 +153,50 @@ func TestHasAbandonAnnotation(t *testing.T) {
 	}
 }
 
+func TestHasReconcilePausedAnnotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		isPaused    bool
+	}{
+		{
+			name: "has reconcile-paused annotation set to true",
+			annotations: map[string]string{
+				k8s.ReconcilePausedAnnotation: "true",
+			},
+			isPaused: true,
+		},
+		{
+			name: "has reconcile-paused annotation set to false",
+			annotations: map[string]string{
+				k8s.ReconcilePausedAnnotation: "false",
+			},
+			isPaused: false,
+		},
+		{
+			name:        "has no reconcile-paused annotation",
+			annotations: map[string]string{},
+			isPaused:    false,
+		},
+		{
+			name:     "has nil annotations map",
+			isPaused: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			obj := &unstructured.Unstructured{}
+			obj.SetAnnotations(tc.annotations)
+			actual := k8s.HasReconcilePausedAnnotation(obj)
+			if actual != tc.isPaused {
+				t.Errorf("incorrect value for HasReconcilePausedAnnotation(): got %v, want %v", actual, tc.isPaused)
+			}
+		})
+	}
+}
+
 func TestSetDefaultContainerAnnotation(t *testing.T) {
 	const (
 		nsName    = "namespace-1"
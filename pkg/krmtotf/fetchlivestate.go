@@ -131,7 +131,7 @@ func fetchLiveStateFromID(ctx context.Context, id string, resource *Resource, pr
 		return nil, err
 	}
 	state = SetBlueprintAttribution(state, resource, provider)
-	state, diagnostics := resource.TFResource.RefreshWithoutUpgrade(ctx, state, provider.Meta())
+	state, diagnostics := resource.TFResource.RefreshWithoutUpgrade(ctx, state, MetaWithContext(ctx, provider))
 	if err := NewErrorFromDiagnostics(diagnostics); err != nil {
 		return nil, fmt.Errorf("error reading underlying resource: %w", err)
 	}
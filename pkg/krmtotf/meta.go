@@ -0,0 +1,36 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package krmtotf
+
+import (
+	"context"
+
+	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+)
+
+// MetaWithContext returns the meta of provider, i.e. the configuration passed to the operations of
+// its resources, with the context of the requests replaced by ctx. The TF provider sends most requests
+// within the context it was configured with; with the returned meta, the requests carry the values
+// of ctx instead, such as the kind of the resource they are made for (see credentials.WithKind).
+func MetaWithContext(ctx context.Context, provider *tfschema.Provider) interface{} {
+	config, ok := provider.Meta().(*transport_tpg.Config)
+	if !ok {
+		return provider.Meta()
+	}
+	withContext := *config
+	withContext.Context = ctx
+	return &withContext
+}
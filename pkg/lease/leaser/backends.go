@@ -0,0 +1,89 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leaser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+
+	"google.golang.org/api/option"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// BackendsConfig configures the external lease backends. A backend is only available if it is
+// configured; resources which select an unavailable backend fail to obtain their lease.
+type BackendsConfig struct {
+	// GCSBucket is the bucket in which the leases of the "gcs" policy are stored.
+	GCSBucket string
+	// GCSPrefix is an optional prefix of the names of the lease objects in GCSBucket.
+	GCSPrefix string
+
+	// HubNamespace is the namespace in which the Leases of the "kubernetes-lease" policy are stored.
+	HubNamespace string
+	// HubKubeconfig is the path to the kubeconfig of the hub cluster in which the Leases are stored.
+	// If empty, the Leases are stored in the cluster Config Connector runs in.
+	HubKubeconfig string
+}
+
+// Backends are the external lease backends, keyed by management conflict prevention policy.
+type Backends map[k8s.ManagementConflictPreventionPolicy]ExternalLeaser
+
+// NewBackends builds the lease backends configured in cfg. restConfig is used to store the Leases
+// of the "kubernetes-lease" policy if no hub kubeconfig is configured, and gcpOpts are used to
+// build the GCS client.
+func NewBackends(ctx context.Context, cfg BackendsConfig, restConfig *rest.Config, gcpOpts ...option.ClientOption) (Backends, error) {
+	backends := make(Backends)
+	if cfg.GCSBucket != "" {
+		gcsLeaser, err := NewGCSLeaser(ctx, cfg.GCSBucket, cfg.GCSPrefix, gcpOpts...)
+		if err != nil {
+			return nil, err
+		}
+		backends[k8s.ManagementConflictPreventionPolicyGCS] = gcsLeaser
+	}
+	if cfg.HubNamespace != "" {
+		hubConfig := restConfig
+		if cfg.HubKubeconfig != "" {
+			var err error
+			hubConfig, err = clientcmd.BuildConfigFromFlags("", cfg.HubKubeconfig)
+			if err != nil {
+				return nil, fmt.Errorf("error loading hub kubeconfig '%v': %w", cfg.HubKubeconfig, err)
+			}
+		}
+		scheme := runtime.NewScheme()
+		if err := coordinationv1.AddToScheme(scheme); err != nil {
+			return nil, fmt.Errorf("error adding 'coordinationv1' resources to the scheme: %w", err)
+		}
+		hubClient, err := client.New(hubConfig, client.Options{Scheme: scheme})
+		if err != nil {
+			return nil, fmt.Errorf("error creating hub cluster client: %w", err)
+		}
+		backends[k8s.ManagementConflictPreventionPolicyKubernetesLease] = NewKubernetesLeaser(hubClient, cfg.HubNamespace)
+	}
+	return backends, nil
+}
+
+// Get returns the backend of the given policy, or an error if it is not configured.
+func (b Backends) Get(policy k8s.ManagementConflictPreventionPolicy) (ExternalLeaser, error) {
+	if backend, ok := b[policy]; ok {
+		return backend, nil
+	}
+	return nil, fmt.Errorf("the lease backend for %v '%v' is not configured", k8s.ManagementConflictPreventionPolicyAnnotation, policy)
+}
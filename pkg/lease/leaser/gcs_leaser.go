@@ -0,0 +1,144 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leaser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	"google.golang.org/api/storage/v1"
+)
+
+// GCSLeaser stores the leases as empty objects in a GCS bucket which is shared by all the
+// Config Connector installations. The lease holder and expiration are kept in the metadata
+// of the object, and concurrent obtains are serialized by generation preconditions.
+type GCSLeaser struct {
+	service *storage.Service
+	bucket  string
+	prefix  string
+}
+
+var _ ExternalLeaser = &GCSLeaser{}
+
+func NewGCSLeaser(ctx context.Context, bucket, prefix string, opts ...option.ClientOption) (*GCSLeaser, error) {
+	service, err := storage.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error building GCS client: %w", err)
+	}
+	return &GCSLeaser{
+		service: service,
+		bucket:  bucket,
+		prefix:  prefix,
+	}, nil
+}
+
+func (l *GCSLeaser) Obtain(ctx context.Context, leaseName string, ownerID string, duration time.Duration, renewalMin time.Duration) error {
+	if renewalMin > duration {
+		return fmt.Errorf("invalid argument, renewalMin '%v' is greater than duration '%v'", renewalMin, duration)
+	}
+	name := l.objectName(leaseName)
+	obj, err := l.getObject(ctx, name)
+	if err != nil {
+		return err
+	}
+	// A generation of 0 makes the insert fail if the object was created in the meantime.
+	var generation int64
+	metadata := make(map[string]string)
+	if obj != nil {
+		generation = obj.Generation
+		for k, v := range obj.Metadata {
+			metadata[k] = v
+		}
+	}
+	leaseHolder, expirationTime := getLeaseHolderAndExpirationTime(metadata)
+	if !canObtainLease(ownerID, leaseHolder, expirationTime) {
+		return fmt.Errorf("resource is under lease by '%v' for an additional %v second(s)", leaseHolder, time.Until(expirationTime))
+	}
+	if leaseHolder == ownerID && !shouldRenewOrObtainLease(renewalMin, expirationTime) {
+		return nil
+	}
+	setLeaseHolder(metadata, ownerID, duration)
+	_, err = l.service.Objects.Insert(l.bucket, &storage.Object{Name: name, Metadata: metadata}).
+		IfGenerationMatch(generation).
+		Media(strings.NewReader("")).
+		Context(ctx).
+		Do()
+	if err != nil {
+		if hasGoogleAPIErrorCode(err, http.StatusPreconditionFailed) {
+			return fmt.Errorf("resource lease 'gs://%v/%v' was concurrently obtained by another owner", l.bucket, name)
+		}
+		return fmt.Errorf("error writing lease 'gs://%v/%v': %w", l.bucket, name, err)
+	}
+	return nil
+}
+
+func (l *GCSLeaser) Release(ctx context.Context, leaseName string, ownerID string) error {
+	name := l.objectName(leaseName)
+	obj, err := l.getObject(ctx, name)
+	if err != nil {
+		return err
+	}
+	if obj == nil {
+		return fmt.Errorf("resource is not under management by '%v' or any other owner", ownerID)
+	}
+	leaseHolder, expirationTime := getLeaseHolderAndExpirationTime(obj.Metadata)
+	if leaseHolder != ownerID {
+		return fmt.Errorf("resource is under lease by '%v' for an additional %v second(s)", leaseHolder, time.Until(expirationTime))
+	}
+	err = l.service.Objects.Delete(l.bucket, name).IfGenerationMatch(obj.Generation).Context(ctx).Do()
+	if err != nil && !hasGoogleAPIErrorCode(err, http.StatusNotFound) {
+		return fmt.Errorf("error deleting lease 'gs://%v/%v': %w", l.bucket, name, err)
+	}
+	return nil
+}
+
+func (l *GCSLeaser) GetOwnerAndExpirationTime(ctx context.Context, leaseName string) (string, time.Time, error) {
+	obj, err := l.getObject(ctx, l.objectName(leaseName))
+	if err != nil {
+		return "", zeroUnixTime, err
+	}
+	if obj == nil {
+		return "", zeroUnixTime, nil
+	}
+	leaseHolder, expirationTime := getLeaseHolderAndExpirationTime(obj.Metadata)
+	return leaseHolder, expirationTime, nil
+}
+
+func (l *GCSLeaser) objectName(leaseName string) string {
+	return l.prefix + leaseName
+}
+
+// getObject returns the lease object with the given name, or nil if it does not exist.
+func (l *GCSLeaser) getObject(ctx context.Context, name string) (*storage.Object, error) {
+	obj, err := l.service.Objects.Get(l.bucket, name).Context(ctx).Do()
+	if err != nil {
+		if hasGoogleAPIErrorCode(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting lease 'gs://%v/%v': %w", l.bucket, name, err)
+	}
+	return obj, nil
+}
+
+func hasGoogleAPIErrorCode(err error, code int) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
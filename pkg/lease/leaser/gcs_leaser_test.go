@@ -0,0 +1,254 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leaser
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/storage/v1"
+)
+
+// fakeGCS is a minimal GCS JSON API which supports the object calls of the GCSLeaser,
+// including the generation preconditions.
+type fakeGCS struct {
+	mu      sync.Mutex
+	objects map[string]*storage.Object
+	// beforeInsert, if set, is called before the generation precondition of an insert is checked.
+	beforeInsert func(name string)
+}
+
+func (f *fakeGCS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := r.URL.EscapedPath()
+	var name string
+	switch {
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "/upload/storage/v1/b/test-bucket/o"):
+		obj, err := readMultipartObject(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		name = obj.Name
+		if f.beforeInsert != nil {
+			f.beforeInsert(name)
+		}
+		if !f.generationMatches(name, r.URL.Query()) {
+			writeError(w, http.StatusPreconditionFailed)
+			return
+		}
+		obj.Generation = 1
+		if existing, ok := f.objects[name]; ok {
+			obj.Generation = existing.Generation + 1
+		}
+		f.objects[name] = obj
+		writeObject(w, obj)
+		return
+	case strings.HasPrefix(path, "/storage/v1/b/test-bucket/o/"):
+		var err error
+		name, err = url.PathUnescape(strings.TrimPrefix(path, "/storage/v1/b/test-bucket/o/"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "unexpected request "+r.Method+" "+path, http.StatusBadRequest)
+		return
+	}
+
+	obj, ok := f.objects[name]
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeObject(w, obj)
+	case http.MethodDelete:
+		if !f.generationMatches(name, r.URL.Query()) {
+			writeError(w, http.StatusPreconditionFailed)
+			return
+		}
+		delete(f.objects, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unexpected method "+r.Method, http.StatusBadRequest)
+	}
+}
+
+func (f *fakeGCS) generationMatches(name string, query url.Values) bool {
+	want := query.Get("ifGenerationMatch")
+	if want == "" {
+		return true
+	}
+	var generation int64
+	if obj, ok := f.objects[name]; ok {
+		generation = obj.Generation
+	}
+	return want == strconv.FormatInt(generation, 10)
+}
+
+// readMultipartObject reads the object metadata from the first part of a multipart upload.
+func readMultipartObject(r *http.Request) (*storage.Object, error) {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	part, err := multipart.NewReader(r.Body, params["boundary"]).NextPart()
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(part)
+	if err != nil {
+		return nil, err
+	}
+	obj := &storage.Object{}
+	if err := json.Unmarshal(b, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func writeObject(w http.ResponseWriter, obj *storage.Object) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+func writeError(w http.ResponseWriter, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{"code": code, "message": http.StatusText(code)},
+	})
+}
+
+func newTestGCSLeaser(t *testing.T) (*GCSLeaser, *fakeGCS) {
+	fake := &fakeGCS{objects: make(map[string]*storage.Object)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	l, err := NewGCSLeaser(context.Background(), "test-bucket", "leases/",
+		option.WithEndpoint(server.URL+"/storage/v1/"), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("error creating GCS leaser: %v", err)
+	}
+	return l, fake
+}
+
+func TestGCSLeaserObtainAndRelease(t *testing.T) {
+	ctx := context.Background()
+	l, fake := newTestGCSLeaser(t)
+	leaseName := "kcc-my-bucket"
+
+	if err := l.Obtain(ctx, leaseName, "owner-1", time.Hour, time.Minute); err != nil {
+		t.Fatalf("error obtaining lease: %v", err)
+	}
+	if _, ok := fake.objects["leases/kcc-my-bucket"]; !ok {
+		t.Fatalf("lease object 'leases/kcc-my-bucket' was not created")
+	}
+	owner, expiration, err := l.GetOwnerAndExpirationTime(ctx, leaseName)
+	if err != nil {
+		t.Fatalf("error getting lease owner: %v", err)
+	}
+	if owner != "owner-1" {
+		t.Fatalf("got lease owner %q, want %q", owner, "owner-1")
+	}
+	if time.Until(expiration) < 59*time.Minute {
+		t.Fatalf("got lease expiration %v, want about an hour from now", expiration)
+	}
+	// Renewing the lease as the same owner succeeds.
+	if err := l.Obtain(ctx, leaseName, "owner-1", time.Hour, time.Hour); err != nil {
+		t.Fatalf("error renewing lease: %v", err)
+	}
+	if err := l.Obtain(ctx, leaseName, "owner-2", time.Hour, time.Minute); err == nil {
+		t.Fatalf("got nil, want an error when obtaining a lease held by another owner")
+	}
+	if err := l.Release(ctx, leaseName, "owner-2"); err == nil {
+		t.Fatalf("got nil, want an error when releasing a lease held by another owner")
+	}
+	if err := l.Release(ctx, leaseName, "owner-1"); err != nil {
+		t.Fatalf("error releasing lease: %v", err)
+	}
+	if _, ok := fake.objects["leases/kcc-my-bucket"]; ok {
+		t.Fatalf("lease object 'leases/kcc-my-bucket' was not deleted")
+	}
+	if err := l.Release(ctx, leaseName, "owner-1"); err == nil {
+		t.Fatalf("got nil, want an error when releasing a lease which does not exist")
+	}
+	if err := l.Obtain(ctx, leaseName, "owner-2", time.Hour, time.Minute); err != nil {
+		t.Fatalf("error obtaining released lease: %v", err)
+	}
+}
+
+func TestGCSLeaserObtainExpiredLease(t *testing.T) {
+	ctx := context.Background()
+	l, _ := newTestGCSLeaser(t)
+	leaseName := "kcc-my-bucket"
+
+	if err := l.Obtain(ctx, leaseName, "owner-1", 0, 0); err != nil {
+		t.Fatalf("error obtaining lease: %v", err)
+	}
+	if err := l.Obtain(ctx, leaseName, "owner-2", time.Hour, time.Minute); err != nil {
+		t.Fatalf("error obtaining expired lease: %v", err)
+	}
+	owner, _, err := l.GetOwnerAndExpirationTime(ctx, leaseName)
+	if err != nil {
+		t.Fatalf("error getting lease owner: %v", err)
+	}
+	if owner != "owner-2" {
+		t.Fatalf("got lease owner %q, want %q", owner, "owner-2")
+	}
+}
+
+func TestGCSLeaserObtainFailsOnConcurrentObtain(t *testing.T) {
+	ctx := context.Background()
+	l, fake := newTestGCSLeaser(t)
+	leaseName := "kcc-my-bucket"
+
+	// Another owner writes the lease between the read and the write of owner-1.
+	fake.beforeInsert = func(name string) {
+		fake.objects[name] = &storage.Object{Name: name, Generation: 1}
+	}
+	if err := l.Obtain(ctx, leaseName, "owner-1", time.Hour, time.Minute); err == nil {
+		t.Fatalf("got nil, want an error when the lease is concurrently obtained")
+	}
+	if fake.objects["leases/"+leaseName].Metadata != nil {
+		t.Fatalf("the concurrently obtained lease was overwritten")
+	}
+}
+
+func TestGCSLeaserGetOwnerOfMissingLease(t *testing.T) {
+	l, _ := newTestGCSLeaser(t)
+	owner, expiration, err := l.GetOwnerAndExpirationTime(context.Background(), "kcc-my-bucket")
+	if err != nil {
+		t.Fatalf("error getting lease owner: %v", err)
+	}
+	if owner != "" || !expiration.Equal(zeroUnixTime) {
+		t.Fatalf("got lease owner %q expiring at %v, want no lease", owner, expiration)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leaser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KubernetesLeaser stores the leases as coordination.k8s.io Leases in a namespace of a
+// (hub) cluster which is shared by all the Config Connector installations. Concurrent
+// obtains are serialized by the resourceVersion of the Lease.
+type KubernetesLeaser struct {
+	client    client.Client
+	namespace string
+}
+
+var _ ExternalLeaser = &KubernetesLeaser{}
+
+func NewKubernetesLeaser(client client.Client, namespace string) *KubernetesLeaser {
+	return &KubernetesLeaser{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+func (l *KubernetesLeaser) Obtain(ctx context.Context, leaseName string, ownerID string, duration time.Duration, renewalMin time.Duration) error {
+	if renewalMin > duration {
+		return fmt.Errorf("invalid argument, renewalMin '%v' is greater than duration '%v'", renewalMin, duration)
+	}
+	lease, err := l.getLease(ctx, leaseName)
+	if err != nil {
+		return err
+	}
+	if lease == nil {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: l.namespace,
+				Name:      leaseName,
+			},
+		}
+		setKubernetesLeaseHolder(lease, ownerID, duration)
+		if err := l.client.Create(ctx, lease); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				return fmt.Errorf("resource lease '%v/%v' was concurrently obtained by another owner", l.namespace, lease.Name)
+			}
+			return fmt.Errorf("error creating lease '%v/%v': %w", l.namespace, lease.Name, err)
+		}
+		return nil
+	}
+	leaseHolder, expirationTime := getKubernetesLeaseHolderAndExpirationTime(lease)
+	if !canObtainLease(ownerID, leaseHolder, expirationTime) {
+		return fmt.Errorf("resource is under lease by '%v' for an additional %v second(s)", leaseHolder, time.Until(expirationTime))
+	}
+	if leaseHolder == ownerID && !shouldRenewOrObtainLease(renewalMin, expirationTime) {
+		return nil
+	}
+	setKubernetesLeaseHolder(lease, ownerID, duration)
+	// The update carries the resourceVersion of the Lease which was read, so it fails if the
+	// Lease was obtained by another owner in the meantime.
+	if err := l.client.Update(ctx, lease); err != nil {
+		if apierrors.IsConflict(err) {
+			return fmt.Errorf("resource lease '%v/%v' was concurrently obtained by another owner", l.namespace, lease.Name)
+		}
+		return fmt.Errorf("error updating lease '%v/%v': %w", l.namespace, lease.Name, err)
+	}
+	return nil
+}
+
+func (l *KubernetesLeaser) Release(ctx context.Context, leaseName string, ownerID string) error {
+	lease, err := l.getLease(ctx, leaseName)
+	if err != nil {
+		return err
+	}
+	if lease == nil {
+		return fmt.Errorf("resource is not under management by '%v' or any other owner", ownerID)
+	}
+	leaseHolder, expirationTime := getKubernetesLeaseHolderAndExpirationTime(lease)
+	if leaseHolder != ownerID {
+		return fmt.Errorf("resource is under lease by '%v' for an additional %v second(s)", leaseHolder, time.Until(expirationTime))
+	}
+	err = l.client.Delete(ctx, lease, client.Preconditions{ResourceVersion: &lease.ResourceVersion})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error deleting lease '%v/%v': %w", l.namespace, lease.Name, err)
+	}
+	return nil
+}
+
+func (l *KubernetesLeaser) GetOwnerAndExpirationTime(ctx context.Context, leaseName string) (string, time.Time, error) {
+	lease, err := l.getLease(ctx, leaseName)
+	if err != nil {
+		return "", zeroUnixTime, err
+	}
+	if lease == nil {
+		return "", zeroUnixTime, nil
+	}
+	leaseHolder, expirationTime := getKubernetesLeaseHolderAndExpirationTime(lease)
+	return leaseHolder, expirationTime, nil
+}
+
+// getLease returns the Lease with the given name, or nil if it does not exist.
+func (l *KubernetesLeaser) getLease(ctx context.Context, leaseName string) (*coordinationv1.Lease, error) {
+	nn := types.NamespacedName{Namespace: l.namespace, Name: leaseName}
+	lease := &coordinationv1.Lease{}
+	if err := l.client.Get(ctx, nn, lease); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting lease '%v': %w", nn, err)
+	}
+	return lease, nil
+}
+
+func setKubernetesLeaseHolder(lease *coordinationv1.Lease, ownerID string, duration time.Duration) {
+	now := metav1.NewMicroTime(time.Now())
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != ownerID {
+		lease.Spec.AcquireTime = &now
+	}
+	durationSeconds := int32(duration.Seconds())
+	lease.Spec.HolderIdentity = &ownerID
+	lease.Spec.LeaseDurationSeconds = &durationSeconds
+	lease.Spec.RenewTime = &now
+}
+
+func getKubernetesLeaseHolderAndExpirationTime(lease *coordinationv1.Lease) (string, time.Time) {
+	if lease.Spec.HolderIdentity == nil {
+		return "", zeroUnixTime
+	}
+	leaseHolder := *lease.Spec.HolderIdentity
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return leaseHolder, zeroUnixTime
+	}
+	return leaseHolder, lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+}
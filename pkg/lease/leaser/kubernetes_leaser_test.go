@@ -0,0 +1,89 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leaser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestKubernetesLeaser(t *testing.T) *KubernetesLeaser {
+	scheme := runtime.NewScheme()
+	if err := coordinationv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("error adding coordinationv1 to the scheme: %v", err)
+	}
+	return NewKubernetesLeaser(fake.NewClientBuilder().WithScheme(scheme).Build(), "leases")
+}
+
+func TestKubernetesLeaserObtainAndRelease(t *testing.T) {
+	ctx := context.Background()
+	l := newTestKubernetesLeaser(t)
+	leaseName := "kcc-my-bucket"
+
+	if err := l.Obtain(ctx, leaseName, "owner-1", time.Hour, time.Minute); err != nil {
+		t.Fatalf("error obtaining lease: %v", err)
+	}
+	owner, expiration, err := l.GetOwnerAndExpirationTime(ctx, leaseName)
+	if err != nil {
+		t.Fatalf("error getting lease owner: %v", err)
+	}
+	if owner != "owner-1" {
+		t.Fatalf("got lease owner %q, want %q", owner, "owner-1")
+	}
+	if time.Until(expiration) < 59*time.Minute {
+		t.Fatalf("got lease expiration %v, want about an hour from now", expiration)
+	}
+	// Renewing the lease as the same owner succeeds.
+	if err := l.Obtain(ctx, leaseName, "owner-1", time.Hour, time.Hour); err != nil {
+		t.Fatalf("error renewing lease: %v", err)
+	}
+	if err := l.Obtain(ctx, leaseName, "owner-2", time.Hour, time.Minute); err == nil {
+		t.Fatalf("got nil, want an error when obtaining a lease held by another owner")
+	}
+	if err := l.Release(ctx, leaseName, "owner-2"); err == nil {
+		t.Fatalf("got nil, want an error when releasing a lease held by another owner")
+	}
+	if err := l.Release(ctx, leaseName, "owner-1"); err != nil {
+		t.Fatalf("error releasing lease: %v", err)
+	}
+	if err := l.Obtain(ctx, leaseName, "owner-2", time.Hour, time.Minute); err != nil {
+		t.Fatalf("error obtaining released lease: %v", err)
+	}
+}
+
+func TestKubernetesLeaserObtainExpiredLease(t *testing.T) {
+	ctx := context.Background()
+	l := newTestKubernetesLeaser(t)
+	leaseName := "kcc-my-bucket"
+
+	if err := l.Obtain(ctx, leaseName, "owner-1", 0, 0); err != nil {
+		t.Fatalf("error obtaining lease: %v", err)
+	}
+	if err := l.Obtain(ctx, leaseName, "owner-2", time.Hour, time.Minute); err != nil {
+		t.Fatalf("error obtaining expired lease: %v", err)
+	}
+	owner, _, err := l.GetOwnerAndExpirationTime(ctx, leaseName)
+	if err != nil {
+		t.Fatalf("error getting lease owner: %v", err)
+	}
+	if owner != "owner-2" {
+		t.Fatalf("got lease owner %q, want %q", owner, "owner-2")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leaser
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	refs "github.com/GoogleCloudPlatform/k8s-config-connector/apis/refs/v1beta1"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Leaser grants an owner the exclusive right to manage a GCP resource for a period of time,
+// so that the resource is not fought over by several Config Connector installations.
+//
+// The LabelLeaser stores the lease in the labels of the GCP resource itself.
+type Leaser interface {
+	// Obtain obtains a lease, for 'ownerID', on the given unstructured for 'duration' time. If the owner
+	// already has a lease on the unstructured the lease is renewed to 'duration' time if the 'renewalMin'
+	// is greater than the remaining time on the lease.
+	Obtain(ctx context.Context, u *unstructured.Unstructured, ownerID string, duration time.Duration, renewalMin time.Duration) error
+	// Release releases the lease of 'ownerID' on the given unstructured.
+	Release(ctx context.Context, u *unstructured.Unstructured, ownerID string) error
+	// GetOwnerAndExpirationTime returns the current holder of the lease on the given unstructured
+	// and the time at which the lease expires. The holder is empty if the unstructured is not leased.
+	GetOwnerAndExpirationTime(ctx context.Context, u *unstructured.Unstructured) (string, time.Time, error)
+}
+
+var _ Leaser = &LabelLeaser{}
+
+// ExternalLeaser is a Leaser which stores the leases outside of the GCP resources, so that
+// resources which do not support labels can be leased too. The leases are keyed by the name
+// returned by LeaseName rather than by the unstructured, since resolving the identity of the
+// GCP resource requires the cluster the resource lives in.
+//
+// The GCSLeaser and KubernetesLeaser are ExternalLeasers.
+type ExternalLeaser interface {
+	// Obtain obtains the lease with the given name for 'ownerID', with the same semantics as Leaser.Obtain.
+	Obtain(ctx context.Context, leaseName string, ownerID string, duration time.Duration, renewalMin time.Duration) error
+	// Release releases the lease with the given name of 'ownerID'.
+	Release(ctx context.Context, leaseName string, ownerID string) error
+	// GetOwnerAndExpirationTime returns the current holder of the lease with the given name and the
+	// time at which the lease expires. The holder is empty if there is no such lease.
+	GetOwnerAndExpirationTime(ctx context.Context, leaseName string) (string, time.Time, error)
+}
+
+const leaseNamePrefix = "kcc-"
+
+// LeaseName returns the name of the external lease of the GCP resource managed by the given
+// unstructured. The name is a hash of the kind and the canonical name of the GCP resource, so the
+// same GCP resource gets the same lease name regardless of the cluster, namespace or references
+// it is managed with. References to the project, folder or organization are resolved with reader.
+//
+// resourceID is the ID of the GCP resource as resolved by the controller of the unstructured,
+// since only the controller knows whether the ID is generated by the server, and where the
+// generated ID is recorded.
+func LeaseName(ctx context.Context, reader client.Reader, u *unstructured.Unstructured, resourceID string) (string, error) {
+	if resourceID == "" {
+		return "", fmt.Errorf("the resource ID of %v %v/%v is not known", u.GetKind(), u.GetNamespace(), u.GetName())
+	}
+	resourceName, err := canonicalResourceName(ctx, reader, u, resourceID)
+	if err != nil {
+		return "", err
+	}
+	gvk := u.GroupVersionKind()
+	sum := sha256.Sum256([]byte(gvk.Group + "/" + gvk.Kind + "/" + resourceName))
+	// Lease names must be valid DNS subdomains, and GCS object names should stay short.
+	return leaseNamePrefix + hex.EncodeToString(sum[:])[:40], nil
+}
+
+// canonicalResourceName returns the name of the GCP resource in the form
+// "{projects,folders,organizations}/<id>[/locations/<location>]/<resourceID>".
+func canonicalResourceName(ctx context.Context, reader client.Reader, u *unstructured.Unstructured, resourceID string) (string, error) {
+	name, err := leaseContainer(ctx, reader, u)
+	if err != nil {
+		return "", err
+	}
+	// Regions and zones are locations in the GCP resource names.
+	for _, field := range []string{"location", "region", "zone"} {
+		if val, _, _ := unstructured.NestedString(u.Object, "spec", field); val != "" {
+			name += "/locations/" + val
+			break
+		}
+	}
+	return name + "/" + resourceID, nil
+}
+
+// leaseContainer returns the resolved project, folder or organization of the GCP resource, from
+// either the container annotations or the spec.projectRef, spec.folderRef or spec.organizationRef.
+func leaseContainer(ctx context.Context, reader client.Reader, u *unstructured.Unstructured) (string, error) {
+	switch {
+	case hasContainer(u, k8s.ProjectIDAnnotation, "projectRef"):
+		projectID, err := refs.ResolveProjectID(ctx, reader, u)
+		if err != nil {
+			return "", fmt.Errorf("error resolving the project of the lease: %w", err)
+		}
+		return "projects/" + projectID, nil
+	case hasContainer(u, k8s.FolderIDAnnotation, "folderRef"):
+		folderID, err := refs.ResolveFolderID(ctx, reader, u)
+		if err != nil {
+			return "", fmt.Errorf("error resolving the folder of the lease: %w", err)
+		}
+		return "folders/" + folderID, nil
+	case hasContainer(u, k8s.OrgIDAnnotation, "organizationRef"):
+		organizationID, err := refs.ResolveOrganizationID(ctx, reader, u)
+		if err != nil {
+			return "", fmt.Errorf("error resolving the organization of the lease: %w", err)
+		}
+		return "organizations/" + organizationID, nil
+	default:
+		// Without a container annotation, the project is the one of the namespace.
+		projectID, err := k8s.GetProjectIDForNamespace(ctx, reader, u.GetNamespace())
+		if err != nil {
+			return "", fmt.Errorf("error resolving the project of the lease: %w", err)
+		}
+		return "projects/" + projectID, nil
+	}
+}
+
+func hasContainer(u *unstructured.Unstructured, annotation string, ref string) bool {
+	if val, ok := k8s.GetAnnotation(annotation, u); ok && val != "" {
+		return true
+	}
+	_, found, _ := unstructured.NestedMap(u.Object, "spec", ref)
+	return found
+}
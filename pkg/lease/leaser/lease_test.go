@@ -0,0 +1,167 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leaser
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestBucket(namespace, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "storage.cnrm.cloud.google.com/v1beta1",
+			"kind":       "StorageBucket",
+			"metadata": map[string]interface{}{
+				"namespace": namespace,
+				"name":      name,
+				"annotations": map[string]interface{}{
+					"cnrm.cloud.google.com/project-id": "my-project",
+				},
+			},
+			"spec": map[string]interface{}{
+				"location": "US",
+			},
+		},
+	}
+}
+
+func newTestSecret(namespace, name string, projectRef map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "secretmanager.cnrm.cloud.google.com/v1beta1",
+			"kind":       "SecretManagerSecret",
+			"metadata": map[string]interface{}{
+				"namespace": namespace,
+				"name":      name,
+			},
+			"spec": map[string]interface{}{},
+		},
+	}
+	if projectRef != nil {
+		u.Object["spec"].(map[string]interface{})["projectRef"] = projectRef
+	}
+	return u
+}
+
+func newTestProjectReader(t *testing.T) client.Reader {
+	gvk := schema.GroupVersionKind{Group: "resourcemanager.cnrm.cloud.google.com", Version: "v1beta1", Kind: "Project"}
+	project := &unstructured.Unstructured{}
+	project.SetGroupVersionKind(gvk)
+	project.SetNamespace("projects")
+	project.SetName("my-project-object")
+	if err := unstructured.SetNestedField(project.Object, "my-project", "spec", "resourceID"); err != nil {
+		t.Fatal(err)
+	}
+	// The project of the namespace "my-project" defaults to its name.
+	namespaces := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-project"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "annotated", Annotations: map[string]string{"cnrm.cloud.google.com/project-id": "my-project"}}},
+	}
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(gvk, meta.RESTScopeNamespace)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	return fake.NewClientBuilder().WithRESTMapper(restMapper).WithObjects(project).WithObjects(namespaces...).Build()
+}
+
+// testResourceID returns the ID of the GCP resource as resolved by the controllers for
+// resources with a user-specified ID.
+func testResourceID(u *unstructured.Unstructured) string {
+	if resourceID, _, _ := unstructured.NestedString(u.Object, "spec", "resourceID"); resourceID != "" {
+		return resourceID
+	}
+	return u.GetName()
+}
+
+func mustLeaseName(t *testing.T, reader client.Reader, u *unstructured.Unstructured) string {
+	t.Helper()
+	name, err := LeaseName(context.Background(), reader, u, testResourceID(u))
+	if err != nil {
+		t.Fatalf("error getting lease name of %v: %v", u.GetName(), err)
+	}
+	return name
+}
+
+func TestLeaseNameIdentifiesTheGCPResource(t *testing.T) {
+	reader := newTestProjectReader(t)
+	want := mustLeaseName(t, reader, newTestSecret("ns-1", "my-secret", map[string]interface{}{"external": "my-project"}))
+
+	sameResource := map[string]*unstructured.Unstructured{
+		"other namespace":                 newTestSecret("ns-2", "my-secret", map[string]interface{}{"external": "my-project"}),
+		"projectRef.external with prefix": newTestSecret("ns-1", "my-secret", map[string]interface{}{"external": "projects/my-project"}),
+		"projectRef.name":                 newTestSecret("ns-1", "my-secret", map[string]interface{}{"name": "my-project-object", "namespace": "projects"}),
+		"project-id annotation":           newTestSecret("ns-1", "my-secret", nil),
+		"namespace as project ID":         newTestSecret("my-project", "my-secret", nil),
+		"namespace project-id annotation": newTestSecret("annotated", "my-secret", nil),
+	}
+	sameResource["project-id annotation"].SetAnnotations(map[string]string{"cnrm.cloud.google.com/project-id": "my-project"})
+	withResourceID := newTestSecret("ns-1", "other-secret", map[string]interface{}{"external": "my-project"})
+	if err := unstructured.SetNestedField(withResourceID.Object, "my-secret", "spec", "resourceID"); err != nil {
+		t.Fatal(err)
+	}
+	sameResource["resourceID"] = withResourceID
+	for desc, u := range sameResource {
+		if got := mustLeaseName(t, reader, u); got != want {
+			t.Errorf("%v: got lease name %q, want %q", desc, got, want)
+		}
+	}
+
+	otherResource := map[string]*unstructured.Unstructured{
+		"other name":    newTestSecret("ns-1", "other-secret", map[string]interface{}{"external": "my-project"}),
+		"other project": newTestSecret("ns-1", "my-secret", map[string]interface{}{"external": "other-project"}),
+		"other kind":    newTestBucket("ns-1", "my-secret"),
+	}
+	for desc, u := range otherResource {
+		if got := mustLeaseName(t, reader, u); got == want {
+			t.Errorf("%v: got the same lease name %q for a different GCP resource", desc, got)
+		}
+	}
+}
+
+func TestLeaseNameDistinguishesLocations(t *testing.T) {
+	reader := newTestProjectReader(t)
+	us := newTestBucket("ns-1", "my-bucket")
+	eu := newTestBucket("ns-1", "my-bucket")
+	if err := unstructured.SetNestedField(eu.Object, "EU", "spec", "location"); err != nil {
+		t.Fatal(err)
+	}
+	if mustLeaseName(t, reader, us) == mustLeaseName(t, reader, eu) {
+		t.Fatalf("got the same lease name for GCP resources in different locations")
+	}
+}
+
+func TestLeaseNameFailsOnUnresolvableProject(t *testing.T) {
+	reader := newTestProjectReader(t)
+	u := newTestSecret("ns-1", "my-secret", map[string]interface{}{"name": "missing-project"})
+	if _, err := LeaseName(context.Background(), reader, u, "my-secret"); err == nil {
+		t.Fatalf("got nil, want an error for a reference to a missing Project")
+	}
+}
+
+func TestLeaseNameFailsOnUnknownResourceID(t *testing.T) {
+	reader := newTestProjectReader(t)
+	u := newTestSecret("ns-1", "my-secret", map[string]interface{}{"external": "my-project"})
+	if _, err := LeaseName(context.Background(), reader, u, ""); err == nil {
+		t.Fatalf("got nil, want an error for a GCP resource whose ID is not known yet")
+	}
+}
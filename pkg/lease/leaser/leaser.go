@@ -41,20 +41,20 @@ var (
 	leaseKeys    = []string{leaseHolderKey, leaseExpirationKey}
 )
 
-// Leaser locks for a period of time by GCP resources by applying 'owner' and 'expiration' labels to the resource. After they are applied,
-// Leaser will only allow a caller to Obtain the resource if one of the following is true:
+// LabelLeaser locks for a period of time by GCP resources by applying 'owner' and 'expiration' labels to the resource. After they are applied,
+// LabelLeaser will only allow a caller to Obtain the resource if one of the following is true:
 // 1. The owner id is the same as what is saved in the labels
 // 2. The expiration time is before the current time
 //
 // No protections are made for race conditions: the last writer will win.
-type Leaser struct {
+type LabelLeaser struct {
 	tfProvider *tfschema.Provider
 	kubeClient client.Client
 	smLoader   *servicemappingloader.ServiceMappingLoader
 }
 
-func NewLeaser(tfProvider *tfschema.Provider, smLoader *servicemappingloader.ServiceMappingLoader, kubeClient client.Client) *Leaser {
-	return &Leaser{
+func NewLabelLeaser(tfProvider *tfschema.Provider, smLoader *servicemappingloader.ServiceMappingLoader, kubeClient client.Client) *LabelLeaser {
+	return &LabelLeaser{
 		tfProvider: tfProvider,
 		kubeClient: kubeClient,
 		smLoader:   smLoader,
@@ -66,7 +66,7 @@ func NewLeaser(tfProvider *tfschema.Provider, smLoader *servicemappingloader.Ser
 // time on the lease.
 //
 // To 'always' renew the lease, pass in a 'renewalMin' that is equal to the duration.
-func (l *Leaser) Obtain(ctx context.Context, u *unstructured.Unstructured, ownerID string, duration time.Duration, renewalMin time.Duration) error {
+func (l *LabelLeaser) Obtain(ctx context.Context, u *unstructured.Unstructured, ownerID string, duration time.Duration, renewalMin time.Duration) error {
 	if err := l.validateUnstructuredSupportsLocking(u); err != nil {
 		return err
 	}
@@ -107,7 +107,7 @@ func (l *Leaser) Obtain(ctx context.Context, u *unstructured.Unstructured, owner
 // Soft obtain obtains a lease for 'ownerID' on the given resource for 'duration' time. See the comment on Obtain(...) for more.
 //
 // It does not write the results to GCP so the caller must apply the changes to GCP if persistence is desired
-func (l *Leaser) SoftObtain(resource *k8s.Resource, liveLabels map[string]string, ownerID string, duration time.Duration, renewalMin time.Duration) error {
+func (l *LabelLeaser) SoftObtain(resource *k8s.Resource, liveLabels map[string]string, ownerID string, duration time.Duration, renewalMin time.Duration) error {
 	if _, err := l.softObtain(liveLabels, ownerID, duration, renewalMin); err != nil {
 		return err
 	}
@@ -126,7 +126,7 @@ func (l *Leaser) SoftObtain(resource *k8s.Resource, liveLabels map[string]string
 
 // checks to see if the lease is obtainable, if not an error is returned. If it is obtainable then the lease is renewed if necessary. If it is unnecessary
 // to renew the lease as the time period is still within the renewalMin window then 'false' is returned for the 'ok' parameter
-func (l *Leaser) softObtain(labels map[string]string, ownerID string, duration time.Duration, renewalMin time.Duration) (ok bool, err error) {
+func (l *LabelLeaser) softObtain(labels map[string]string, ownerID string, duration time.Duration, renewalMin time.Duration) (ok bool, err error) {
 	leaseHolder, expirationTime := getLeaseHolderAndExpirationTime(labels)
 	if !canObtainLease(ownerID, leaseHolder, expirationTime) {
 		return false, fmt.Errorf("resource is under lease by '%v' for an additional %v second(s)", leaseHolder, expirationTime.Sub(time.Now()))
@@ -138,7 +138,7 @@ func (l *Leaser) softObtain(labels map[string]string, ownerID string, duration t
 	return true, nil
 }
 
-func (l *Leaser) Release(ctx context.Context, u *unstructured.Unstructured, ownerID string) error {
+func (l *LabelLeaser) Release(ctx context.Context, u *unstructured.Unstructured, ownerID string) error {
 	if err := l.validateUnstructuredSupportsLocking(u); err != nil {
 		return err
 	}
@@ -173,7 +173,7 @@ func (l *Leaser) Release(ctx context.Context, u *unstructured.Unstructured, owne
 	return nil
 }
 
-func (l *Leaser) GetOwnerAndExpirationTime(ctx context.Context, u *unstructured.Unstructured) (string, time.Time, error) {
+func (l *LabelLeaser) GetOwnerAndExpirationTime(ctx context.Context, u *unstructured.Unstructured) (string, time.Time, error) {
 	if err := l.validateUnstructuredSupportsLocking(u); err != nil {
 		return "", zeroUnixTime, err
 	}
@@ -185,7 +185,7 @@ func (l *Leaser) GetOwnerAndExpirationTime(ctx context.Context, u *unstructured.
 	return leaseHolder, expirationTime, nil
 }
 
-func (l *Leaser) getResourceAndLiveState(ctx context.Context, u *unstructured.Unstructured) (*krmtotf.Resource,
+func (l *LabelLeaser) getResourceAndLiveState(ctx context.Context, u *unstructured.Unstructured) (*krmtotf.Resource,
 	*terraform.InstanceState, error) {
 	sm, err := l.smLoader.GetServiceMapping(u.GroupVersionKind().Group)
 	if err != nil {
@@ -214,7 +214,7 @@ func GetLabelKeys() []string {
 	return leaseKeys
 }
 
-func (l *Leaser) UnstructuredSupportsLeasing(u *unstructured.Unstructured) (ok bool, err error) {
+func (l *LabelLeaser) UnstructuredSupportsLeasing(u *unstructured.Unstructured) (ok bool, err error) {
 	sm, err := l.smLoader.GetServiceMapping(u.GroupVersionKind().Group)
 	if err != nil {
 		return false, fmt.Errorf("error getting service mapping: %w", err)
@@ -226,7 +226,7 @@ func (l *Leaser) UnstructuredSupportsLeasing(u *unstructured.Unstructured) (ok b
 	return leasable.ResourceConfigSupportsLeasing(rc, l.tfProvider.ResourcesMap)
 }
 
-func (l *Leaser) validateUnstructuredSupportsLocking(u *unstructured.Unstructured) error {
+func (l *LabelLeaser) validateUnstructuredSupportsLocking(u *unstructured.Unstructured) error {
 	ok, err := l.UnstructuredSupportsLeasing(u)
 	if err != nil {
 		return fmt.Errorf("error determining if gvk '%v' supports locking: %w", u.GroupVersionKind(), err)
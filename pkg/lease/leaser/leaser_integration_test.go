@@ -52,7 +52,7 @@ func TestUnsupportedResourceShouldFail(t *testing.T) {
 		}
 	}
 	testFunc := func(ctx context.Context, t *testing.T, testContext testrunner.TestContext, systemContext testrunner.SystemContext) {
-		leaser := leaser.NewLeaser(systemContext.TFProvider, systemContext.SMLoader, systemContext.Manager.GetClient())
+		leaser := leaser.NewLabelLeaser(systemContext.TFProvider, systemContext.SMLoader, systemContext.Manager.GetClient())
 		ok, err := leaser.UnstructuredSupportsLeasing(testContext.CreateUnstruct)
 		if err != nil {
 			t.Fatalf("error checking for lease support: %v", err)
@@ -96,7 +96,7 @@ func TestAll(t *testing.T) {
 		resourceCleanup := systemContext.Reconciler.BuildCleanupFunc(ctx, testContext.CreateUnstruct, testreconciler.CleanupPolicyAlways)
 		defer resourceCleanup()
 		systemContext.Reconciler.Reconcile(ctx, testContext.CreateUnstruct, testreconciler.ExpectedSuccessfulReconcileResultFor(systemContext.Reconciler, testContext.CreateUnstruct), nil)
-		leaser := leaser.NewLeaser(systemContext.TFProvider, systemContext.SMLoader, systemContext.Manager.GetClient())
+		leaser := leaser.NewLabelLeaser(systemContext.TFProvider, systemContext.SMLoader, systemContext.Manager.GetClient())
 		uniqueId1 := fmt.Sprintf("l1-%v", testContext.UniqueID)
 		uniqueId2 := fmt.Sprintf("l2-%v", testContext.UniqueID)
 		initialUnstruct := testContext.CreateUnstruct
@@ -113,48 +113,48 @@ func TestAll(t *testing.T) {
 	testrunner.RunAllWithDependenciesCreatedButNotObject(ctx, t, mgr, shouldRun, testFunc)
 }
 
-func testObtainReleaseShouldSucceed(t *testing.T, u *unstructured.Unstructured, uniqueID string, leaser *leaser.Leaser) {
+func testObtainReleaseShouldSucceed(t *testing.T, u *unstructured.Unstructured, uniqueID string, leaser *leaser.LabelLeaser) {
 	obtainAssertSuccess(t, u, uniqueID, defaultLeaseDuration, defaultLeaseDuration, leaser)
 	releaseAssertSuccess(t, u, uniqueID, leaser)
 }
 
-func testObtainTwiceShouldSucceed(t *testing.T, u *unstructured.Unstructured, uniqueID string, leaser *leaser.Leaser) {
+func testObtainTwiceShouldSucceed(t *testing.T, u *unstructured.Unstructured, uniqueID string, leaser *leaser.LabelLeaser) {
 	obtainAssertSuccess(t, u, uniqueID, defaultLeaseDuration, defaultLeaseDuration, leaser)
 	obtainAssertSuccess(t, u, uniqueID, defaultLeaseDuration, defaultLeaseDuration, leaser)
 	releaseAssertSuccess(t, u, uniqueID, leaser)
 }
 
-func testReleaseUnobtainedShouldFail(t *testing.T, u *unstructured.Unstructured, uniqueID string, leaser *leaser.Leaser) {
+func testReleaseUnobtainedShouldFail(t *testing.T, u *unstructured.Unstructured, uniqueID string, leaser *leaser.LabelLeaser) {
 	releaseAssertError(t, u, uniqueID, leaser)
 }
 
-func testObtainingPreviouslyReleasedResourceShouldSucceed(t *testing.T, u *unstructured.Unstructured, uniqueId1, uniqueId2 string, leaser *leaser.Leaser) {
+func testObtainingPreviouslyReleasedResourceShouldSucceed(t *testing.T, u *unstructured.Unstructured, uniqueId1, uniqueId2 string, leaser *leaser.LabelLeaser) {
 	obtainAssertSuccess(t, u, uniqueId1, defaultLeaseDuration, defaultLeaseDuration, leaser)
 	releaseAssertSuccess(t, u, uniqueId1, leaser)
 	obtainAssertSuccess(t, u, uniqueId2, defaultLeaseDuration, defaultLeaseDuration, leaser)
 	releaseAssertSuccess(t, u, uniqueId2, leaser)
 }
 
-func testObtainingLockedResourceShouldFail(t *testing.T, u *unstructured.Unstructured, uniqueId1, uniqueId2 string, leaser *leaser.Leaser) {
+func testObtainingLockedResourceShouldFail(t *testing.T, u *unstructured.Unstructured, uniqueId1, uniqueId2 string, leaser *leaser.LabelLeaser) {
 	obtainAssertSuccess(t, u, uniqueId1, defaultLeaseDuration, defaultLeaseDuration, leaser)
 	obtainAssertError(t, u, uniqueId2, defaultLeaseDuration, defaultLeaseDuration, leaser)
 	releaseAssertSuccess(t, u, uniqueId1, leaser)
 }
 
-func testReleasingLockedResourceShouldFail(t *testing.T, u *unstructured.Unstructured, uniqueId1, uniqueId2 string, leaser *leaser.Leaser) {
+func testReleasingLockedResourceShouldFail(t *testing.T, u *unstructured.Unstructured, uniqueId1, uniqueId2 string, leaser *leaser.LabelLeaser) {
 	obtainAssertSuccess(t, u, uniqueId1, defaultLeaseDuration, defaultLeaseDuration, leaser)
 	releaseAssertError(t, u, uniqueId2, leaser)
 	releaseAssertSuccess(t, u, uniqueId1, leaser)
 }
 
-func testReleasingExpiredResourceShouldFail(t *testing.T, u *unstructured.Unstructured, uniqueID string, leaser *leaser.Leaser) {
+func testReleasingExpiredResourceShouldFail(t *testing.T, u *unstructured.Unstructured, uniqueID string, leaser *leaser.LabelLeaser) {
 	shortLeaseDuration := 1 * time.Second
 	obtainAssertSuccess(t, u, uniqueID, shortLeaseDuration, shortLeaseDuration, leaser)
 	time.Sleep(shortLeaseDuration + 1*time.Second)
 	releaseAssertError(t, u, uniqueID, leaser)
 }
 
-func testObtainingExpiredLeaseShouldSucceed(t *testing.T, u *unstructured.Unstructured, uniqueId1, uniqueId2 string, leaser *leaser.Leaser) {
+func testObtainingExpiredLeaseShouldSucceed(t *testing.T, u *unstructured.Unstructured, uniqueId1, uniqueId2 string, leaser *leaser.LabelLeaser) {
 	shortLeaseDuration := 10 * time.Second
 	obtainAssertSuccess(t, u, uniqueId1, shortLeaseDuration, shortLeaseDuration, leaser)
 	obtainAssertError(t, u, uniqueId2, defaultLeaseDuration, defaultLeaseDuration, leaser)
@@ -164,7 +164,7 @@ func testObtainingExpiredLeaseShouldSucceed(t *testing.T, u *unstructured.Unstru
 	releaseAssertSuccess(t, u, uniqueId2, leaser)
 }
 
-func testRenewLease(t *testing.T, u *unstructured.Unstructured, uniqueID string, leaser *leaser.Leaser) {
+func testRenewLease(t *testing.T, u *unstructured.Unstructured, uniqueID string, leaser *leaser.LabelLeaser) {
 	shortMinRemaining := 2 * time.Second
 	obtainAssertSuccess(t, u, uniqueID, defaultLeaseDuration, defaultLeaseDuration, leaser)
 	_, originalExpirationTIme := getOwnerAndExpirationTime(t, u, leaser)
@@ -185,7 +185,7 @@ func testRenewLease(t *testing.T, u *unstructured.Unstructured, uniqueID string,
 	releaseAssertSuccess(t, u, uniqueID, leaser)
 }
 
-func getOwnerAndExpirationTime(t *testing.T, u *unstructured.Unstructured, leaser *leaser.Leaser) (string, time.Time) {
+func getOwnerAndExpirationTime(t *testing.T, u *unstructured.Unstructured, leaser *leaser.LabelLeaser) (string, time.Time) {
 	ownerID, expirationTime, err := leaser.GetOwnerAndExpirationTime(context.Background(), u)
 	if err != nil {
 		t.Fatalf("error getting owner and expiration time: %v", err)
@@ -193,7 +193,7 @@ func getOwnerAndExpirationTime(t *testing.T, u *unstructured.Unstructured, lease
 	return ownerID, expirationTime
 }
 
-func obtainAssertSuccess(t *testing.T, u *unstructured.Unstructured, uniqueID string, duration time.Duration, minRemaining time.Duration, leaser *leaser.Leaser) {
+func obtainAssertSuccess(t *testing.T, u *unstructured.Unstructured, uniqueID string, duration time.Duration, minRemaining time.Duration, leaser *leaser.LabelLeaser) {
 	t.Helper()
 	err := leaser.Obtain(context.Background(), u, uniqueID, duration, minRemaining)
 	if err != nil {
@@ -201,7 +201,7 @@ func obtainAssertSuccess(t *testing.T, u *unstructured.Unstructured, uniqueID st
 	}
 }
 
-func obtainAssertError(t *testing.T, u *unstructured.Unstructured, uniqueID string, duration time.Duration, minRemaining time.Duration, leaser *leaser.Leaser) {
+func obtainAssertError(t *testing.T, u *unstructured.Unstructured, uniqueID string, duration time.Duration, minRemaining time.Duration, leaser *leaser.LabelLeaser) {
 	t.Helper()
 	err := leaser.Obtain(context.Background(), u, uniqueID, duration, minRemaining)
 	if err == nil {
@@ -209,7 +209,7 @@ func obtainAssertError(t *testing.T, u *unstructured.Unstructured, uniqueID stri
 	}
 }
 
-func releaseAssertSuccess(t *testing.T, u *unstructured.Unstructured, uniqueID string, leaser *leaser.Leaser) {
+func releaseAssertSuccess(t *testing.T, u *unstructured.Unstructured, uniqueID string, leaser *leaser.LabelLeaser) {
 	t.Helper()
 	err := leaser.Release(context.Background(), u, uniqueID)
 	if err != nil {
@@ -217,7 +217,7 @@ func releaseAssertSuccess(t *testing.T, u *unstructured.Unstructured, uniqueID s
 	}
 }
 
-func releaseAssertError(t *testing.T, u *unstructured.Unstructured, uniqueID string, leaser *leaser.Leaser) {
+func releaseAssertError(t *testing.T, u *unstructured.Unstructured, uniqueID string, leaser *leaser.LabelLeaser) {
 	t.Helper()
 	err := leaser.Release(context.Background(), u, uniqueID)
 	if err == nil {
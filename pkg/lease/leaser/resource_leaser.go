@@ -31,13 +31,15 @@ import (
 
 type ResourceLeaser struct {
 	kubeClient client.Client
-	leaser     *Leaser
+	leaser     *LabelLeaser
+	backends   Backends
 }
 
-func NewResourceLeaser(tfProvider *schema.Provider, smLoader *servicemappingloader.ServiceMappingLoader, kubeClient client.Client) *ResourceLeaser {
+func NewResourceLeaser(tfProvider *schema.Provider, smLoader *servicemappingloader.ServiceMappingLoader, kubeClient client.Client, backends Backends) *ResourceLeaser {
 	return &ResourceLeaser{
 		kubeClient: kubeClient,
-		leaser:     NewLeaser(tfProvider, smLoader, kubeClient),
+		leaser:     NewLabelLeaser(tfProvider, smLoader, kubeClient),
+		backends:   backends,
 	}
 }
 
@@ -69,3 +71,74 @@ func (l *ResourceLeaser) Release(ctx context.Context, u *unstructured.Unstructur
 func (l *ResourceLeaser) IsLeasable(resource *krmtotf.Resource) (ok bool, err error) {
 	return leasable.ResourceConfigSupportsLeasing(&resource.ResourceConfig, l.leaser.tfProvider.ResourcesMap)
 }
+
+// ObtainExternal obtains a lease for the resource from the external backend of the given policy.
+// Unlike SoftObtain, the lease is persisted immediately. resourceID is the ID of the GCP resource,
+// see LeaseName. An empty resourceID means that the ID is generated by the server and that the GCP
+// resource has not been created yet, in which case there is nothing to lease yet.
+func (l *ResourceLeaser) ObtainExternal(ctx context.Context, policy k8s.ManagementConflictPreventionPolicy, resource *k8s.Resource, resourceID string) error {
+	if resourceID == "" {
+		return nil
+	}
+	backend, err := l.backends.Get(policy)
+	if err != nil {
+		return err
+	}
+	u, err := resource.MarshalAsUnstructured()
+	if err != nil {
+		return err
+	}
+	uniqueID, err := cluster.GetNamespaceID(ctx, k8s.NamespaceIDConfigMapNN, l.kubeClient, u.GetNamespace())
+	if err != nil {
+		return fmt.Errorf("error getting unique id for namespace '%v': %w", u.GetNamespace(), err)
+	}
+	leaseName, err := LeaseName(ctx, l.kubeClient, u, resourceID)
+	if err != nil {
+		return err
+	}
+	if err := backend.Obtain(ctx, leaseName, uniqueID, k8s.TimeToLeaseExpiration, k8s.TimeToLeaseRenewal); err != nil {
+		return fmt.Errorf("error obtaining lease: %w", err)
+	}
+	return nil
+}
+
+// ReleaseExternalIfNecessary releases the lease of the resource if it is held in an external
+// backend by the namespace of the resource, so that other owners do not have to wait for the
+// lease to expire once the resource is deleted or abandoned. resourceID is the ID of the GCP
+// resource as passed to ObtainExternal.
+func (l *ResourceLeaser) ReleaseExternalIfNecessary(ctx context.Context, resource *k8s.Resource, resourceID string) error {
+	policy, err := k8s.GetManagementConflictPreventionAnnotationValue(resource)
+	if err != nil {
+		return err
+	}
+	if !k8s.IsExternalLeasePolicy(policy) || resourceID == "" {
+		return nil
+	}
+	backend, err := l.backends.Get(policy)
+	if err != nil {
+		return err
+	}
+	u, err := resource.MarshalAsUnstructured()
+	if err != nil {
+		return err
+	}
+	uniqueID, err := cluster.GetNamespaceID(ctx, k8s.NamespaceIDConfigMapNN, l.kubeClient, u.GetNamespace())
+	if err != nil {
+		return fmt.Errorf("error getting unique id for namespace '%v': %w", u.GetNamespace(), err)
+	}
+	leaseName, err := LeaseName(ctx, l.kubeClient, u, resourceID)
+	if err != nil {
+		return err
+	}
+	leaseHolder, _, err := backend.GetOwnerAndExpirationTime(ctx, leaseName)
+	if err != nil {
+		return err
+	}
+	if leaseHolder != uniqueID {
+		return nil
+	}
+	if err := backend.Release(ctx, leaseName, uniqueID); err != nil {
+		return fmt.Errorf("error releasing lease on %v with name '%v': %w", u.GroupVersionKind(), u.GetName(), err)
+	}
+	return nil
+}
@@ -61,7 +61,7 @@ func TestObtainAndReleaseResourceLease(t *testing.T) {
 		krmResource1 := testkrmtotf.NewKRMResource(t, u, sm, systemContext.TFProvider)
 		krmResource2 := testkrmtotf.NewKRMResource(t, u, sm, systemContext.TFProvider)
 		krmResource2.SetNamespace(testvariable.NewUniqueID())
-		resourceLeaser := leaser.NewResourceLeaser(systemContext.TFProvider, systemContext.SMLoader, systemContext.Manager.GetClient())
+		resourceLeaser := leaser.NewResourceLeaser(systemContext.TFProvider, systemContext.SMLoader, systemContext.Manager.GetClient(), nil)
 		liveState1 := testkrmtotf.FetchLiveState(t, krmResource1, systemContext.TFProvider, systemContext.Manager.GetClient(), systemContext.SMLoader)
 		// obtain a lease for the first namespace
 		liveLabels1 := krmtotf.GetLabelsFromState(krmResource1, liveState1)
@@ -15,11 +15,9 @@
 package metrics
 
 import (
-	"context"
 	"os"
 
 	"github.com/prometheus/procfs"
-	"go.opencensus.io/stats"
 )
 
 func RecordProcessStartTime() error {
@@ -35,6 +33,6 @@ func RecordProcessStartTime() error {
 	if err != nil {
 		return err
 	}
-	stats.Record(context.Background(), MProcessStartTime.M(startTime))
+	MProcessStartTime.Set(startTime, nil)
 	return nil
 }
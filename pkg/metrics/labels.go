@@ -0,0 +1,81 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The labels of the controller metrics.
+const (
+	KindLabel         = "group_version_kind"
+	StatusLabel       = "status"
+	NamespaceLabel    = "namespace"
+	ResourceNameLabel = "name"
+	QueueLaneLabel    = "lane"
+	ProjectLabel      = "project"
+	ServiceLabel      = "service"
+	CodeLabel         = "code"
+)
+
+// Labels are the label values of a measurement, keyed by label name. A measurement can carry
+// more labels than the metric is exported with; the labels which are not selected for the
+// metric are dropped.
+type Labels map[string]string
+
+// LabelConfig selects the labels each metric is exported with, keyed by the name of the metric
+// without the "configconnector_" prefix, e.g. "reconcile_requests_total". The metrics which are
+// not in the map are exported with their default labels.
+type LabelConfig map[string][]string
+
+// ParseMetricLabels parses the labels selected for a metric in the format
+// "<metric>=<label>[,<label>...]", for example "reconcile_requests_total=group_version_kind,status".
+// An empty list of labels, e.g. "reconcile_requests_total=", exports the metric without labels.
+func ParseMetricLabels(s string) (string, []string, error) {
+	name, value, found := strings.Cut(s, "=")
+	if !found || name == "" {
+		return "", nil, fmt.Errorf("invalid metric labels %q, expected <metric>=<label>[,<label>...]", s)
+	}
+	labels := []string{}
+	for _, label := range strings.Split(value, ",") {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			continue
+		}
+		labels = append(labels, label)
+	}
+	return name, labels, nil
+}
+
+// Add selects the given labels for the named metric, in addition to the labels already selected
+// for it, or to its default labels.
+func (c LabelConfig) Add(name string, labels ...string) {
+	selected, found := c[name]
+	if !found {
+		for _, m := range allMetrics {
+			if m.definition().name == name {
+				selected = slices.Clone(m.definition().defaultLabels)
+			}
+		}
+	}
+	for _, label := range labels {
+		if !slices.Contains(selected, label) {
+			selected = append(selected, label)
+		}
+	}
+	c[name] = selected
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestParseMetricLabels(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantName   string
+		wantLabels []string
+		wantErr    bool
+	}{
+		{
+			input:      "reconcile_requests_total=group_version_kind,status",
+			wantName:   "reconcile_requests_total",
+			wantLabels: []string{"group_version_kind", "status"},
+		},
+		{
+			input:      "reconcile_requests_total=",
+			wantName:   "reconcile_requests_total",
+			wantLabels: []string{},
+		},
+		{
+			input:   "reconcile_requests_total",
+			wantErr: true,
+		},
+		{
+			input:   "=status",
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			name, labels, err := ParseMetricLabels(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseMetricLabels(%q): got error %v, want error %v", tc.input, err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if name != tc.wantName || !reflect.DeepEqual(labels, tc.wantLabels) {
+				t.Errorf("ParseMetricLabels(%q): got %q %v, want %q %v", tc.input, name, labels, tc.wantName, tc.wantLabels)
+			}
+		})
+	}
+}
+
+func TestLabelConfigAdd(t *testing.T) {
+	c := LabelConfig{}
+	c.Add("reconcile_requests_total", ResourceNameLabel)
+	want := []string{KindLabel, NamespaceLabel, StatusLabel, ResourceNameLabel}
+	if got := c["reconcile_requests_total"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("got labels %v, want %v", got, want)
+	}
+}
+
+func TestSelectedLabels(t *testing.T) {
+	counter := newCounter("test_requests_total", "test", []string{KindLabel, NamespaceLabel, ResourceNameLabel}, []string{KindLabel, NamespaceLabel})
+	if err := counter.selectLabels([]string{"unknown"}); err == nil {
+		t.Fatalf("selectLabels: got nil, want an error for an unknown label")
+	}
+
+	// Measurements recorded before the metric is registered are dropped.
+	counter.Inc(Labels{KindLabel: "Foo.test.cnrm.cloud.google.com"})
+
+	if err := counter.selectLabels([]string{KindLabel}); err != nil {
+		t.Fatalf("selectLabels: unexpected error: %v", err)
+	}
+	registry := prometheus.NewRegistry()
+	if err := counter.register(registry); err != nil {
+		t.Fatalf("register: unexpected error: %v", err)
+	}
+	counter.Inc(Labels{KindLabel: "Foo.test.cnrm.cloud.google.com", NamespaceLabel: "ns-1", ResourceNameLabel: "a"})
+	counter.Inc(Labels{KindLabel: "Foo.test.cnrm.cloud.google.com", NamespaceLabel: "ns-2", ResourceNameLabel: "b"})
+
+	want := `
+# HELP configconnector_test_requests_total test
+# TYPE configconnector_test_requests_total counter
+configconnector_test_requests_total{group_version_kind="Foo.test.cnrm.cloud.google.com"} 2
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGaugeLabelsCannotBeDropped(t *testing.T) {
+	gauge := newGauge("test_queue_depth", "test", []string{KindLabel, QueueLaneLabel}, []string{KindLabel, QueueLaneLabel})
+	if err := gauge.selectLabels([]string{KindLabel}); err == nil {
+		t.Fatalf("selectLabels: got nil, want an error when dropping a label of a gauge")
+	}
+	if err := gauge.selectLabels([]string{QueueLaneLabel, KindLabel}); err != nil {
+		t.Fatalf("selectLabels: unexpected error: %v", err)
+	}
+}
@@ -18,7 +18,6 @@ import (
 	"fmt"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"go.opencensus.io/stats"
 )
 
 var (
@@ -26,16 +25,60 @@ var (
 	labels    = []string{"namespace", "group_version_kind", "status"}
 )
 
-// metrics defined in the format of OpenCensus
+// The controller metrics, exported by the controller manager. Measurements can be recorded
+// before the metrics are registered with RegisterControllerMetrics, but they are dropped.
 var (
-	MReconcileOccupiedWorkers = stats.Int64("ReconcileOccupiedWorkers", "The number of occupied reconcile workers", stats.UnitDimensionless)
-	MReconcileTotalWorkers    = stats.Int64("ReconcileTotalWorkers", "The number of total reconcile workers", stats.UnitDimensionless)
-	MReconcileRequests        = stats.Int64("ReconcileRequests", "The number of reconcile requests", stats.UnitDimensionless)
-	MInternalErrors           = stats.Int64("InternalErrorsTotal", "The number of internal errors", stats.UnitDimensionless)
-	MReconcileDuration        = stats.Float64("ReconcileDuration", "The duration of reconcile requests", "seconds")
-	MProcessStartTime         = stats.Float64("ProcessStartTimeSeconds", "Start time of the process since unix epoch in seconds", "seconds")
+	MReconcileOccupiedWorkers = newGauge("reconcile_occupied_workers_total", "The number of occupied reconcile workers",
+		[]string{KindLabel}, []string{KindLabel})
+	MReconcileTotalWorkers = newGauge("reconcile_workers_total", "The number of total reconcile workers",
+		[]string{KindLabel}, []string{KindLabel})
+	MReconcileRequests = newCounter("reconcile_requests_total", "The number of reconcile requests",
+		[]string{KindLabel, NamespaceLabel, StatusLabel, ResourceNameLabel}, []string{KindLabel, NamespaceLabel, StatusLabel})
+	MInternalErrors = newCounter("internal_errors_total", "The number of internal errors",
+		[]string{KindLabel, NamespaceLabel}, []string{KindLabel, NamespaceLabel})
+	// Latency in buckets:
+	// [>=0s, >=5s, >=10s, >=25s, >=1min, >=5min, >=10min, >=15min, >=30min, >=45min, >1h]
+	MReconcileDuration = newHistogram("reconcile_request_duration_seconds", "The duration of reconcile requests",
+		[]string{KindLabel, NamespaceLabel, StatusLabel, ResourceNameLabel}, []string{KindLabel, NamespaceLabel, StatusLabel},
+		[]float64{0, 5, 10, 25, 60, 5 * 60, 10 * 60, 15 * 60, 30 * 60, 45 * 60, 60 * 60})
+	// Latency in buckets:
+	// [>=0s, >=100ms, >=250ms, >=500ms, >=1s, >=2.5s, >=5s, >=10s, >=30s, >=1min, >=2min, >=5min, >10min]
+	MReconcileLatency = newHistogram("reconcile_latency_seconds", "The latency of reconcile requests per resource kind",
+		[]string{KindLabel, NamespaceLabel, StatusLabel}, []string{KindLabel},
+		[]float64{0, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 2 * 60, 5 * 60, 10 * 60})
+	MProcessStartTime = newGauge("process_start_time_seconds", "Start time of the process since unix epoch in seconds",
+		nil, nil)
+	MReconcileQueueDepth = newGauge("reconcile_queue_depth", "The number of requests waiting in the reconcile queue",
+		[]string{KindLabel, QueueLaneLabel}, []string{KindLabel, QueueLaneLabel})
+	MGCPRequests = newCounter("gcp_requests_total", "The number of requests made to GCP APIs",
+		[]string{ProjectLabel, ServiceLabel}, []string{ProjectLabel, ServiceLabel})
+	// Wait time in buckets:
+	// [>=0s, >=10ms, >=100ms, >=500ms, >=1s, >=5s, >=10s, >=30s, >1min]
+	MGCPRateLimitWait = newHistogram("gcp_rate_limit_wait_seconds", "The time requests to GCP APIs waited for the client-side rate limit",
+		[]string{ProjectLabel, ServiceLabel}, []string{ProjectLabel, ServiceLabel},
+		[]float64{0, 0.01, 0.1, 0.5, 1, 5, 10, 30, 60})
+	// Latency in buckets:
+	// [>=0s, >=50ms, >=100ms, >=250ms, >=500ms, >=1s, >=2.5s, >=5s, >=10s, >=30s, >1min]
+	MGCPRequestDuration = newHistogram("gcp_request_duration_seconds", "The duration of the requests made to GCP APIs",
+		[]string{ProjectLabel, ServiceLabel, CodeLabel}, []string{ServiceLabel, CodeLabel},
+		[]float64{0, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60})
 )
 
+// allMetrics are the controller metrics registered by RegisterControllerMetrics.
+var allMetrics = []controllerMetric{
+	MReconcileOccupiedWorkers,
+	MReconcileTotalWorkers,
+	MReconcileRequests,
+	MInternalErrors,
+	MReconcileDuration,
+	MReconcileLatency,
+	MProcessStartTime,
+	MReconcileQueueDepth,
+	MGCPRequests,
+	MGCPRateLimitWait,
+	MGCPRequestDuration,
+}
+
 // metrics defined in the format of prometheus/client_golang
 func NewAppliedResourcesCollector() *prometheus.GaugeVec {
 	return prometheus.NewGaugeVec(prometheus.GaugeOpts{
@@ -18,34 +18,50 @@ import (
 	"fmt"
 	"net/http"
 
-	"contrib.go.opencensus.io/exporter/prometheus"
-	"go.opencensus.io/stats/view"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/logging"
 )
 
-func RegisterControllerOpenCensusViews() error {
-	// Register the views
-	return view.Register(GetControllerViews()...)
-}
+// Registry is the registry of the controller metrics, served by RegisterPrometheusExporter.
+var Registry = prometheus.NewRegistry()
 
-func RegisterControllerOpenCensusViewsWithResourceNameLabel() error {
-	// Register the views
-	return view.Register(GetControllerViewsWithResourceNameLabel()...)
+// RegisterControllerMetrics registers the controller metrics with Registry. Each metric is
+// exported with the labels selected in labelConfig, or with its default labels.
+func RegisterControllerMetrics(labelConfig LabelConfig) error {
+	known := make(map[string]bool)
+	for _, m := range allMetrics {
+		known[m.definition().name] = true
+	}
+	for name := range labelConfig {
+		if !known[name] {
+			return fmt.Errorf("unknown metric %q in the metric labels", name)
+		}
+	}
+	for _, m := range allMetrics {
+		def := m.definition()
+		labels, found := labelConfig[def.name]
+		if !found {
+			labels = def.defaultLabels
+		}
+		if err := def.selectLabels(labels); err != nil {
+			return err
+		}
+		if err := m.register(Registry); err != nil {
+			return fmt.Errorf("error registering metric %v: %w", def.name, err)
+		}
+	}
+	return nil
 }
 
 func RegisterPrometheusExporter(addr string) error {
-	pe, err := prometheus.NewExporter(prometheus.Options{
-		Namespace: "configconnector",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create the Prometheus stats exporter: %w", err)
-	}
+	handler := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
 
 	// Run the Prometheus exporter as a scrape endpoint.
 	go func() {
 		mux := http.NewServeMux()
-		mux.Handle("/metrics", pe)
+		mux.Handle("/metrics", handler)
 		if err := http.ListenAndServe(addr, mux); err != nil {
 			logging.Fatal(err, "failed to run Prometheus scrape endpoint")
 		}
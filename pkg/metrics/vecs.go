@@ -0,0 +1,149 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metric is the definition of a controller metric. The vector of a metric is only created when
+// the metric is registered, since the labels it is exported with are configurable; the
+// measurements recorded before are dropped.
+type metric struct {
+	name string
+	help string
+	// labels are the labels the metric can be exported with, i.e. the labels its measurements carry.
+	labels []string
+	// defaultLabels are the labels the metric is exported with if they are not configured.
+	defaultLabels []string
+	// selected are the labels the metric is exported with once registered.
+	selected []string
+	// keepsAllLabels is set for the metrics which must be exported with all of their labels.
+	// A gauge cannot drop labels, since the measurements which only differ in the dropped
+	// labels would overwrite each other instead of being aggregated.
+	keepsAllLabels bool
+}
+
+// controllerMetric is implemented by Counter, Gauge and Histogram.
+type controllerMetric interface {
+	definition() *metric
+	register(registerer prometheus.Registerer) error
+}
+
+func (m *metric) definition() *metric {
+	return m
+}
+
+func (m *metric) selectLabels(labels []string) error {
+	for _, label := range labels {
+		if !slices.Contains(m.labels, label) {
+			return fmt.Errorf("metric %v does not have label %q, supported labels: %v", m.name, label, m.labels)
+		}
+	}
+	if m.keepsAllLabels {
+		for _, label := range m.labels {
+			if !slices.Contains(labels, label) {
+				return fmt.Errorf("metric %v cannot drop label %q, the labels of a gauge cannot be dropped", m.name, label)
+			}
+		}
+	}
+	m.selected = labels
+	return nil
+}
+
+func (m *metric) values(labels Labels) []string {
+	values := make([]string, len(m.selected))
+	for i, label := range m.selected {
+		values[i] = labels[label]
+	}
+	return values
+}
+
+type Counter struct {
+	metric
+	vec atomic.Pointer[prometheus.CounterVec]
+}
+
+func newCounter(name, help string, labels, defaultLabels []string) *Counter {
+	return &Counter{metric: metric{name: name, help: help, labels: labels, defaultLabels: defaultLabels}}
+}
+
+func (c *Counter) register(registerer prometheus.Registerer) error {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: namespace, Name: c.name, Help: c.help}, c.selected)
+	if err := registerer.Register(vec); err != nil {
+		return err
+	}
+	c.vec.Store(vec)
+	return nil
+}
+
+func (c *Counter) Inc(labels Labels) {
+	if vec := c.vec.Load(); vec != nil {
+		vec.WithLabelValues(c.values(labels)...).Inc()
+	}
+}
+
+type Gauge struct {
+	metric
+	vec atomic.Pointer[prometheus.GaugeVec]
+}
+
+func newGauge(name, help string, labels, defaultLabels []string) *Gauge {
+	return &Gauge{metric: metric{name: name, help: help, labels: labels, defaultLabels: defaultLabels, keepsAllLabels: true}}
+}
+
+func (g *Gauge) register(registerer prometheus.Registerer) error {
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{Namespace: namespace, Name: g.name, Help: g.help}, g.selected)
+	if err := registerer.Register(vec); err != nil {
+		return err
+	}
+	g.vec.Store(vec)
+	return nil
+}
+
+func (g *Gauge) Set(value float64, labels Labels) {
+	if vec := g.vec.Load(); vec != nil {
+		vec.WithLabelValues(g.values(labels)...).Set(value)
+	}
+}
+
+type Histogram struct {
+	metric
+	buckets []float64
+	vec     atomic.Pointer[prometheus.HistogramVec]
+}
+
+func newHistogram(name, help string, labels, defaultLabels []string, buckets []float64) *Histogram {
+	return &Histogram{metric: metric{name: name, help: help, labels: labels, defaultLabels: defaultLabels}, buckets: buckets}
+}
+
+func (h *Histogram) register(registerer prometheus.Registerer) error {
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{Namespace: namespace, Name: h.name, Help: h.help, Buckets: h.buckets}, h.selected)
+	if err := registerer.Register(vec); err != nil {
+		return err
+	}
+	h.vec.Store(vec)
+	return nil
+}
+
+func (h *Histogram) Observe(value float64, labels Labels) {
+	if vec := h.vec.Load(); vec != nil {
+		vec.WithLabelValues(h.values(labels)...).Observe(value)
+	}
+}
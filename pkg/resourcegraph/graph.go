@@ -0,0 +1,463 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resourcegraph builds the graph of the references between Config
+// Connector resources, e.g. from a ComputeSubnetwork to the ComputeNetwork
+// in its spec.networkRef, and derives from it the order in which the
+// resources can be created or deleted.
+package resourcegraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// NodeID identifies a resource in the graph.
+type NodeID struct {
+	Group     string `json:"group"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+func NodeIDFromUnstructured(u *unstructured.Unstructured) NodeID {
+	return NodeID{
+		Group:     u.GroupVersionKind().Group,
+		Kind:      u.GetKind(),
+		Namespace: u.GetNamespace(),
+		Name:      u.GetName(),
+	}
+}
+
+func (id NodeID) String() string {
+	kind := id.Kind
+	if kind == "" {
+		kind = "<unknown kind>"
+	}
+	if id.Namespace == "" {
+		return fmt.Sprintf("%v %v", kind, id.Name)
+	}
+	return fmt.Sprintf("%v %v/%v", kind, id.Namespace, id.Name)
+}
+
+func (id NodeID) less(other NodeID) bool {
+	if id.Namespace != other.Namespace {
+		return id.Namespace < other.Namespace
+	}
+	if id.Group != other.Group {
+		return id.Group < other.Group
+	}
+	if id.Kind != other.Kind {
+		return id.Kind < other.Kind
+	}
+	return id.Name < other.Name
+}
+
+// Reference is a reference field of a resource, e.g. spec.networkRef, which
+// points at another resource.
+type Reference struct {
+	From NodeID `json:"from"`
+	// To is the referenced resource. For a dangling reference which doesn't
+	// specify the kind of the referenced resource, the group and kind are
+	// empty.
+	To NodeID `json:"to"`
+	// Field is the path of the reference field in the referencing resource,
+	// e.g. "spec.networkRef" or "spec.secondaryRanges[1].networkRef".
+	Field string `json:"field"`
+}
+
+// DanglingReference is a reference which doesn't resolve to exactly one
+// resource in the graph.
+type DanglingReference struct {
+	Reference
+	Reason string `json:"reason"`
+}
+
+// Graph is the graph of the references between a set of resources. An edge
+// goes from a resource to each resource it depends on.
+type Graph struct {
+	nodes      []NodeID
+	objects    map[NodeID]*unstructured.Unstructured
+	references []Reference
+	dangling   []DanglingReference
+	deps       map[NodeID][]NodeID
+	dependents map[NodeID][]NodeID
+}
+
+// Build returns the graph of the references between the given resources.
+// References which use 'external' are not part of the graph, and
+// references to resources outside of the given set are reported as
+// dangling.
+func Build(objects []*unstructured.Unstructured) (*Graph, error) {
+	g := &Graph{
+		objects:    make(map[NodeID]*unstructured.Unstructured),
+		deps:       make(map[NodeID][]NodeID),
+		dependents: make(map[NodeID][]NodeID),
+	}
+	byName := make(map[nameKey][]NodeID)
+	for _, u := range objects {
+		id := NodeIDFromUnstructured(u)
+		if id.Kind == "" || id.Name == "" {
+			return nil, fmt.Errorf("resource is missing a kind or a name: %v", id)
+		}
+		if _, ok := g.objects[id]; ok {
+			return nil, fmt.Errorf("resource %v is specified more than once", id)
+		}
+		g.objects[id] = u
+		g.nodes = append(g.nodes, id)
+		key := nameKey{namespace: id.Namespace, name: id.Name}
+		byName[key] = append(byName[key], id)
+	}
+	sort.Slice(g.nodes, func(i, j int) bool { return g.nodes[i].less(g.nodes[j]) })
+
+	for _, id := range g.nodes {
+		u := g.objects[id]
+		spec, ok := u.Object["spec"]
+		if !ok {
+			continue
+		}
+		for _, r := range findReferences("spec", spec) {
+			ref := Reference{From: id, Field: r.field}
+			ref.To.Name = r.name
+			ref.To.Namespace = id.Namespace
+			if r.namespace != "" {
+				ref.To.Namespace = r.namespace
+			}
+			if r.kind != "" {
+				ref.To.Kind = r.kind
+				if r.apiVersion != "" {
+					if gv, err := schema.ParseGroupVersion(r.apiVersion); err == nil {
+						ref.To.Group = gv.Group
+					}
+				}
+			}
+			candidates := byName[nameKey{namespace: ref.To.Namespace, name: ref.To.Name}]
+			to, reason := resolve(ref, r.fieldName, candidates)
+			if reason != "" {
+				g.dangling = append(g.dangling, DanglingReference{Reference: ref, Reason: reason})
+				continue
+			}
+			ref.To = to
+			g.references = append(g.references, ref)
+			g.addEdge(id, to)
+		}
+	}
+	return g, nil
+}
+
+// nameKey groups the resources by namespace and name, which is all that a
+// reference without a kind specifies.
+type nameKey struct {
+	namespace string
+	name      string
+}
+
+func (g *Graph) addEdge(from, to NodeID) {
+	for _, d := range g.deps[from] {
+		if d == to {
+			return
+		}
+	}
+	g.deps[from] = append(g.deps[from], to)
+	g.dependents[to] = append(g.dependents[to], from)
+}
+
+// resolve picks the resource the reference points at among the resources
+// with the referenced name, other than the referencing resource itself. Most
+// references don't specify the kind of the referenced resource, in which case
+// the field name is matched against the kind, e.g. networkRef against
+// ComputeNetwork, and masterInstanceRef against SQLInstance.
+func resolve(ref Reference, fieldName string, candidates []NodeID) (NodeID, string) {
+	var others []NodeID
+	for _, c := range candidates {
+		if c != ref.From {
+			others = append(others, c)
+		}
+	}
+	var matches []NodeID
+	if ref.To.Kind != "" {
+		for _, c := range others {
+			if c.Kind == ref.To.Kind && (ref.To.Group == "" || c.Group == ref.To.Group) {
+				matches = append(matches, c)
+			}
+		}
+	} else {
+		target := strings.TrimSuffix(strings.TrimSuffix(fieldName, "Refs"), "Ref")
+		// Try the whole field name first, then drop its leading words.
+		for words := target; words != "" && len(matches) == 0; words = dropFirstWord(words) {
+			for _, c := range others {
+				if kindEndsWith(c.Kind, words) {
+					matches = append(matches, c)
+				}
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return NodeID{}, "referenced resource not found"
+	case 1:
+		return matches[0], ""
+	}
+	return NodeID{}, fmt.Sprintf("reference is ambiguous, %v resources of a matching kind are named %q", len(matches), ref.To.Name)
+}
+
+// dropFirstWord returns the given lowerCamelCase words without the first
+// one, e.g. "Instance" for "masterInstance", or "" if there is only one.
+func dropFirstWord(words string) string {
+	for i, r := range words {
+		if i > 0 && unicode.IsUpper(r) {
+			return words[i:]
+		}
+	}
+	return ""
+}
+
+// kindEndsWith returns whether the kind ends with the given words, ignoring
+// case, e.g. ComputeNetwork ends with "network" but ComputeSubnetwork
+// doesn't.
+func kindEndsWith(kind, words string) bool {
+	if words == "" || len(words) > len(kind) {
+		return false
+	}
+	start := len(kind) - len(words)
+	if !strings.EqualFold(kind[start:], words) {
+		return false
+	}
+	return unicode.IsUpper(rune(kind[start]))
+}
+
+type foundReference struct {
+	field      string
+	fieldName  string
+	name       string
+	namespace  string
+	kind       string
+	apiVersion string
+}
+
+// findReferences walks the given value for the reference fields, i.e. the
+// '*Ref' objects and the '*Refs' lists of objects, which specify a name.
+func findReferences(path string, v interface{}) []foundReference {
+	var refs []foundReference
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			field := path + "." + k
+			switch child := v[k].(type) {
+			case map[string]interface{}:
+				if strings.HasSuffix(k, "Ref") {
+					if r, ok := toReference(field, k, child); ok {
+						refs = append(refs, r)
+					}
+					continue
+				}
+				refs = append(refs, findReferences(field, child)...)
+			case []interface{}:
+				if strings.HasSuffix(k, "Refs") {
+					for i, item := range child {
+						if m, ok := item.(map[string]interface{}); ok {
+							if r, ok := toReference(fmt.Sprintf("%v[%v]", field, i), k, m); ok {
+								refs = append(refs, r)
+							}
+						}
+					}
+					continue
+				}
+				refs = append(refs, findReferences(field, child)...)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			refs = append(refs, findReferences(fmt.Sprintf("%v[%v]", path, i), item)...)
+		}
+	}
+	return refs
+}
+
+func toReference(field, fieldName string, m map[string]interface{}) (foundReference, bool) {
+	name, _ := m["name"].(string)
+	if name == "" {
+		return foundReference{}, false
+	}
+	r := foundReference{field: field, fieldName: fieldName, name: name}
+	r.namespace, _ = m["namespace"].(string)
+	r.kind, _ = m["kind"].(string)
+	r.apiVersion, _ = m["apiVersion"].(string)
+	return r, true
+}
+
+// Nodes returns the resources in the graph.
+func (g *Graph) Nodes() []NodeID {
+	return g.nodes
+}
+
+// Object returns the resource with the given ID, or nil if it isn't in the
+// graph.
+func (g *Graph) Object(id NodeID) *unstructured.Unstructured {
+	return g.objects[id]
+}
+
+// References returns the references which resolve to a resource in the
+// graph.
+func (g *Graph) References() []Reference {
+	return g.references
+}
+
+// Dangling returns the references which don't resolve to a resource in the
+// graph.
+func (g *Graph) Dangling() []DanglingReference {
+	return g.dangling
+}
+
+// Dependencies returns the resources the given resource references.
+func (g *Graph) Dependencies(id NodeID) []NodeID {
+	return g.deps[id]
+}
+
+// Dependents returns the resources which reference the given resource.
+func (g *Graph) Dependents(id NodeID) []NodeID {
+	return g.dependents[id]
+}
+
+// Cycles returns the sets of resources which reference each other, directly
+// or indirectly, including the resources which reference themselves.
+func (g *Graph) Cycles() [][]NodeID {
+	// Tarjan's strongly connected components algorithm.
+	index := 0
+	indices := make(map[NodeID]int)
+	lowlinks := make(map[NodeID]int)
+	onStack := make(map[NodeID]bool)
+	var stack []NodeID
+	var cycles [][]NodeID
+
+	var strongConnect func(v NodeID)
+	strongConnect = func(v NodeID) {
+		indices[v] = index
+		lowlinks[v] = index
+		index++
+		stack = append(stack, v)
+		onStack[v] = true
+		selfLoop := false
+		for _, w := range g.deps[v] {
+			if w == v {
+				selfLoop = true
+			}
+			if _, visited := indices[w]; !visited {
+				strongConnect(w)
+				lowlinks[v] = min(lowlinks[v], lowlinks[w])
+			} else if onStack[w] {
+				lowlinks[v] = min(lowlinks[v], indices[w])
+			}
+		}
+		if lowlinks[v] != indices[v] {
+			return
+		}
+		var component []NodeID
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component = append(component, w)
+			if w == v {
+				break
+			}
+		}
+		if len(component) > 1 || selfLoop {
+			sort.Slice(component, func(i, j int) bool { return component[i].less(component[j]) })
+			cycles = append(cycles, component)
+		}
+	}
+	for _, v := range g.nodes {
+		if _, visited := indices[v]; !visited {
+			strongConnect(v)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0].less(cycles[j][0]) })
+	return cycles
+}
+
+// CycleError is returned when the resources can't be ordered because some
+// of them reference each other.
+type CycleError struct {
+	Cycles [][]NodeID
+}
+
+func (e *CycleError) Error() string {
+	var cycles []string
+	for _, c := range e.Cycles {
+		var ids []string
+		for _, id := range c {
+			ids = append(ids, id.String())
+		}
+		cycles = append(cycles, "["+strings.Join(ids, ", ")+"]")
+	}
+	return fmt.Sprintf("resources reference each other in a cycle: %v", strings.Join(cycles, ", "))
+}
+
+// CreateOrder returns the resources in an order in which each resource
+// comes after the resources it references, i.e. the order in which they can
+// be created. It returns a *CycleError if some of the resources reference
+// each other.
+func (g *Graph) CreateOrder() ([]NodeID, error) {
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		return nil, &CycleError{Cycles: cycles}
+	}
+	remaining := make(map[NodeID]int, len(g.nodes))
+	var ready []NodeID
+	for _, id := range g.nodes {
+		remaining[id] = len(g.deps[id])
+		if remaining[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+	order := make([]NodeID, 0, len(g.nodes))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return ready[i].less(ready[j]) })
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+		for _, dependent := range g.dependents[id] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	return order, nil
+}
+
+// DeleteOrder returns the resources in an order in which each resource
+// comes before the resources it references, i.e. the order in which they
+// can be deleted. It returns a *CycleError if some of the resources
+// reference each other.
+func (g *Graph) DeleteOrder() ([]NodeID, error) {
+	order, err := g.CreateOrder()
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+	return order, nil
+}
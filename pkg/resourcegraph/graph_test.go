@@ -0,0 +1,309 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	network = `
+apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeNetwork
+metadata:
+  name: net
+  namespace: ns
+`
+	subnetwork = `
+apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeSubnetwork
+metadata:
+  name: subnet
+  namespace: ns
+spec:
+  networkRef:
+    name: net
+`
+	instance = `
+apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeInstance
+metadata:
+  name: vm
+  namespace: ns
+spec:
+  networkInterface:
+  - networkRef:
+      name: net
+    subnetworkRef:
+      name: subnet
+  serviceAccount:
+    serviceAccountRef:
+      external: sa@project.iam.gserviceaccount.com
+`
+	policyMember = `
+apiVersion: iam.cnrm.cloud.google.com/v1beta1
+kind: IAMPolicyMember
+metadata:
+  name: member
+  namespace: ns
+spec:
+  member: user:someone@example.com
+  role: roles/compute.viewer
+  resourceRef:
+    apiVersion: compute.cnrm.cloud.google.com/v1beta1
+    kind: ComputeInstance
+    name: vm
+`
+	dangling = `
+apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeSubnetwork
+metadata:
+  name: orphan
+  namespace: ns
+spec:
+  networkRef:
+    name: missing
+`
+	cycleA = `
+apiVersion: test.cnrm.cloud.google.com/v1beta1
+kind: TestA
+metadata:
+  name: a
+  namespace: ns
+spec:
+  testBRef:
+    name: b
+`
+	cycleB = `
+apiVersion: test.cnrm.cloud.google.com/v1beta1
+kind: TestB
+metadata:
+  name: b
+  namespace: ns
+spec:
+  testARef:
+    name: a
+`
+	sameNameNetwork = `
+apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeNetwork
+metadata:
+  name: shared
+  namespace: ns
+`
+	sameNameSubnetwork = `
+apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeSubnetwork
+metadata:
+  name: shared
+  namespace: ns
+spec:
+  networkRef:
+    name: shared
+`
+	projectScopedNetwork = `
+apiVersion: compute.cnrm.cloud.google.com/v1beta1
+kind: ComputeNetwork
+metadata:
+  name: net
+  namespace: ns
+spec:
+  projectRef:
+    name: net
+`
+	primaryInstance = `
+apiVersion: sql.cnrm.cloud.google.com/v1beta1
+kind: SQLInstance
+metadata:
+  name: primary
+  namespace: ns
+`
+	replicaInstance = `
+apiVersion: sql.cnrm.cloud.google.com/v1beta1
+kind: SQLInstance
+metadata:
+  name: replica
+  namespace: ns
+spec:
+  masterInstanceRef:
+    name: primary
+`
+)
+
+func id(kind, name string) NodeID {
+	group := "compute.cnrm.cloud.google.com"
+	switch kind {
+	case "IAMPolicyMember":
+		group = "iam.cnrm.cloud.google.com"
+	case "TestA", "TestB":
+		group = "test.cnrm.cloud.google.com"
+	case "SQLInstance":
+		group = "sql.cnrm.cloud.google.com"
+	}
+	return NodeID{Group: group, Kind: kind, Namespace: "ns", Name: name}
+}
+
+func TestGraph(t *testing.T) {
+	tests := []struct {
+		name             string
+		objects          []string
+		expectedRefs     []string
+		expectedDangling []string
+		expectedCycles   [][]NodeID
+		expectedOrder    []NodeID
+	}{
+		{
+			name:    "chain of references",
+			objects: []string{policyMember, instance, subnetwork, network},
+			expectedRefs: []string{
+				"ComputeInstance ns/vm spec.networkInterface[0].networkRef -> ComputeNetwork ns/net",
+				"ComputeInstance ns/vm spec.networkInterface[0].subnetworkRef -> ComputeSubnetwork ns/subnet",
+				"ComputeSubnetwork ns/subnet spec.networkRef -> ComputeNetwork ns/net",
+				"IAMPolicyMember ns/member spec.resourceRef -> ComputeInstance ns/vm",
+			},
+			expectedOrder: []NodeID{
+				id("ComputeNetwork", "net"),
+				id("ComputeSubnetwork", "subnet"),
+				id("ComputeInstance", "vm"),
+				id("IAMPolicyMember", "member"),
+			},
+		},
+		{
+			name:    "dangling reference",
+			objects: []string{network, dangling},
+			expectedDangling: []string{
+				"ComputeSubnetwork ns/orphan spec.networkRef -> <unknown kind> ns/missing",
+			},
+			expectedOrder: []NodeID{
+				id("ComputeNetwork", "net"),
+				id("ComputeSubnetwork", "orphan"),
+			},
+		},
+		{
+			name:    "cycle",
+			objects: []string{network, cycleA, cycleB},
+			expectedRefs: []string{
+				"TestA ns/a spec.testBRef -> TestB ns/b",
+				"TestB ns/b spec.testARef -> TestA ns/a",
+			},
+			expectedCycles: [][]NodeID{{id("TestA", "a"), id("TestB", "b")}},
+		},
+		{
+			name:    "kind matched against the field name",
+			objects: []string{sameNameSubnetwork, sameNameNetwork},
+			expectedRefs: []string{
+				"ComputeSubnetwork ns/shared spec.networkRef -> ComputeNetwork ns/shared",
+			},
+			expectedOrder: []NodeID{
+				id("ComputeNetwork", "shared"),
+				id("ComputeSubnetwork", "shared"),
+			},
+		},
+		{
+			name:    "reference to a resource of another kind or to itself",
+			objects: []string{projectScopedNetwork, sameNameSubnetwork},
+			expectedDangling: []string{
+				"ComputeNetwork ns/net spec.projectRef -> <unknown kind> ns/net",
+				"ComputeSubnetwork ns/shared spec.networkRef -> <unknown kind> ns/shared",
+			},
+			expectedOrder: []NodeID{
+				id("ComputeNetwork", "net"),
+				id("ComputeSubnetwork", "shared"),
+			},
+		},
+		{
+			name:    "kind matched against the last words of the field name",
+			objects: []string{replicaInstance, primaryInstance},
+			expectedRefs: []string{
+				"SQLInstance ns/replica spec.masterInstanceRef -> SQLInstance ns/primary",
+			},
+			expectedOrder: []NodeID{
+				id("SQLInstance", "primary"),
+				id("SQLInstance", "replica"),
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var objects []*unstructured.Unstructured
+			for _, s := range tc.objects {
+				u := &unstructured.Unstructured{}
+				if err := yaml.Unmarshal([]byte(s), &u.Object); err != nil {
+					t.Fatalf("error parsing test object: %v", err)
+				}
+				objects = append(objects, u)
+			}
+			g, err := Build(objects)
+			if err != nil {
+				t.Fatalf("error building graph: %v", err)
+			}
+			var refs []string
+			for _, r := range g.References() {
+				refs = append(refs, r.From.String()+" "+r.Field+" -> "+r.To.String())
+			}
+			if diff := cmp.Diff(tc.expectedRefs, refs); diff != "" {
+				t.Errorf("unexpected references (-want +got):\n%v", diff)
+			}
+			var dangling []string
+			for _, r := range g.Dangling() {
+				dangling = append(dangling, r.From.String()+" "+r.Field+" -> "+r.To.String())
+			}
+			if diff := cmp.Diff(tc.expectedDangling, dangling); diff != "" {
+				t.Errorf("unexpected dangling references (-want +got):\n%v", diff)
+			}
+			if diff := cmp.Diff(tc.expectedCycles, g.Cycles()); diff != "" {
+				t.Errorf("unexpected cycles (-want +got):\n%v", diff)
+			}
+			order, err := g.CreateOrder()
+			if len(tc.expectedCycles) > 0 {
+				if !isCycleError(err) {
+					t.Fatalf("got error %v, want a cycle error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error computing create order: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedOrder, order); diff != "" {
+				t.Errorf("unexpected create order (-want +got):\n%v", diff)
+			}
+			deleteOrder, err := g.DeleteOrder()
+			if err != nil {
+				t.Fatalf("error computing delete order: %v", err)
+			}
+			if deleteOrder[0] != order[len(order)-1] {
+				t.Errorf("got %v first in the delete order, want %v", deleteOrder[0], order[len(order)-1])
+			}
+		})
+	}
+}
+
+func TestBuildDuplicate(t *testing.T) {
+	u := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(network), &u.Object); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Build([]*unstructured.Unstructured{u, u.DeepCopy()})
+	if err == nil || !strings.Contains(err.Error(), "more than once") {
+		t.Fatalf("got error %v, want a duplicate resource error", err)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type graphJSON struct {
+	Nodes       []NodeID            `json:"nodes"`
+	References  []Reference         `json:"references"`
+	Dangling    []DanglingReference `json:"dangling"`
+	Cycles      [][]NodeID          `json:"cycles"`
+	CreateOrder []NodeID            `json:"createOrder,omitempty"`
+	DeleteOrder []NodeID            `json:"deleteOrder,omitempty"`
+}
+
+// WriteJSON writes the graph as JSON, along with its cycles and dangling
+// references and, if it has no cycles, the create and delete orders.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	out := graphJSON{
+		Nodes:      nonNil(g.nodes),
+		References: nonNil(g.references),
+		Dangling:   nonNil(g.dangling),
+		Cycles:     nonNil(g.Cycles()),
+	}
+	createOrder, err := g.CreateOrder()
+	if err != nil && !isCycleError(err) {
+		return err
+	}
+	if err == nil {
+		deleteOrder, _ := g.DeleteOrder()
+		out.CreateOrder = createOrder
+		out.DeleteOrder = deleteOrder
+	}
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling graph to json: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language. The resources are
+// labelled with their position in the create order, the references which
+// are part of a cycle are red, and the dangling references point at dashed
+// placeholder nodes.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph resources {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	position := make(map[NodeID]int)
+	if order, err := g.CreateOrder(); err == nil {
+		for i, id := range order {
+			position[id] = i + 1
+		}
+	} else if !isCycleError(err) {
+		return err
+	}
+	inCycle := make(map[NodeID]int)
+	for i, c := range g.Cycles() {
+		for _, id := range c {
+			inCycle[id] = i + 1
+		}
+	}
+
+	for _, id := range g.nodes {
+		label := id.String()
+		if p, ok := position[id]; ok {
+			label = fmt.Sprintf("%v. %v", p, label)
+		}
+		fmt.Fprintf(&b, "  %q [label=%q];\n", id.String(), label)
+	}
+	for _, ref := range g.references {
+		attrs := fmt.Sprintf("label=%q", ref.Field)
+		if c := inCycle[ref.From]; c != 0 && c == inCycle[ref.To] {
+			attrs += ", color=red"
+		}
+		fmt.Fprintf(&b, "  %q -> %q [%v];\n", ref.From.String(), ref.To.String(), attrs)
+	}
+	for i, ref := range g.dangling {
+		missing := fmt.Sprintf("dangling-%v", i)
+		fmt.Fprintf(&b, "  %q [label=%q, style=dashed];\n", missing, ref.To.String()+"\n("+ref.Reason+")")
+		fmt.Fprintf(&b, "  %q -> %q [label=%q, style=dashed];\n", ref.From.String(), missing, ref.Field)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func isCycleError(err error) bool {
+	var cycleErr *CycleError
+	return errors.As(err, &cycleErr)
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -301,14 +301,14 @@ func (r *TestReconciler) newReconcilerForObject(u *unstructured.Unstructured) re
 		}
 		return reconciler
 	case ReconcilerTypeTerraform:
-		reconciler, err := tf.NewReconciler(r.mgr, crd, r.provider, r.smLoader, immediateReconcileRequests, resourceWatcherRoutines, defaulters, jg)
+		reconciler, err := tf.NewReconciler(r.mgr, crd, r.provider, r.smLoader, immediateReconcileRequests, resourceWatcherRoutines, defaulters, jg, nil)
 		if err != nil {
 			r.t.Fatalf("error creating reconciler: %v", err)
 		}
 		return reconciler
 	case ReconcilerTypeDCL:
 		// Create DCL reconciler.
-		reconciler, err := dclcontroller.NewReconciler(r.mgr, crd, r.dclConverter, r.dclConfig, r.smLoader, immediateReconcileRequests, resourceWatcherRoutines, defaulters, jg)
+		reconciler, err := dclcontroller.NewReconciler(r.mgr, crd, r.dclConverter, r.dclConfig, r.smLoader, immediateReconcileRequests, resourceWatcherRoutines, defaulters, jg, nil)
 		if err != nil {
 			r.t.Fatalf("error creating reconciler: %v", err)
 		}
@@ -323,7 +323,7 @@ func (r *TestReconciler) newReconcilerForObject(u *unstructured.Unstructured) re
 		if !found {
 			r.t.Fatalf("no preferred GVK for %v", gk)
 		}
-		reconciler, err := directbase.NewReconciler(r.mgr, immediateReconcileRequests, resourceWatcherRoutines, gvk, model, jg)
+		reconciler, err := directbase.NewReconciler(r.mgr, immediateReconcileRequests, resourceWatcherRoutines, gvk, model, jg, nil)
 		if err != nil {
 			r.t.Fatalf("error creating reconciler: %v", err)
 		}
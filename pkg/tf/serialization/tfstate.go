@@ -0,0 +1,145 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serialization
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/google/uuid"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	tfschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const (
+	// StateVersion is the version of the terraform state file format, used by terraform 0.12 and later.
+	StateVersion = 4
+
+	// StateTerraformVersion is the terraform version recorded in generated state files. Terraform refuses
+	// to read a state file written by a newer version of terraform, so this is the oldest version that
+	// supports all the resources of the provider.
+	StateTerraformVersion = "1.0.0"
+
+	// StateProvider is the provider address of the generated resources, which is the provider
+	// terraform resolves for 'google_*' resources when no required_providers block is present.
+	StateProvider = `provider["registry.terraform.io/hashicorp/google"]`
+)
+
+// State is a terraform state file in the version 4 format.
+type State struct {
+	Version          int                        `json:"version"`
+	TerraformVersion string                     `json:"terraform_version"`
+	Serial           int64                      `json:"serial"`
+	Lineage          string                     `json:"lineage"`
+	Outputs          map[string]json.RawMessage `json:"outputs"`
+	Resources        []*StateResource           `json:"resources"`
+}
+
+// StateResource is a single (managed) resource in a terraform state file.
+type StateResource struct {
+	Mode      string           `json:"mode"`
+	Type      string           `json:"type"`
+	Name      string           `json:"name"`
+	Provider  string           `json:"provider"`
+	Instances []*StateInstance `json:"instances"`
+}
+
+// StateInstance is an instance of a resource in a terraform state file.
+type StateInstance struct {
+	SchemaVersion       int             `json:"schema_version"`
+	Attributes          json.RawMessage `json:"attributes"`
+	SensitiveAttributes []any           `json:"sensitive_attributes"`
+}
+
+// Address returns the address of the resource, as used in terraform commands and configuration.
+func (r *StateResource) Address() string {
+	return fmt.Sprintf("%v.%v", r.Type, r.Name)
+}
+
+// NewState returns an empty state, with a new lineage.
+func NewState() *State {
+	return &State{
+		Version:          StateVersion,
+		TerraformVersion: StateTerraformVersion,
+		Serial:           1,
+		Lineage:          uuid.New().String(),
+		Outputs:          map[string]json.RawMessage{},
+		Resources:        []*StateResource{},
+	}
+}
+
+// AddResource adds the resource to the state. Terraform requires resource addresses
+// to be unique, so an error is returned if the address is already in use.
+func (s *State) AddResource(r *StateResource) error {
+	for _, existing := range s.Resources {
+		if existing.Mode == r.Mode && existing.Address() == r.Address() {
+			return fmt.Errorf("duplicate resource address '%v' in terraform state", r.Address())
+		}
+	}
+	s.Resources = append(s.Resources, r)
+	return nil
+}
+
+// WriteTo writes the state as JSON, with the resources sorted by address.
+func (s *State) WriteTo(w io.Writer) (int64, error) {
+	sort.SliceStable(s.Resources, func(i, j int) bool {
+		return s.Resources[i].Address() < s.Resources[j].Address()
+	})
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("error marshalling terraform state: %w", err)
+	}
+	b = append(b, '\n')
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// InstanceStateToStateResource converts a terraform.InstanceState into the entry for the resource in a terraform state file.
+// The state must have its ID set, as terraform uses the ID to refresh the resource.
+func InstanceStateToStateResource(state *terraform.InstanceState, info *terraform.InstanceInfo, provider *tfschema.Provider) (*StateResource, error) {
+	resource, ok := provider.ResourcesMap[info.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown terraform resource type '%v'", info.Type)
+	}
+	if state.ID == "" {
+		return nil, fmt.Errorf("terraform state for '%v.%v' has no ID", info.Type, info.Id)
+	}
+	ty := resource.CoreConfigSchema().ImpliedType()
+	// AttrsAsObjectValue modifies the state, so work on a copy.
+	val, err := state.DeepCopy().AttrsAsObjectValue(ty)
+	if err != nil {
+		return nil, fmt.Errorf("error converting attributes of '%v.%v': %w", info.Type, info.Id, err)
+	}
+	attributes, err := ctyjson.Marshal(val, ty)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling attributes of '%v.%v': %w", info.Type, info.Id, err)
+	}
+	return &StateResource{
+		Mode:     "managed",
+		Type:     info.Type,
+		Name:     info.Id,
+		Provider: StateProvider,
+		Instances: []*StateInstance{
+			{
+				SchemaVersion:       resource.SchemaVersion,
+				Attributes:          attributes,
+				SensitiveAttributes: []any{},
+			},
+		},
+	}, nil
+}
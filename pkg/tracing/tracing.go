@@ -0,0 +1,145 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing instruments the reconciliation of Config Connector resources with
+// OpenTelemetry spans. Until Setup is called, the spans are no-ops.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/stats"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const instrumentationName = "github.com/GoogleCloudPlatform/k8s-config-connector"
+
+// The attributes identifying the Config Connector resource of a span.
+const (
+	GroupKey     = attribute.Key("k8s.group")
+	VersionKey   = attribute.Key("k8s.version")
+	KindKey      = attribute.Key("k8s.kind")
+	NamespaceKey = attribute.Key("k8s.namespace")
+	NameKey      = attribute.Key("k8s.name")
+)
+
+// The tracer is resolved through the global tracer provider, so the spans started before
+// Setup are no-ops and the spans started after Setup are exported.
+var tracer = otel.Tracer(instrumentationName)
+
+type Config struct {
+	// OTLPEndpoint is the URL of the OTLP/HTTP collector the spans are exported to, e.g.
+	// "http://otel-collector:4318". Tracing is disabled if it is empty.
+	OTLPEndpoint string
+	// SampleRatio is the fraction of the reconciliations which are traced. Spans whose
+	// parent is sampled are always sampled.
+	SampleRatio float64
+}
+
+func (c *Config) IsEnabled() bool {
+	return c != nil && c.OTLPEndpoint != ""
+}
+
+// Setup installs a global tracer provider which exports the spans to the OTLP collector
+// of cfg. The returned function flushes the pending spans and must be called on shutdown.
+func Setup(ctx context.Context, cfg Config, serviceName string) (shutdown func(context.Context) error, err error) {
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, fmt.Errorf("invalid trace sample ratio %v: must be between 0 and 1", cfg.SampleRatio)
+	}
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.OTLPEndpoint))
+	if err != nil {
+		return nil, fmt.Errorf("error creating the OTLP trace exporter: %w", err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown, nil
+}
+
+// ResourceAttributes returns the attributes identifying the given Config Connector resource.
+func ResourceAttributes(gvk schema.GroupVersionKind, nn types.NamespacedName) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		GroupKey.String(gvk.Group),
+		VersionKey.String(gvk.Version),
+		KindKey.String(gvk.Kind),
+		NamespaceKey.String(nn.Namespace),
+		NameKey.String(nn.Name),
+	}
+}
+
+// StartReconcile starts the root span of the reconciliation of the given resource. The spans
+// started from the returned context are the children of the reconciliation span.
+func StartReconcile(ctx context.Context, gvk schema.GroupVersionKind, nn types.NamespacedName) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "Reconcile "+gvk.Kind, trace.WithAttributes(ResourceAttributes(gvk, nn)...))
+}
+
+// Start starts a span for a step of the reconciliation, e.g. the resolution of the references.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends the span, recording err if it is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// WrapHTTPClient returns a copy of the given http client whose requests are recorded as
+// spans, and carry the trace context to the server.
+func WrapHTTPClient(inner *http.Client) *http.Client {
+	if inner == nil {
+		inner = http.DefaultClient
+	}
+	wrapped := &http.Client{}
+	*wrapped = *inner
+	wrapped.Transport = RoundTripper(inner.Transport)
+	return wrapped
+}
+
+// RoundTripper returns an http.RoundTripper which records the requests sent through inner as
+// spans named after the method and host of the request. If inner is nil,
+// http.DefaultTransport is used.
+func RoundTripper(inner http.RoundTripper) http.RoundTripper {
+	if inner == nil {
+		inner = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(inner, otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+		return req.Method + " " + req.URL.Host
+	}))
+}
+
+// GRPCStatsHandler returns a gRPC stats handler which records the calls of a gRPC client as spans.
+func GRPCStatsHandler() stats.Handler {
+	return otelgrpc.NewClientHandler()
+}
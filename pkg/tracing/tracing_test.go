@@ -0,0 +1,149 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// recorder records the spans of all the tests. The package tracer delegates to the first
+// global tracer provider only, so the provider is installed once and the tests tell their
+// spans apart by the trace ID of the root span they start.
+var recorder = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	os.Exit(m.Run())
+}
+
+// endedSpans returns the ended spans of the trace of ctx.
+func endedSpans(ctx context.Context) []sdktrace.ReadOnlySpan {
+	traceID := trace.SpanContextFromContext(ctx).TraceID()
+	var spans []sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.SpanContext().TraceID() == traceID {
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+func TestStartReconcile(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "pubsub.cnrm.cloud.google.com", Version: "v1beta1", Kind: "PubSubTopic"}
+	nn := types.NamespacedName{Namespace: "ns", Name: "topic"}
+
+	ctx, span := StartReconcile(context.Background(), gvk, nn)
+	_, child := Start(ctx, "ResolveReferences")
+	End(child, nil)
+	End(span, nil)
+
+	spans := endedSpans(ctx)
+	if len(spans) != 2 {
+		t.Fatalf("got %v spans, want 2", len(spans))
+	}
+	reconcile := spans[1]
+	if got, want := reconcile.Name(), "Reconcile PubSubTopic"; got != want {
+		t.Errorf("got span name %q, want %q", got, want)
+	}
+	wantAttrs := []attribute.KeyValue{
+		GroupKey.String("pubsub.cnrm.cloud.google.com"),
+		VersionKey.String("v1beta1"),
+		KindKey.String("PubSubTopic"),
+		NamespaceKey.String("ns"),
+		NameKey.String("topic"),
+	}
+	if diff := cmp.Diff(wantAttrs, reconcile.Attributes(), cmp.AllowUnexported(attribute.Value{})); diff != "" {
+		t.Errorf("unexpected attributes (-want +got):\n%v", diff)
+	}
+	if got := reconcile.Status().Code; got != codes.Unset {
+		t.Errorf("got status %v, want %v", got, codes.Unset)
+	}
+	if got, want := spans[0].Parent().SpanID(), reconcile.SpanContext().SpanID(); got != want {
+		t.Errorf("got parent span %v, want the reconcile span %v", got, want)
+	}
+}
+
+func TestEndWithError(t *testing.T) {
+	ctx, span := Start(context.Background(), "Apply")
+	End(span, errors.New("error applying resource"))
+
+	spans := endedSpans(ctx)
+	if len(spans) != 1 {
+		t.Fatalf("got %v spans, want 1", len(spans))
+	}
+	status := spans[0].Status()
+	if status.Code != codes.Error || status.Description != "error applying resource" {
+		t.Errorf("got status %+v, want an error status with the error message", status)
+	}
+	events := spans[0].Events()
+	if len(events) != 1 || events[0].Name != "exception" {
+		t.Errorf("got events %+v, want the recorded error", events)
+	}
+}
+
+func TestWrapHTTPClient(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	ctx, span := Start(context.Background(), "Apply")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("error creating request: %v", err)
+	}
+	res, err := WrapHTTPClient(server.Client()).Do(req)
+	if err != nil {
+		t.Fatalf("error sending request: %v", err)
+	}
+	res.Body.Close()
+	End(span, nil)
+
+	spans := endedSpans(ctx)
+	if len(spans) != 2 {
+		t.Fatalf("got %v spans, want 2", len(spans))
+	}
+	client := spans[0]
+	if got, want := client.Name(), "GET "+req.URL.Host; got != want {
+		t.Errorf("got span name %q, want %q", got, want)
+	}
+	if got := client.SpanKind(); got != trace.SpanKindClient {
+		t.Errorf("got span kind %v, want %v", got, trace.SpanKindClient)
+	}
+	if got, want := client.Parent().SpanID(), span.SpanContext().SpanID(); got != want {
+		t.Errorf("got parent span %v, want %v", got, want)
+	}
+	if traceparent == "" {
+		t.Errorf("got no traceparent header, want the trace context to be sent to the server")
+	}
+}
@@ -23,7 +23,7 @@ import (
 )
 
 func isIAMResource(obj *unstructured.Unstructured) bool {
-	return isIAMPolicy(obj) || isIAMPartialPolicy(obj) || isIAMPolicyMember(obj) || isIAMAuditConfig(obj)
+	return isIAMPolicy(obj) || isIAMPartialPolicy(obj) || isIAMPolicyMember(obj) || isIAMAuditConfig(obj) || isIAMDenyPolicy(obj)
 }
 
 func isIAMPolicy(obj *unstructured.Unstructured) bool {
@@ -42,6 +42,10 @@ func isIAMAuditConfig(obj *unstructured.Unstructured) bool {
 	return obj.GroupVersionKind() == iamapi.IAMAuditConfigGVK
 }
 
+func isIAMDenyPolicy(obj *unstructured.Unstructured) bool {
+	return obj.GroupVersionKind() == iamapi.IAMDenyPolicyGVK
+}
+
 func isIAMSpecModified(oldSpec, newSpec map[string]interface{}) bool {
 	return !reflect.DeepEqual(oldSpec, newSpec)
 }
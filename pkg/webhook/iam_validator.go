@@ -107,6 +107,12 @@ func (a *iamValidatorHandler) Handle(_ context.Context, req admission.Request) a
 			return admission.Errored(http.StatusBadRequest, err)
 		}
 		return validateIAMAuditConfig(auditConfig, rcs)
+	case isIAMDenyPolicy(obj):
+		denyPolicy, err := toIAMDenyPolicy(obj)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		return validateIAMDenyPolicy(denyPolicy)
 	default:
 		return admission.Errored(http.StatusInternalServerError,
 			fmt.Errorf("object of GroupVersionKind %v is not a supported IAM resource", obj.GroupVersionKind()))
@@ -145,6 +151,14 @@ func toIAMAuditConfig(obj *unstructured.Unstructured) (*v1beta1.IAMAuditConfig,
 	return auditConfig, nil
 }
 
+func toIAMDenyPolicy(obj *unstructured.Unstructured) (*v1beta1.IAMDenyPolicy, error) {
+	denyPolicy := &v1beta1.IAMDenyPolicy{}
+	if err := util.Marshal(obj, denyPolicy); err != nil {
+		return nil, fmt.Errorf("error parsing %v into IAMDenyPolicy object: %w", obj.GetName(), err)
+	}
+	return denyPolicy, nil
+}
+
 func getDCLSchema(gvk schema.GroupVersionKind, serviceMetadataLoader metadata.ServiceMetadataLoader, schemaLoader dclschemaloader.DCLSchemaLoader) (*openapi.Schema, admission.Response) {
 	dclSchema, err := dclschemaloader.GetDCLSchemaForGVK(gvk, serviceMetadataLoader, schemaLoader)
 	if err != nil {
@@ -229,6 +243,20 @@ func validateIAMAuditConfig(auditConfig *v1beta1.IAMAuditConfig, refResourceRCs
 	return allowedResponse
 }
 
+func validateIAMDenyPolicy(denyPolicy *v1beta1.IAMDenyPolicy) admission.Response {
+	resourceRef := denyPolicy.Spec.ResourceReference
+	switch resourceRef.Kind {
+	case "Project", "Folder", "Organization":
+	default:
+		return admission.Errored(http.StatusForbidden,
+			fmt.Errorf("IAM deny policies can only be attached to a Project, Folder or Organization, got kind %v", resourceRef.Kind))
+	}
+	if len(denyPolicy.Spec.Rules) == 0 {
+		return admission.Errored(http.StatusForbidden, fmt.Errorf("IAM deny policies must have at least one rule"))
+	}
+	return allowedResponse
+}
+
 func (a *iamValidatorHandler) dclValidateIAMPolicy(policy *v1beta1.IAMPolicy) admission.Response {
 	resourceRef := policy.Spec.ResourceReference
 	// Check that DCL-based resource supports IAMPolicy
@@ -460,6 +460,9 @@ func validateImmutableFieldsForIAMResource(oldObj *unstructured.Unstructured, ol
 	if isIAMAuditConfig(oldObj) {
 		return handleIAMAuditConfig(oldSpec, newSpec)
 	}
+	if isIAMDenyPolicy(oldObj) {
+		return handleIAMDenyPolicy(oldSpec, newSpec)
+	}
 	return admission.ValidationResponse(false, fmt.Sprintf("unknown IAM resource type: %v", oldObj.GroupVersionKind()))
 }
 
@@ -499,6 +502,18 @@ func handleIAMAuditConfig(oldSpec, newSpec map[string]interface{}) admission.Res
 	return allowedResponse
 }
 
+func handleIAMDenyPolicy(oldSpec, newSpec map[string]interface{}) admission.Response {
+	if isIAMResourceReferenceModified(oldSpec, newSpec) {
+		msg := fmt.Sprintf("the IAMDenyPolicy's spec.resourceRef is immutable")
+		return admission.ValidationResponse(false, msg)
+	}
+	if isResourceIDModified(newSpec, oldSpec) {
+		msg := fmt.Sprintf("the IAMDenyPolicy's spec.resourceID is immutable")
+		return admission.ValidationResponse(false, msg)
+	}
+	return allowedResponse
+}
+
 func findChangesOnImmutableResourceIDField(spec, oldSpec map[string]interface{}, rc *corekccv1alpha1.ResourceConfig) bool {
 	if rc.ResourceID.TargetField == "" {
 		return false
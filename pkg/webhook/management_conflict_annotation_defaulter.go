@@ -70,7 +70,7 @@ func (a *managementConflictAnnotationDefaulter) Handle(ctx context.Context, req
 			fmt.Errorf("error getting Namespace %v: %w", obj.GetNamespace(), err))
 	}
 	if supportedgvks.IsDirectByGVK(obj.GroupVersionKind()) {
-		return admission.Allowed("")
+		return defaultManagementConflictAnnotationForDirectResources(obj, ns)
 	}
 	if dclmetadata.IsDCLBasedResourceKind(obj.GroupVersionKind(), a.serviceMetadataLoader) {
 		return defaultManagementConflictAnnotationForDCLBasedResources(obj, ns, a.dclSchemaLoader, a.serviceMetadataLoader)
@@ -97,6 +97,14 @@ func defaultManagementConflictAnnotationForDCLBasedResources(obj *unstructured.U
 	return constructPatchResponse(obj, newObj)
 }
 
+func defaultManagementConflictAnnotationForDirectResources(obj *unstructured.Unstructured, ns *corev1.Namespace) admission.Response {
+	newObj := obj.DeepCopy()
+	if err := k8s.ValidateOrDefaultManagementConflictPreventionAnnotationForDirectResource(newObj, ns); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("error validating or defaulting management conflict policy annotation: %w", err))
+	}
+	return constructPatchResponse(obj, newObj)
+}
+
 func defaultManagementConflictAnnotationForTFBasedResources(obj *unstructured.Unstructured, ns *corev1.Namespace, smLoader *servicemappingloader.ServiceMappingLoader, tfResourceMap map[string]*tfschema.Resource) admission.Response {
 	rc, err := smLoader.GetResourceConfig(obj)
 	if err != nil {
@@ -20,6 +20,7 @@ import (
 	"path"
 	"strings"
 
+	customizev1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/customize/v1beta1"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/crd/crdgeneration"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/metadata"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/dcl/schema/dclschemaloader"
@@ -86,6 +87,7 @@ func GetCommonWebhookConfigs() ([]Config, error) {
 	dynamicResourcesRules := getRulesFromResources(supportedgvks.AllDynamicTypes(smLoader, serviceMetadataLoader))
 	handwrittenIamResourcesRules := getRulesFromResources(supportedgvks.BasedOnHandwrittenIAMTypes())
 	resourcesWithOverridesRules := getRulesForResourcesWithCustomValidation(allGVKs)
+	validationPolicyRules := getRulesFromResources(append(allGVKs, customizev1beta1.ValidationPolicyGroupVersionKind))
 	whCfgs := []Config{
 		{
 			Name:          "deny-immutable-field-updates.cnrm.cloud.google.com",
@@ -183,6 +185,18 @@ func GetCommonWebhookConfigs() ([]Config, error) {
 			),
 			SideEffects: admissionregistration.SideEffectClassNone,
 		},
+		{
+			Name:          "validation-policy.cnrm.cloud.google.com",
+			Path:          "/validation-policy",
+			Type:          Validating,
+			HandlerFunc:   NewRequestLoggingHandler(NewValidationPolicyValidatorHandler(), "validation policy"),
+			FailurePolicy: admissionregistration.Fail,
+			Rules: getRulesForOperationTypes(validationPolicyRules,
+				admissionregistration.Create,
+				admissionregistration.Update,
+			),
+			SideEffects: admissionregistration.SideEffectClassNone,
+		},
 		{
 			Name:          "state-into-spec-validation.cnrm.cloud.google.com",
 			Path:          "/state-into-spec-validation",
@@ -0,0 +1,245 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	customizev1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/customize/v1beta1"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// validationPolicyCostLimit bounds the cost of evaluating a single rule, so that an expensive
+// expression cannot stall the webhook. It matches the per-expression limit of kubernetes.
+const validationPolicyCostLimit = 1000000
+
+var controllerManagerServiceAccountRegex = regexp.MustCompile(ControllerManagerServiceAccountRegex)
+
+type validationPolicyValidatorHandler struct {
+	client   client.Client
+	programs *celProgramCache
+}
+
+// NewValidationPolicyValidatorHandler creates a handler which denies config connector resources
+// violating the rules of the ValidationPolicy objects in the cluster, and denies ValidationPolicy
+// objects with rules that are not valid CEL.
+func NewValidationPolicyValidatorHandler() HandlerFunc {
+	return func(mgr manager.Manager) admission.Handler {
+		return &validationPolicyValidatorHandler{
+			client:   mgr.GetClient(),
+			programs: newCELProgramCache(),
+		}
+	}
+}
+
+func (a *validationPolicyValidatorHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	deserializer := codecs.UniversalDeserializer()
+	obj := &unstructured.Unstructured{}
+	if _, _, err := deserializer.Decode(req.AdmissionRequest.Object.Raw, nil, obj); err != nil {
+		klog.Error(err)
+		return admission.Errored(http.StatusBadRequest,
+			fmt.Errorf("error decoding object: %w", err))
+	}
+	if obj.GroupVersionKind().GroupKind() == customizev1beta1.ValidationPolicyGroupVersionKind.GroupKind() {
+		return a.validatePolicy(obj)
+	}
+
+	// Policies are only enforced on users, so that the controllers can still finalize or
+	// delete existing resources which violate a policy created after them.
+	if controllerManagerServiceAccountRegex.MatchString(req.AdmissionRequest.UserInfo.Username) {
+		return admission.ValidationResponse(true, "ignore non-user requests")
+	}
+
+	var oldObj *unstructured.Unstructured
+	if req.AdmissionRequest.Operation == admissionv1.Update {
+		oldObj = &unstructured.Unstructured{}
+		if _, _, err := deserializer.Decode(req.AdmissionRequest.OldObject.Raw, nil, oldObj); err != nil {
+			klog.Error(err)
+			return admission.Errored(http.StatusBadRequest,
+				fmt.Errorf("error decoding old object: %w", err))
+		}
+	}
+
+	policies := &customizev1beta1.ValidationPolicyList{}
+	if err := a.client.List(ctx, policies); err != nil {
+		if meta.IsNoMatchError(err) {
+			// The ValidationPolicy CRD is not installed, so there are no policies to enforce.
+			return allowedResponse
+		}
+		klog.Error(err)
+		return admission.Errored(http.StatusInternalServerError,
+			fmt.Errorf("error listing ValidationPolicies: %w", err))
+	}
+	violations := a.programs.evaluatePolicies(policies.Items, obj, oldObj)
+	if len(violations) != 0 {
+		return admission.Errored(http.StatusForbidden,
+			fmt.Errorf("%v violates validation policy: %v", obj.GetKind(), strings.Join(violations, "; ")))
+	}
+	return allowedResponse
+}
+
+func (a *validationPolicyValidatorHandler) validatePolicy(obj *unstructured.Unstructured) admission.Response {
+	policy := &customizev1beta1.ValidationPolicy{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, policy); err != nil {
+		return admission.Errored(http.StatusBadRequest,
+			fmt.Errorf("error converting ValidationPolicy: %w", err))
+	}
+	var errs []string
+	for _, rule := range policy.Spec.Rules {
+		if _, err := a.programs.get(rule.Expression); err != nil {
+			errs = append(errs, fmt.Sprintf("rule %q: %v", rule.Name, err))
+		}
+	}
+	if len(errs) != 0 {
+		return admission.Errored(http.StatusForbidden,
+			fmt.Errorf("invalid ValidationPolicy %v: %v", policy.Name, strings.Join(errs, "; ")))
+	}
+	return allowedResponse
+}
+
+// celProgramCache compiles the CEL expressions of ValidationPolicy rules, keeping the compiled
+// programs so that each expression is only compiled once.
+type celProgramCache struct {
+	env *cel.Env
+
+	mutex    sync.Mutex
+	programs map[string]cel.Program
+}
+
+func newCELProgramCache() *celProgramCache {
+	env, err := cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("oldObject", cel.DynType),
+		ext.Strings(),
+	)
+	if err != nil {
+		// The environment is static, so this can only be a programming error.
+		panic(fmt.Sprintf("error creating CEL environment: %v", err))
+	}
+	return &celProgramCache{
+		env:      env,
+		programs: make(map[string]cel.Program),
+	}
+}
+
+func (c *celProgramCache) get(expression string) (cel.Program, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if program, found := c.programs[expression]; found {
+		return program, nil
+	}
+	ast, issues := c.env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("error compiling expression: %w", issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression must evaluate to a bool, not %v", ast.OutputType())
+	}
+	program, err := c.env.Program(ast, cel.CostLimit(validationPolicyCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("error building program for expression: %w", err)
+	}
+	c.programs[expression] = program
+	return program, nil
+}
+
+// evaluatePolicies returns a description of each rule of the policies that obj violates.
+// oldObj is the existing object on updates, and nil otherwise. A rule which cannot be
+// evaluated is reported as violated.
+func (c *celProgramCache) evaluatePolicies(policies []customizev1beta1.ValidationPolicy, obj, oldObj *unstructured.Unstructured) []string {
+	activation := map[string]any{
+		"object":    obj.Object,
+		"oldObject": nil,
+	}
+	if oldObj != nil {
+		activation["oldObject"] = oldObj.Object
+	}
+	gvk := obj.GroupVersionKind()
+
+	var violations []string
+	for _, policy := range policies {
+		for _, rule := range policy.Spec.Rules {
+			if !ruleMatches(rule, gvk) {
+				continue
+			}
+			ok, err := c.evaluate(rule.Expression, activation)
+			if err != nil {
+				violations = append(violations, fmt.Sprintf("%v/%v: error evaluating rule: %v", policy.Name, rule.Name, err))
+				continue
+			}
+			if ok {
+				continue
+			}
+			message := rule.Message
+			if message == "" {
+				message = fmt.Sprintf("failed expression %q", rule.Expression)
+			}
+			violations = append(violations, fmt.Sprintf("%v/%v: %v", policy.Name, rule.Name, message))
+		}
+	}
+	return violations
+}
+
+func (c *celProgramCache) evaluate(expression string, activation map[string]any) (bool, error) {
+	program, err := c.get(expression)
+	if err != nil {
+		return false, err
+	}
+	out, _, err := program.Eval(activation)
+	if err != nil {
+		return false, err
+	}
+	ok, isBool := out.Value().(bool)
+	if !isBool {
+		return false, fmt.Errorf("expression evaluated to %v, not a bool", out.Type())
+	}
+	return ok, nil
+}
+
+func ruleMatches(rule customizev1beta1.ValidationRule, gvk schema.GroupVersionKind) bool {
+	if len(rule.MatchResources) == 0 {
+		return true
+	}
+	for _, selector := range rule.MatchResources {
+		if selector.Group != gvk.Group {
+			continue
+		}
+		if selector.Kind != "" && selector.Kind != gvk.Kind {
+			continue
+		}
+		if selector.Version != "" && selector.Version != gvk.Version {
+			continue
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"reflect"
+	"testing"
+
+	customizev1beta1 "github.com/GoogleCloudPlatform/k8s-config-connector/operator/pkg/apis/core/customize/v1beta1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newTestValidationPolicy(name string, rules ...customizev1beta1.ValidationRule) customizev1beta1.ValidationPolicy {
+	return customizev1beta1.ValidationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       customizev1beta1.ValidationPolicySpec{Rules: rules},
+	}
+}
+
+func newTestStorageBucket(location string, publicAccessPrevention string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"location": location,
+	}
+	if publicAccessPrevention != "" {
+		spec["publicAccessPrevention"] = publicAccessPrevention
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "storage.cnrm.cloud.google.com/v1beta1",
+			"kind":       "StorageBucket",
+			"metadata": map[string]interface{}{
+				"name":      "test-bucket",
+				"namespace": "test-ns",
+			},
+			"spec": spec,
+		},
+	}
+}
+
+func TestEvaluateValidationPolicies(t *testing.T) {
+	approvedRegions := customizev1beta1.ValidationRule{
+		Name:       "approved-regions",
+		Expression: "object.spec.location in ['US', 'EU']",
+		Message:    "only the US and EU locations are approved",
+	}
+	noPublicBuckets := customizev1beta1.ValidationRule{
+		Name: "no-public-buckets",
+		MatchResources: []customizev1beta1.ResourceSelector{
+			{Group: "storage.cnrm.cloud.google.com", Kind: "StorageBucket"},
+		},
+		Expression: "has(object.spec.publicAccessPrevention) && object.spec.publicAccessPrevention == 'enforced'",
+	}
+	otherKind := customizev1beta1.ValidationRule{
+		Name: "sql-cmek",
+		MatchResources: []customizev1beta1.ResourceSelector{
+			{Group: "sql.cnrm.cloud.google.com", Kind: "SQLInstance"},
+		},
+		Expression: "has(object.spec.encryptionKMSCryptoKeyRef)",
+	}
+	locationUnchanged := customizev1beta1.ValidationRule{
+		Name:       "location-unchanged",
+		Expression: "oldObject == null || object.spec.location == oldObject.spec.location",
+	}
+	invalidField := customizev1beta1.ValidationRule{
+		Name:       "invalid-field",
+		Expression: "object.spec.doesNotExist == 'value'",
+	}
+
+	tests := []struct {
+		name     string
+		policies []customizev1beta1.ValidationPolicy
+		obj      *unstructured.Unstructured
+		oldObj   *unstructured.Unstructured
+		want     []string
+	}{
+		{
+			name:     "no policies",
+			policies: nil,
+			obj:      newTestStorageBucket("ASIA", ""),
+		},
+		{
+			name:     "satisfies all rules",
+			policies: []customizev1beta1.ValidationPolicy{newTestValidationPolicy("org", approvedRegions, noPublicBuckets, otherKind)},
+			obj:      newTestStorageBucket("US", "enforced"),
+		},
+		{
+			name:     "violates rules",
+			policies: []customizev1beta1.ValidationPolicy{newTestValidationPolicy("org", approvedRegions, noPublicBuckets, otherKind)},
+			obj:      newTestStorageBucket("ASIA", ""),
+			want: []string{
+				"org/approved-regions: only the US and EU locations are approved",
+				`org/no-public-buckets: failed expression "has(object.spec.publicAccessPrevention) && object.spec.publicAccessPrevention == 'enforced'"`,
+			},
+		},
+		{
+			name: "rules from several policies",
+			policies: []customizev1beta1.ValidationPolicy{
+				newTestValidationPolicy("regions", approvedRegions),
+				newTestValidationPolicy("immutable", locationUnchanged),
+			},
+			obj:    newTestStorageBucket("EU", ""),
+			oldObj: newTestStorageBucket("US", ""),
+			want: []string{
+				`immutable/location-unchanged: failed expression "oldObject == null || object.spec.location == oldObject.spec.location"`,
+			},
+		},
+		{
+			name:     "oldObject is null on create",
+			policies: []customizev1beta1.ValidationPolicy{newTestValidationPolicy("immutable", locationUnchanged)},
+			obj:      newTestStorageBucket("EU", ""),
+		},
+		{
+			name:     "evaluation error is a violation",
+			policies: []customizev1beta1.ValidationPolicy{newTestValidationPolicy("broken", invalidField)},
+			obj:      newTestStorageBucket("EU", ""),
+			want: []string{
+				"broken/invalid-field: error evaluating rule: no such key: doesNotExist",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			programs := newCELProgramCache()
+			got := programs.evaluatePolicies(tc.policies, tc.obj, tc.oldObj)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("evaluatePolicies: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateValidationPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		expression  string
+		wantAllowed bool
+	}{
+		{
+			name:        "valid expression",
+			expression:  "object.spec.location in ['US', 'EU']",
+			wantAllowed: true,
+		},
+		{
+			name:        "syntax error",
+			expression:  "object.spec.location in [",
+			wantAllowed: false,
+		},
+		{
+			name:        "not a bool",
+			expression:  "'US'",
+			wantAllowed: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := newTestValidationPolicy("test-policy", customizev1beta1.ValidationRule{
+				Name:       "test-rule",
+				Expression: tc.expression,
+			})
+			policy.SetGroupVersionKind(customizev1beta1.ValidationPolicyGroupVersionKind)
+			u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&policy)
+			if err != nil {
+				t.Fatalf("error converting policy: %v", err)
+			}
+			obj := &unstructured.Unstructured{Object: u}
+			handler := &validationPolicyValidatorHandler{programs: newCELProgramCache()}
+			resp := handler.validatePolicy(obj)
+			if resp.Allowed != tc.wantAllowed {
+				t.Errorf("validatePolicy: got allowed %v, want %v; response: %v", resp.Allowed, tc.wantAllowed, resp.Result)
+			}
+		})
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/GoogleCloudPlatform/k8s-config-connector/config/tests/samples/create"
+	"github.com/GoogleCloudPlatform/k8s-config-connector/mockgcp/common/httpmux"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/cli/cmd"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/k8s"
 	"github.com/GoogleCloudPlatform/k8s-config-connector/pkg/test"
@@ -36,6 +37,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
@@ -142,6 +144,26 @@ func TestE2EScript(t *testing.T) {
 						continue
 					}
 
+					if obj.GroupVersionKind().Kind == "MockGCPFault" {
+						if h.MockGCP != nil {
+							clearFaults, _, _ := unstructured.NestedBool(obj.Object, "clear")
+							if clearFaults {
+								h.MockGCP.ClearFaults()
+							} else {
+								fault := httpmux.Fault{}
+								if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &fault); err != nil {
+									h.Fatalf("parsing MockGCPFault: %v", err)
+								}
+								if err := h.MockGCP.InjectFault(fault); err != nil {
+									h.Fatalf("injecting fault: %v", err)
+								}
+							}
+						} else {
+							h.T.Logf("skipping MockGCPFault command, because not running against mockgcp")
+						}
+						continue
+					}
+
 					// Try to delete this object as part of cleanup
 					objectsToDelete = append(objectsToDelete, obj)
 